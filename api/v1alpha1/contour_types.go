@@ -31,6 +31,26 @@ const (
 
 	// ContourFinalizer is the name of the finalizer used for a Contour.
 	ContourFinalizer = "contour.operator.projectcontour.io/finalizer"
+
+	// RotateCertificatesAnnotation is the annotation of a Contour requesting the
+	// regeneration of its xDS certificates followed by a restart of its Contour
+	// and Envoy pods. Setting it to a new value, i.e. the current time, requests
+	// another rotation. The operator sets it to "expiry-<timestamp>" to
+	// re-issue xDS certificates that expire soon.
+	RotateCertificatesAnnotation = "contour.operator.projectcontour.io/rotate-certificates"
+
+	// SuspendAnnotation is the annotation of a Contour putting it in maintenance
+	// mode when "true": the Contour Deployment is scaled to zero and the Envoy
+	// pods are removed from all nodes. Removing the annotation restores the
+	// declared state.
+	SuspendAnnotation = "contour.operator.projectcontour.io/suspend"
+
+	// ResyncAnnotation is the annotation of a Contour requesting the operator to
+	// re-evaluate and repair all of its managed resources, i.e. after manual
+	// emergency changes, applying Envoy pod template changes deferred to a
+	// maintenance window. Setting it to a new value, i.e. the current time,
+	// requests another resync.
+	ResyncAnnotation = "contour.operator.projectcontour.io/resync"
 )
 
 // +kubebuilder:object:root=true
@@ -67,6 +87,26 @@ type ContourSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// Version is the Contour version to run, i.e. "v1.15.1". The operator
+	// uses the Contour and Envoy images it knows to be compatible with the
+	// version, so routine upgrades only require changing the version.
+	//
+	// If unset, the Contour and Envoy images of the operator's configuration
+	// are used.
+	//
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+\.[0-9]+$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ImageFlavor is the variant of the Contour and Envoy images of the
+	// version to run, i.e. "Distroless" for images without a shell or package
+	// manager, or "FIPS" for FIPS-validated images in regulated environments.
+	// Requires version to be set to a version with images of the flavor.
+	//
+	// +kubebuilder:default=Standard
+	// +optional
+	ImageFlavor ImageFlavor `json:"imageFlavor,omitempty"`
+
 	// Namespace defines the schema of a Contour namespace. See each field for
 	// additional details. Namespace name should be the same namespace as the
 	// Gateway when GatewayClassRef is set.
@@ -111,218 +151,1809 @@ type ContourSpec struct {
 	//
 	// +optional
 	NodePlacement *NodePlacement `json:"nodePlacement,omitempty"`
+
+	// RootNamespaces restricts the namespaces Contour will search for root
+	// HTTPProxy resources. If set, Contour is passed the --root-namespaces flag.
+	// Contour still watches secrets, services, ingresses and the other resources
+	// it proxies in all namespaces, i.e. for HTTPProxies included from other
+	// namespaces and TLS certificate delegation, so its ClusterRole is not
+	// narrowed.
+	//
+	// If unset, Contour searches all namespaces for root HTTPProxy resources.
+	//
+	// +optional
+	RootNamespaces []string `json:"rootNamespaces,omitempty"`
+
+	// ResourceNameTemplate is the template used to name the resources generated
+	// for the Contour, i.e. "{contour-name}-{resource}" names the Envoy DaemonSet
+	// "<contour-name>-envoy". The template must contain the "{resource}"
+	// placeholder and may contain the "{contour-name}" and "{contour-namespace}"
+	// placeholders. The secrets generated by certgen are not named using the
+	// template.
+	//
+	// If unset, resources are named "{resource}", or "{contour-name}-{resource}"
+	// if the Contour shares its namespace.
+	//
+	// The template cannot be changed once the Contour is created.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9{}-]+$`
+	// +optional
+	ResourceNameTemplate string `json:"resourceNameTemplate,omitempty"`
+
+	// ResourceLabels are labels added to every resource generated for the
+	// Contour, i.e. for cost attribution or backup selectors. Labels set by
+	// the operator, i.e. the owning Contour labels, take precedence.
+	//
+	// +optional
+	ResourceLabels map[string]string `json:"resourceLabels,omitempty"`
+
+	// ResourceAnnotations are annotations added to every resource generated
+	// for the Contour. Annotations set by the operator take precedence.
+	//
+	// Removing an annotation does not remove it from existing resources.
+	//
+	// +optional
+	ResourceAnnotations map[string]string `json:"resourceAnnotations,omitempty"`
+
+	// Ownership is the way the operator records that it owns the resources
+	// generated for the Contour. Valid values are:
+	//
+	// * Labels: Resources are identified by the owning Contour labels.
+	//
+	// * OwnerReferences: In addition to the owning Contour labels, resources
+	//   in the namespace of the Contour are given a controller owner reference
+	//   to the Contour, so they are garbage collected with the Contour and
+	//   shown as its dependents. Owner references can not cross namespaces,
+	//   so resources in other namespaces and cluster-scoped resources are
+	//   still identified by labels only.
+	//
+	// Regardless of the value, the operator does not update or remove a
+	// resource whose controller owner reference is not the Contour.
+	//
+	// +kubebuilder:default=Labels
+	// +optional
+	Ownership OwnershipType `json:"ownership,omitempty"`
+
+	// AdoptExistingResources instructs the operator to take ownership of
+	// existing resources that have the names of the resources generated for
+	// the Contour but are not owned by any Contour, i.e. resources of a
+	// Contour installed from the quickstart manifest. Adopted resources are
+	// labeled as owned by the Contour and converged to their desired state,
+	// and are removed when the Contour is deleted.
+	//
+	// If unset or false, such resources are left unchanged.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	AdoptExistingResources bool `json:"adoptExistingResources,omitempty"`
+
+	// RetentionPolicy defines which resources generated for the Contour are
+	// retained when the Contour is deleted, i.e. to keep the TLS secrets and
+	// namespace for a replacement Contour.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// Policy defines the request and response header policies that Contour
+	// applies to all routes, i.e. to inject a platform-wide "X-Environment"
+	// header without modifying every HTTPProxy.
+	//
+	// If unset, no global header policy is applied.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	Policy *PolicyParameters `json:"policy,omitempty"`
+
+	// Security defines the security baseline of the Contour instance.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	Security *SecurityParameters `json:"security,omitempty"`
+
+	// Timeouts defines the proxy timeouts of the Contour instance, i.e. to raise
+	// the stream idle timeout of all routes for websocket or gRPC streaming
+	// workloads.
+	//
+	// If unset, Contour's default timeouts are used.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	Timeouts *TimeoutParameters `json:"timeouts,omitempty"`
+
+	// LeaderElection defines the leader election of the Contour replicas, i.e.
+	// to give Contours that share a namespace distinct locks.
+	//
+	// If unset, the Contour replicas hold the "leader-elect" ConfigMap of the
+	// spec namespace, prefixed by the name of the Contour if the namespace is
+	// shared, using Contour's default durations.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	LeaderElection *LeaderElectionParameters `json:"leaderElection,omitempty"`
+
+	// KubernetesClient defines the rate limits of the Kubernetes API client of
+	// Contour, i.e. to raise them on large clusters where the client throttles
+	// the watches and status updates that Contour rebuilds its DAG from.
+	//
+	// Client rate limits require a Contour image whose serve command supports
+	// the --kubernetes-client-qps and --kubernetes-client-burst flags.
+	//
+	// If unset, Contour's default rate limits are used.
+	//
+	// +optional
+	KubernetesClient *KubernetesClientParameters `json:"kubernetesClient,omitempty"`
+
+	// Logging defines the logging of Contour, i.e. to diagnose the interactions
+	// of a Contour instance with the Kubernetes API. It does not change the log
+	// level of Envoy.
+	//
+	// If unset, Contour logs at the info level without Kubernetes client logs.
+	//
+	// +optional
+	Logging *LoggingParameters `json:"logging,omitempty"`
+
+	// Certgen defines the certgen Job generating the xDS certificates of
+	// Contour and Envoy, i.e. to schedule it onto specific nodes or to keep
+	// finished Jobs around for debugging.
+	//
+	// If unset, the Job is retried once, deleted by the operator as soon as it
+	// finishes, and scheduled to any available node without resource requests.
+	//
+	// +optional
+	Certgen *CertgenParameters `json:"certgen,omitempty"`
+
+	// CircuitBreakers defines the default circuit breaker thresholds of all
+	// upstream clusters, i.e. to raise the limits for high fan-in APIs. The
+	// thresholds of a service's own circuit breaker annotations take precedence.
+	//
+	// Global circuit breaker defaults require a Contour image that supports
+	// them, i.e. Contour v1.27 or later.
+	//
+	// If unset, Envoy's default thresholds are used.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	CircuitBreakers *CircuitBreakerParameters `json:"circuitBreakers,omitempty"`
+
+	// Networking defines the upstream networking settings of the Contour
+	// instance.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	Networking *NetworkingParameters `json:"networking,omitempty"`
+
+	// ServerHeaderTransformation defines how Envoy handles the "Server" header
+	// of responses. Use "pass_through" to stop Envoy from adding its own server
+	// header, i.e. for compliance requirements against disclosing the proxy.
+	//
+	// Server header transformation requires a Contour image that supports it,
+	// i.e. Contour v1.19 or later.
+	//
+	// If unset, Contour's default "overwrite" is used.
+	//
+	// +kubebuilder:validation:Enum=overwrite;append_if_absent;pass_through
+	// +optional
+	ServerHeaderTransformation ServerHeaderTransformationType `json:"serverHeaderTransformation,omitempty"`
+
+	// AccessLog defines the Envoy access logs of the Contour instance.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	AccessLog *AccessLogParameters `json:"accessLog,omitempty"`
+
+	// EnvoyRollout defines how changes to the Envoy pod template are rolled out
+	// to the Envoy pods, i.e. to update a canary set of nodes first.
+	//
+	// If unset, Envoy pods are replaced by a rolling update of at most 10% of
+	// the pods at a time.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	EnvoyRollout *EnvoyRollout `json:"envoyRollout,omitempty"`
+
+	// UpdatePolicy defines when changes that restart the Envoy pods, i.e.
+	// changes of the Envoy image or bootstrap configuration, are applied.
+	//
+	// If unset, all changes are applied immediately.
+	//
+	// +optional
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// SecurityContextConstraints defines the schema for the OpenShift
+	// SecurityContextConstraints used by Envoy pods. Only used when the
+	// security.openshift.io API group is served by the cluster.
+	//
+	// If unset, the operator manages a SecurityContextConstraints permitting
+	// the Envoy hostPorts and user IDs.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	SecurityContextConstraints *SecurityContextConstraints `json:"securityContextConstraints,omitempty"`
+
+	// DefaultCertificate defines a default TLS certificate issued by cert-manager
+	// for a list of domains, i.e. a wildcard certificate. The operator manages
+	// the cert-manager Certificate, configures its secret as Contour's fallback
+	// certificate and delegates the secret to the root namespaces of the Contour,
+	// or to all namespaces if no root namespaces are specified.
+	//
+	// The default certificate requires the cert-manager.io API group to be
+	// served by the cluster.
+	//
+	// If unset, no default certificate is managed.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	DefaultCertificate *DefaultCertificate `json:"defaultCertificate,omitempty"`
+
+	// Alerts defines Prometheus alerts for the Contour instance, managed as a
+	// prometheus-operator PrometheusRule in the namespace specified by
+	// spec.namespace.name. The alerts use the metrics of kube-state-metrics
+	// and of the Envoy pods.
+	//
+	// The alerts require the monitoring.coreos.com API group to be served by
+	// the cluster.
+	//
+	// If unset, no alerts are managed.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	Alerts *AlertParameters `json:"alerts,omitempty"`
+
+	// ClusterAutoscaler defines how the cluster autoscaler treats the Envoy
+	// pods when it scales down nodes.
+	//
+	// If unset, the Envoy pods have no cluster autoscaler annotations and the
+	// defaults of the autoscaler apply, i.e. an Envoy pod blocks the scale-down
+	// of its node because of its local emptyDir volume, unless the autoscaler
+	// runs with --skip-nodes-with-local-storage=false.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	ClusterAutoscaler *ClusterAutoscalerParameters `json:"clusterAutoscaler,omitempty"`
+
+	// VerticalPodAutoscaler defines VerticalPodAutoscalers of the contour and
+	// envoy containers of the Contour instance, i.e. to generate right-sizing
+	// recommendations for them.
+	//
+	// VerticalPodAutoscalers require the autoscaling.k8s.io API group to be
+	// served by the cluster.
+	//
+	// If unset, no VerticalPodAutoscalers are managed.
+	//
+	// See each field for additional details.
+	//
+	// +optional
+	VerticalPodAutoscaler *VerticalPodAutoscalerParameters `json:"verticalPodAutoscaler,omitempty"`
+
+	// ExcludeFromServiceMesh excludes the Contour, Envoy and certgen pods from
+	// the sidecar injection of the Istio and Linkerd service meshes, by
+	// labeling and annotating the pods and the spec namespace to disable
+	// injection. A mesh sidecar intercepts the xDS connection between Envoy and
+	// Contour and the health checks of the pods, so that Envoy never becomes
+	// ready.
+	//
+	// If unset or false, the pods and namespace are not labeled or annotated
+	// for service meshes.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	ExcludeFromServiceMesh bool `json:"excludeFromServiceMesh,omitempty"`
+
+	// ClusterDomain is the DNS domain of the cluster, i.e. "cluster.local".
+	// When set, Envoy connects to Contour by the fully qualified name of the
+	// Contour service in the cluster domain instead of relying on the DNS
+	// search path of the pods.
+	//
+	// If unset, Envoy connects to Contour by the name of the Contour service.
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	ClusterDomain string `json:"clusterDomain,omitempty"`
 }
 
-// NodePlacement describes node scheduling configuration of Contour and Envoy pods.
-type NodePlacement struct {
-	// Contour describes node scheduling configuration of Contour pods.
+// VerticalPodAutoscalerParameters defines the VerticalPodAutoscalers of a
+// Contour instance.
+type VerticalPodAutoscalerParameters struct {
+	// UpdateMode determines whether the recommended resources are applied to
+	// the contour and envoy containers.
+	//
+	// "Off" only generates recommendations, i.e. in the status of the
+	// VerticalPodAutoscalers.
+	//
+	// "Initial" applies the recommended resources when pods are created, i.e.
+	// during a rollout.
 	//
+	// "Auto" also evicts running pods to apply the recommended resources,
+	// which drains the Envoy pods being evicted.
+	//
+	// +kubebuilder:default=Off
 	// +optional
-	Contour *ContourNodePlacement `json:"contour,omitempty"`
+	UpdateMode VerticalPodAutoscalerUpdateMode `json:"updateMode,omitempty"`
+}
 
-	// Envoy describes node scheduling configuration of Envoy pods.
+// VerticalPodAutoscalerUpdateMode is the update mode of a VerticalPodAutoscaler.
+// +kubebuilder:validation:Enum=Off;Initial;Auto
+type VerticalPodAutoscalerUpdateMode string
+
+const (
+	// VerticalPodAutoscalerUpdateModeOff only generates recommendations.
+	VerticalPodAutoscalerUpdateModeOff VerticalPodAutoscalerUpdateMode = "Off"
+
+	// VerticalPodAutoscalerUpdateModeInitial applies recommendations when pods
+	// are created.
+	VerticalPodAutoscalerUpdateModeInitial VerticalPodAutoscalerUpdateMode = "Initial"
+
+	// VerticalPodAutoscalerUpdateModeAuto applies recommendations by evicting
+	// running pods.
+	VerticalPodAutoscalerUpdateModeAuto VerticalPodAutoscalerUpdateMode = "Auto"
+)
+
+// ClusterAutoscalerParameters defines how the cluster autoscaler treats the
+// Envoy pods of a Contour.
+type ClusterAutoscalerParameters struct {
+	// EnvoyEviction determines whether the cluster autoscaler may evict the
+	// Envoy pods to scale down their nodes.
+	//
+	// "Allow" annotates the Envoy pods as safe to evict, including their local
+	// volumes, and to be evicted before their node is removed, so that Envoy
+	// drains its connections instead of being killed with the node.
+	//
+	// "Forbid" annotates the Envoy pods as not safe to evict, so that nodes
+	// running Envoy are never scaled down by the autoscaler.
+	EnvoyEviction EvictionPolicy `json:"envoyEviction"`
+}
+
+// EvictionPolicy determines whether pods may be evicted.
+// +kubebuilder:validation:Enum=Allow;Forbid
+type EvictionPolicy string
+
+const (
+	// AllowEviction allows pods to be evicted.
+	AllowEviction EvictionPolicy = "Allow"
+
+	// ForbidEviction forbids pods to be evicted.
+	ForbidEviction EvictionPolicy = "Forbid"
+)
+
+// AlertParameters defines the Prometheus alerts of a Contour instance.
+type AlertParameters struct {
+	// RuleLabels are the labels of the PrometheusRule, i.e. to match the rule
+	// selector of a Prometheus.
 	//
 	// +optional
-	Envoy *EnvoyNodePlacement `json:"envoy,omitempty"`
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+
+	// Severity is the severity label of the alerts.
+	//
+	// +kubebuilder:default=warning
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// EnvoyNotReadyPercent is the percentage of nodes that Envoy must not be
+	// ready on for the EnvoyNotReady alert to fire.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	EnvoyNotReadyPercent int32 `json:"envoyNotReadyPercent,omitempty"`
+
+	// CertificateExpiryDays is the number of days before the first certificate
+	// of Envoy, i.e. its xDS client certificate, expires for the
+	// EnvoyCertificateExpiring alert to fire.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=14
+	// +optional
+	CertificateExpiryDays int32 `json:"certificateExpiryDays,omitempty"`
+
+	// ServerErrorPercent is the percentage of Envoy responses with a 5xx status
+	// over 5 minutes for the EnvoyServerErrors alert to fire.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=5
+	// +optional
+	ServerErrorPercent int32 `json:"serverErrorPercent,omitempty"`
 }
 
-// ContourNodePlacement describes node scheduling configuration for Contour pods.
-// If nodeSelector and tolerations are specified, the scheduler will use both to
-// determine where to place the Contour pod(s).
-type ContourNodePlacement struct {
-	// NodeSelector is the simplest recommended form of node selection constraint
-	// and specifies a map of key-value pairs. For the Contour pod to be eligible
-	// to run on a node, the node must have each of the indicated key-value pairs
-	// as labels (it can have additional labels as well).
+// DefaultCertificate defines a default TLS certificate issued by cert-manager.
+type DefaultCertificate struct {
+	// IssuerRef references the cert-manager issuer of the certificate. An
+	// Issuer must be in the namespace specified by spec.namespace.name.
+	IssuerRef CertificateIssuerReference `json:"issuerRef"`
+
+	// DNSNames are the domains of the certificate, i.e. "*.example.com".
 	//
-	// If unset, the Contour pod(s) will be scheduled to any available node.
+	// +kubebuilder:validation:MinItems=1
+	DNSNames []string `json:"dnsNames"`
+}
+
+// CertificateIssuerReference references a cert-manager issuer.
+type CertificateIssuerReference struct {
+	// Name is the name of the issuer.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind is the kind of the issuer, either "Issuer" or "ClusterIssuer".
 	//
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
 	// +optional
-	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	Kind string `json:"kind,omitempty"`
 
-	// Tolerations work with taints to ensure that Envoy pods are not scheduled
-	// onto inappropriate nodes. One or more taints are applied to a node; this
-	// marks that the node should not accept any pods that do not tolerate the
-	// taints.
+	// Group is the API group of the issuer, i.e. of an external issuer.
 	//
-	// The default is an empty list.
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// ImageFlavor is a variant of the Contour and Envoy images of a version.
+// +kubebuilder:validation:Enum=Standard;FIPS;Distroless
+type ImageFlavor string
+
+const (
+	// StandardImageFlavor is the regular images of a version.
+	StandardImageFlavor ImageFlavor = "Standard"
+
+	// FIPSImageFlavor is the FIPS-validated images of a version.
+	FIPSImageFlavor ImageFlavor = "FIPS"
+
+	// DistrolessImageFlavor is the images of a version without a shell or
+	// package manager.
+	DistrolessImageFlavor ImageFlavor = "Distroless"
+)
+
+// RetentionPolicy defines the retention of resource classes when a Contour
+// is deleted.
+type RetentionPolicy struct {
+	// Namespace is the retention of the spec namespace. Deletion is subject
+	// to the conditions of spec.namespace.removeOnDeletion.
 	//
-	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
-	// for additional details.
+	// If unset, the namespace is deleted if spec.namespace.removeOnDeletion
+	// is true and retained otherwise.
 	//
 	// +optional
-	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	Namespace RetentionAction `json:"namespace,omitempty"`
+
+	// Certificates is the retention of the TLS secrets generated for Contour
+	// and Envoy, i.e. "contourcert" and "envoycert".
+	//
+	// If unset, defaults to Retain.
+	//
+	// +optional
+	Certificates RetentionAction `json:"certificates,omitempty"`
+
+	// RBAC is the retention of the ServiceAccounts, Roles, RoleBindings,
+	// ClusterRoles and ClusterRoleBindings generated for the Contour.
+	//
+	// If unset, defaults to Delete.
+	//
+	// +optional
+	RBAC RetentionAction `json:"rbac,omitempty"`
+
+	// Services is the retention of the Contour and Envoy Services and the
+	// Envoy Route, i.e. to keep the address of a load balancer.
+	//
+	// If unset, defaults to Delete.
+	//
+	// +optional
+	Services RetentionAction `json:"services,omitempty"`
 }
 
-// EnvoyNodePlacement describes node scheduling configuration for Envoy pods.
-// If nodeSelector and tolerations are specified, the scheduler will use both
-// to determine where to place the Envoy pod(s).
-type EnvoyNodePlacement struct {
-	// NodeSelector is the simplest recommended form of node selection constraint
-	// and specifies a map of key-value pairs. For the Envoy pod to be eligible to
-	// run on a node, the node must have each of the indicated key-value pairs as
-	// labels (it can have additional labels as well).
+// RetentionAction is the action taken for a resource class when a Contour
+// is deleted.
+// +kubebuilder:validation:Enum=Retain;Delete
+type RetentionAction string
+
+const (
+	// RetainAction keeps the resources when the Contour is deleted.
+	RetainAction RetentionAction = "Retain"
+
+	// DeleteAction deletes the resources when the Contour is deleted.
+	DeleteAction RetentionAction = "Delete"
+)
+
+// PolicyParameters defines the global policies applied by Contour.
+type PolicyParameters struct {
+	// RequestHeaders defines the header policy applied to requests of all
+	// routes before they are proxied to the upstream.
 	//
-	// If unset, the Envoy pod(s) will be scheduled to any available node.
+	// +optional
+	RequestHeaders *HeadersPolicy `json:"requestHeaders,omitempty"`
+
+	// ResponseHeaders defines the header policy applied to responses of all
+	// routes before they are returned to the client.
 	//
 	// +optional
-	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	ResponseHeaders *HeadersPolicy `json:"responseHeaders,omitempty"`
+}
+
+// HeadersPolicy defines the headers to set and remove.
+type HeadersPolicy struct {
+	// Set is a map of header names to the values they are set to. A header
+	// set by a route's own header policy takes precedence.
+	//
+	// +optional
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove is the list of header names to remove.
+	//
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+}
+
+// SecurityParameters defines the security baseline of a Contour instance.
+type SecurityParameters struct {
+	// Headers defines the security response headers Envoy adds to the responses
+	// of all routes. The headers are rendered into the global response header
+	// policy; a header set by spec.policy.responseHeaders takes precedence.
+	//
+	// +optional
+	Headers *SecurityHeaders `json:"headers,omitempty"`
+}
+
+// SecurityHeaders defines the security response headers of a Contour instance.
+type SecurityHeaders struct {
+	// HSTS sets the "Strict-Transport-Security" response header. Clients ignore
+	// the header on responses served over plain HTTP.
+	//
+	// +optional
+	HSTS *HSTSPolicy `json:"hsts,omitempty"`
+
+	// ContentTypeNoSniff sets the "X-Content-Type-Options: nosniff" response
+	// header when true.
+	//
+	// +optional
+	ContentTypeNoSniff bool `json:"contentTypeNoSniff,omitempty"`
+
+	// FrameOptions sets the "X-Frame-Options" response header when specified.
+	//
+	// +kubebuilder:validation:Enum=DENY;SAMEORIGIN
+	// +optional
+	FrameOptions string `json:"frameOptions,omitempty"`
+
+	// ReferrerPolicy sets the "Referrer-Policy" response header when specified.
+	//
+	// +kubebuilder:validation:Enum=no-referrer;no-referrer-when-downgrade;origin;origin-when-cross-origin;same-origin;strict-origin;strict-origin-when-cross-origin;unsafe-url
+	// +optional
+	ReferrerPolicy string `json:"referrerPolicy,omitempty"`
+}
+
+// HSTSPolicy defines the "Strict-Transport-Security" response header.
+type HSTSPolicy struct {
+	// MaxAgeSeconds is the time, in seconds, that clients only access the
+	// host using HTTPS.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=31536000
+	MaxAgeSeconds int64 `json:"maxAgeSeconds,omitempty"`
+
+	// IncludeSubDomains applies the policy to all subdomains of the host
+	// when true.
+	//
+	// +optional
+	IncludeSubDomains bool `json:"includeSubDomains,omitempty"`
+
+	// Preload allows the host to be included in browser HSTS preload lists
+	// when true. Preload requires includeSubDomains and a maxAgeSeconds of at
+	// least 31536000.
+	//
+	// +optional
+	Preload bool `json:"preload,omitempty"`
+}
+
+// KubernetesClientParameters defines the rate limits of the Kubernetes API
+// client of Contour.
+type KubernetesClientParameters struct {
+	// QPS is the maximum number of queries per second Contour sends to the
+	// Kubernetes API server.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+
+	// Burst is the maximum burst of queries Contour sends to the Kubernetes
+	// API server above QPS. It must not be less than QPS.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// LoggingParameters defines the logging of Contour.
+type LoggingParameters struct {
+	// Debug enables the debug logs of Contour.
+	//
+	// +optional
+	Debug bool `json:"debug,omitempty"`
+
+	// KubernetesVerbosity is the verbosity of the logs of the Kubernetes client
+	// of Contour, from 0, no logs, to 9, the requests and responses of the API
+	// server.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=9
+	// +optional
+	KubernetesVerbosity int32 `json:"kubernetesVerbosity,omitempty"`
+}
+
+// CertgenParameters defines the certgen Job of a Contour.
+type CertgenParameters struct {
+	// BackoffLimit is the number of retries of the certgen Job before it is
+	// marked as failed.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// TTLSecondsAfterFinished is the number of seconds a completed or failed
+	// certgen Job is kept before the operator deletes it along with its pods.
+	// A failed Job is recreated once deleted if the certificates do not exist.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// NodePlacement describes the node scheduling configuration of the certgen
+	// pods.
+	//
+	// If unset, the pods are scheduled to any available node.
+	//
+	// +optional
+	NodePlacement *ContourNodePlacement `json:"nodePlacement,omitempty"`
+
+	// Resources are the compute resources of the certgen container.
+	//
+	// If unset, no resources are requested.
+	//
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// LeaderElectionParameters defines the leader election of the Contour replicas.
+type LeaderElectionParameters struct {
+	// ConfigMapName is the name of the ConfigMap the Contour replicas elect a
+	// leader with. It must be distinct from the ConfigMaps of the other Contours
+	// in ConfigMapNamespace.
+	//
+	// If unset, defaults to "leader-elect", prefixed by the name of the Contour
+	// if the spec namespace is shared.
+	//
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// ConfigMapNamespace is the namespace of the ConfigMap the Contour replicas
+	// elect a leader with.
+	//
+	// If unset, defaults to the spec namespace.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ConfigMapNamespace string `json:"configMapNamespace,omitempty"`
+
+	// LeaseDuration is the duration non-leader replicas wait to force acquiring
+	// leadership. It must be greater than RenewDeadline. Contour's default is 15s.
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +optional
+	LeaseDuration string `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the duration the leader retries refreshing leadership
+	// before giving it up. It must be greater than RetryPeriod. Contour's default
+	// is 10s.
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +optional
+	RenewDeadline string `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the duration the replicas wait between leader election
+	// actions. Contour's default is 2s.
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +optional
+	RetryPeriod string `json:"retryPeriod,omitempty"`
+
+	// DisableForSingleReplica disables leader election while the Contour has a
+	// single replica, so the replica does not wait for the lock of a replica it
+	// replaces during a rollout. Leader election is enabled again once Replicas
+	// is greater than 1.
+	//
+	// +optional
+	DisableForSingleReplica bool `json:"disableForSingleReplica,omitempty"`
+}
+
+// TimeoutParameters defines the proxy timeouts of a Contour instance. Each timeout
+// is a duration, i.e. "90s" or "1h30m", or "infinity" to disable the timeout. An
+// unset timeout uses Contour's default.
+type TimeoutParameters struct {
+	// RequestTimeout is the timeout for an entire request to be received from
+	// the client and the response to be returned. Contour's default is infinity.
+	//
+	// +kubebuilder:validation:Pattern=`^(infinity|([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+)$`
+	// +optional
+	RequestTimeout string `json:"requestTimeout,omitempty"`
+
+	// ConnectionIdleTimeout is the time a connection may be idle, i.e. have no
+	// active requests, before it is closed. Contour's default is 60s.
+	//
+	// +kubebuilder:validation:Pattern=`^(infinity|([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+)$`
+	// +optional
+	ConnectionIdleTimeout string `json:"connectionIdleTimeout,omitempty"`
+
+	// StreamIdleTimeout is the time a request or stream, i.e. a websocket or
+	// gRPC stream, may be idle before it is reset. Contour's default is 5m.
+	//
+	// +kubebuilder:validation:Pattern=`^(infinity|([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+)$`
+	// +optional
+	StreamIdleTimeout string `json:"streamIdleTimeout,omitempty"`
+
+	// MaxConnectionDuration is the maximum time a connection may exist,
+	// regardless of activity. Contour's default is infinity.
+	//
+	// +kubebuilder:validation:Pattern=`^(infinity|([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+)$`
+	// +optional
+	MaxConnectionDuration string `json:"maxConnectionDuration,omitempty"`
+
+	// DelayedCloseTimeout is the time Envoy waits for the client to close a
+	// connection after the response is sent. Contour's default is 1s.
+	//
+	// +kubebuilder:validation:Pattern=`^(infinity|([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+)$`
+	// +optional
+	DelayedCloseTimeout string `json:"delayedCloseTimeout,omitempty"`
+
+	// ConnectionShutdownGracePeriod is the time between Envoy notifying an HTTP/2
+	// client that a connection is draining and closing the connection. Contour's
+	// default is 5s.
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +optional
+	ConnectionShutdownGracePeriod string `json:"connectionShutdownGracePeriod,omitempty"`
+}
+
+// CircuitBreakerParameters defines the default circuit breaker thresholds of
+// upstream clusters. An unset threshold uses Envoy's default.
+type CircuitBreakerParameters struct {
+	// MaxConnections is the maximum number of connections to an upstream
+	// cluster. Envoy's default is 1024.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections int32 `json:"maxConnections,omitempty"`
+
+	// MaxPendingRequests is the maximum number of requests waiting for a
+	// connection to an upstream cluster. Envoy's default is 1024.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPendingRequests int32 `json:"maxPendingRequests,omitempty"`
+
+	// MaxRequests is the maximum number of parallel requests to an upstream
+	// cluster. Envoy's default is 1024.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRequests int32 `json:"maxRequests,omitempty"`
+
+	// MaxRetries is the maximum number of parallel retries to an upstream
+	// cluster. Envoy's default is 3.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+
+	// PerHostMaxConnections is the maximum number of connections to each host
+	// of an upstream cluster. Envoy does not limit connections per host by
+	// default.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PerHostMaxConnections int32 `json:"perHostMaxConnections,omitempty"`
+}
+
+// NetworkingParameters defines the upstream networking settings of a Contour
+// instance.
+type NetworkingParameters struct {
+	// DNSLookupFamily is the IP address family Envoy uses when resolving the
+	// names of upstream clusters, i.e. ExternalName services. Use "v6" for
+	// IPv6-only clusters.
+	//
+	// The "all" family requires a Contour image that supports it, i.e. Contour
+	// v1.25 or later.
+	//
+	// If unset, Contour's default "auto" is used, which prefers IPv6 and falls
+	// back to IPv4.
+	//
+	// +kubebuilder:validation:Enum=auto;v4;v6;all
+	// +optional
+	DNSLookupFamily DNSLookupFamilyType `json:"dnsLookupFamily,omitempty"`
+}
+
+// DNSLookupFamilyType is the IP address family used to resolve upstream names.
+type DNSLookupFamilyType string
+
+const (
+	// AutoDNSLookupFamily resolves IPv6 addresses, falling back to IPv4.
+	AutoDNSLookupFamily DNSLookupFamilyType = "auto"
+
+	// IPv4DNSLookupFamily resolves IPv4 addresses only.
+	IPv4DNSLookupFamily DNSLookupFamilyType = "v4"
+
+	// IPv6DNSLookupFamily resolves IPv6 addresses only.
+	IPv6DNSLookupFamily DNSLookupFamilyType = "v6"
+
+	// AllDNSLookupFamily resolves both IPv4 and IPv6 addresses.
+	AllDNSLookupFamily DNSLookupFamilyType = "all"
+)
+
+// ServerHeaderTransformationType defines how Envoy handles the "Server" header.
+type ServerHeaderTransformationType string
+
+const (
+	// OverwriteServerHeader sets the "Server" header of responses to "envoy".
+	OverwriteServerHeader ServerHeaderTransformationType = "overwrite"
+
+	// AppendIfAbsentServerHeader sets the "Server" header of responses to
+	// "envoy" if the upstream response has no "Server" header.
+	AppendIfAbsentServerHeader ServerHeaderTransformationType = "append_if_absent"
+
+	// PassThroughServerHeader keeps the "Server" header of upstream responses
+	// and does not add one.
+	PassThroughServerHeader ServerHeaderTransformationType = "pass_through"
+)
+
+// AccessLogParameters defines the Envoy access logs of a Contour instance.
+type AccessLogParameters struct {
+	// Level is the minimum response class that Envoy logs, i.e. "error" to only
+	// log responses with a status code of 400 or above on high-traffic
+	// instances. Envoy does not support sampling successful requests.
+	//
+	// Access log levels require a Contour image that supports them, i.e.
+	// Contour v1.24 or later.
+	//
+	// If unset, Contour's default "info" is used, which logs all requests.
+	//
+	// +kubebuilder:validation:Enum=info;error;critical;disabled
+	// +optional
+	Level AccessLogLevel `json:"level,omitempty"`
+
+	// Format is the format of Envoy access logs.
+	//
+	// If unset, Contour's default "envoy" format is used.
+	//
+	// +kubebuilder:validation:Enum=envoy;json
+	// +optional
+	Format AccessLogFormat `json:"format,omitempty"`
+
+	// JSONFields is the exact list of fields of JSON access logs, i.e.
+	// "@timestamp" or "response_code". A custom field is specified as
+	// "<name>=<envoy format string>", i.e. "host=%REQ(:AUTHORITY)%".
+	// Requires format json.
+	//
+	// If unset, Contour's default JSON fields are logged. The canonical list of
+	// fields is available at
+	// https://godoc.org/github.com/projectcontour/contour/internal/envoy#JSONFields
+	//
+	// +optional
+	JSONFields []string `json:"jsonFields,omitempty"`
+
+	// IncludeContourInstance adds a "contour_instance" field with the
+	// namespace/name of the Contour to JSON access logs when true, so log
+	// pipelines can tell the instances apart. Requires format json.
+	//
+	// +optional
+	IncludeContourInstance bool `json:"includeContourInstance,omitempty"`
+}
+
+// AccessLogFormat is the format of Envoy access logs.
+type AccessLogFormat string
+
+const (
+	// EnvoyAccessLogFormat logs requests in Envoy's default text format.
+	EnvoyAccessLogFormat AccessLogFormat = "envoy"
+
+	// JSONAccessLogFormat logs requests as JSON objects.
+	JSONAccessLogFormat AccessLogFormat = "json"
+)
+
+// AccessLogLevel is the minimum response class that Envoy logs.
+type AccessLogLevel string
+
+const (
+	// InfoAccessLogLevel logs all requests.
+	InfoAccessLogLevel AccessLogLevel = "info"
+
+	// ErrorAccessLogLevel logs requests with a response status code of 400
+	// or above.
+	ErrorAccessLogLevel AccessLogLevel = "error"
+
+	// CriticalAccessLogLevel logs requests with a response status code of
+	// 500 or above.
+	CriticalAccessLogLevel AccessLogLevel = "critical"
+
+	// DisabledAccessLogLevel disables access logs.
+	DisabledAccessLogLevel AccessLogLevel = "disabled"
+)
+
+// UpdatePolicy defines when disruptive changes are applied.
+type UpdatePolicy struct {
+	// MaintenanceWindows are the time windows in which changes of the Envoy
+	// pod template, which restart the Envoy pods, are applied. Such changes
+	// made outside of a window are deferred until the next window starts,
+	// while other changes are applied immediately.
+	//
+	// If unset, changes of the Envoy pod template are applied immediately.
+	//
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time window.
+type MaintenanceWindow struct {
+	// Days are the days of the week the window starts on. If unset, the
+	// window starts every day.
+	//
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the UTC time of day the window starts, in the format "HH:MM",
+	// i.e. "02:00".
+	//
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the length of the window, i.e. "4h" or "90m". The duration
+	// must be at most one week.
+	//
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(h|m))+$`
+	Duration string `json:"duration"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// EnvoyRollout defines the rollout of changes to the Envoy pods.
+type EnvoyRollout struct {
+	// Strategy is the rollout strategy of the Envoy pods. Valid values are:
+	//
+	// * "RollingUpdate": The Envoy DaemonSet replaces at most 10% of the pods
+	//   at a time.
+	//
+	// * "Canary": The operator replaces the pods of the canary nodes first and
+	//   waits for them to be healthy before replacing the remaining pods, halting
+	//   the rollout if an updated pod fails.
+	//
+	// * "BlueGreen": The operator provisions a parallel Envoy DaemonSet from the
+	//   updated pod template and switches the selector of the Envoy Service to
+	//   its pods once all of them are available, then removes the previous Envoy
+	//   DaemonSet. Requires capacity for two Envoy pods per node during a rollout.
+	//
+	// If unset, defaults to "RollingUpdate".
+	//
+	// +kubebuilder:default=RollingUpdate
+	// +kubebuilder:validation:Enum=RollingUpdate;Canary;BlueGreen
+	Strategy EnvoyRolloutStrategyType `json:"strategy,omitempty"`
+
+	// Canary defines the canary rollout. Only used with strategy Canary.
+	//
+	// +optional
+	Canary *EnvoyCanaryRollout `json:"canary,omitempty"`
+}
+
+// EnvoyRolloutStrategyType is a strategy for rolling out Envoy pods.
+type EnvoyRolloutStrategyType string
+
+const (
+	// RollingUpdateEnvoyRolloutStrategy replaces Envoy pods by a rolling update
+	// of the Envoy DaemonSet.
+	RollingUpdateEnvoyRolloutStrategy EnvoyRolloutStrategyType = "RollingUpdate"
+
+	// CanaryEnvoyRolloutStrategy replaces the Envoy pods of canary nodes first.
+	CanaryEnvoyRolloutStrategy EnvoyRolloutStrategyType = "Canary"
+
+	// BlueGreenEnvoyRolloutStrategy switches the Envoy Service to a parallel
+	// Envoy DaemonSet once its pods are available.
+	BlueGreenEnvoyRolloutStrategy EnvoyRolloutStrategyType = "BlueGreen"
+)
+
+// EnvoyCanaryRollout defines the canary rollout of Envoy pods.
+type EnvoyCanaryRollout struct {
+	// NodeSelector selects the canary nodes by their labels. If unset, the
+	// canary nodes are the first nodes of the Envoy pods, ordered by node name,
+	// up to Percentage of the Envoy pods.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Percentage is the percentage of Envoy pods that are updated first when
+	// NodeSelector is unset. At least one pod is updated first.
+	//
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage int32 `json:"percentage,omitempty"`
+
+	// PauseSeconds is the time, in seconds, that all updated canary pods must
+	// be ready before the remaining pods are updated.
+	//
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=0
+	PauseSeconds int32 `json:"pauseSeconds,omitempty"`
+
+	// MaxRestarts is the number of container restarts of an updated Envoy pod
+	// that halts the rollout. A halted rollout resumes when the Envoy pod
+	// template changes, i.e. when the change is reverted.
+	//
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=0
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+}
+
+// OwnershipType is a way of recording ownership of generated resources.
+// +kubebuilder:validation:Enum=Labels;OwnerReferences
+type OwnershipType string
+
+const (
+	// LabelsOwnershipType identifies generated resources by the owning
+	// Contour labels.
+	LabelsOwnershipType OwnershipType = "Labels"
+
+	// OwnerReferencesOwnershipType additionally sets a controller owner
+	// reference to the Contour on generated resources in its namespace.
+	OwnerReferencesOwnershipType OwnershipType = "OwnerReferences"
+)
+
+// SecurityContextConstraints describes the OpenShift SecurityContextConstraints
+// used by Envoy pods.
+type SecurityContextConstraints struct {
+	// Name is the name of an existing SecurityContextConstraints that Envoy's
+	// service account is granted use of. The SecurityContextConstraints must
+	// permit the Envoy hostPorts and user IDs.
+	//
+	// If unset, the operator creates a SecurityContextConstraints named
+	// "contour-envoy-<spec.namespace.name>" and grants Envoy's service
+	// account use of it.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Name *string `json:"name,omitempty"`
+}
+
+// NodePlacement describes node scheduling configuration of Contour and Envoy pods.
+type NodePlacement struct {
+	// Contour describes node scheduling configuration of Contour pods.
+	//
+	// +optional
+	Contour *ContourNodePlacement `json:"contour,omitempty"`
+
+	// Envoy describes node scheduling configuration of Envoy pods.
+	//
+	// +optional
+	Envoy *EnvoyNodePlacement `json:"envoy,omitempty"`
+
+	// Architectures is the list of node architectures the Contour and Envoy pods
+	// may be scheduled onto, i.e. the architectures of the variants of their
+	// images. The pods are only scheduled onto Linux nodes of the architectures
+	// using a required node affinity.
+	//
+	// If unset, defaults to amd64 and arm64, the architectures of the Contour
+	// and Envoy images of the operator.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Architectures []NodeArchitecture `json:"architectures,omitempty"`
+}
+
+// NodeArchitecture is the architecture of a node, as in the kubernetes.io/arch
+// label of the node.
+// +kubebuilder:validation:Enum=amd64;arm64;arm;ppc64le;s390x
+type NodeArchitecture string
+
+const (
+	// AMD64NodeArchitecture is the architecture of x86-64 nodes.
+	AMD64NodeArchitecture NodeArchitecture = "amd64"
+
+	// ARM64NodeArchitecture is the architecture of 64-bit ARM nodes.
+	ARM64NodeArchitecture NodeArchitecture = "arm64"
+)
+
+// ContourNodePlacement describes node scheduling configuration for Contour pods.
+// If nodeSelector and tolerations are specified, the scheduler will use both to
+// determine where to place the Contour pod(s).
+type ContourNodePlacement struct {
+	// NodeSelector is the simplest recommended form of node selection constraint
+	// and specifies a map of key-value pairs. For the Contour pod to be eligible
+	// to run on a node, the node must have each of the indicated key-value pairs
+	// as labels (it can have additional labels as well).
+	//
+	// If unset, the Contour pod(s) will be scheduled to any available node.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations work with taints to ensure that Envoy pods are not scheduled
+	// onto inappropriate nodes. One or more taints are applied to a node; this
+	// marks that the node should not accept any pods that do not tolerate the
+	// taints.
+	//
+	// The default is an empty list.
+	//
+	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
+	// for additional details.
+	//
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// EnvoyNodePlacement describes node scheduling configuration for Envoy pods.
+// If nodeSelector and tolerations are specified, the scheduler will use both
+// to determine where to place the Envoy pod(s).
+type EnvoyNodePlacement struct {
+	// NodeSelector is the simplest recommended form of node selection constraint
+	// and specifies a map of key-value pairs. For the Envoy pod to be eligible to
+	// run on a node, the node must have each of the indicated key-value pairs as
+	// labels (it can have additional labels as well).
+	//
+	// If unset, the Envoy pod(s) will be scheduled to any available node.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations work with taints to ensure that Envoy pods are not scheduled
+	// onto inappropriate nodes. One or more taints are applied to a node; this
+	// marks that the node should not accept any pods that do not tolerate the taints.
+	//
+	// The default is an empty list.
+	//
+	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
+	// for additional details.
+	//
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// OperatingSystems is the list of node operating systems, as in the
+	// kubernetes.io/os label of the nodes, the Envoy pods may be scheduled onto.
+	// Nodes of other operating systems, i.e. Windows nodes, are excluded using
+	// a required node affinity, even if they match NodeSelector.
+	//
+	// If unset, defaults to linux. The Envoy images of the operator are only
+	// published for Linux.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	OperatingSystems []string `json:"operatingSystems,omitempty"`
+}
+
+// NamespaceSpec defines the schema of a Contour namespace.
+type NamespaceSpec struct {
+	// Name is the name of the namespace to run Contour and dependent
+	// resources. If unset, defaults to "projectcontour".
+	//
+	// +kubebuilder:default=projectcontour
+	Name string `json:"name,omitempty"`
+
+	// RemoveOnDeletion will remove the namespace when the Contour is
+	// deleted, unless overridden by spec.retentionPolicy.namespace. If
+	// set to True, deletion will not occur if any of the following
+	// conditions exist:
+	//
+	// 1. The Contour namespace is "default", "kube-system" or the
+	//    contour-operator's namespace.
+	//
+	// 2. Another Contour exists in the namespace.
+	//
+	// 3. The namespace does not contain the Contour owning label.
+	//
+	// The deletion of the Contour is blocked, and reported by the
+	// NamespaceRemovalBlocked condition, while the namespace contains
+	// workloads, services or persistent volume claims that are neither owned
+	// by the Contour nor controlled by another object.
+	//
+	// +kubebuilder:default=false
+	RemoveOnDeletion bool `json:"removeOnDeletion,omitempty"`
+
+	// Shared allows the namespace to be shared with other Contours. When set,
+	// the names of resources generated for the Contour are prefixed with the
+	// name of the Contour, i.e. "<contour-name>-envoy", so that several Contours
+	// can run in the namespace. Every Contour in the namespace must set Shared.
+	// Shared cannot be changed once the Contour is created.
+	//
+	// +kubebuilder:default=false
+	Shared bool `json:"shared,omitempty"`
+
+	// Labels are labels added to the namespace, i.e. Pod Security Admission
+	// labels. Labels added to the namespace by other controllers are kept,
+	// and the owning Contour labels take precedence.
+	//
+	// Removing a label does not remove it from the namespace.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are annotations added to the namespace. Annotations added
+	// to the namespace by other controllers are kept.
+	//
+	// Removing an annotation does not remove it from the namespace.
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NetworkPublishing defines the schema for publishing Contour to a network.
+type NetworkPublishing struct {
+	// Envoy provides the schema for publishing the network endpoints of Envoy.
+	//
+	// If unset, defaults to:
+	//   type: LoadBalancerService
+	//   containerPorts:
+	//   - name: http
+	//     portNumber: 8080
+	//   - name: https
+	//     portNumber: 8443
+	//
+	// +kubebuilder:default={type: LoadBalancerService, loadBalancer: {scope: External, providerParameters: {type: AWS}}, containerPorts: {{name: http, portNumber: 8080}, {name: https, portNumber: 8443}}}
+	Envoy EnvoyNetworkPublishing `json:"envoy,omitempty"`
+
+	// Preset publishes Envoy with a predefined configuration. The only valid
+	// value is "LocalDevelopment", which publishes Envoy on node ports 30080
+	// (http) and 30443 (https) of kind and minikube clusters without load
+	// balancers, i.e. to map ports 80 and 443 of a kind node to them. The
+	// preset overrides the type and load balancer of envoy, which must be
+	// unset or NodePortService, and the node ports unless nodePorts is set.
+	// Not supported with gatewayClassRef.
+	//
+	// If unset, Envoy is published as defined by envoy.
+	//
+	// +optional
+	Preset NetworkPublishingPreset `json:"preset,omitempty"`
+
+	// AppProtocols overrides the application protocols of the ports of the
+	// Contour and Envoy Services, i.e. for service meshes and load balancers
+	// honoring the appProtocol of Service ports.
+	//
+	// +optional
+	AppProtocols AppProtocols `json:"appProtocols,omitempty"`
+}
+
+// AppProtocols holds the application protocols of the ports of the Contour and
+// Envoy Services. An application protocol must be an IANA standard service name
+// or a domain prefixed name, i.e. "kubernetes.io/h2c". An empty application
+// protocol unsets the appProtocol of the port.
+type AppProtocols struct {
+	// HTTP is the application protocol of the http port of the Envoy Services.
+	//
+	// If unset, defaults to "http".
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	HTTP *string `json:"http,omitempty"`
+
+	// HTTPS is the application protocol of the https port of the Envoy Services.
+	//
+	// If unset, defaults to "https", or "http" if TLS is terminated by an AWS
+	// load balancer.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	HTTPS *string `json:"https,omitempty"`
+
+	// XDS is the application protocol of the xds port of the Contour Service.
+	//
+	// If unset, defaults to "https", since Envoy connects to Contour using
+	// gRPC over TLS.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	XDS *string `json:"xds,omitempty"`
+}
+
+// EnvoyNetworkPublishing defines the schema to publish Envoy to a network.
+// +union
+type EnvoyNetworkPublishing struct {
+	// Type is the type of publishing strategy to use. Valid values are:
+	//
+	// * LoadBalancerService
+	//
+	// In this configuration, network endpoints for Envoy use container networking.
+	// A Kubernetes LoadBalancer Service is created to publish Envoy network
+	// endpoints. The Service uses port 80 to publish Envoy's HTTP network endpoint
+	// and port 443 to publish Envoy's HTTPS network endpoint.
+	//
+	// See: https://kubernetes.io/docs/concepts/services-networking/service/#loadbalancer
+	//
+	// * NodePortService
+	//
+	// Publishes Envoy network endpoints using a Kubernetes NodePort Service.
+	//
+	// In this configuration, Envoy network endpoints use container networking. A Kubernetes
+	// NodePort Service is created to publish the network endpoints.
+	//
+	// See: https://kubernetes.io/docs/concepts/services-networking/service/#nodeport
+	//
+	// * ClusterIPService
+	//
+	// Publishes Envoy network endpoints using a Kubernetes ClusterIP Service.
+	//
+	// In this configuration, Envoy network endpoints use container networking. A Kubernetes
+	// ClusterIP Service is created to publish the network endpoints.
+	//
+	// See: https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types
+	//
+	// * Route
+	//
+	// Publishes Envoy network endpoints using an OpenShift Route.
+	//
+	// In this configuration, Envoy network endpoints use container networking. A Kubernetes
+	// ClusterIP Service is created for Envoy and an OpenShift Route is created in front of
+	// the Service to publish Envoy's HTTPS network endpoint through the OpenShift router.
+	// The route.openshift.io API group must be served by the cluster.
+	//
+	// See: https://docs.openshift.com/container-platform/latest/networking/routes/route-configuration.html
+	//
+	// +unionDiscriminator
+	// +kubebuilder:default=LoadBalancerService
+	Type NetworkPublishingType `json:"type,omitempty"`
+
+	// LoadBalancer holds parameters for the load balancer. Present only if type is
+	// LoadBalancerService.
+	//
+	// If unspecified, defaults to an external Classic AWS ELB.
+	//
+	// +kubebuilder:default={scope: External, providerParameters: {type: AWS}}
+	LoadBalancer LoadBalancerStrategy `json:"loadBalancer,omitempty"`
+
+	// Route holds parameters for the OpenShift Route. Present only if type is
+	// Route.
+	//
+	// If unspecified, defaults to a passthrough Route using the hostname
+	// generated by the OpenShift router.
+	//
+	// +optional
+	Route *RouteParameters `json:"route,omitempty"`
+
+	// HTTPService holds parameters for publishing Envoy's HTTP network endpoint
+	// on a separate Service, i.e. to publish HTTPS on an internet-facing load
+	// balancer and HTTP on an internal one. If set, the Envoy Service only
+	// publishes Envoy's HTTPS network endpoint and the operator manages a second
+	// Service named "envoy-http" for the HTTP network endpoint. Present only if
+	// type is LoadBalancerService, NodePortService or ClusterIPService.
+	//
+	// If unset, Envoy's HTTP and HTTPS network endpoints are published by the
+	// Envoy Service.
+	//
+	// +optional
+	HTTPService *HTTPServiceParameters `json:"httpService,omitempty"`
+
+	// AdditionalServices is a list of Services publishing Envoy in addition to
+	// the Envoy Service, i.e. an internal load balancer next to an external one.
+	// Each Service is named "envoy-<name>" and routes to the same Envoy pods as
+	// the Envoy Service. The operator deletes the Services removed from the list.
+	//
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	AdditionalServices []AdditionalEnvoyService `json:"additionalServices,omitempty"`
+
+	// ExternalIPs is a list of IP addresses for which nodes in the cluster will
+	// accept traffic for Envoy's Service, i.e. the IPs of pre-configured routers
+	// in on-premises environments. Present only if type is NodePortService or
+	// ClusterIPService. For additional information on external IPs, see:
+	//
+	//  https://kubernetes.io/docs/concepts/services-networking/service/#external-ips
+	//
+	// Each entry must be an IPv4 or IPv6 address.
+	//
+	// +optional
+	ExternalIPs []string `json:"externalIPs,omitempty"`
+
+	// NodePorts is a list of network ports to expose on each node's IP at a static
+	// port number using a NodePort Service. Present only if type is NodePortService.
+	// A ClusterIP Service, which the NodePort Service routes to, is automatically
+	// created. You'll be able to contact the NodePort Service, from outside the
+	// cluster, by requesting <NodeIP>:<NodePort>.
+	//
+	// If type is NodePortService and nodePorts is unspecified, two nodeports will be
+	// created, one named "http" and the other named "https", with port numbers auto
+	// assigned by Kubernetes API server. For additional information on the NodePort
+	// Service, see:
+	//
+	//  https://kubernetes.io/docs/concepts/services-networking/service/#nodeport
+	//
+	// Names and port numbers must be unique in the list. Two ports must be specified,
+	// one named "http" for Envoy's insecure service and one named "https" for Envoy's
+	// secure service.
+	//
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	NodePorts []NodePort `json:"nodePorts,omitempty"`
+
+	// ContainerPorts is a list of container ports to expose from the Envoy container(s).
+	// Exposing a port here gives the system additional information about the network
+	// connections the Envoy container uses, but is primarily informational. Not specifying
+	// a port here DOES NOT prevent that port from being exposed by Envoy. Any port which is
+	// listening on the default "0.0.0.0" address inside the Envoy container will be accessible
+	// from the network. Names and port numbers must be unique in the list container ports. Two
+	// ports must be specified, one named "http" for Envoy's insecure service and one named
+	// "https" for Envoy's secure service.
+	//
+	// TODO [danehans]: Update minItems to 1, requiring only https when the following issue
+	// is fixed: https://github.com/projectcontour/contour/issues/2577.
+	//
+	// TODO [danehans]: Increase maxItems when https://github.com/projectcontour/contour/pull/3263
+	// is implemented.
+	//
+	// +kubebuilder:validation:MinItems=2
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:default={{name: http, portNumber: 8080}, {name: https, portNumber: 8443}}
+	ContainerPorts []ContainerPort `json:"containerPorts,omitempty"`
+
+	// ExtraPorts is a list of additional network ports exposed from the Envoy
+	// container(s) and published by the Envoy Service, i.e. a TCP port proxied
+	// by Contour for TCPProxy routes. Names, container port numbers, service
+	// port numbers and node port numbers must be unique across extraPorts and
+	// the http and https ports.
+	//
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	ExtraPorts []EnvoyExtraPort `json:"extraPorts,omitempty"`
+
+	// ServicePorts overrides the names and port numbers of the ports of the
+	// Envoy Service publishing the http and https container ports, i.e. to
+	// publish them on ports 8080 and 8443. Each entry must map to a different
+	// container port. Node ports are still specified by the names of the
+	// container ports. Not supported by type Route.
+	//
+	// If unset, the http container port is published by a port named "http"
+	// on port 80 and the https container port by a port named "https" on port
+	// 443.
+	//
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	ServicePorts []EnvoyServicePortMapping `json:"servicePorts,omitempty"`
+
+	// HostPorts binds container ports of the Envoy pods to ports of their
+	// nodes, i.e. to serve ports 80 and 443 of bare metal nodes without a load
+	// balancer. Each entry maps a container port, either "http", "https" or an
+	// extra port, to a different host port. The http3 container port is bound
+	// to the host port of the https container port. Not supported by the
+	// BlueGreen Envoy rollout strategy, since the pods of both fleets would
+	// bind the same host ports.
+	//
+	// If unset, no host ports are used.
+	//
+	// +kubebuilder:validation:MaxItems=18
+	// +optional
+	HostPorts []EnvoyHostPortMapping `json:"hostPorts,omitempty"`
+
+	// HTTP3 determines whether or not Envoy's HTTPS network endpoint is also
+	// published over UDP for HTTP/3 (QUIC). If true, a UDP container port named
+	// "http3" with the port number of the https container port is exposed from
+	// the Envoy container(s) and published by the Envoy Service on port 443.
+	//
+	// HTTP/3 is experimental: the HTTP/3 listeners of Envoy are configured by
+	// the Contour version of the contour, and a LoadBalancer Service with TCP
+	// and UDP ports requires the MixedProtocolLBService feature gate of the
+	// cluster. HTTP/3 is not supported by type Route, by AWS Classic load
+	// balancers nor by TLS termination at an AWS load balancer.
+	//
+	// +optional
+	HTTP3 bool `json:"http3,omitempty"`
+
+	// SessionAffinity is the session affinity of the Envoy Service. Valid values
+	// are "None" and "ClientIP", which routes the connections of a client IP to
+	// the same Envoy pod. Not supported by type Route.
+	//
+	// If unset, defaults to "None".
+	//
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// SessionAffinityConfig holds the parameters of the ClientIP session affinity
+	// of the Envoy Service. Present only if sessionAffinity is ClientIP.
+	//
+	// If unset, the session affinity of a client IP times out after 10800 seconds
+	// (3 hours).
+	//
+	// +optional
+	SessionAffinityConfig *corev1.SessionAffinityConfig `json:"sessionAffinityConfig,omitempty"`
+
+	// InternalTrafficPolicy is the internal traffic policy of the Envoy Service.
+	// Valid values are "Cluster" and "Local", which routes the traffic of
+	// in-cluster clients only to the Envoy pod of their node. Requires the
+	// ServiceInternalTrafficPolicy feature gate of the cluster.
+	//
+	// If unset, the policy is left to the default of the cluster.
+	//
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	InternalTrafficPolicy *corev1.ServiceInternalTrafficPolicyType `json:"internalTrafficPolicy,omitempty"`
+
+	// TopologyAwareHints determines whether or not the Envoy Service is annotated
+	// for topology aware hints, so that in-cluster clients prefer the Envoy pods
+	// of their zone. Requires the TopologyAwareHints feature gate of the cluster.
+	//
+	// +optional
+	TopologyAwareHints bool `json:"topologyAwareHints,omitempty"`
+}
+
+// EnvoyServicePortMapping is the name and port number of the port of the Envoy
+// Service publishing a container port.
+type EnvoyServicePortMapping struct {
+	// ContainerPort is the name of the published container port, either
+	// "http" or "https".
+	//
+	// +kubebuilder:validation:Enum=http;https
+	ContainerPort string `json:"containerPort"`
+
+	// Name is the name of the Service port.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Port is the port number of the Service port.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// EnvoyHostPortMapping is the host port a container port of the Envoy pods is
+// bound to.
+type EnvoyHostPortMapping struct {
+	// ContainerPort is the name of the bound container port, either "http",
+	// "https" or the name of an extra port.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	ContainerPort string `json:"containerPort"`
+
+	// HostPort is the port number of the node the container port is bound to.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	HostPort int32 `json:"hostPort"`
+}
+
+// EnvoyExtraPort is an additional TCP port of Envoy.
+type EnvoyExtraPort struct {
+	// Name is an IANA_SVC_NAME of the port within the pod and the Envoy
+	// Service. "http" and "https" are reserved.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// ContainerPort is the network port number exposed on the Envoy pods.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ContainerPort int32 `json:"containerPort"`
+
+	// ServicePort is the port number of the Envoy Service routing to the
+	// container port.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ServicePort int32 `json:"servicePort"`
+
+	// NodePort is the node port of the Envoy Service. Present only if type is
+	// NodePortService.
+	//
+	// If unset, the node port is assigned by the API server.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+}
+
+// NetworkPublishingPreset is a predefined way to publish Envoy.
+// +kubebuilder:validation:Enum=LocalDevelopment
+type NetworkPublishingPreset string
+
+const (
+	// LocalDevelopmentPublishingPreset publishes Envoy on fixed node ports of
+	// local development clusters, i.e. kind or minikube.
+	LocalDevelopmentPublishingPreset NetworkPublishingPreset = "LocalDevelopment"
+)
+
+// NetworkPublishingType is a way to publish network endpoints.
+// +kubebuilder:validation:Enum=LoadBalancerService;NodePortService;ClusterIPService;Route
+type NetworkPublishingType string
+
+const (
+	// LoadBalancerServicePublishingType publishes a network endpoint using a Kubernetes
+	// LoadBalancer Service.
+	LoadBalancerServicePublishingType NetworkPublishingType = "LoadBalancerService"
+
+	// NodePortServicePublishingType publishes a network endpoint using a Kubernetes
+	// NodePort Service.
+	NodePortServicePublishingType NetworkPublishingType = "NodePortService"
+
+	// ClusterIPServicePublishingType publishes a network endpoint using a Kubernetes
+	// ClusterIP Service.
+	ClusterIPServicePublishingType NetworkPublishingType = "ClusterIPService"
 
-	// Tolerations work with taints to ensure that Envoy pods are not scheduled
-	// onto inappropriate nodes. One or more taints are applied to a node; this
-	// marks that the node should not accept any pods that do not tolerate the taints.
+	// RoutePublishingType publishes a network endpoint using an OpenShift Route
+	// in front of a Kubernetes ClusterIP Service.
+	RoutePublishingType NetworkPublishingType = "Route"
+)
+
+// RouteParameters holds parameters for an OpenShift Route.
+type RouteParameters struct {
+	// Termination is the TLS termination type of the Route. Valid values are:
 	//
-	// The default is an empty list.
+	// * "Passthrough": The OpenShift router passes encrypted traffic to Envoy
+	//   without terminating TLS, so Envoy terminates TLS.
 	//
-	// See https://kubernetes.io/docs/concepts/configuration/taint-and-toleration/
-	// for additional details.
+	// * "Reencrypt": The OpenShift router terminates TLS and establishes a new
+	//   TLS connection to Envoy.
 	//
+	// If unset, defaults to "Passthrough".
+	//
+	// +kubebuilder:default=Passthrough
 	// +optional
-	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
-}
+	Termination RouteTerminationType `json:"termination,omitempty"`
 
-// NamespaceSpec defines the schema of a Contour namespace.
-type NamespaceSpec struct {
-	// Name is the name of the namespace to run Contour and dependent
-	// resources. If unset, defaults to "projectcontour".
+	// Hostname is the hostname of the Route. If unset, the OpenShift router
+	// generates a hostname for the Route.
 	//
-	// +kubebuilder:default=projectcontour
-	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Hostname *string `json:"hostname,omitempty"`
 
-	// RemoveOnDeletion will remove the namespace when the Contour is
-	// deleted. If set to True, deletion will not occur if any of the
-	// following conditions exist:
-	//
-	// 1. The Contour namespace is "default", "kube-system" or the
-	//    contour-operator's namespace.
+	// DestinationCACertificate is the PEM encoded CA certificate used by the
+	// OpenShift router to validate the certificate served by Envoy. Relevant
+	// only if termination is "Reencrypt".
 	//
-	// 2. Another Contour exists in the namespace.
-	//
-	// 3. The namespace does not contain the Contour owning label.
+	// If unset, the router validates Envoy's certificate using the service
+	// serving certificate CA of the cluster.
 	//
-	// +kubebuilder:default=false
-	RemoveOnDeletion bool `json:"removeOnDeletion,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	DestinationCACertificate *string `json:"destinationCACertificate,omitempty"`
 }
 
-// NetworkPublishing defines the schema for publishing Contour to a network.
-type NetworkPublishing struct {
-	// Envoy provides the schema for publishing the network endpoints of Envoy.
+// HTTPServiceParameters holds parameters for the Service publishing Envoy's
+// HTTP network endpoint.
+type HTTPServiceParameters struct {
+	// Type is the type of the Service. Valid values are LoadBalancerService,
+	// NodePortService and ClusterIPService. Node ports and external IPs of the
+	// Service are taken from the nodePorts and externalIPs of the Envoy network
+	// publishing strategy.
 	//
-	// If unset, defaults to:
-	//   type: LoadBalancerService
-	//   containerPorts:
-	//   - name: http
-	//     portNumber: 8080
-	//   - name: https
-	//     portNumber: 8443
+	// If unset, defaults to LoadBalancerService.
 	//
-	// +kubebuilder:default={type: LoadBalancerService, loadBalancer: {scope: External, providerParameters: {type: AWS}}, containerPorts: {{name: http, portNumber: 8080}, {name: https, portNumber: 8443}}}
-	Envoy EnvoyNetworkPublishing `json:"envoy,omitempty"`
-}
+	// +kubebuilder:validation:Enum=LoadBalancerService;NodePortService;ClusterIPService
+	// +kubebuilder:default=LoadBalancerService
+	// +optional
+	Type NetworkPublishingType `json:"type,omitempty"`
 
-// EnvoyNetworkPublishing defines the schema to publish Envoy to a network.
-// +union
-type EnvoyNetworkPublishing struct {
-	// Type is the type of publishing strategy to use. Valid values are:
-	//
-	// * LoadBalancerService
-	//
-	// In this configuration, network endpoints for Envoy use container networking.
-	// A Kubernetes LoadBalancer Service is created to publish Envoy network
-	// endpoints. The Service uses port 80 to publish Envoy's HTTP network endpoint
-	// and port 443 to publish Envoy's HTTPS network endpoint.
-	//
-	// See: https://kubernetes.io/docs/concepts/services-networking/service/#loadbalancer
-	//
-	// * NodePortService
-	//
-	// Publishes Envoy network endpoints using a Kubernetes NodePort Service.
+	// LoadBalancer holds parameters for the load balancer. Present only if type
+	// is LoadBalancerService.
 	//
-	// In this configuration, Envoy network endpoints use container networking. A Kubernetes
-	// NodePort Service is created to publish the network endpoints.
-	//
-	// See: https://kubernetes.io/docs/concepts/services-networking/service/#nodeport
-	//
-	// * ClusterIPService
+	// If unspecified, defaults to an external Classic AWS ELB.
 	//
-	// Publishes Envoy network endpoints using a Kubernetes ClusterIP Service.
+	// +kubebuilder:default={scope: External, providerParameters: {type: AWS}}
+	// +optional
+	LoadBalancer LoadBalancerStrategy `json:"loadBalancer,omitempty"`
+}
+
+// AdditionalEnvoyService holds parameters for a Service publishing Envoy in
+// addition to the Envoy Service.
+type AdditionalEnvoyService struct {
+	// Name is the name of the Service, appended to "envoy-". Names must be
+	// unique in the list and must not be "http", the name of the Envoy HTTP
+	// Service.
 	//
-	// In this configuration, Envoy network endpoints use container networking. A Kubernetes
-	// ClusterIP Service is created to publish the network endpoints.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=48
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Type is the type of the Service. Valid values are LoadBalancerService,
+	// NodePortService and ClusterIPService.
 	//
-	// See: https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types
+	// If unset, defaults to LoadBalancerService.
 	//
-	// +unionDiscriminator
+	// +kubebuilder:validation:Enum=LoadBalancerService;NodePortService;ClusterIPService
 	// +kubebuilder:default=LoadBalancerService
+	// +optional
 	Type NetworkPublishingType `json:"type,omitempty"`
 
-	// LoadBalancer holds parameters for the load balancer. Present only if type is
-	// LoadBalancerService.
+	// LoadBalancer holds parameters for the load balancer. Present only if type
+	// is LoadBalancerService.
 	//
 	// If unspecified, defaults to an external Classic AWS ELB.
 	//
 	// +kubebuilder:default={scope: External, providerParameters: {type: AWS}}
+	// +optional
 	LoadBalancer LoadBalancerStrategy `json:"loadBalancer,omitempty"`
 
-	// NodePorts is a list of network ports to expose on each node's IP at a static
-	// port number using a NodePort Service. Present only if type is NodePortService.
-	// A ClusterIP Service, which the NodePort Service routes to, is automatically
-	// created. You'll be able to contact the NodePort Service, from outside the
-	// cluster, by requesting <NodeIP>:<NodePort>.
-	//
-	// If type is NodePortService and nodePorts is unspecified, two nodeports will be
-	// created, one named "http" and the other named "https", with port numbers auto
-	// assigned by Kubernetes API server. For additional information on the NodePort
-	// Service, see:
+	// Annotations are added to the Service, overriding the annotations the
+	// operator sets for the load balancer.
 	//
-	//  https://kubernetes.io/docs/concepts/services-networking/service/#nodeport
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Ports is a list of Envoy network endpoints published by the Service.
+	// Names and port numbers must be unique in the list.
 	//
-	// Names and port numbers must be unique in the list. Two ports must be specified,
-	// one named "http" for Envoy's insecure service and one named "https" for Envoy's
-	// secure service.
+	// If unspecified, Envoy's HTTP network endpoint is published on port 80 and
+	// its HTTPS network endpoint on port 443.
 	//
-	// +kubebuilder:validation:MinItems=2
-	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:MaxItems=18
 	// +optional
-	NodePorts []NodePort `json:"nodePorts,omitempty"`
+	Ports []EnvoyServicePort `json:"ports,omitempty"`
+}
 
-	// ContainerPorts is a list of container ports to expose from the Envoy container(s).
-	// Exposing a port here gives the system additional information about the network
-	// connections the Envoy container uses, but is primarily informational. Not specifying
-	// a port here DOES NOT prevent that port from being exposed by Envoy. Any port which is
-	// listening on the default "0.0.0.0" address inside the Envoy container will be accessible
-	// from the network. Names and port numbers must be unique in the list container ports. Two
-	// ports must be specified, one named "http" for Envoy's insecure service and one named
-	// "https" for Envoy's secure service.
+// EnvoyServicePort is an Envoy network endpoint published by a Service.
+type EnvoyServicePort struct {
+	// Name is the name of the Envoy container port of the network endpoint,
+	// either "http", "https" or the name of an extra port.
 	//
-	// TODO [danehans]: Update minItems to 1, requiring only https when the following issue
-	// is fixed: https://github.com/projectcontour/contour/issues/2577.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=15
+	Name string `json:"name"`
+
+	// Port is the port number of the Service.
 	//
-	// TODO [danehans]: Increase maxItems when https://github.com/projectcontour/contour/pull/3263
-	// is implemented.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// NodePort is the node port of the Service. Present only if the type of
+	// the Service is NodePortService.
 	//
-	// +kubebuilder:validation:MinItems=2
-	// +kubebuilder:validation:MaxItems=2
-	// +kubebuilder:default={{name: http, portNumber: 8080}, {name: https, portNumber: 8443}}
-	ContainerPorts []ContainerPort `json:"containerPorts,omitempty"`
+	// If unset, the node port is assigned by the API server.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
 }
 
-// NetworkPublishingType is a way to publish network endpoints.
-// +kubebuilder:validation:Enum=LoadBalancerService;NodePortService;ClusterIPService
-type NetworkPublishingType string
+// RouteTerminationType is the TLS termination type of an OpenShift Route.
+// +kubebuilder:validation:Enum=Passthrough;Reencrypt
+type RouteTerminationType string
 
 const (
-	// LoadBalancerServicePublishingType publishes a network endpoint using a Kubernetes
-	// LoadBalancer Service.
-	LoadBalancerServicePublishingType NetworkPublishingType = "LoadBalancerService"
-
-	// NodePortServicePublishingType publishes a network endpoint using a Kubernetes
-	// NodePort Service.
-	NodePortServicePublishingType NetworkPublishingType = "NodePortService"
+	// PassthroughRouteTermination passes encrypted traffic through the
+	// OpenShift router to Envoy.
+	PassthroughRouteTermination RouteTerminationType = "Passthrough"
 
-	// ClusterIPServicePublishingType publishes a network endpoint using a Kubernetes
-	// ClusterIP Service.
-	ClusterIPServicePublishingType NetworkPublishingType = "ClusterIPService"
+	// ReencryptRouteTermination terminates TLS at the OpenShift router and
+	// re-encrypts traffic to Envoy.
+	ReencryptRouteTermination RouteTerminationType = "Reencrypt"
 )
 
 // LoadBalancerStrategy holds parameters for a load balancer.
@@ -338,6 +1969,36 @@ type LoadBalancerStrategy struct {
 	//
 	// +kubebuilder:default={type: "AWS"}
 	ProviderParameters ProviderLoadBalancerParameters `json:"providerParameters,omitempty"`
+
+	// HealthCheckNodePort is the node port used by the load balancer to health
+	// check Envoy's Service, so that external load balancer health checks can be
+	// pre-configured in firewalls. If unspecified, a port number will be assigned
+	// from the cluster's nodeport service range, i.e. --service-node-port-range
+	// flag (default: 30000-32767).
+	//
+	// If specified, the number must be within the default nodeport service
+	// range and must not be used by another node port of the Contour.
+	//
+	// Since Kubernetes does not allow the health check node port of an existing
+	// Service to be changed, healthCheckNodePort is only applied when Envoy's
+	// Service is created.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HealthCheckNodePort *int32 `json:"healthCheckNodePort,omitempty"`
+
+	// AllocateLoadBalancerNodePorts determines whether or not node ports are
+	// allocated for the ports of Envoy's Service. Set it to false for load
+	// balancers routing directly to the Envoy pods, i.e. an AWS Network Load
+	// Balancer with IP targets, so that no node ports are used by the Service.
+	// Not supported by AWS Classic load balancers and AWS Network Load Balancers
+	// with instance targets, which route to node ports.
+	//
+	// If unset, node ports are allocated.
+	//
+	// +optional
+	AllocateLoadBalancerNodePorts *bool `json:"allocateLoadBalancerNodePorts,omitempty"`
 }
 
 // LoadBalancerScope is the scope at which a load balancer is exposed.
@@ -360,7 +2021,7 @@ var (
 // +union
 type ProviderLoadBalancerParameters struct {
 	// Type is the underlying infrastructure provider for the load balancer.
-	// Allowed values are "AWS", "Azure", and "GCP".
+	// Allowed values are "AWS", "Azure", "GCP", "MetalLB", and "Generic".
 	//
 	// +unionDiscriminator
 	// +kubebuilder:default=AWS
@@ -392,18 +2053,38 @@ type ProviderLoadBalancerParameters struct {
 	//
 	// +optional
 	GCP *GCPLoadBalancerParameters `json:"gcp,omitempty"`
+
+	// MetalLB provides configuration settings that are specific to MetalLB
+	// load balancers on bare-metal clusters.
+	//
+	// If empty, defaults will be applied. See specific metallb fields for
+	// details about their defaults.
+	//
+	// +optional
+	MetalLB *MetalLBLoadBalancerParameters `json:"metalLB,omitempty"`
+
+	// Generic provides configuration settings for load balancers of
+	// infrastructure providers that are not otherwise supported, i.e.
+	// DigitalOcean, Scaleway, OVH, etc.
+	//
+	// If empty, no provider-specific configuration is applied.
+	//
+	// +optional
+	Generic *GenericLoadBalancerParameters `json:"generic,omitempty"`
 }
 
 // LoadBalancerProviderType is the underlying infrastructure provider for the
-// load balancer. Allowed values are "AWS", "Azure", and "GCP".
+// load balancer. Allowed values are "AWS", "Azure", "GCP", "MetalLB", and "Generic".
 //
-// +kubebuilder:validation:Enum=AWS;Azure;GCP
+// +kubebuilder:validation:Enum=AWS;Azure;GCP;MetalLB;Generic
 type LoadBalancerProviderType string
 
 const (
-	AWSLoadBalancerProvider   LoadBalancerProviderType = "AWS"
-	AzureLoadBalancerProvider LoadBalancerProviderType = "Azure"
-	GCPLoadBalancerProvider   LoadBalancerProviderType = "GCP"
+	AWSLoadBalancerProvider     LoadBalancerProviderType = "AWS"
+	AzureLoadBalancerProvider   LoadBalancerProviderType = "Azure"
+	GCPLoadBalancerProvider     LoadBalancerProviderType = "GCP"
+	MetalLBLoadBalancerProvider LoadBalancerProviderType = "MetalLB"
+	GenericLoadBalancerProvider LoadBalancerProviderType = "Generic"
 )
 
 // AWSLoadBalancerParameters provides configuration settings that are specific to
@@ -441,8 +2122,95 @@ type AWSLoadBalancerParameters struct {
 	//
 	// +optional
 	AllocationIDs []string `json:"allocationIds,omitempty"`
+
+	// Subnets is a list of subnet IDs or names that the Network Load Balancer is
+	// placed in. Works only with type NLB. If AllocationIDs are specified, the
+	// number of subnets must match the number of Allocation IDs.
+	//
+	// Example: "subnet-<xxxxxxxxxxxxxxxxx>"
+	//
+	// If unset, subnets are automatically discovered by the AWS cloud provider.
+	//
+	// See: https://kubernetes-sigs.github.io/aws-load-balancer-controller/latest/guide/service/annotations/#subnets
+	//
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// TargetType is the type of targets that the Network Load Balancer routes
+	// traffic to. Works only with type NLB. Valid values are:
+	//
+	// * "Instance": The Network Load Balancer routes traffic to the nodes of the
+	//   cluster using the Envoy Service node ports.
+	//
+	// * "IP": The Network Load Balancer routes traffic directly to the Envoy pod
+	//   IPs. Requires the AWS Load Balancer Controller to be running in the cluster.
+	//
+	// If unset, the AWS cloud provider default is used.
+	//
+	// See: https://kubernetes-sigs.github.io/aws-load-balancer-controller/latest/guide/service/nlb/
+	//
+	// +optional
+	TargetType AWSNetworkLoadBalancerTargetType `json:"targetType,omitempty"`
+
+	// TLSTermination configures the load balancer to terminate TLS for Envoy's
+	// HTTPS network endpoint. When set, Envoy receives plain HTTP on the https
+	// Service port, i.e. the https Service port targets Envoy's http container port.
+	//
+	// If unset, TLS is passed through the load balancer and terminated by Envoy.
+	//
+	// See: https://kubernetes.io/docs/concepts/services-networking/service/#ssl-support-on-aws
+	//
+	// +optional
+	TLSTermination *AWSTLSTermination `json:"tlsTermination,omitempty"`
+}
+
+// AWSTLSTermination holds parameters for terminating TLS at an AWS load balancer.
+type AWSTLSTermination struct {
+	// CertificateARN is the ARN of the ACM or IAM certificate used by the
+	// load balancer to terminate TLS.
+	//
+	// Example: "arn:aws:acm:us-east-1:123456789012:certificate/<xxxxxxxx>"
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=2048
+	CertificateARN string `json:"certificateARN"`
+
+	// BackendProtocol is the protocol used by the load balancer to send
+	// traffic to Envoy. Valid values are:
+	//
+	// * "TCP": The load balancer forwards the decrypted TCP stream to Envoy.
+	//
+	// * "HTTP": The load balancer acts as an HTTP proxy and adds the
+	//   X-Forwarded-For header. Works only with type Classic, and disables the
+	//   PROXY protocol on the load balancer.
+	//
+	// If unset, defaults to "TCP".
+	//
+	// +kubebuilder:default=TCP
+	// +optional
+	BackendProtocol AWSBackendProtocol `json:"backendProtocol,omitempty"`
 }
 
+// AWSBackendProtocol is the protocol used by an AWS load balancer to send
+// traffic to its backends.
+// +kubebuilder:validation:Enum=TCP;HTTP
+type AWSBackendProtocol string
+
+const (
+	AWSTCPBackendProtocol  AWSBackendProtocol = "TCP"
+	AWSHTTPBackendProtocol AWSBackendProtocol = "HTTP"
+)
+
+// AWSNetworkLoadBalancerTargetType is the type of targets that an AWS Network
+// Load Balancer routes traffic to.
+// +kubebuilder:validation:Enum=Instance;IP
+type AWSNetworkLoadBalancerTargetType string
+
+const (
+	AWSNetworkLoadBalancerInstanceTarget AWSNetworkLoadBalancerTargetType = "Instance"
+	AWSNetworkLoadBalancerIPTarget       AWSNetworkLoadBalancerTargetType = "IP"
+)
+
 // AWSLoadBalancerType is the type of AWS load balancer to manage.
 // +kubebuilder:validation:Enum=Classic;NLB
 type AWSLoadBalancerType string
@@ -490,6 +2258,60 @@ type AzureLoadBalancerParameters struct {
 	// +kubebuilder:validation:MaxLength=80
 	// +optional
 	Subnet *string `json:"subnet,omitempty"`
+
+	// PublicIPName is the name of an existing public IP resource used by the load
+	// balancer. Relevant only if scope is "External". If the public IP resource does
+	// not reside in the same resource group as the AKS cluster, the resourceGroup
+	// parameter is also required.
+	//
+	// Takes precedence over "address" when both are specified.
+	//
+	// See: https://docs.microsoft.com/en-us/azure/aks/static-ip#create-a-service-using-the-static-ip-address
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=80
+	// +optional
+	PublicIPName *string `json:"publicIPName,omitempty"`
+}
+
+// MetalLBLoadBalancerParameters provides configuration settings that are specific
+// to MetalLB load balancers.
+type MetalLBLoadBalancerParameters struct {
+	// AddressPool is the name of the MetalLB address pool that the load balancer
+	// IP is allocated from. If unset, MetalLB allocates the IP from any pool
+	// with auto-assign enabled.
+	//
+	// See: https://metallb.universe.tf/usage/#requesting-specific-ips
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	AddressPool *string `json:"addressPool,omitempty"`
+
+	// SharingKey allows the load balancer IP to be shared with other Services
+	// that specify the same sharing key, provided the Services do not use
+	// the same ports.
+	//
+	// See: https://metallb.universe.tf/usage/#ip-address-sharing
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	SharingKey *string `json:"sharingKey,omitempty"`
+}
+
+// GenericLoadBalancerParameters provides configuration settings for load balancers
+// of infrastructure providers that are not otherwise supported.
+type GenericLoadBalancerParameters struct {
+	// Annotations are the provider-specific annotations applied to Envoy's
+	// Service, i.e. "service.beta.kubernetes.io/do-loadbalancer-protocol".
+	// The operator reconciles these annotations as authoritative, so changes
+	// made directly to the Service are reverted.
+	//
+	// Annotations are applied as-is, including when scope is "Internal". Since
+	// the operator has no knowledge of the provider, any annotation required to
+	// request an internal load balancer must be included.
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type GCPLoadBalancerParameters struct {
@@ -517,6 +2339,28 @@ type GCPLoadBalancerParameters struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +optional
 	Subnet *string `json:"subnet,omitempty"`
+
+	// GlobalAccess allows clients from any region to access an internal load
+	// balancer. Relevant only if scope is "Internal".
+	//
+	// If unset, defaults to false, i.e. only clients in the same region as the
+	// load balancer can access it.
+	//
+	// See: https://cloud.google.com/kubernetes-engine/docs/how-to/internal-load-balancing#global_access
+	//
+	// +optional
+	GlobalAccess bool `json:"globalAccess,omitempty"`
+
+	// NetworkEndpointGroups creates standalone zonal network endpoint groups (NEGs)
+	// for Envoy's HTTP and HTTPS Service ports, allowing GCP load balancers to
+	// route traffic directly to Envoy pods.
+	//
+	// If unset, defaults to false.
+	//
+	// See: https://cloud.google.com/kubernetes-engine/docs/how-to/standalone-neg
+	//
+	// +optional
+	NetworkEndpointGroups bool `json:"networkEndpointGroups,omitempty"`
 }
 
 // NodePort is the schema to specify a network port for a NodePort Service.
@@ -564,8 +2408,65 @@ type ContainerPort struct {
 
 const (
 	// ContourAvailableConditionType indicates that the contour is running
-	// and available.
+	// and available, i.e. that all the Envoy pods run the current pod
+	// template and are ready, and that the Envoy service of a contour
+	// published by a load balancer has a load balancer address.
 	ContourAvailableConditionType = "Available"
+
+	// EnvoyRolloutHaltedConditionType indicates that the canary rollout of the
+	// Envoy pods is halted due to an unhealthy updated pod.
+	EnvoyRolloutHaltedConditionType = "EnvoyRolloutHalted"
+
+	// ContourDegradedConditionType indicates that the Contour Deployment or
+	// Envoy DaemonSet was rolled back to its last known-good pod template after
+	// the rollout of a spec change failed.
+	ContourDegradedConditionType = "Degraded"
+
+	// UpgradeBlockedConditionType indicates that the upgrade to the Contour
+	// version of spec.version is blocked by failed pre-upgrade checks, i.e. an
+	// HTTPProxy feature or Kubernetes version the version does not support.
+	UpgradeBlockedConditionType = "UpgradeBlocked"
+
+	// ImagesVerifiedConditionType indicates that the Contour and Envoy images
+	// were pinned to their digest and their signatures verified, if the
+	// operator is configured to do so. Images are not rolled out otherwise.
+	ImagesVerifiedConditionType = "ImagesVerified"
+
+	// SuspendedConditionType indicates that the contour is in maintenance mode,
+	// i.e. suspended by its suspend annotation.
+	SuspendedConditionType = "Suspended"
+
+	// NamespaceTerminatingConditionType indicates that the spec namespace
+	// removed on deletion of the contour is stuck terminating, i.e. because of
+	// the finalizers of other controllers. The contour is deleted once the
+	// condition is reported, while the namespace keeps terminating.
+	NamespaceTerminatingConditionType = "NamespaceTerminating"
+
+	// NamespaceRemovalBlockedConditionType indicates that the spec namespace
+	// of the contour is not removed on deletion of the contour because it
+	// contains workloads not owned by the contour, which blocks the deletion
+	// of the contour.
+	NamespaceRemovalBlockedConditionType = "NamespaceRemovalBlocked"
+
+	// ConflictedConditionType indicates that the GatewayClass of
+	// spec.gatewayClassRef of the contour is claimed by another contour, which
+	// the GatewayClass is managed for instead.
+	ConflictedConditionType = "Conflicted"
+
+	// NodePortConflictConditionType indicates that a node port of the Envoy
+	// service of the contour is allocated to another service, which keeps the
+	// Envoy service from being created or updated.
+	NodePortConflictConditionType = "NodePortConflict"
+
+	// ConfigRejectedConditionType indicates that the Contour configuration
+	// rendered for the contour does not match the configuration file schema of
+	// Contour, which keeps the configuration and the Contour and Envoy
+	// workloads from being rolled out.
+	ConfigRejectedConditionType = "ConfigRejected"
+
+	// CertificatesExpiringConditionType indicates that an xDS certificate of
+	// the contour expires soon and the certificates are re-issued.
+	CertificatesExpiringConditionType = "CertificatesExpiring"
 )
 
 // ContourStatus defines the observed state of Contour.
@@ -581,6 +2482,26 @@ type ContourStatus struct {
 	// namespace specified by spec.namespace.name of the contour.
 	AvailableEnvoys int32 `json:"availableEnvoys"`
 
+	// Version is the Contour version of the Contour deployment, observed
+	// from the image tag of its pods once all replicas run the same image.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// CertificatesRotation is the value of the rotate-certificates annotation
+	// of the last rotation of the xDS certificates started by the operator.
+	// The Contour and Envoy pods are restarted once the certificates of the
+	// rotation are regenerated.
+	//
+	// +optional
+	CertificatesRotation string `json:"certificatesRotation,omitempty"`
+
+	// Resync is the value of the resync annotation of the last resync of the
+	// managed resources completed by the operator.
+	//
+	// +optional
+	Resync string `json:"resync,omitempty"`
+
 	// Conditions represent the observations of a contour's current state.
 	// Known condition types are "Available". Reference the condition type
 	// for additional details.
@@ -95,3 +95,68 @@ func (c *Contour) EnvoyTolerationsExist() bool {
 
 	return false
 }
+
+// RetainsNamespace returns true if the spec namespace of Contour is retained
+// when Contour is deleted.
+func (c *Contour) RetainsNamespace() bool {
+	if c.Spec.RetentionPolicy != nil && c.Spec.RetentionPolicy.Namespace != "" {
+		return c.Spec.RetentionPolicy.Namespace == RetainAction
+	}
+	return !c.Spec.Namespace.RemoveOnDeletion
+}
+
+// RetainsCertificates returns true if the TLS secrets of Contour are retained
+// when Contour is deleted.
+func (c *Contour) RetainsCertificates() bool {
+	return c.Spec.RetentionPolicy == nil || c.Spec.RetentionPolicy.Certificates != DeleteAction
+}
+
+// RetainsRBAC returns true if the RBAC resources of Contour are retained when
+// Contour is deleted.
+func (c *Contour) RetainsRBAC() bool {
+	return c.Spec.RetentionPolicy != nil && c.Spec.RetentionPolicy.RBAC == RetainAction
+}
+
+// RetainsServices returns true if the Services and Route of Contour are retained
+// when Contour is deleted.
+func (c *Contour) RetainsServices() bool {
+	return c.Spec.RetentionPolicy != nil && c.Spec.RetentionPolicy.Services == RetainAction
+}
+
+// EnvoyCanaryRollout returns true if the Envoy pods of Contour are rolled out
+// using the canary strategy.
+func (c *Contour) EnvoyCanaryRollout() bool {
+	return c.Spec.EnvoyRollout != nil && c.Spec.EnvoyRollout.Strategy == CanaryEnvoyRolloutStrategy
+}
+
+// EnvoyBlueGreenRollout returns true if the Envoy pods of Contour are rolled
+// out using the blue-green strategy.
+func (c *Contour) EnvoyBlueGreenRollout() bool {
+	return c.Spec.EnvoyRollout != nil && c.Spec.EnvoyRollout.Strategy == BlueGreenEnvoyRolloutStrategy
+}
+
+// MaintenanceWindowsExist returns true if changes to the Envoy pod template of
+// Contour are deferred to maintenance windows.
+func (c *Contour) MaintenanceWindowsExist() bool {
+	return c.Spec.UpdatePolicy != nil && len(c.Spec.UpdatePolicy.MaintenanceWindows) > 0
+}
+
+// CertificatesRotationRequested returns true if the RotateCertificatesAnnotation
+// of Contour requests a rotation of its xDS certificates that was not started.
+func (c *Contour) CertificatesRotationRequested() bool {
+	requested := c.Annotations[RotateCertificatesAnnotation]
+	return requested != "" && requested != c.Status.CertificatesRotation
+}
+
+// ResyncRequested returns true if the ResyncAnnotation of Contour requests a
+// resync of its managed resources that was not completed.
+func (c *Contour) ResyncRequested() bool {
+	requested := c.Annotations[ResyncAnnotation]
+	return requested != "" && requested != c.Status.Resync
+}
+
+// Suspended returns true if Contour is in maintenance mode, i.e. its Contour
+// Deployment is scaled to zero and its Envoy pods are removed.
+func (c *Contour) Suspended() bool {
+	return c.Annotations[SuspendAnnotation] == "true"
+}
@@ -31,6 +31,16 @@ func (in *AWSLoadBalancerParameters) DeepCopyInto(out *AWSLoadBalancerParameters
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSTermination != nil {
+		in, out := &in.TLSTermination, &out.TLSTermination
+		*out = new(AWSTLSTermination)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerParameters.
@@ -43,6 +53,123 @@ func (in *AWSLoadBalancerParameters) DeepCopy() *AWSLoadBalancerParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSTLSTermination) DeepCopyInto(out *AWSTLSTermination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSTLSTermination.
+func (in *AWSTLSTermination) DeepCopy() *AWSTLSTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSTLSTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessLogParameters) DeepCopyInto(out *AccessLogParameters) {
+	*out = *in
+	if in.JSONFields != nil {
+		in, out := &in.JSONFields, &out.JSONFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessLogParameters.
+func (in *AccessLogParameters) DeepCopy() *AccessLogParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessLogParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalEnvoyService) DeepCopyInto(out *AdditionalEnvoyService) {
+	*out = *in
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]EnvoyServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalEnvoyService.
+func (in *AdditionalEnvoyService) DeepCopy() *AdditionalEnvoyService {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalEnvoyService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertParameters) DeepCopyInto(out *AlertParameters) {
+	*out = *in
+	if in.RuleLabels != nil {
+		in, out := &in.RuleLabels, &out.RuleLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertParameters.
+func (in *AlertParameters) DeepCopy() *AlertParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppProtocols) DeepCopyInto(out *AppProtocols) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTPS != nil {
+		in, out := &in.HTTPS, &out.HTTPS
+		*out = new(string)
+		**out = **in
+	}
+	if in.XDS != nil {
+		in, out := &in.XDS, &out.XDS
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppProtocols.
+func (in *AppProtocols) DeepCopy() *AppProtocols {
+	if in == nil {
+		return nil
+	}
+	out := new(AppProtocols)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureLoadBalancerParameters) DeepCopyInto(out *AzureLoadBalancerParameters) {
 	*out = *in
@@ -61,6 +188,11 @@ func (in *AzureLoadBalancerParameters) DeepCopyInto(out *AzureLoadBalancerParame
 		*out = new(string)
 		**out = **in
 	}
+	if in.PublicIPName != nil {
+		in, out := &in.PublicIPName, &out.PublicIPName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureLoadBalancerParameters.
@@ -73,6 +205,86 @@ func (in *AzureLoadBalancerParameters) DeepCopy() *AzureLoadBalancerParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertgenParameters) DeepCopyInto(out *CertgenParameters) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodePlacement != nil {
+		in, out := &in.NodePlacement, &out.NodePlacement
+		*out = new(ContourNodePlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertgenParameters.
+func (in *CertgenParameters) DeepCopy() *CertgenParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(CertgenParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerReference) DeepCopyInto(out *CertificateIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerReference.
+func (in *CertificateIssuerReference) DeepCopy() *CertificateIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerParameters) DeepCopyInto(out *CircuitBreakerParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerParameters.
+func (in *CircuitBreakerParameters) DeepCopy() *CircuitBreakerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAutoscalerParameters) DeepCopyInto(out *ClusterAutoscalerParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAutoscalerParameters.
+func (in *ClusterAutoscalerParameters) DeepCopy() *ClusterAutoscalerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscalerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
 	*out = *in
@@ -179,7 +391,7 @@ func (in *ContourNodePlacement) DeepCopy() *ContourNodePlacement {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContourSpec) DeepCopyInto(out *ContourSpec) {
 	*out = *in
-	out.Namespace = in.Namespace
+	in.Namespace.DeepCopyInto(&out.Namespace)
 	in.NetworkPublishing.DeepCopyInto(&out.NetworkPublishing)
 	if in.GatewayClassRef != nil {
 		in, out := &in.GatewayClassRef, &out.GatewayClassRef
@@ -196,6 +408,115 @@ func (in *ContourSpec) DeepCopyInto(out *ContourSpec) {
 		*out = new(NodePlacement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RootNamespaces != nil {
+		in, out := &in.RootNamespaces, &out.RootNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceLabels != nil {
+		in, out := &in.ResourceLabels, &out.ResourceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceAnnotations != nil {
+		in, out := &in.ResourceAnnotations, &out.ResourceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(RetentionPolicy)
+		**out = **in
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PolicyParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecurityParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(TimeoutParameters)
+		**out = **in
+	}
+	if in.LeaderElection != nil {
+		in, out := &in.LeaderElection, &out.LeaderElection
+		*out = new(LeaderElectionParameters)
+		**out = **in
+	}
+	if in.KubernetesClient != nil {
+		in, out := &in.KubernetesClient, &out.KubernetesClient
+		*out = new(KubernetesClientParameters)
+		**out = **in
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingParameters)
+		**out = **in
+	}
+	if in.Certgen != nil {
+		in, out := &in.Certgen, &out.Certgen
+		*out = new(CertgenParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = new(CircuitBreakerParameters)
+		**out = **in
+	}
+	if in.Networking != nil {
+		in, out := &in.Networking, &out.Networking
+		*out = new(NetworkingParameters)
+		**out = **in
+	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(AccessLogParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvoyRollout != nil {
+		in, out := &in.EnvoyRollout, &out.EnvoyRollout
+		*out = new(EnvoyRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(UpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContextConstraints != nil {
+		in, out := &in.SecurityContextConstraints, &out.SecurityContextConstraints
+		*out = new(SecurityContextConstraints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultCertificate != nil {
+		in, out := &in.DefaultCertificate, &out.DefaultCertificate
+		*out = new(DefaultCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(ClusterAutoscalerParameters)
+		**out = **in
+	}
+	if in.VerticalPodAutoscaler != nil {
+		in, out := &in.VerticalPodAutoscaler, &out.VerticalPodAutoscaler
+		*out = new(VerticalPodAutoscalerParameters)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContourSpec.
@@ -231,35 +552,28 @@ func (in *ContourStatus) DeepCopy() *ContourStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvoyNetworkPublishing) DeepCopyInto(out *EnvoyNetworkPublishing) {
+func (in *DefaultCertificate) DeepCopyInto(out *DefaultCertificate) {
 	*out = *in
-	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
-	if in.NodePorts != nil {
-		in, out := &in.NodePorts, &out.NodePorts
-		*out = make([]NodePort, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ContainerPorts != nil {
-		in, out := &in.ContainerPorts, &out.ContainerPorts
-		*out = make([]ContainerPort, len(*in))
+	out.IssuerRef = in.IssuerRef
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyNetworkPublishing.
-func (in *EnvoyNetworkPublishing) DeepCopy() *EnvoyNetworkPublishing {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCertificate.
+func (in *DefaultCertificate) DeepCopy() *DefaultCertificate {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvoyNetworkPublishing)
+	out := new(DefaultCertificate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvoyNodePlacement) DeepCopyInto(out *EnvoyNodePlacement) {
+func (in *EnvoyCanaryRollout) DeepCopyInto(out *EnvoyCanaryRollout) {
 	*out = *in
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
@@ -268,85 +582,474 @@ func (in *EnvoyNodePlacement) DeepCopyInto(out *EnvoyNodePlacement) {
 			(*out)[key] = val
 		}
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyNodePlacement.
-func (in *EnvoyNodePlacement) DeepCopy() *EnvoyNodePlacement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyCanaryRollout.
+func (in *EnvoyCanaryRollout) DeepCopy() *EnvoyCanaryRollout {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvoyNodePlacement)
+	out := new(EnvoyCanaryRollout)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GCPLoadBalancerParameters) DeepCopyInto(out *GCPLoadBalancerParameters) {
+func (in *EnvoyExtraPort) DeepCopyInto(out *EnvoyExtraPort) {
 	*out = *in
-	if in.Address != nil {
-		in, out := &in.Address, &out.Address
-		*out = new(string)
-		**out = **in
-	}
-	if in.Subnet != nil {
-		in, out := &in.Subnet, &out.Subnet
-		*out = new(string)
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPLoadBalancerParameters.
-func (in *GCPLoadBalancerParameters) DeepCopy() *GCPLoadBalancerParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyExtraPort.
+func (in *EnvoyExtraPort) DeepCopy() *EnvoyExtraPort {
 	if in == nil {
 		return nil
 	}
-	out := new(GCPLoadBalancerParameters)
+	out := new(EnvoyExtraPort)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancerStrategy) DeepCopyInto(out *LoadBalancerStrategy) {
+func (in *EnvoyHostPortMapping) DeepCopyInto(out *EnvoyHostPortMapping) {
 	*out = *in
-	in.ProviderParameters.DeepCopyInto(&out.ProviderParameters)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerStrategy.
-func (in *LoadBalancerStrategy) DeepCopy() *LoadBalancerStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyHostPortMapping.
+func (in *EnvoyHostPortMapping) DeepCopy() *EnvoyHostPortMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancerStrategy)
+	out := new(EnvoyHostPortMapping)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceSpec) DeepCopyInto(out *NamespaceSpec) {
+func (in *EnvoyNetworkPublishing) DeepCopyInto(out *EnvoyNetworkPublishing) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSpec.
-func (in *NamespaceSpec) DeepCopy() *NamespaceSpec {
-	if in == nil {
-		return nil
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(RouteParameters)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(NamespaceSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
+	if in.HTTPService != nil {
+		in, out := &in.HTTPService, &out.HTTPService
+		*out = new(HTTPServiceParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalServices != nil {
+		in, out := &in.AdditionalServices, &out.AdditionalServices
+		*out = make([]AdditionalEnvoyService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalIPs != nil {
+		in, out := &in.ExternalIPs, &out.ExternalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePorts != nil {
+		in, out := &in.NodePorts, &out.NodePorts
+		*out = make([]NodePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerPorts != nil {
+		in, out := &in.ContainerPorts, &out.ContainerPorts
+		*out = make([]ContainerPort, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraPorts != nil {
+		in, out := &in.ExtraPorts, &out.ExtraPorts
+		*out = make([]EnvoyExtraPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServicePorts != nil {
+		in, out := &in.ServicePorts, &out.ServicePorts
+		*out = make([]EnvoyServicePortMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostPorts != nil {
+		in, out := &in.HostPorts, &out.HostPorts
+		*out = make([]EnvoyHostPortMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.SessionAffinityConfig != nil {
+		in, out := &in.SessionAffinityConfig, &out.SessionAffinityConfig
+		*out = new(v1.SessionAffinityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalTrafficPolicy != nil {
+		in, out := &in.InternalTrafficPolicy, &out.InternalTrafficPolicy
+		*out = new(v1.ServiceInternalTrafficPolicyType)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyNetworkPublishing.
+func (in *EnvoyNetworkPublishing) DeepCopy() *EnvoyNetworkPublishing {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyNetworkPublishing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyNodePlacement) DeepCopyInto(out *EnvoyNodePlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OperatingSystems != nil {
+		in, out := &in.OperatingSystems, &out.OperatingSystems
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyNodePlacement.
+func (in *EnvoyNodePlacement) DeepCopy() *EnvoyNodePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyNodePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyRollout) DeepCopyInto(out *EnvoyRollout) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(EnvoyCanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyRollout.
+func (in *EnvoyRollout) DeepCopy() *EnvoyRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyServicePort) DeepCopyInto(out *EnvoyServicePort) {
+	*out = *in
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyServicePort.
+func (in *EnvoyServicePort) DeepCopy() *EnvoyServicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyServicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoyServicePortMapping) DeepCopyInto(out *EnvoyServicePortMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoyServicePortMapping.
+func (in *EnvoyServicePortMapping) DeepCopy() *EnvoyServicePortMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoyServicePortMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCPLoadBalancerParameters) DeepCopyInto(out *GCPLoadBalancerParameters) {
+	*out = *in
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(string)
+		**out = **in
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPLoadBalancerParameters.
+func (in *GCPLoadBalancerParameters) DeepCopy() *GCPLoadBalancerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPLoadBalancerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericLoadBalancerParameters) DeepCopyInto(out *GenericLoadBalancerParameters) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericLoadBalancerParameters.
+func (in *GenericLoadBalancerParameters) DeepCopy() *GenericLoadBalancerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericLoadBalancerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HSTSPolicy) DeepCopyInto(out *HSTSPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HSTSPolicy.
+func (in *HSTSPolicy) DeepCopy() *HSTSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HSTSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPServiceParameters) DeepCopyInto(out *HTTPServiceParameters) {
+	*out = *in
+	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPServiceParameters.
+func (in *HTTPServiceParameters) DeepCopy() *HTTPServiceParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPServiceParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadersPolicy) DeepCopyInto(out *HeadersPolicy) {
+	*out = *in
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadersPolicy.
+func (in *HeadersPolicy) DeepCopy() *HeadersPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadersPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesClientParameters) DeepCopyInto(out *KubernetesClientParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesClientParameters.
+func (in *KubernetesClientParameters) DeepCopy() *KubernetesClientParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesClientParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionParameters) DeepCopyInto(out *LeaderElectionParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionParameters.
+func (in *LeaderElectionParameters) DeepCopy() *LeaderElectionParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerStrategy) DeepCopyInto(out *LoadBalancerStrategy) {
+	*out = *in
+	in.ProviderParameters.DeepCopyInto(&out.ProviderParameters)
+	if in.HealthCheckNodePort != nil {
+		in, out := &in.HealthCheckNodePort, &out.HealthCheckNodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllocateLoadBalancerNodePorts != nil {
+		in, out := &in.AllocateLoadBalancerNodePorts, &out.AllocateLoadBalancerNodePorts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerStrategy.
+func (in *LoadBalancerStrategy) DeepCopy() *LoadBalancerStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingParameters) DeepCopyInto(out *LoggingParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingParameters.
+func (in *LoggingParameters) DeepCopy() *LoggingParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetalLBLoadBalancerParameters) DeepCopyInto(out *MetalLBLoadBalancerParameters) {
+	*out = *in
+	if in.AddressPool != nil {
+		in, out := &in.AddressPool, &out.AddressPool
+		*out = new(string)
+		**out = **in
+	}
+	if in.SharingKey != nil {
+		in, out := &in.SharingKey, &out.SharingKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalLBLoadBalancerParameters.
+func (in *MetalLBLoadBalancerParameters) DeepCopy() *MetalLBLoadBalancerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(MetalLBLoadBalancerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSpec) DeepCopyInto(out *NamespaceSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSpec.
+func (in *NamespaceSpec) DeepCopy() *NamespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkPublishing) DeepCopyInto(out *NetworkPublishing) {
 	*out = *in
 	in.Envoy.DeepCopyInto(&out.Envoy)
+	in.AppProtocols.DeepCopyInto(&out.AppProtocols)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPublishing.
@@ -359,6 +1062,21 @@ func (in *NetworkPublishing) DeepCopy() *NetworkPublishing {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkingParameters) DeepCopyInto(out *NetworkingParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkingParameters.
+func (in *NetworkingParameters) DeepCopy() *NetworkingParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkingParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePlacement) DeepCopyInto(out *NodePlacement) {
 	*out = *in
@@ -372,6 +1090,11 @@ func (in *NodePlacement) DeepCopyInto(out *NodePlacement) {
 		*out = new(EnvoyNodePlacement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]NodeArchitecture, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePlacement.
@@ -404,6 +1127,31 @@ func (in *NodePort) DeepCopy() *NodePort {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyParameters) DeepCopyInto(out *PolicyParameters) {
+	*out = *in
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = new(HeadersPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = new(HeadersPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyParameters.
+func (in *PolicyParameters) DeepCopy() *PolicyParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderLoadBalancerParameters) DeepCopyInto(out *ProviderLoadBalancerParameters) {
 	*out = *in
@@ -422,6 +1170,16 @@ func (in *ProviderLoadBalancerParameters) DeepCopyInto(out *ProviderLoadBalancer
 		*out = new(GCPLoadBalancerParameters)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MetalLB != nil {
+		in, out := &in.MetalLB, &out.MetalLB
+		*out = new(MetalLBLoadBalancerParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Generic != nil {
+		in, out := &in.Generic, &out.Generic
+		*out = new(GenericLoadBalancerParameters)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderLoadBalancerParameters.
@@ -433,3 +1191,155 @@ func (in *ProviderLoadBalancerParameters) DeepCopy() *ProviderLoadBalancerParame
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteParameters) DeepCopyInto(out *RouteParameters) {
+	*out = *in
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
+	if in.DestinationCACertificate != nil {
+		in, out := &in.DestinationCACertificate, &out.DestinationCACertificate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteParameters.
+func (in *RouteParameters) DeepCopy() *RouteParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityContextConstraints) DeepCopyInto(out *SecurityContextConstraints) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityContextConstraints.
+func (in *SecurityContextConstraints) DeepCopy() *SecurityContextConstraints {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContextConstraints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityHeaders) DeepCopyInto(out *SecurityHeaders) {
+	*out = *in
+	if in.HSTS != nil {
+		in, out := &in.HSTS, &out.HSTS
+		*out = new(HSTSPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityHeaders.
+func (in *SecurityHeaders) DeepCopy() *SecurityHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityParameters) DeepCopyInto(out *SecurityParameters) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(SecurityHeaders)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityParameters.
+func (in *SecurityParameters) DeepCopy() *SecurityParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutParameters) DeepCopyInto(out *TimeoutParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutParameters.
+func (in *TimeoutParameters) DeepCopy() *TimeoutParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePolicy) DeepCopyInto(out *UpdatePolicy) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePolicy.
+func (in *UpdatePolicy) DeepCopy() *UpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalPodAutoscalerParameters) DeepCopyInto(out *VerticalPodAutoscalerParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalPodAutoscalerParameters.
+func (in *VerticalPodAutoscalerParameters) DeepCopy() *VerticalPodAutoscalerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalPodAutoscalerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -14,51 +14,217 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/bundle"
+	"github.com/projectcontour/contour-operator/internal/conformance"
+	"github.com/projectcontour/contour-operator/internal/doctor"
+	"github.com/projectcontour/contour-operator/internal/migrate"
 	"github.com/projectcontour/contour-operator/internal/operator"
 	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
 	"github.com/projectcontour/contour-operator/internal/parse"
+	"github.com/projectcontour/contour-operator/internal/render"
+	"github.com/projectcontour/contour-operator/internal/report"
+	"github.com/projectcontour/contour-operator/internal/scaffold"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 var (
-	opCfg operatorconfig.Config
+	opCfg                 operatorconfig.Config
+	watchNamespaces       string
+	disableLeaderElection bool
+	kubeAPIQPS            float64
+	verifyRegistries      bool
+	imageKeyFiles         string
 )
 
+// registryTimeout is how long image registry verification may take.
+const registryTimeout = 10 * time.Second
+
 func main() {
-	flag.StringVar(&opCfg.ContourImage, "contour-image", operatorconfig.DefaultContourImage,
-		"The container image used for the managed Contour.")
-	flag.StringVar(&opCfg.EnvoyImage, "envoy-image", operatorconfig.DefaultEnvoyImage,
-		"The container image used for the managed Envoy.")
+	// The binary doubles as the "kubectl contour-operator" plugin when
+	// installed as kubectl-contour_operator, which runs its subcommands.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "status":
+			os.Exit(runStatus(os.Args[2:]))
+		case "render":
+			os.Exit(runRender(os.Args[2:]))
+		case "init":
+			os.Exit(runInit(os.Args[2:]))
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:]))
+		case "rotate-certificates":
+			os.Exit(runRotateCertificates(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "resync":
+			os.Exit(runResync(os.Args[2:]))
+		case "conformance":
+			os.Exit(runConformance(os.Args[2:]))
+		case "suspend":
+			os.Exit(runSuspend(os.Args[2:], true))
+		case "resume":
+			os.Exit(runSuspend(os.Args[2:], false))
+		}
+	}
+
+	flag.StringVar(&opCfg.ContourImage, "contour-image",
+		envOrDefault(operatorconfig.ContourImageEnvVar, operatorconfig.DefaultContourImage),
+		"The container image used for the managed Contour. Defaults to the "+
+			operatorconfig.ContourImageEnvVar+" environment variable, if set.")
+	flag.StringVar(&opCfg.EnvoyImage, "envoy-image",
+		envOrDefault(operatorconfig.EnvoyImageEnvVar, operatorconfig.DefaultEnvoyImage),
+		"The container image used for the managed Envoy. Defaults to the "+
+			operatorconfig.EnvoyImageEnvVar+" environment variable, if set.")
+	flag.BoolVar(&verifyRegistries, "verify-image-registries", false,
+		"Verify at startup that the registries of the Contour and Envoy images are reachable.")
+	flag.StringVar(&opCfg.RegistryMirror, "registry-mirror", "",
+		"The registry, and optional path prefix, that replaces the registry of the Contour and Envoy images, "+
+			"i.e. \"registry.example.com/mirror\" for air-gapped clusters.")
 	flag.StringVar(&opCfg.MetricsBindAddress, "metrics-addr", operatorconfig.DefaultMetricsAddr, "The "+
 		"address the metric endpoint binds to. It can be set to \"0\" to disable serving metrics.")
+	flag.StringVar(&opCfg.HealthProbeBindAddress, "health-probe-addr", operatorconfig.DefaultHealthProbeAddr,
+		"The address the /healthz and /readyz endpoints bind to. It can be set to \"0\" to disable health probes.")
+	flag.StringVar(&opCfg.MetricsCertFile, "metrics-tls-cert-file", "",
+		"The path of the PEM-encoded certificate used to serve metrics over TLS. Requires --metrics-tls-key-file.")
+	flag.StringVar(&opCfg.MetricsKeyFile, "metrics-tls-key-file", "",
+		"The path of the PEM-encoded key used to serve metrics over TLS. Requires --metrics-tls-cert-file.")
+	flag.StringVar(&opCfg.MetricsClientCAFile, "metrics-client-ca-file", "",
+		"The path of the PEM-encoded CA bundle used to authenticate metrics clients by certificate. Requires TLS.")
+	flag.BoolVar(&opCfg.MetricsTokenAuth, "metrics-token-auth", false,
+		"Require metrics clients without a client certificate to present a bearer token that is authorized "+
+			"to get the /metrics non-resource URL. Requires TLS.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", operatorconfig.DefaultKubeAPIQPS,
+		"The maximum number of queries per second sent to the Kubernetes API server.")
+	flag.IntVar(&opCfg.KubeAPIBurst, "kube-api-burst", operatorconfig.DefaultKubeAPIBurst,
+		"The maximum burst of queries sent to the Kubernetes API server above --kube-api-qps.")
 	flag.BoolVar(&opCfg.LeaderElection, "enable-leader-election", operatorconfig.DefaultEnableLeaderElection,
 		"Enable leader election for the operator. Enabling this will ensure there is only one active operator.")
+	flag.StringVar(&opCfg.ConfigFile, "config", "", "The path of the operator configuration file, i.e. a "+
+		"mounted ConfigMap. Values in the file take precedence over flags and the file is reloaded when it changes.")
+	flag.BoolVar(&disableLeaderElection, "disable-leader-election", false,
+		"Disable leader election for the operator, overriding --enable-leader-election. Useful for "+
+			"single-node development clusters.")
+	flag.StringVar(&opCfg.LeaderElectionID, "leader-election-id", operatorconfig.DefaultEnableLeaderElectionID,
+		"The name of the resource used by leader election to hold the leader lock.")
+	flag.StringVar(&opCfg.LeaderElectionNamespace, "leader-election-namespace", "",
+		"The namespace of the leader election resource. Defaults to --operator-namespace.")
+	flag.StringVar(&opCfg.OperatorNamespace, "operator-namespace", operatorconfig.DetectNamespace(),
+		"The namespace the operator runs in. Defaults to the "+operatorconfig.OperatorNamespaceEnvVar+
+			" environment variable, i.e. set from the downward API, or the namespace of the service account "+
+			"of the operator pod.")
+	flag.DurationVar(&opCfg.LeaseDuration, "leader-election-lease-duration", operatorconfig.DefaultLeaderElectionLeaseDuration,
+		"The duration that non-leader operators wait to force acquire leadership.")
+	flag.DurationVar(&opCfg.RenewDeadline, "leader-election-renew-deadline", operatorconfig.DefaultLeaderElectionRenewDeadline,
+		"The duration that the leading operator retries refreshing leadership before giving up.")
+	flag.DurationVar(&opCfg.RetryPeriod, "leader-election-retry-period", operatorconfig.DefaultLeaderElectionRetryPeriod,
+		"The duration operators wait between leader election actions.")
+	flag.BoolVar(&opCfg.LeaderElectionReleaseOnCancel, "leader-election-release-on-cancel",
+		operatorconfig.DefaultLeaderElectionReleaseOnCancel, "Release the leader lock when the leading operator "+
+			"is stopped, so another replica takes over without waiting for the lease to expire.")
+	flag.IntVar(&opCfg.WebhookPort, "webhook-port", 0, "The port the validating webhook of Contours is "+
+		"served at by every operator replica. If 0, the webhook is not served.")
+	flag.StringVar(&opCfg.WebhookCertDir, "webhook-cert-dir", "", "The directory of the tls.crt and tls.key "+
+		"files of the webhook server. Defaults to /tmp/k8s-webhook-server/serving-certs.")
+	flag.DurationVar(&opCfg.DrainTimeout, "drain-timeout", operatorconfig.DefaultDrainTimeout,
+		"The duration in-flight reconciles are given to finish when the operator is stopped.")
+	flag.BoolVar(&opCfg.DisableContourController, "disable-contour-controller", false,
+		"Disable the Contour controller, i.e. to only manage Contours for Gateway API resources.")
+	flag.BoolVar(&opCfg.DisableGatewayControllers, "disable-gateway-controllers", false,
+		"Disable the GatewayClass and Gateway controllers, i.e. to only manage Contour resources.")
+	flag.BoolVar(&opCfg.SkipContourCRDs, "skip-contour-crds", false,
+		"Skip installing and upgrading the Contour CRDs, i.e. HTTPProxy, on clusters where they are managed elsewhere.")
+	flag.BoolVar(&opCfg.ResolveImageDigests, "resolve-image-digests", false,
+		"Pin the Contour and Envoy images to the digest of their tag before rolling them out.")
+	flag.StringVar(&imageKeyFiles, "image-verification-keys", "",
+		"A comma-separated list of paths of PEM-encoded cosign public keys. If set, the Contour and Envoy "+
+			"images must have a cosign signature that verifies with one of the keys before they are rolled out, "+
+			"and are pinned to the verified digest.")
+	flag.StringVar(&opCfg.RegistryCredentialsFile, "registry-credentials-file", "",
+		"The path of a docker config file, i.e. mounted from a kubernetes.io/dockerconfigjson Secret, with the "+
+			"credentials of the registries used by --resolve-image-digests and --image-verification-keys, "+
+			"including the registry set by --registry-mirror. If unset, registries are accessed anonymously.")
+	flag.StringVar(&opCfg.TracingEndpoint, "tracing-endpoint", "",
+		"The host and port of the OTLP/HTTP collector that reconcile spans are exported to, i.e. "+
+			"\"otel-collector.observability:4318\". If empty, reconciles are not traced.")
+	flag.BoolVar(&opCfg.TracingInsecure, "tracing-insecure", false,
+		"Export spans to --tracing-endpoint without TLS.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", os.Getenv(operatorconfig.WatchNamespacesEnvVar),
+		"A comma-separated list of namespaces the operator watches for Contours and manages resources in. "+
+			"Defaults to the "+operatorconfig.WatchNamespacesEnvVar+" environment variable, i.e. set "+
+			"from the downward API. If empty, all namespaces are watched.")
 	flag.Parse()
 
-	opCfg.LeaderElectionID = operatorconfig.DefaultEnableLeaderElectionID
+	if disableLeaderElection {
+		opCfg.LeaderElection = false
+	}
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 	setupLog := ctrl.Log.WithName("setup")
 
-	images := []string{opCfg.ContourImage, opCfg.EnvoyImage}
-	for _, image := range images {
-		// Parse will not handle short digests.
-		if err := parse.Image(image); err != nil {
-			setupLog.Error(err, "invalid image reference", "value", image)
+	opCfg.KubeAPIQPS = float32(kubeAPIQPS)
+	for _, path := range strings.Split(imageKeyFiles, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			opCfg.ImageVerificationKeyFiles = append(opCfg.ImageVerificationKeyFiles, path)
+		}
+	}
+	if err := opCfg.Validate(); err != nil {
+		setupLog.Error(err, "invalid operator configuration")
+		os.Exit(1)
+	}
+	if verifyRegistries {
+		ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+		err := opCfg.VerifyRegistries(ctx, &http.Client{Timeout: registryTimeout})
+		cancel()
+		if err != nil {
+			setupLog.Error(err, "failed to verify image registries; unset --verify-image-registries to skip "+
+				"registry verification")
 			os.Exit(1)
 		}
 	}
 
+	namespaces, err := parse.Namespaces(watchNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid watch namespaces", "value", watchNamespaces)
+		os.Exit(1)
+	}
+	opCfg.WatchNamespaces = namespaces
+	if len(opCfg.WatchNamespaces) > 0 {
+		setupLog.Info("watching namespaces", "namespaces", opCfg.WatchNamespaces)
+	}
+
 	setupLog.Info("using contour", "image", opCfg.ContourImage)
 	setupLog.Info("using envoy", "image", opCfg.EnvoyImage)
+	if opCfg.ConfigFile != "" {
+		setupLog.Info("using config file", "path", opCfg.ConfigFile)
+	}
+	if opCfg.TracingEndpoint != "" {
+		setupLog.Info("exporting reconcile spans", "endpoint", opCfg.TracingEndpoint)
+	}
+
+	cliCfg := ctrl.GetConfigOrDie()
+	cliCfg.QPS = opCfg.KubeAPIQPS
+	cliCfg.Burst = opCfg.KubeAPIBurst
+	setupLog.Info("using kube api rate limits", "qps", cliCfg.QPS, "burst", cliCfg.Burst)
 
-	op, err := operator.New(ctrl.GetConfigOrDie(), &opCfg)
+	op, err := operator.New(cliCfg, &opCfg)
 	if err != nil {
 		setupLog.Error(err, "failed to create contour operator")
 		os.Exit(1)
@@ -70,3 +236,682 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runMigrate runs the migrate subcommand with args, printing the Contour
+// equivalent to an installation of the example manifest and the plan for
+// adopting its resources. It returns the exit code of the subcommand.
+func runMigrate(args []string) int {
+	var opts migrate.Options
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "projectcontour",
+		"The namespace of the Contour installed from the example manifest.")
+	fs.StringVar(&opts.ContourName, "contour-name", "contour", "The name of the generated Contour.")
+	fs.StringVar(&opts.ContourNamespace, "contour-namespace", "default", "The namespace of the generated Contour.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s migrate [flags]\n\n"+
+			"Prints the Contour equivalent to an installation of the example manifest, with the plan\n"+
+			"for adopting its resources, i.e. \"%s migrate | kubectl apply -f -\". The cluster is\n"+
+			"selected using the KUBECONFIG environment variable or the in-cluster configuration.\n\n", os.Args[0], os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get kubeconfig: %v\n", err)
+		return 1
+	}
+	cli, err := client.New(cfg, client.Options{Scheme: operator.GetOperatorScheme()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return 1
+	}
+	plan, err := migrate.Inspect(context.Background(), cli, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to inspect contour installation: %v\n", err)
+		return 1
+	}
+	if err := plan.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write migration plan: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runStatus runs the status subcommand with args, printing the conditions,
+// workload readiness, published address and recent events of Contours.
+func runStatus(args []string) int {
+	var opts report.Options
+	var allNamespaces bool
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contours. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.BoolVar(&allNamespaces, "all-namespaces", false, "Report the Contours of all namespaces.")
+	fs.BoolVar(&allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+	fs.IntVar(&opts.Events, "events", report.DefaultEvents, "The maximum number of recent events reported per Contour.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kubectl contour-operator status [flags] [name]\n\n"+
+			"Prints the conditions, workload readiness, published address and recent events of\n"+
+			"Contours. Install the operator binary as kubectl-contour_operator on the PATH to use\n"+
+			"it as a kubectl plugin, or run \"%s status\".\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+
+	cli, code := selectContours(&opts, allNamespaces)
+	if cli == nil {
+		return code
+	}
+	reports, err := report.Contours(context.Background(), cli, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get contour status: %v\n", err)
+		return 1
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(os.Stderr, "No contours found.")
+		return 0
+	}
+	if err := report.Write(os.Stdout, reports, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write contour status: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// selectContours completes opts with the namespace of the current kubeconfig
+// context unless allNamespaces is set, returning a client of the kubeconfig
+// cluster, or nil and the exit code if it fails.
+func selectContours(opts *report.Options, allNamespaces bool) (client.Client, int) {
+	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{})
+	switch {
+	case allNamespaces && opts.Name != "":
+		fmt.Fprintln(os.Stderr, "a contour name can not be used with --all-namespaces")
+		return nil, 2
+	case allNamespaces:
+		opts.Namespace = ""
+	case opts.Namespace == "":
+		ns, _, err := kubeconfig.Namespace()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get namespace of kubeconfig context: %v\n", err)
+			return nil, 1
+		}
+		opts.Namespace = ns
+	}
+
+	cfg, err := kubeconfig.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get kubeconfig: %v\n", err)
+		return nil, 1
+	}
+	cli, err := client.New(cfg, client.Options{Scheme: operator.GetOperatorScheme()})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return nil, 1
+	}
+	return cli, 0
+}
+
+// runDoctor runs the doctor subcommand with args, printing the common
+// misconfigurations of Contours with remediation hints.
+func runDoctor(args []string) int {
+	var opts report.Options
+	var allNamespaces bool
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contours. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.BoolVar(&allNamespaces, "all-namespaces", false, "Check the Contours of all namespaces.")
+	fs.BoolVar(&allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s doctor [flags] [name]\n\n"+
+			"Checks Contours for missing Gateway API CRDs, missing or expired xDS certificates, pending\n"+
+			"load balancers, node port conflicts and Pod Security Admission violations, printing\n"+
+			"remediation hints. Exits with 1 if an error is found.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+
+	cli, code := selectContours(&opts, allNamespaces)
+	if cli == nil {
+		return code
+	}
+	findings, err := doctor.Diagnose(context.Background(), cli, opts, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to check contours: %v\n", err)
+		return 1
+	}
+	if err := doctor.Write(os.Stdout, findings); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write findings: %v\n", err)
+		return 1
+	}
+	if doctor.HasErrors(findings) {
+		return 1
+	}
+	return 0
+}
+
+// runConformance runs the conformance subcommand with args, provisioning a
+// Gateway through the operator and checking how it routes traffic.
+func runConformance(args []string) int {
+	opts := conformance.Defaults()
+	var keep bool
+	fs := flag.NewFlagSet("conformance", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", opts.Namespace, "The namespace of the provisioned Contour. "+
+		"The Gateway, its routes and backends are in the namespace suffixed by \"-gateway\".")
+	fs.StringVar(&opts.Namespace, "n", opts.Namespace, "Shorthand for --namespace.")
+	fs.StringVar(&opts.GatewayClass, "gatewayclass", opts.GatewayClass, "The name of the provisioned GatewayClass.")
+	fs.StringVar(&opts.Address, "address", "", "The host and optional port to send requests to, i.e. of a "+
+		"port-forward. Defaults to the load balancer address of the Envoy service.")
+	fs.DurationVar(&opts.Timeout, "timeout", opts.Timeout,
+		"How long each case, and waiting for the Envoy address, may take before failing.")
+	fs.StringVar(&opts.EchoImage, "echo-image", opts.EchoImage, "The image of the echo backends.")
+	fs.BoolVar(&keep, "keep", false, "Keep the provisioned objects after the run, i.e. for debugging.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s conformance [flags]\n\n"+
+			"Provisions a Gateway through the operator of the current kubeconfig cluster and checks that\n"+
+			"it routes HTTP traffic as the Gateway API requires. The v1alpha1 Gateway API ships no\n"+
+			"conformance suite, so the cases are modelled on the HTTPRoute tests of the upstream suite.\n"+
+			"Exits with 1 if a case fails.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cli, code := selectContours(&report.Options{Namespace: opts.Namespace}, false)
+	if cli == nil {
+		return code
+	}
+	ctx := context.Background()
+	cases := conformance.Cases(opts)
+	if err := conformance.Setup(ctx, cli, opts, cases); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if !keep {
+		defer func() {
+			if err := conformance.Cleanup(ctx, cli, opts); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+		}()
+	}
+	addr, err := conformance.Address(ctx, cli, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	results := conformance.Run(ctx, addr, opts, cases)
+	if err := conformance.Write(os.Stdout, results); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write results: %v\n", err)
+		return 1
+	}
+	if len(conformance.Failed(results)) != 0 {
+		return 1
+	}
+	return 0
+}
+
+// runRotateCertificates runs the rotate-certificates subcommand with args,
+// requesting the rotation of the xDS certificates of a Contour.
+func runRotateCertificates(args []string) int {
+	var opts report.Options
+	fs := flag.NewFlagSet("rotate-certificates", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contour. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s rotate-certificates [flags] name\n\n"+
+			"Requests the regeneration of the xDS certificates of a Contour, i.e. after a suspected key\n"+
+			"compromise, by setting its %s annotation. The operator\n"+
+			"restarts the Contour and Envoy pods once the certificates are regenerated.\n\n",
+			os.Args[0], operatorv1alpha1.RotateCertificatesAnnotation)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+
+	cli, code := selectContours(&opts, false)
+	if cli == nil {
+		return code
+	}
+	ctx := context.Background()
+	contour := &operatorv1alpha1.Contour{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, contour); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	rotation := time.Now().UTC().Format(time.RFC3339)
+	patch := client.MergeFrom(contour.DeepCopy())
+	if contour.Annotations == nil {
+		contour.Annotations = map[string]string{}
+	}
+	contour.Annotations[operatorv1alpha1.RotateCertificatesAnnotation] = rotation
+	if err := cli.Patch(ctx, contour, patch); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to annotate contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	fmt.Printf("Requested certificates rotation %s of contour %s/%s. The rotation has started once\n"+
+		"status.certificatesRotation of the contour is %s.\n", rotation, opts.Namespace, opts.Name, rotation)
+	return 0
+}
+
+// runResync runs the resync subcommand with args, requesting the repair of all
+// managed resources of a Contour.
+func runResync(args []string) int {
+	var opts report.Options
+	fs := flag.NewFlagSet("resync", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contour. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s resync [flags] name\n\n"+
+			"Requests the operator to re-evaluate and repair all managed resources of a Contour, i.e. after\n"+
+			"manual emergency changes, by setting its %s annotation. Envoy pod\n"+
+			"template changes deferred to a maintenance window are applied.\n\n",
+			os.Args[0], operatorv1alpha1.ResyncAnnotation)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+
+	cli, code := selectContours(&opts, false)
+	if cli == nil {
+		return code
+	}
+	ctx := context.Background()
+	contour := &operatorv1alpha1.Contour{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, contour); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	resync := time.Now().UTC().Format(time.RFC3339)
+	patch := client.MergeFrom(contour.DeepCopy())
+	if contour.Annotations == nil {
+		contour.Annotations = map[string]string{}
+	}
+	contour.Annotations[operatorv1alpha1.ResyncAnnotation] = resync
+	if err := cli.Patch(ctx, contour, patch); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to annotate contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	fmt.Printf("Requested resync %s of contour %s/%s. The resync is complete once status.resync\n"+
+		"of the contour is %s.\n", resync, opts.Namespace, opts.Name, resync)
+	return 0
+}
+
+// runSuspend runs the suspend subcommand with args if suspend is true, putting
+// a Contour in maintenance mode, or the resume subcommand otherwise, restoring
+// its declared state.
+func runSuspend(args []string, suspend bool) int {
+	var opts report.Options
+	cmd, done, usage := "resume", "resumed", "Restores the declared state of a Contour suspended by \"%s suspend\".\n\n"
+	if suspend {
+		cmd, done, usage = "suspend", "suspended", "Puts a Contour in maintenance mode: the operator scales the Contour Deployment to zero\n"+
+			"and removes the Envoy pods from all nodes until \"%s resume\" is run.\n\n"
+	}
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contour. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] name\n\n"+usage, os.Args[0], cmd, os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+
+	cli, code := selectContours(&opts, false)
+	if cli == nil {
+		return code
+	}
+	ctx := context.Background()
+	contour := &operatorv1alpha1.Contour{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, contour); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	if contour.Suspended() == suspend {
+		fmt.Printf("Contour %s/%s is already %s.\n", opts.Namespace, opts.Name, done)
+		return 0
+	}
+	patch := client.MergeFrom(contour.DeepCopy())
+	if suspend {
+		if contour.Annotations == nil {
+			contour.Annotations = map[string]string{}
+		}
+		contour.Annotations[operatorv1alpha1.SuspendAnnotation] = "true"
+	} else {
+		delete(contour.Annotations, operatorv1alpha1.SuspendAnnotation)
+	}
+	if err := cli.Patch(ctx, contour, patch); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to annotate contour %s/%s: %v\n", opts.Namespace, opts.Name, err)
+		return 1
+	}
+	fmt.Printf("Contour %s/%s %s.\n", opts.Namespace, opts.Name, done)
+	return 0
+}
+
+// runExport runs the export subcommand with args, writing the effective
+// configuration of a Contour to a bundle.
+func runExport(args []string) int {
+	var opts report.Options
+	var output string
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&opts.Namespace, "namespace", "",
+		"The namespace of the Contour. Defaults to the namespace of the current kubeconfig context.")
+	fs.StringVar(&opts.Namespace, "n", "", "Shorthand for --namespace.")
+	fs.StringVar(&output, "o", "", "The path of the bundle, or \"-\" to write it to stdout. Defaults to NAME.tar.gz.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s export [flags] name\n\n"+
+			"Exports a Contour, its rendered Contour configuration and the metadata of its generated\n"+
+			"certificates into a gzipped tar bundle, i.e. for disaster recovery with \"%s import\".\n"+
+			"Private keys are not exported.\n\n", os.Args[0], os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	opts.Name = fs.Arg(0)
+	if output == "" {
+		output = opts.Name + ".tar.gz"
+	}
+
+	cli, code := selectContours(&opts, false)
+	if cli == nil {
+		return code
+	}
+	b, err := bundle.Export(context.Background(), cli, opts.Namespace, opts.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	out := os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create bundle: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := b.Write(out, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write bundle: %v\n", err)
+		return 1
+	}
+	if output != "-" {
+		fmt.Printf("Exported contour %s/%s to %s.\n", opts.Namespace, opts.Name, output)
+	}
+	return 0
+}
+
+// runImport runs the import subcommand with args, creating the Contour of a
+// bundle written by the export subcommand.
+func runImport(args []string) int {
+	var file, namespace string
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&file, "f", "", "The path of the bundle, or \"-\" to read it from stdin.")
+	fs.StringVar(&namespace, "namespace", "", "The namespace of the Contour. Defaults to its namespace in the bundle.")
+	fs.StringVar(&namespace, "n", "", "Shorthand for --namespace.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s import -f FILE [flags]\n\n"+
+			"Creates the Contour of a bundle written by \"%s export\" in the cluster selected by the\n"+
+			"KUBECONFIG environment variable. The operator of the cluster generates new certificates.\n\n",
+			os.Args[0], os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if file == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open bundle: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	b, err := bundle.Read(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	opts := report.Options{Namespace: namespace, Name: b.Contour.Name}
+	if opts.Namespace == "" {
+		// Keep the namespace of the bundle rather than the kubeconfig context.
+		opts.Namespace = b.Contour.Namespace
+	}
+	cli, code := selectContours(&opts, false)
+	if cli == nil {
+		return code
+	}
+	contour, err := bundle.Import(context.Background(), cli, b, opts.Namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Printf("Imported contour %s/%s.\n", contour.Namespace, contour.Name)
+	return 0
+}
+
+// runRender runs the render subcommand with args, printing the resources the
+// operator manages for a Contour.
+func runRender(args []string) int {
+	var opts render.Options
+	var file, outputDir string
+	var serverDefaults bool
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	fs.StringVar(&file, "f", "", "The path of the Contour manifest, or \"-\" to read it from stdin.")
+	fs.StringVar(&outputDir, "output-dir", "",
+		"The directory to write each resource to as a separate file. If empty, resources are written to stdout.")
+	fs.StringVar(&opts.ContourImage, "contour-image",
+		envOrDefault(operatorconfig.ContourImageEnvVar, operatorconfig.DefaultContourImage),
+		"The Contour image of a Contour without spec.version.")
+	fs.StringVar(&opts.EnvoyImage, "envoy-image",
+		envOrDefault(operatorconfig.EnvoyImageEnvVar, operatorconfig.DefaultEnvoyImage),
+		"The Envoy image of a Contour without spec.version.")
+	fs.StringVar(&opts.RegistryMirror, "registry-mirror", "",
+		"The registry, and optional path prefix, that replaces the registry of the images.")
+	fs.BoolVar(&opts.OpenShift, "openshift", false,
+		"Render the OpenShift resources, i.e. SecurityContextConstraints and Routes.")
+	fs.BoolVar(&serverDefaults, "server-defaults", true,
+		"Apply the defaults of the Contour CRD with a dry-run apply to the cluster selected by the KUBECONFIG "+
+			"environment variable. If false, the manifest must set every field the CRD defaults.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s render -f FILE [flags]\n\n"+
+			"Prints the resources the operator manages for the Contour of FILE, i.e. to review them or\n"+
+			"apply them with GitOps tools in clusters where the operator runs read-only.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if file == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open contour manifest: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+	contour, err := render.ReadContour(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	if serverDefaults {
+		kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{})
+		if contour.Namespace == "" {
+			if contour.Namespace, _, err = kubeconfig.Namespace(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to get namespace of kubeconfig context: %v\n", err)
+				return 1
+			}
+		}
+		cfg, err := kubeconfig.ClientConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get kubeconfig: %v\n", err)
+			return 1
+		}
+		cli, err := client.New(cfg, client.Options{Scheme: operator.GetOperatorScheme()})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+			return 1
+		}
+		if contour, err = render.ServerDefaults(ctx, cli, contour); err != nil {
+			fmt.Fprintf(os.Stderr, "%v; set --server-defaults=false to render without a cluster\n", err)
+			return 1
+		}
+	}
+
+	objs, err := render.Objects(ctx, contour, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if outputDir != "" {
+		err = render.WriteDir(outputDir, objs)
+	} else {
+		err = render.Write(os.Stdout, objs)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write resources: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runInit runs the "init" subcommand, which prints a Contour for a common
+// scenario from flags or interactive answers, returning the exit code.
+func runInit(args []string) int {
+	opts := scaffold.Defaults()
+	var scenario, provider string
+	var interactive bool
+	var httpNodePort, httpsNodePort, replicas int
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.BoolVar(&interactive, "i", false, "Ask for the options interactively, defaulting to the values of the flags.")
+	fs.StringVar(&scenario, "scenario", string(opts.Scenario),
+		"The scenario of the Contour: cloud-lb, nodeport or gateway-api.")
+	fs.StringVar(&opts.Name, "name", opts.Name, "The name of the Contour.")
+	fs.StringVar(&opts.Namespace, "namespace", opts.Namespace, "The namespace of the Contour.")
+	fs.StringVar(&opts.SpecNamespace, "spec-namespace", opts.SpecNamespace,
+		"The namespace to run Contour and Envoy in.")
+	fs.IntVar(&replicas, "replicas", int(opts.Replicas), "The number of Contour replicas.")
+	fs.StringVar(&opts.Version, "version", "",
+		"The Contour version, i.e. v1.15.1. If empty, the images of the operator are used.")
+	fs.StringVar(&provider, "provider", string(opts.Provider),
+		"The load balancer provider of the cloud-lb and gateway-api scenarios: AWS, Azure, GCP or Generic.")
+	fs.BoolVar(&opts.Internal, "internal", false,
+		"Only expose the load balancer of the cloud-lb and gateway-api scenarios on the cloud network.")
+	fs.IntVar(&httpNodePort, "http-node-port", 0,
+		"The HTTP node port of the nodeport scenario. If 0, the port is allocated.")
+	fs.IntVar(&httpsNodePort, "https-node-port", 0,
+		"The HTTPS node port of the nodeport scenario. If 0, the port is allocated.")
+	fs.StringVar(&opts.GatewayClass, "gateway-class", opts.GatewayClass,
+		"The name of the GatewayClass of the gateway-api scenario.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s init [flags]\n\n"+
+			"Prints a Contour for a common scenario, and the GatewayClass of the gateway-api scenario,\n"+
+			"i.e. to save it and apply it with \"kubectl apply -f\".\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	opts.Scenario = scaffold.Scenario(scenario)
+	opts.Provider = operatorv1alpha1.LoadBalancerProviderType(provider)
+	opts.Replicas = int32(replicas)
+	opts.HTTPNodePort = int32(httpNodePort)
+	opts.HTTPSNodePort = int32(httpsNodePort)
+
+	if interactive {
+		// Questions go to stderr so that stdout can be redirected to a file.
+		if err := scaffold.Prompt(os.Stdin, os.Stderr, &opts); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read answers: %v\n", err)
+			return 1
+		}
+	}
+	objs, err := scaffold.Objects(context.Background(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if err := scaffold.Write(os.Stdout, objs); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write resources: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// envOrDefault returns the value of the environment variable key, or def if
+// the variable is unset or empty.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
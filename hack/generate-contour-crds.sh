@@ -32,3 +32,17 @@ else
   echo "Failed to get the operator YAML document from ${URL}."
   exit 1
 fi
+
+# Generate the Go source of the Contour CRDs installed by the operator.
+GO_FILE="internal/objects/crd/zz_generated.contour_crds.go"
+echo "Generating ${GO_FILE}..."
+{
+  cat hack/boilerplate.go.txt
+  printf '\n// Code generated by hack/generate-contour-crds.sh. DO NOT EDIT.\n\npackage crd\n\n'
+  printf '// contourCRDsVersion is the Contour version of contourCRDs.\n'
+  printf 'const contourCRDsVersion = "%s"\n\n' "${VERSION}"
+  printf '// contourCRDs is the YAML document of the Contour CRDs.\n'
+  printf 'const contourCRDs = `'
+  sed 's/`/` + "`" + `/g' config/crd/contour/01-crds.yaml
+  printf '`\n'
+} > "${GO_FILE}"
@@ -0,0 +1,281 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle exports the effective configuration of a Contour into a
+// portable bundle and imports it into another cluster, i.e. for the "export"
+// and "import" subcommands.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcm "github.com/projectcontour/contour-operator/internal/objects/configmap"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objjob "github.com/projectcontour/contour-operator/internal/objects/job"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// contourFile is the file of the Contour in a bundle.
+	contourFile = "contour.yaml"
+	// configFile is the file of the rendered Contour configuration in a bundle.
+	configFile = "contour-config.yaml"
+	// certificatesFile is the file of the certificates metadata in a bundle.
+	certificatesFile = "certificates.yaml"
+)
+
+// Bundle is the effective configuration of a Contour.
+type Bundle struct {
+	// Contour is the Contour without the fields set by the API server.
+	Contour *operatorv1alpha1.Contour
+	// Config is the Contour configuration file rendered by the operator, or
+	// empty if the ConfigMap of the Contour does not exist.
+	Config string
+	// Certificates are the metadata of the certificates generated for the
+	// Contour. Private keys are never exported.
+	Certificates []Certificate
+}
+
+// Certificate is the metadata of a generated certificate.
+type Certificate struct {
+	// Secret is the name of the secret of the certificate.
+	Secret string `json:"secret"`
+	// Key is the key of the certificate in the secret, i.e. "tls.crt".
+	Key string `json:"key"`
+	// Subject and Issuer are the distinguished names of the certificate.
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer"`
+	// DNSNames are the subject alternative DNS names of the certificate.
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber string `json:"serialNumber"`
+	// NotBefore and NotAfter are the validity period of the certificate.
+	NotBefore metav1.Time `json:"notBefore"`
+	NotAfter  metav1.Time `json:"notAfter"`
+	// SHA256Fingerprint is the hex-encoded SHA-256 digest of the certificate.
+	SHA256Fingerprint string `json:"sha256Fingerprint"`
+}
+
+// Export returns the Bundle of the Contour named name in namespace ns.
+func Export(ctx context.Context, cli client.Client, ns, name string) (*Bundle, error) {
+	contour := &operatorv1alpha1.Contour{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, contour); err != nil {
+		return nil, fmt.Errorf("failed to get contour %s/%s: %w", ns, name, err)
+	}
+	b := &Bundle{Contour: portable(contour)}
+
+	cm, err := objcm.Current(ctx, cli, objcm.NewCfgForContour(contour))
+	switch {
+	case err == nil:
+		b.Config = cm.Data[objcm.ContourCfgFileName]
+	case !errors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get configmap of contour %s/%s: %w", ns, name, err)
+	}
+
+	for _, base := range objjob.CertsSecretNames {
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Namespace: contour.Spec.Namespace.Name, Name: objcontour.CertsSecretName(contour, base)}
+		if err := cli.Get(ctx, key, secret); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", key.Namespace, key.Name, err)
+		}
+		for _, k := range []string{corev1.TLSCertKey, corev1.ServiceAccountRootCAKey} {
+			cert, err := certificate(secret.Data[k])
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate %s of secret %s/%s: %w", k, key.Namespace, key.Name, err)
+			}
+			if cert == nil {
+				continue
+			}
+			cert.Secret, cert.Key = secret.Name, k
+			b.Certificates = append(b.Certificates, *cert)
+		}
+	}
+	return b, nil
+}
+
+// portable returns a copy of contour without the fields set by the API server
+// and the operator, and without pending requests to rotate its certificates or
+// resync its resources.
+func portable(contour *operatorv1alpha1.Contour) *operatorv1alpha1.Contour {
+	p := &operatorv1alpha1.Contour{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1alpha1.GroupVersion.String(),
+			Kind:       "Contour",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        contour.Name,
+			Namespace:   contour.Namespace,
+			Labels:      contour.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: *contour.Spec.DeepCopy(),
+	}
+	for k, v := range contour.Annotations {
+		switch k {
+		case operatorv1alpha1.RotateCertificatesAnnotation, operatorv1alpha1.ResyncAnnotation,
+			corev1.LastAppliedConfigAnnotation:
+		default:
+			p.Annotations[k] = v
+		}
+	}
+	if len(p.Annotations) == 0 {
+		p.Annotations = nil
+	}
+	return p
+}
+
+// certificate returns the metadata of the PEM certificate data, or nil if data
+// is empty.
+func certificate(data []byte) (*Certificate, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &Certificate{
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		DNSNames:          cert.DNSNames,
+		SerialNumber:      cert.SerialNumber.String(),
+		NotBefore:         metav1.NewTime(cert.NotBefore.UTC()),
+		NotAfter:          metav1.NewTime(cert.NotAfter.UTC()),
+		SHA256Fingerprint: hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+// Write writes b to w as a gzipped tar archive of YAML files, using now as the
+// modification time of the files.
+func (b *Bundle) Write(w io.Writer, now time.Time) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(b.Contour)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "status")
+	contour, err := yaml.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contour: %w", err)
+	}
+	certs, err := yaml.Marshal(b.Certificates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal certificates: %w", err)
+	}
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{contourFile, contour},
+		{configFile, []byte(b.Config)},
+		{certificatesFile, certs},
+	} {
+		hdr := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Read reads a Bundle written by Write from r.
+func Read(r io.Reader) (*Bundle, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	b := &Bundle{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of bundle: %w", hdr.Name, err)
+		}
+		switch hdr.Name {
+		case contourFile:
+			b.Contour = &operatorv1alpha1.Contour{}
+			if err := yaml.UnmarshalStrict(data, b.Contour); err != nil {
+				return nil, fmt.Errorf("invalid %s of bundle: %w", hdr.Name, err)
+			}
+		case configFile:
+			b.Config = string(data)
+		case certificatesFile:
+			if err := yaml.Unmarshal(data, &b.Certificates); err != nil {
+				return nil, fmt.Errorf("invalid %s of bundle: %w", hdr.Name, err)
+			}
+		}
+	}
+	if b.Contour == nil {
+		return nil, fmt.Errorf("invalid bundle: %s not found", contourFile)
+	}
+	return b, nil
+}
+
+// Import creates the Contour of b, in namespace ns if not empty, creating its
+// namespace if it does not exist. The operator of the cluster of cli generates
+// new certificates for the Contour.
+func Import(ctx context.Context, cli client.Client, b *Bundle, ns string) (*operatorv1alpha1.Contour, error) {
+	contour := b.Contour.DeepCopy()
+	if ns != "" {
+		contour.Namespace = ns
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: contour.Namespace}}
+	if err := cli.Create(ctx, namespace); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create namespace %s: %w", contour.Namespace, err)
+	}
+	if err := cli.Create(ctx, contour); err != nil {
+		return nil, fmt.Errorf("failed to create contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	return contour, nil
+}
@@ -0,0 +1,132 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcm "github.com/projectcontour/contour-operator/internal/objects/configmap"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	"github.com/projectcontour/contour-operator/internal/operator"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC)
+	contour := objcontour.New(objcontour.Config{
+		Name:        "contour",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	contour.Annotations = map[string]string{
+		operatorv1alpha1.RotateCertificatesAnnotation: "2021-06-07T11:00:00Z",
+		"example.com/owner":                           "team-a",
+	}
+	contour.Finalizers = []string{operatorv1alpha1.ContourFinalizer}
+	contour.Status.Version = "v1.15.1"
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "envoy"},
+		DNSNames:     []string{"envoy"},
+		NotBefore:    now,
+		NotAfter:     now.Add(365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: "envoycert"},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: []byte("private"),
+		},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: objcm.ContourCfgMapName},
+		Data:       map[string]string{objcm.ContourCfgFileName: "disablePermitInsecure: false\n"},
+	}
+	objs := []client.Object{contour, secret, cm}
+	cli := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme()).WithObjects(objs...).Build()
+
+	exported, err := Export(ctx, cli, "default", "contour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := exported.Write(out, now); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Contour.ResourceVersion != "" || b.Contour.Finalizers != nil || b.Contour.Status.Version != "" {
+		t.Errorf("expected the contour without the fields set by the cluster, got %+v", b.Contour.ObjectMeta)
+	}
+	if _, ok := b.Contour.Annotations[operatorv1alpha1.RotateCertificatesAnnotation]; ok {
+		t.Errorf("expected the certificates rotation annotation to be dropped")
+	}
+	if b.Contour.Annotations["example.com/owner"] != "team-a" {
+		t.Errorf("expected the other annotations to be kept, got %v", b.Contour.Annotations)
+	}
+	if b.Config != cm.Data[objcm.ContourCfgFileName] {
+		t.Errorf("expected config %q, got %q", cm.Data[objcm.ContourCfgFileName], b.Config)
+	}
+	if len(b.Certificates) != 1 {
+		t.Fatalf("expected 1 certificate, got %+v", b.Certificates)
+	}
+	cert := b.Certificates[0]
+	if cert.Secret != "envoycert" || cert.SerialNumber != "42" || !cert.NotAfter.Time.Equal(tmpl.NotAfter) {
+		t.Errorf("unexpected certificate metadata %+v", cert)
+	}
+
+	target := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme()).Build()
+	imported, err := Import(ctx, target, b, "restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Get(ctx, client.ObjectKey{Namespace: "restored", Name: "contour"}, &operatorv1alpha1.Contour{}); err != nil {
+		t.Errorf("expected contour %s/%s to be imported: %v", imported.Namespace, imported.Name, err)
+	}
+	if err := target.Get(ctx, client.ObjectKey{Name: "restored"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("expected the namespace of the contour to be created: %v", err)
+	}
+	if _, err := Import(ctx, target, b, "restored"); err == nil {
+		t.Errorf("expected importing an existing contour to fail")
+	}
+}
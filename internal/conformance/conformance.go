@@ -0,0 +1,497 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance provisions a Gateway through the operator and checks
+// that it routes traffic as the Gateway API requires, i.e. for the
+// "conformance" subcommand.
+//
+// The v1alpha1 Gateway API served by the operator ships no conformance suite;
+// the upstream suite starts with v1alpha2. The cases of this package are
+// modelled on the HTTPRoute tests of the upstream suite, using its echo
+// backends, so that results are comparable once the operator serves v1alpha2.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+const (
+	// contourName is the name of the Contour provisioning the Gateway.
+	contourName = "contour-conformance"
+	// gatewayName is the name of the Gateway under test.
+	gatewayName = "conformance"
+	// routeLabel is the label selecting the HTTPRoutes of the Gateway.
+	routeLabel = "conformance.projectcontour.io/gateway"
+	// backendPort is the port of the echo backends.
+	backendPort = 3000
+	// pollInterval is how often the Envoy address and the requests of a case
+	// are retried until they succeed.
+	pollInterval = 2 * time.Second
+	// requestTimeout is how long a request to the Gateway may take.
+	requestTimeout = 5 * time.Second
+)
+
+// backends are the names of the echo backends, as in the upstream suite.
+var backends = []string{"infra-backend-v1", "infra-backend-v2"}
+
+// Options are the options of a conformance run.
+type Options struct {
+	// Namespace is the namespace of the Contour. The Gateway, its routes and
+	// backends are in Namespace suffixed by "-gateway".
+	Namespace string
+	// GatewayClass is the name of the GatewayClass of the Gateway.
+	GatewayClass string
+	// Address is the host and optional port requests are sent to, or empty
+	// for the load balancer address of the Envoy service.
+	Address string
+	// Timeout is how long each case, and waiting for the Envoy address, may
+	// take before failing.
+	Timeout time.Duration
+	// EchoImage is the image of the echo backends.
+	EchoImage string
+}
+
+// Defaults returns the default Options.
+func Defaults() Options {
+	return Options{
+		Namespace:    "contour-conformance",
+		GatewayClass: "contour-conformance",
+		Timeout:      3 * time.Minute,
+		EchoImage:    "gcr.io/k8s-staging-ingressconformance/echoserver:v20210922-cec7cf2",
+	}
+}
+
+// gatewayNamespace returns the namespace of the Gateway of opts.
+func (opts Options) gatewayNamespace() string {
+	return opts.Namespace + "-gateway"
+}
+
+// Request is a request sent to the Gateway by a case.
+type Request struct {
+	// Host and Path are the host and path of the request.
+	Host string
+	Path string
+	// Headers are the headers of the request.
+	Headers map[string]string
+	// Backend is the echo backend expected to answer the request, or empty if
+	// the Gateway is expected to answer with 404 Not Found.
+	Backend string
+}
+
+// Case is a conformance case.
+type Case struct {
+	// Name is the name of the case, i.e. the name of the upstream test it is
+	// modelled on.
+	Name string
+	// Description is a short description of the case.
+	Description string
+	// Route is the HTTPRoute of the case.
+	Route *gatewayv1alpha1.HTTPRoute
+	// Requests are the requests sent to the Gateway once Route is created.
+	Requests []Request
+}
+
+// Result is the result of a case.
+type Result struct {
+	// Case is the name of the case.
+	Case string
+	// Description is the description of the case.
+	Description string
+	// Err is the reason the case failed, or nil if it passed.
+	Err error
+}
+
+// Cases returns the conformance cases of opts.
+func Cases(opts Options) []Case {
+	exact := gatewayv1alpha1.PathMatchExact
+	prefix := gatewayv1alpha1.PathMatchPrefix
+	return []Case{
+		{
+			Name:        "HTTPRouteSimpleSameNamespace",
+			Description: "A route forwards all paths of its hostname to a backend.",
+			Route: route(opts, "simple", []gatewayv1alpha1.Hostname{"simple.conformance.example.com"},
+				gatewayv1alpha1.HTTPRouteRule{Matches: []gatewayv1alpha1.HTTPRouteMatch{pathMatch(prefix, "/")},
+					ForwardTo: forwardTo(backends[0])}),
+			Requests: []Request{
+				{Host: "simple.conformance.example.com", Path: "/", Backend: backends[0]},
+				{Host: "simple.conformance.example.com", Path: "/any/path", Backend: backends[0]},
+			},
+		},
+		{
+			Name:        "HTTPRouteMatching",
+			Description: "Exact and prefix path matches forward to different backends.",
+			Route: route(opts, "matching", []gatewayv1alpha1.Hostname{"matching.conformance.example.com"},
+				gatewayv1alpha1.HTTPRouteRule{Matches: []gatewayv1alpha1.HTTPRouteMatch{pathMatch(exact, "/")},
+					ForwardTo: forwardTo(backends[0])},
+				gatewayv1alpha1.HTTPRouteRule{Matches: []gatewayv1alpha1.HTTPRouteMatch{pathMatch(prefix, "/v2")},
+					ForwardTo: forwardTo(backends[1])}),
+			Requests: []Request{
+				{Host: "matching.conformance.example.com", Path: "/", Backend: backends[0]},
+				{Host: "matching.conformance.example.com", Path: "/v2", Backend: backends[1]},
+				{Host: "matching.conformance.example.com", Path: "/v2/example", Backend: backends[1]},
+			},
+		},
+		{
+			Name:        "HTTPRouteHeaderMatching",
+			Description: "Header matches forward to different backends.",
+			Route: route(opts, "header-matching", []gatewayv1alpha1.Hostname{"headers.conformance.example.com"},
+				gatewayv1alpha1.HTTPRouteRule{Matches: []gatewayv1alpha1.HTTPRouteMatch{headerMatch("version", "one")},
+					ForwardTo: forwardTo(backends[0])},
+				gatewayv1alpha1.HTTPRouteRule{Matches: []gatewayv1alpha1.HTTPRouteMatch{headerMatch("version", "two")},
+					ForwardTo: forwardTo(backends[1])}),
+			Requests: []Request{
+				{Host: "headers.conformance.example.com", Path: "/", Headers: map[string]string{"version": "one"},
+					Backend: backends[0]},
+				{Host: "headers.conformance.example.com", Path: "/", Headers: map[string]string{"version": "two"},
+					Backend: backends[1]},
+			},
+		},
+		{
+			Name:        "HTTPRouteHostnames",
+			Description: "A route only matches requests for its hostnames.",
+			Route: route(opts, "hostnames", []gatewayv1alpha1.Hostname{"a.conformance.example.com",
+				"b.conformance.example.com"}, gatewayv1alpha1.HTTPRouteRule{ForwardTo: forwardTo(backends[0])}),
+			Requests: []Request{
+				{Host: "a.conformance.example.com", Path: "/", Backend: backends[0]},
+				{Host: "b.conformance.example.com", Path: "/", Backend: backends[0]},
+				{Host: "c.conformance.example.com", Path: "/"},
+			},
+		},
+	}
+}
+
+// route returns the HTTPRoute named name of opts with hostnames and rules.
+func route(opts Options, name string, hostnames []gatewayv1alpha1.Hostname,
+	rules ...gatewayv1alpha1.HTTPRouteRule) *gatewayv1alpha1.HTTPRoute {
+	return &gatewayv1alpha1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: opts.gatewayNamespace(),
+			Labels:    map[string]string{routeLabel: gatewayName},
+		},
+		Spec: gatewayv1alpha1.HTTPRouteSpec{
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+}
+
+// pathMatch returns an HTTPRouteMatch of paths matching value with typ.
+func pathMatch(typ gatewayv1alpha1.PathMatchType, value string) gatewayv1alpha1.HTTPRouteMatch {
+	return gatewayv1alpha1.HTTPRouteMatch{Path: &gatewayv1alpha1.HTTPPathMatch{Type: &typ, Value: &value}}
+}
+
+// headerMatch returns an HTTPRouteMatch of requests with header name set to
+// value.
+func headerMatch(name, value string) gatewayv1alpha1.HTTPRouteMatch {
+	typ := gatewayv1alpha1.HeaderMatchExact
+	return gatewayv1alpha1.HTTPRouteMatch{
+		Headers: &gatewayv1alpha1.HTTPHeaderMatch{Type: &typ, Values: map[string]string{name: value}},
+	}
+}
+
+// forwardTo returns the HTTPRouteForwardTo of backend.
+func forwardTo(backend string) []gatewayv1alpha1.HTTPRouteForwardTo {
+	port := gatewayv1alpha1.PortNumber(backendPort)
+	return []gatewayv1alpha1.HTTPRouteForwardTo{{ServiceName: &backend, Port: &port}}
+}
+
+// newContour returns the Contour provisioning the Gateway of opts.
+func newContour(opts Options) *operatorv1alpha1.Contour {
+	return objcontour.New(objcontour.Config{
+		Name:         contourName,
+		Namespace:    opts.Namespace,
+		SpecNs:       opts.gatewayNamespace(),
+		RemoveNs:     true,
+		Replicas:     1,
+		NetworkType:  operatorv1alpha1.LoadBalancerServicePublishingType,
+		GatewayClass: &opts.GatewayClass,
+	})
+}
+
+// newGatewayClass returns the GatewayClass of opts, parameterized by contour.
+func newGatewayClass(opts Options, contour *operatorv1alpha1.Contour) *gatewayv1alpha1.GatewayClass {
+	scope := "Namespace"
+	return &gatewayv1alpha1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: opts.GatewayClass},
+		Spec: gatewayv1alpha1.GatewayClassSpec{
+			Controller: operatorv1alpha1.GatewayClassControllerRef,
+			ParametersRef: &gatewayv1alpha1.ParametersReference{
+				Group:     operatorv1alpha1.GatewayClassParamsRefGroup,
+				Kind:      operatorv1alpha1.GatewayClassParamsRefKind,
+				Name:      contour.Name,
+				Scope:     &scope,
+				Namespace: &contour.Namespace,
+			},
+		},
+	}
+}
+
+// Objects returns the objects provisioning the Gateway of opts and its echo
+// backends, in creation order. The routes of the cases are not included.
+func Objects(opts Options) []client.Object {
+	contour := newContour(opts)
+	gw := &gatewayv1alpha1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayName, Namespace: opts.gatewayNamespace()},
+		Spec: gatewayv1alpha1.GatewaySpec{
+			GatewayClassName: opts.GatewayClass,
+			Listeners: []gatewayv1alpha1.Listener{{
+				Protocol: gatewayv1alpha1.HTTPProtocolType,
+				Port:     80,
+				Routes: gatewayv1alpha1.RouteBindingSelector{
+					Kind:     "HTTPRoute",
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{routeLabel: gatewayName}},
+				},
+			}},
+		},
+	}
+	objs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.gatewayNamespace()}},
+		contour,
+		newGatewayClass(opts, contour),
+		gw,
+	}
+	for _, b := range backends {
+		objs = append(objs, backendObjects(opts, b)...)
+	}
+	return objs
+}
+
+// backendObjects returns the Deployment and Service of the echo backend named
+// name.
+func backendObjects(opts Options, name string) []client.Object {
+	labels := map[string]string{"app": name}
+	replicas := int32(1)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: opts.gatewayNamespace(), Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "echo",
+						Image: opts.EchoImage,
+						Ports: []corev1.ContainerPort{{ContainerPort: backendPort}},
+						Env: []corev1.EnvVar{
+							{Name: "SERVICE_NAME", Value: name},
+							{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+							}},
+							{Name: "NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: opts.gatewayNamespace(), Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Protocol:   corev1.ProtocolTCP,
+				Port:       backendPort,
+				TargetPort: intstr.FromInt(backendPort),
+			}},
+		},
+	}
+	return []client.Object{deploy, svc}
+}
+
+// Setup creates the objects of opts and the routes of cases, keeping the
+// objects that already exist.
+func Setup(ctx context.Context, cli client.Client, opts Options, cases []Case) error {
+	objs := Objects(opts)
+	for _, c := range cases {
+		objs = append(objs, c.Route.DeepCopy())
+	}
+	for _, obj := range objs {
+		if err := cli.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create %T %s: %w", obj, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Cleanup deletes the GatewayClass, the Contour and the namespace of opts. The
+// operator removes the namespace of the Gateway with the Contour.
+func Cleanup(ctx context.Context, cli client.Client, opts Options) error {
+	contour := newContour(opts)
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}}
+	for _, obj := range []client.Object{newGatewayClass(opts, contour), contour, ns} {
+		if err := cli.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %T %s: %w", obj, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Address returns opts.Address, or waits for the load balancer address of the
+// Envoy service of the Gateway of opts until opts.Timeout.
+func Address(ctx context.Context, cli client.Client, opts Options) (string, error) {
+	if opts.Address != "" {
+		return opts.Address, nil
+	}
+	contour := newContour(opts)
+	var addr string
+	err := wait.PollImmediate(pollInterval, opts.Timeout, func() (bool, error) {
+		svc, err := objsvc.CurrentEnvoyService(ctx, cli, contour)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if ing.IP != "" {
+				addr = ing.IP
+			} else if ing.Hostname != "" {
+				addr = ing.Hostname
+			}
+		}
+		return addr != "", nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get load balancer address of envoy service: %w", err)
+	}
+	return addr, nil
+}
+
+// Run sends the requests of cases to addr, retrying the requests of each case
+// until they are answered as expected or opts.Timeout expires.
+func Run(ctx context.Context, addr string, opts Options, cases []Case) []Result {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	var results []Result
+	for _, c := range cases {
+		var last error
+		err := wait.PollImmediate(pollInterval, opts.Timeout, func() (bool, error) {
+			for _, r := range c.Requests {
+				if last = check(ctx, httpClient, addr, r); last != nil {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if err != nil && last == nil {
+			last = err
+		}
+		results = append(results, Result{Case: c.Name, Description: c.Description, Err: last})
+	}
+	return results
+}
+
+// echoResponse is the response of an echo backend.
+type echoResponse struct {
+	Service string `json:"service"`
+	Pod     string `json:"pod"`
+}
+
+// check sends r to addr, returning an error if it is not answered as expected.
+func check(ctx context.Context, httpClient *http.Client, addr string, r Request) error {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "80")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+r.Path, nil)
+	if err != nil {
+		return err
+	}
+	req.Host = r.Host
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request for %s%s failed: %w", r.Host, r.Path, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response for %s%s: %w", r.Host, r.Path, err)
+	}
+
+	if r.Backend == "" {
+		if resp.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("expected status %d for %s%s, got %d", http.StatusNotFound, r.Host, r.Path,
+				resp.StatusCode)
+		}
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected status %d for %s%s, got %d", http.StatusOK, r.Host, r.Path, resp.StatusCode)
+	}
+	echo := &echoResponse{}
+	if err := json.Unmarshal(body, echo); err != nil {
+		return fmt.Errorf("invalid echo response for %s%s: %w", r.Host, r.Path, err)
+	}
+	if echo.Service != r.Backend {
+		return fmt.Errorf("expected backend %s for %s%s, got %q", r.Backend, r.Host, r.Path, echo.Service)
+	}
+	return nil
+}
+
+// Write writes results to w as a table.
+func Write(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CASE\tRESULT\tDESCRIPTION")
+	for _, r := range results {
+		result := "PASS"
+		if r.Err != nil {
+			result = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Case, result, r.Description)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(tw, "\n%s: %v\n", r.Case, r.Err)
+		}
+	}
+	fmt.Fprintf(tw, "\n%d of %d cases passed.\n", len(results)-len(Failed(results)), len(results))
+	return tw.Flush()
+}
+
+// Failed returns the results of failed cases.
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,132 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/operator"
+	"github.com/projectcontour/contour-operator/pkg/validation"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+func TestSetupCleanup(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme()).Build()
+	opts := Defaults()
+	cases := Cases(opts)
+
+	if err := Setup(ctx, cli, opts, cases); err != nil {
+		t.Fatal(err)
+	}
+	// Setup is idempotent.
+	if err := Setup(ctx, cli, opts, cases); err != nil {
+		t.Fatal(err)
+	}
+
+	contour := &operatorv1alpha1.Contour{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: contourName}, contour); err != nil {
+		t.Fatal(err)
+	}
+	if err := validation.Contour(ctx, cli, contour); err != nil {
+		t.Errorf("expected a valid contour, got %v", err)
+	}
+	gc := &gatewayv1alpha1.GatewayClass{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: opts.GatewayClass}, gc); err != nil {
+		t.Fatal(err)
+	}
+	if err := validation.GatewayClass(gc); err != nil {
+		t.Errorf("expected a valid gatewayclass, got %v", err)
+	}
+	gw := &gatewayv1alpha1.Gateway{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: contour.Spec.Namespace.Name, Name: gatewayName}, gw); err != nil {
+		t.Fatal(err)
+	}
+	selector := labels.SelectorFromSet(gw.Spec.Listeners[0].Routes.Selector.MatchLabels)
+	for _, c := range cases {
+		if c.Route.Namespace != gw.Namespace || !selector.Matches(labels.Set(c.Route.Labels)) {
+			t.Errorf("expected route of case %s to be selected by the gateway", c.Name)
+		}
+	}
+
+	if err := Cleanup(ctx, cli, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: contourName}, contour); err == nil {
+		t.Errorf("expected contour to be deleted")
+	}
+}
+
+func TestRun(t *testing.T) {
+	// The server answers as backend v1 for every host but c.conformance.example.com.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "c.conformance.example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"service": %q, "pod": "%s-abc"}`, backends[0], backends[0])
+	}))
+	defer srv.Close()
+
+	opts := Defaults()
+	opts.Address = strings.TrimPrefix(srv.URL, "http://")
+	opts.Timeout = 100 * time.Millisecond
+	addr, err := Address(context.Background(), nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := Run(context.Background(), addr, opts, Cases(opts))
+
+	passed := map[string]bool{}
+	for _, r := range results {
+		passed[r.Case] = r.Err == nil
+	}
+	expected := map[string]bool{
+		"HTTPRouteSimpleSameNamespace": true,
+		"HTTPRouteMatching":            false,
+		"HTTPRouteHeaderMatching":      false,
+		"HTTPRouteHostnames":           true,
+	}
+	for name, pass := range expected {
+		if passed[name] != pass {
+			t.Errorf("expected case %s to pass: %t, got %t", name, pass, passed[name])
+		}
+	}
+	if len(Failed(results)) != 2 {
+		t.Errorf("expected 2 failed cases, got %d", len(Failed(results)))
+	}
+
+	out := &bytes.Buffer{}
+	if err := Write(out, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2 of 4 cases passed.") {
+		t.Errorf("expected a summary of the results, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "expected backend infra-backend-v2") {
+		t.Errorf("expected the reason of failed cases, got %q", out.String())
+	}
+}
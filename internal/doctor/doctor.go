@@ -0,0 +1,368 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doctor checks a cluster for the common misconfigurations of Contours,
+// i.e. for the "doctor" subcommand.
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
+	objdeploy "github.com/projectcontour/contour-operator/internal/objects/deployment"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+	"github.com/projectcontour/contour-operator/internal/operator"
+	"github.com/projectcontour/contour-operator/internal/report"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// certExpiryWarning is how long before expiry a certificate is reported.
+	certExpiryWarning = 7 * 24 * time.Hour
+	// loadBalancerGracePeriod is how long a load balancer may be pending before
+	// it is reported.
+	loadBalancerGracePeriod = 5 * time.Minute
+	// podSecurityEnforceLabel is the namespace label of the enforced Pod
+	// Security Admission level.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+// Severity is the severity of a Finding.
+type Severity string
+
+const (
+	// ErrorSeverity is a misconfiguration that breaks a Contour.
+	ErrorSeverity Severity = "Error"
+	// WarningSeverity is a misconfiguration that may break a Contour.
+	WarningSeverity Severity = "Warning"
+)
+
+// Finding is a misconfiguration found by a check.
+type Finding struct {
+	// Contour is the namespace/name of the affected Contour.
+	Contour string
+	// Severity is the severity of the misconfiguration.
+	Severity Severity
+	// Check is the name of the check that found the misconfiguration.
+	Check string
+	// Message describes the misconfiguration.
+	Message string
+	// Hint describes how to remediate the misconfiguration.
+	Hint string
+}
+
+// Diagnose runs the checks against the Contours selected by opts at now,
+// returning the findings in the order of the Contours.
+func Diagnose(ctx context.Context, cli client.Client, opts report.Options, now time.Time) ([]Finding, error) {
+	contours, err := report.List(ctx, cli, opts)
+	if err != nil {
+		return nil, err
+	}
+	missingCRDs, err := missingGatewayCRDs(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	services := &corev1.ServiceList{}
+	if err := cli.List(ctx, services); err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var findings []Finding
+	for i := range contours {
+		d := &diagnosis{ctx: ctx, cli: cli, contour: &contours[i], now: now}
+		d.gatewayAPI(missingCRDs)
+		if err := d.certificates(); err != nil {
+			return nil, err
+		}
+		if err := d.loadBalancer(); err != nil {
+			return nil, err
+		}
+		d.nodePorts(services.Items)
+		if err := d.podSecurity(); err != nil {
+			return nil, err
+		}
+		findings = append(findings, d.findings...)
+	}
+	return findings, nil
+}
+
+// diagnosis collects the findings of a Contour.
+type diagnosis struct {
+	ctx      context.Context
+	cli      client.Client
+	contour  *operatorv1alpha1.Contour
+	now      time.Time
+	findings []Finding
+}
+
+// report adds a finding of check for the Contour of d.
+func (d *diagnosis) report(severity Severity, check, hint, format string, args ...interface{}) {
+	d.findings = append(d.findings, Finding{
+		Contour:  d.contour.Namespace + "/" + d.contour.Name,
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
+
+// missingGatewayCRDs returns the names of the Gateway API CRDs required by the
+// operator that do not exist.
+func missingGatewayCRDs(ctx context.Context, cli client.Client) ([]string, error) {
+	var missing []string
+	for _, gvr := range operator.GatewayAPIResources() {
+		name := gvr.Resource + "." + gvr.Group
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get crd %s: %w", name, err)
+		}
+	}
+	return missing, nil
+}
+
+// gatewayAPI reports a Contour managed for a GatewayClass if Gateway API CRDs
+// are missing.
+func (d *diagnosis) gatewayAPI(missingCRDs []string) {
+	if d.contour.Spec.GatewayClassRef == nil || len(missingCRDs) == 0 {
+		return
+	}
+	d.report(ErrorSeverity, "Gateway API",
+		"Install the Gateway API v1alpha1 CRDs, i.e. \"kubectl apply -k github.com/kubernetes-sigs/gateway-api/config/crd?ref=v0.3.0\", and restart the operator.",
+		"spec.gatewayClassRef is set but the CRDs %s do not exist", strings.Join(missingCRDs, ", "))
+}
+
+// certificates reports missing, expired and expiring xDS certificates.
+func (d *diagnosis) certificates() error {
+	ns := d.contour.Spec.Namespace.Name
+	hint := "Delete the certgen Job in namespace " + ns + " so that the operator regenerates the certificates."
+	for _, base := range []string{"contourcert", "envoycert"} {
+		name := objcontour.CertsSecretName(d.contour, base)
+		secret := &corev1.Secret{}
+		if err := d.cli.Get(d.ctx, client.ObjectKey{Namespace: ns, Name: name}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				d.report(ErrorSeverity, "xDS certificates", hint, "secret %s/%s does not exist", ns, name)
+				continue
+			}
+			return fmt.Errorf("failed to get secret %s/%s: %w", ns, name, err)
+		}
+		block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+		if block == nil {
+			d.report(ErrorSeverity, "xDS certificates", hint, "secret %s/%s has no PEM certificate", ns, name)
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			d.report(ErrorSeverity, "xDS certificates", hint, "secret %s/%s has an invalid certificate: %v", ns, name, err)
+			continue
+		}
+		switch left := cert.NotAfter.Sub(d.now); {
+		case left <= 0:
+			d.report(ErrorSeverity, "xDS certificates", hint, "the certificate of secret %s/%s expired %s ago",
+				ns, name, duration.HumanDuration(-left))
+		case left < certExpiryWarning:
+			d.report(WarningSeverity, "xDS certificates", hint, "the certificate of secret %s/%s expires in %s",
+				ns, name, duration.HumanDuration(left))
+		}
+	}
+	return nil
+}
+
+// loadBalancer reports an Envoy LoadBalancer Service that is pending for longer
+// than loadBalancerGracePeriod.
+func (d *diagnosis) loadBalancer() error {
+	svc, err := objsvc.CurrentEnvoyService(d.ctx, d.cli, d.contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get envoy service of contour %s/%s: %w", d.contour.Namespace, d.contour.Name, err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || len(svc.Status.LoadBalancer.Ingress) > 0 {
+		return nil
+	}
+	if pending := d.now.Sub(svc.CreationTimestamp.Time); pending >= loadBalancerGracePeriod {
+		d.report(WarningSeverity, "Load balancer",
+			"Check the events of the Service. Clusters without a cloud provider need a load balancer implementation "+
+				"such as MetalLB, or spec.networkPublishing.envoy.type set to NodePortService.",
+			"service %s/%s has no load balancer address after %s", svc.Namespace, svc.Name, duration.HumanDuration(pending))
+	}
+	return nil
+}
+
+// nodePorts reports node ports of the Contour that are allocated to other
+// Services of services.
+func (d *diagnosis) nodePorts(services []corev1.Service) {
+	envoy := d.contour.Spec.NetworkPublishing.Envoy
+	if envoy.Type != operatorv1alpha1.NodePortServicePublishingType {
+		return
+	}
+	ns, name := d.contour.Spec.Namespace.Name, objcontour.ResourceName(d.contour, "envoy")
+	for _, np := range envoy.NodePorts {
+		if np.PortNumber == nil {
+			continue
+		}
+		for _, svc := range services {
+			if svc.Namespace == ns && svc.Name == name {
+				continue
+			}
+			for _, port := range svc.Spec.Ports {
+				if port.NodePort == *np.PortNumber {
+					d.report(ErrorSeverity, "Port conflicts",
+						"Use a different node port in spec.networkPublishing.envoy.nodePorts, or remove the port to have one allocated.",
+						"node port %d of %s is allocated to service %s/%s", port.NodePort, np.Name, svc.Namespace, svc.Name)
+				}
+			}
+		}
+	}
+}
+
+// podSecurity reports Contour and Envoy pods rejected by the Pod Security
+// Admission level enforced in the spec namespace.
+func (d *diagnosis) podSecurity() error {
+	ns := &corev1.Namespace{}
+	name := d.contour.Spec.Namespace.Name
+	if err := d.cli.Get(d.ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	level := ns.Labels[podSecurityEnforceLabel]
+	if level != "baseline" && level != "restricted" {
+		return nil
+	}
+	hint := fmt.Sprintf("Label namespace %s with %s=privileged, i.e. \"kubectl label ns %s %s=privileged --overwrite\".",
+		name, podSecurityEnforceLabel, name, podSecurityEnforceLabel)
+
+	var templates []podTemplate
+	deploy, err := objdeploy.CurrentDeployment(d.ctx, d.cli, d.contour)
+	switch {
+	case err == nil:
+		templates = append(templates, podTemplate{"deployment", deploy.Name, &deploy.Spec.Template.Spec})
+	case !errors.IsNotFound(err):
+		return fmt.Errorf("failed to get deployment of contour %s/%s: %w", d.contour.Namespace, d.contour.Name, err)
+	}
+	ds, err := objds.CurrentDaemonSet(d.ctx, d.cli, d.contour)
+	switch {
+	case err == nil:
+		templates = append(templates, podTemplate{"daemonset", ds.Name, &ds.Spec.Template.Spec})
+	case !errors.IsNotFound(err):
+		return fmt.Errorf("failed to get daemonset of contour %s/%s: %w", d.contour.Namespace, d.contour.Name, err)
+	}
+	for _, t := range templates {
+		if violations := podSecurityViolations(level, t.spec); len(violations) > 0 {
+			d.report(ErrorSeverity, "Pod security", hint, "the pods of %s %s/%s violate the %q level enforced in namespace %s: %s",
+				t.kind, name, t.name, level, name, strings.Join(violations, "; "))
+		}
+	}
+	return nil
+}
+
+// podTemplate is the pod template of a workload.
+type podTemplate struct {
+	kind, name string
+	spec       *corev1.PodSpec
+}
+
+// podSecurityViolations returns the violations of spec of the Pod Security
+// Standard level, i.e. "baseline" or "restricted".
+func podSecurityViolations(level string, spec *corev1.PodSpec) []string {
+	var violations []string
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "host namespaces")
+	}
+	podNonRoot := spec.SecurityContext != nil && spec.SecurityContext.RunAsNonRoot != nil && *spec.SecurityContext.RunAsNonRoot
+	podSeccomp := spec.SecurityContext != nil && spec.SecurityContext.SeccompProfile != nil
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		sc := c.SecurityContext
+		if sc != nil && sc.Privileged != nil && *sc.Privileged {
+			violations = append(violations, fmt.Sprintf("container %s is privileged", c.Name))
+		}
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				violations = append(violations, fmt.Sprintf("container %s uses host port %d", c.Name, p.HostPort))
+			}
+		}
+		if level != "restricted" {
+			continue
+		}
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, fmt.Sprintf("container %s does not set allowPrivilegeEscalation=false", c.Name))
+		}
+		if !podNonRoot && (sc == nil || sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot) {
+			violations = append(violations, fmt.Sprintf("container %s does not set runAsNonRoot=true", c.Name))
+		}
+		if !podSeccomp && (sc == nil || sc.SeccompProfile == nil) {
+			violations = append(violations, fmt.Sprintf("container %s does not set a seccompProfile", c.Name))
+		}
+		if !dropsAll(sc) {
+			violations = append(violations, fmt.Sprintf("container %s does not drop all capabilities", c.Name))
+		}
+	}
+	return violations
+}
+
+// dropsAll returns true if sc drops all capabilities.
+func dropsAll(sc *corev1.SecurityContext) bool {
+	if sc == nil || sc.Capabilities == nil {
+		return false
+	}
+	for _, c := range sc.Capabilities.Drop {
+		if c == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes findings to w with their remediation hints.
+func Write(w io.Writer, findings []Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "No problems found.")
+		return err
+	}
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s: %s\n  Hint: %s\n", f.Severity, f.Contour, f.Check, f.Message, f.Hint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasErrors returns true if any of findings is of ErrorSeverity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == ErrorSeverity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	"github.com/projectcontour/contour-operator/internal/operator"
+	"github.com/projectcontour/contour-operator/internal/report"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func certSecret(t *testing.T, ns, name string, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	now := time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC)
+	gc := "contour"
+	port := int32(30080)
+	nodePorts := objcontour.New(objcontour.Config{
+		Name:         "nodeport",
+		Namespace:    "default",
+		SpecNs:       "edge",
+		NetworkType:  operatorv1alpha1.NodePortServicePublishingType,
+		NodePorts:    []operatorv1alpha1.NodePort{{Name: "http", PortNumber: &port}, {Name: "https"}},
+		GatewayClass: &gc,
+	})
+	lb := objcontour.New(objcontour.Config{
+		Name:        "lb",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	objs := []client.Object{
+		nodePorts,
+		lb,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "edge",
+			Labels: map[string]string{podSecurityEnforceLabel: "restricted"},
+		}},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "edge", Name: "envoy"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "envoy"}},
+			}}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Port: 80, NodePort: port}},
+			},
+		},
+		certSecret(t, "edge", "contourcert", now.Add(-48*time.Hour)),
+		certSecret(t, "edge", "envoycert", now.Add(365*24*time.Hour)),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "projectcontour",
+				Name:              "envoy",
+				CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute)),
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		},
+		certSecret(t, "projectcontour", "contourcert", now.Add(24*time.Hour)),
+	}
+	cli := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme()).WithObjects(objs...).Build()
+
+	findings, err := Diagnose(context.Background(), cli, report.Options{Namespace: "default"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		contour  string
+		severity Severity
+		check    string
+		message  string
+	}{
+		{"default/lb", WarningSeverity, "xDS certificates", "projectcontour/contourcert expires in 24h"},
+		{"default/lb", ErrorSeverity, "xDS certificates", "projectcontour/envoycert does not exist"},
+		{"default/lb", WarningSeverity, "Load balancer", "after 10m"},
+		{"default/nodeport", ErrorSeverity, "Gateway API", "gatewayclasses.networking.x-k8s.io"},
+		{"default/nodeport", ErrorSeverity, "xDS certificates", "edge/contourcert expired 2d"},
+		{"default/nodeport", ErrorSeverity, "Port conflicts", "service default/web"},
+		{"default/nodeport", ErrorSeverity, "Pod security", "container envoy does not set runAsNonRoot=true"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("expected %d findings, got %+v", len(expected), findings)
+	}
+	for i, e := range expected {
+		f := findings[i]
+		if f.Contour != e.contour || f.Severity != e.severity || f.Check != e.check || !strings.Contains(f.Message, e.message) {
+			t.Errorf("expected finding %d to be %+v, got %+v", i, e, f)
+		}
+	}
+	if !HasErrors(findings) {
+		t.Errorf("expected findings to have errors")
+	}
+
+	out := &bytes.Buffer{}
+	if err := Write(out, findings); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "Hint: ") != len(findings) {
+		t.Errorf("expected a hint per finding, got:\n%s", out.String())
+	}
+}
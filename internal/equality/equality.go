@@ -14,13 +14,19 @@
 package equality
 
 import (
+	"sort"
+
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
@@ -41,6 +47,16 @@ func DaemonsetConfigChanged(current, expected *appsv1.DaemonSet) (*appsv1.Daemon
 		updated.Spec = expected.Spec
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -53,6 +69,10 @@ func DaemonSetSelectorsDiffer(current, expected *appsv1.DaemonSet) bool {
 	return !apiequality.Semantic.DeepEqual(current.Spec.Selector, expected.Spec.Selector)
 }
 
+// jobGeneratedLabels are the pod template labels the job controller adds to
+// the jobs it runs.
+var jobGeneratedLabels = map[string]bool{"controller-uid": true, "job-name": true}
+
 // JobConfigChanged checks if the current and expected Job match and if not,
 // returns true and the expected job.
 func JobConfigChanged(current, expected *batchv1.Job) (*batchv1.Job, bool) {
@@ -74,6 +94,11 @@ func JobConfigChanged(current, expected *batchv1.Job) (*batchv1.Job, bool) {
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.TTLSecondsAfterFinished, expected.Spec.TTLSecondsAfterFinished) {
+		updated = expected
+		changed = true
+	}
+
 	// The completions field is immutable, so no need to compare. Ignore job-generated
 	// labels and only check the presence of the contour owning labels.
 	if current.Spec.Template.Labels != nil {
@@ -84,12 +109,39 @@ func JobConfigChanged(current, expected *batchv1.Job) (*batchv1.Job, bool) {
 			changed = true
 		}
 	}
+	for k, v := range expected.Spec.Template.Labels {
+		if current.Spec.Template.Labels[k] != v {
+			updated = expected
+			changed = true
+		}
+	}
+	for k := range current.Spec.Template.Labels {
+		if _, found := expected.Spec.Template.Labels[k]; !found && !jobGeneratedLabels[k] {
+			updated = expected
+			changed = true
+		}
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Spec.Template.Annotations, expected.Spec.Template.Annotations) {
+		updated = expected
+		changed = true
+	}
 
 	if !apiequality.Semantic.DeepEqual(current.Spec.Template.Spec, expected.Spec.Template.Spec) {
 		updated = expected
 		changed = true
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -113,6 +165,16 @@ func DeploymentConfigChanged(current, expected *appsv1.Deployment) (*appsv1.Depl
 		changed = true
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -153,11 +215,43 @@ func ClusterIPServiceChanged(current, expected *corev1.Service) (*corev1.Service
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
+		changed = true
+	}
+
+	// The internal traffic policy is defaulted by the API server if unspecified.
+	if expected.Spec.InternalTrafficPolicy != nil &&
+		!apiequality.Semantic.DeepEqual(current.Spec.InternalTrafficPolicy, expected.Spec.InternalTrafficPolicy) {
+		updated.Spec.InternalTrafficPolicy = expected.Spec.InternalTrafficPolicy
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Spec.Type, expected.Spec.Type) {
 		updated.Spec.Type = expected.Spec.Type
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.ExternalIPs, expected.Spec.ExternalIPs) {
+		updated.Spec.ExternalIPs = expected.Spec.ExternalIPs
+		changed = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
+		updated.Labels = expected.Labels
+		changed = true
+	}
+
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -194,6 +288,10 @@ func LoadBalancerServiceChanged(current, expected *corev1.Service) (*corev1.Serv
 				updated.Spec.Ports[i].TargetPort = expected.Spec.Ports[i].TargetPort
 				changed = true
 			}
+			if !apiequality.Semantic.DeepEqual(p.AppProtocol, expected.Spec.Ports[i].AppProtocol) {
+				updated.Spec.Ports[i].AppProtocol = expected.Spec.Ports[i].AppProtocol
+				changed = true
+			}
 		}
 	}
 
@@ -212,21 +310,55 @@ func LoadBalancerServiceChanged(current, expected *corev1.Service) (*corev1.Serv
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
+		changed = true
+	}
+
+	// The internal traffic policy is defaulted by the API server if unspecified.
+	if expected.Spec.InternalTrafficPolicy != nil &&
+		!apiequality.Semantic.DeepEqual(current.Spec.InternalTrafficPolicy, expected.Spec.InternalTrafficPolicy) {
+		updated.Spec.InternalTrafficPolicy = expected.Spec.InternalTrafficPolicy
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Spec.Type, expected.Spec.Type) {
 		updated.Spec.Type = expected.Spec.Type
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.ExternalIPs, expected.Spec.ExternalIPs) {
+		updated.Spec.ExternalIPs = expected.Spec.ExternalIPs
+		changed = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
+		updated.Labels = expected.Labels
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Annotations, expected.Annotations) {
 		updated.Annotations = expected.Annotations
 		changed = true
 	}
 
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Spec.LoadBalancerIP, expected.Spec.LoadBalancerIP) {
 		updated.Spec.LoadBalancerIP = expected.Spec.LoadBalancerIP
 		changed = true
 	}
 
+	// Node ports are allocated by the API server if unspecified.
+	if expected.Spec.AllocateLoadBalancerNodePorts != nil &&
+		!apiequality.Semantic.DeepEqual(current.Spec.AllocateLoadBalancerNodePorts, expected.Spec.AllocateLoadBalancerNodePorts) {
+		updated.Spec.AllocateLoadBalancerNodePorts = expected.Spec.AllocateLoadBalancerNodePorts
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -268,16 +400,43 @@ func NodePortServiceChanged(current, expected *corev1.Service) (*corev1.Service,
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.SessionAffinityConfig, expected.Spec.SessionAffinityConfig) {
+		updated.Spec.SessionAffinityConfig = expected.Spec.SessionAffinityConfig
+		changed = true
+	}
+
+	// The internal traffic policy is defaulted by the API server if unspecified.
+	if expected.Spec.InternalTrafficPolicy != nil &&
+		!apiequality.Semantic.DeepEqual(current.Spec.InternalTrafficPolicy, expected.Spec.InternalTrafficPolicy) {
+		updated.Spec.InternalTrafficPolicy = expected.Spec.InternalTrafficPolicy
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Spec.Type, expected.Spec.Type) {
 		updated.Spec.Type = expected.Spec.Type
 		changed = true
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Spec.ExternalIPs, expected.Spec.ExternalIPs) {
+		updated.Spec.ExternalIPs = expected.Spec.ExternalIPs
+		changed = true
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
+		updated.Labels = expected.Labels
+		changed = true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Annotations, expected.Annotations) {
 		updated.Annotations = expected.Annotations
 		changed = true
 	}
 
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -296,6 +455,18 @@ func ContourStatusChanged(current, expected operatorv1alpha1.ContourStatus) bool
 		return true
 	}
 
+	if current.Version != expected.Version {
+		return true
+	}
+
+	if current.CertificatesRotation != expected.CertificatesRotation {
+		return true
+	}
+
+	if current.Resync != expected.Resync {
+		return true
+	}
+
 	if !apiequality.Semantic.DeepEqual(current.Conditions, expected.Conditions) {
 		return true
 	}
@@ -304,13 +475,25 @@ func ContourStatusChanged(current, expected operatorv1alpha1.ContourStatus) bool
 }
 
 // NamespaceConfigChanged checks if the current and expected Namespace match
-// and if not, returns true and the expected Namespace.
+// and if not, returns true and the updated Namespace. The labels and annotations
+// of expected are merged into those of current.
 func NamespaceConfigChanged(current, expected *corev1.Namespace) (*corev1.Namespace, bool) {
 	changed := false
 	updated := current.DeepCopy()
 
-	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
-		updated = expected
+	// Labels added by other controllers, i.e. the namespace name label, are kept.
+	if labels, ok := mergedMap(current.Labels, expected.Labels); ok {
+		updated.Labels = labels
+		changed = true
+	}
+
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
 		changed = true
 	}
 
@@ -332,6 +515,16 @@ func ServiceAccountConfigChanged(current, expected *corev1.ServiceAccount) (*cor
 		changed = true
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -355,6 +548,16 @@ func ClusterRoleConfigChanged(current, expected *rbacv1.ClusterRole) (*rbacv1.Cl
 		updated.Rules = expected.Rules
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -384,6 +587,16 @@ func ClusterRoleBindingConfigChanged(current, expected *rbacv1.ClusterRoleBindin
 		updated.RoleRef = expected.RoleRef
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -407,6 +620,16 @@ func RoleConfigChanged(current, expected *rbacv1.Role) (*rbacv1.Role, bool) {
 		updated.Rules = expected.Rules
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -436,6 +659,133 @@ func RoleBindingConfigChanged(current, expected *rbacv1.RoleBinding) (*rbacv1.Ro
 		updated.RoleRef = expected.RoleRef
 	}
 
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		updated.OwnerReferences = refs
+		changed = true
+	}
+
+	if !changed {
+		return nil, false
+	}
+
+	return updated, true
+}
+
+// RouteConfigChanged checks if the current and expected OpenShift Route match
+// and if not, returns true and the updated Route. The route host is only compared
+// when set by expected, since the OpenShift router generates a host otherwise.
+func RouteConfigChanged(current, expected *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	changed := false
+	updated := current.DeepCopy()
+
+	if !apiequality.Semantic.DeepEqual(current.GetLabels(), expected.GetLabels()) {
+		changed = true
+		updated.SetLabels(expected.GetLabels())
+	}
+
+	fields := []string{"to", "port", "tls"}
+	if _, found, _ := unstructured.NestedFieldNoCopy(expected.Object, "spec", "host"); found {
+		fields = append(fields, "host")
+	}
+	for _, field := range fields {
+		currentVal, _, _ := unstructured.NestedFieldNoCopy(current.Object, "spec", field)
+		expectedVal, _, _ := unstructured.NestedFieldCopy(expected.Object, "spec", field)
+		if !apiequality.Semantic.DeepEqual(currentVal, expectedVal) {
+			changed = true
+			if err := unstructured.SetNestedField(updated.Object, expectedVal, "spec", field); err != nil {
+				return expected, true
+			}
+		}
+	}
+
+	if annotations, ok := MergedAnnotations(current.GetAnnotations(), expected.GetAnnotations()); ok {
+		updated.SetAnnotations(annotations)
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.GetOwnerReferences(), expected.GetOwnerReferences()); ok {
+		updated.SetOwnerReferences(refs)
+		changed = true
+	}
+
+	if !changed {
+		return nil, false
+	}
+
+	return updated, true
+}
+
+// SecurityContextConstraintsChanged checks if the current and expected OpenShift
+// SecurityContextConstraints match and if not, returns true and the updated
+// SecurityContextConstraints. Only the labels and fields set by expected are
+// compared.
+func SecurityContextConstraintsChanged(current, expected *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	changed := false
+	updated := current.DeepCopy()
+
+	if !apiequality.Semantic.DeepEqual(current.GetLabels(), expected.GetLabels()) {
+		changed = true
+		updated.SetLabels(expected.GetLabels())
+	}
+
+	for field, expectedVal := range expected.Object {
+		if field == "apiVersion" || field == "kind" || field == "metadata" {
+			continue
+		}
+		if !apiequality.Semantic.DeepEqual(current.Object[field], expectedVal) {
+			changed = true
+			updated.Object[field] = runtime.DeepCopyJSONValue(expectedVal)
+		}
+	}
+
+	if annotations, ok := MergedAnnotations(current.GetAnnotations(), expected.GetAnnotations()); ok {
+		updated.SetAnnotations(annotations)
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.GetOwnerReferences(), expected.GetOwnerReferences()); ok {
+		updated.SetOwnerReferences(refs)
+		changed = true
+	}
+
+	if !changed {
+		return nil, false
+	}
+
+	return updated, true
+}
+
+// SpecChanged checks if the labels and spec of the current and expected
+// objects match and if not, returns true and the updated object.
+func SpecChanged(current, expected *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	changed := false
+	updated := current.DeepCopy()
+
+	if !apiequality.Semantic.DeepEqual(current.GetLabels(), expected.GetLabels()) {
+		changed = true
+		updated.SetLabels(expected.GetLabels())
+	}
+
+	if !apiequality.Semantic.DeepEqual(current.Object["spec"], expected.Object["spec"]) {
+		changed = true
+		updated.Object["spec"] = runtime.DeepCopyJSONValue(expected.Object["spec"])
+	}
+
+	if annotations, ok := MergedAnnotations(current.GetAnnotations(), expected.GetAnnotations()); ok {
+		updated.SetAnnotations(annotations)
+		changed = true
+	}
+
+	if refs, ok := MergedOwnerReferences(current.GetOwnerReferences(), expected.GetOwnerReferences()); ok {
+		updated.SetOwnerReferences(refs)
+		changed = true
+	}
+
 	if !changed {
 		return nil, false
 	}
@@ -454,3 +804,138 @@ func GatewayClassStatusChanged(current, expected gatewayv1alpha1.GatewayClassSta
 func GatewayStatusChanged(current, expected gatewayv1alpha1.GatewayStatus) bool {
 	return !apiequality.Semantic.DeepEqual(current.Conditions, expected.Conditions)
 }
+
+// CustomResourceDefinitionChanged checks if the spec and annotations of current
+// and expected match and if not, returns true and current updated with the spec
+// and annotations of expected.
+func CustomResourceDefinitionChanged(current, expected *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, bool) {
+	changed := false
+	updated := current.DeepCopy()
+
+	if !apiequality.Semantic.DeepEqual(current.Spec, expected.Spec) {
+		changed = true
+		updated.Spec = expected.Spec
+	}
+
+	if annotations, ok := MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		updated.Annotations = annotations
+		changed = true
+	}
+
+	if !changed {
+		return nil, false
+	}
+
+	return updated, true
+}
+
+// MergedAnnotations returns current with the annotations of expected set and
+// true if any of them are missing from or differ in current. Annotations of
+// current that expected does not set, i.e. those added by other controllers,
+// are kept.
+func MergedAnnotations(current, expected map[string]string) (map[string]string, bool) {
+	return mergedMap(current, expected)
+}
+
+// mergedMap returns current with the keys of expected set and true if any of
+// them are missing from or differ in current.
+func mergedMap(current, expected map[string]string) (map[string]string, bool) {
+	changed := false
+	for k, v := range expected {
+		if cv, ok := current[k]; !ok || cv != v {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return current, false
+	}
+	merged := make(map[string]string, len(current)+len(expected))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range expected {
+		merged[k] = v
+	}
+	return merged, true
+}
+
+// MergedOwnerReferences returns current with the owner references of expected
+// set and true if any of them are missing from or differ in current. Owner
+// references are matched by UID and those of current that expected does not
+// set are kept.
+func MergedOwnerReferences(current, expected []metav1.OwnerReference) ([]metav1.OwnerReference, bool) {
+	changed := false
+	merged := append([]metav1.OwnerReference{}, current...)
+	for _, ref := range expected {
+		found := false
+		for i := range merged {
+			if merged[i].UID != ref.UID {
+				continue
+			}
+			found = true
+			if !apiequality.Semantic.DeepEqual(merged[i], ref) {
+				merged[i] = ref
+				changed = true
+			}
+		}
+		if !found {
+			merged = append(merged, ref)
+			changed = true
+		}
+	}
+	if !changed {
+		return current, false
+	}
+	return merged, true
+}
+
+// ChangedFields returns the sorted paths of the fields of the metadata, spec
+// and other top-level content of updated that differ in current, i.e.
+// "spec.template.spec.containers". Lists are compared as a whole and the fields
+// set by the API server, as well as the status, are ignored.
+func ChangedFields(current, updated map[string]interface{}) []string {
+	var fields []string
+	changedFields(withoutServerFields(current), withoutServerFields(updated), "", &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+// withoutServerFields returns the content of an object without its status and the
+// metadata set by the API server.
+func withoutServerFields(content map[string]interface{}) map[string]interface{} {
+	c := runtime.DeepCopyJSON(content)
+	delete(c, "status")
+	if metadata, ok := c["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"resourceVersion", "uid", "generation", "creationTimestamp", "managedFields", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	return c
+}
+
+// changedFields appends the paths below prefix of the fields that differ
+// between current and updated to fields.
+func changedFields(current, updated map[string]interface{}, prefix string, fields *[]string) {
+	keys := map[string]struct{}{}
+	for k := range current {
+		keys[k] = struct{}{}
+	}
+	for k := range updated {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		c, cIsMap := current[k].(map[string]interface{})
+		u, uIsMap := updated[k].(map[string]interface{})
+		switch {
+		case cIsMap && uIsMap:
+			changedFields(c, u, path, fields)
+		case !apiequality.Semantic.DeepEqual(current[k], updated[k]):
+			*fields = append(*fields, path)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -186,6 +187,35 @@ func TestJobConfigChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if ttlSecondsAfterFinished is changed",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.TTLSecondsAfterFinished = nil
+			},
+			expect: true,
+		},
+		{
+			description: "if a pod template label is added",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Labels["foo"] = "bar"
+			},
+			expect: true,
+		},
+		{
+			description: "if the job controller labels are added",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Labels["controller-uid"] = "abc"
+				job.Spec.Template.Labels["job-name"] = "contour-certgen"
+			},
+			expect: false,
+		},
+		{
+			description: "if a pod template annotation is added",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+			},
+			expect: true,
+		},
 		// Completions is immutable, so performing an equality comparison is unneeded.
 		{
 			description: "if backoffLimit is changed",
@@ -431,6 +461,27 @@ func TestClusterIpServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if external IPs changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Spec.ExternalIPs = []string{"192.0.2.10"}
+			},
+			expect: true,
+		},
+		{
+			description: "if labels changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Labels = map[string]string{"foo": "bar"}
+			},
+			expect: true,
+		},
+		{
+			description: "if an annotation is added by another controller",
+			mutate: func(svc *corev1.Service) {
+				svc.Annotations = map[string]string{"foo": "bar"}
+			},
+			expect: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -550,6 +601,40 @@ func TestLoadBalancerServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if internal traffic policy is defaulted",
+			mutate: func(svc *corev1.Service) {
+				policy := corev1.ServiceInternalTrafficPolicyCluster
+				svc.Spec.InternalTrafficPolicy = &policy
+			},
+			expect: false,
+		},
+		{
+			description: "if session affinity timeout changed",
+			mutate: func(svc *corev1.Service) {
+				timeout := int32(60)
+				svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+					ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+				}
+			},
+			expect: true,
+		},
+		{
+			description: "if the port app protocol changed",
+			mutate: func(svc *corev1.Service) {
+				protocol := "kubernetes.io/h2c"
+				svc.Spec.Ports[0].AppProtocol = &protocol
+			},
+			expect: true,
+		},
+		{
+			description: "if load balancer node ports are defaulted",
+			mutate: func(svc *corev1.Service) {
+				allocate := true
+				svc.Spec.AllocateLoadBalancerNodePorts = &allocate
+			},
+			expect: false,
+		},
 		{
 			description: "if load balancer IP changed",
 			mutate: func(svc *corev1.Service) {
@@ -629,6 +714,13 @@ func TestNodePortServiceChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if external IPs changed",
+			mutate: func(svc *corev1.Service) {
+				svc.Spec.ExternalIPs = []string{"192.0.2.10"}
+			},
+			expect: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -690,6 +782,14 @@ func TestContourStatusChangedChanged(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			description: "if resync changed",
+			current:     operatorv1alpha1.ContourStatus{},
+			mutate: func(status *operatorv1alpha1.ContourStatus) {
+				status.Resync = "2021-06-09T12:00:00Z"
+			},
+			expect: true,
+		},
 		{
 			description: "if a condition is added",
 			current:     operatorv1alpha1.ContourStatus{},
@@ -739,3 +839,198 @@ func TestContourStatusChangedChanged(t *testing.T) {
 		}
 	}
 }
+
+func TestNamespaceConfigChanged(t *testing.T) {
+	testCases := []struct {
+		description string
+		mutate      func(ns *corev1.Namespace)
+		expect      bool
+	}{
+		{
+			description: "if nothing changed",
+			mutate:      func(_ *corev1.Namespace) {},
+			expect:      false,
+		},
+		{
+			description: "if a label is added by another controller",
+			mutate: func(ns *corev1.Namespace) {
+				ns.Labels["kubernetes.io/metadata.name"] = ns.Name
+			},
+			expect: false,
+		},
+		{
+			description: "if a label is removed",
+			mutate: func(ns *corev1.Namespace) {
+				delete(ns.Labels, "pod-security.kubernetes.io/enforce")
+			},
+			expect: true,
+		},
+		{
+			description: "if an annotation changed",
+			mutate: func(ns *corev1.Namespace) {
+				ns.Annotations["team"] = "other"
+			},
+			expect: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		expected := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: testNs,
+				Labels: map[string]string{
+					operatorv1alpha1.OwningContourNameLabel: testName,
+					"pod-security.kubernetes.io/enforce":    "baseline",
+				},
+				Annotations: map[string]string{"team": "edge"},
+			},
+		}
+		mutated := expected.DeepCopy()
+		tc.mutate(mutated)
+		if updated, changed := equality.NamespaceConfigChanged(mutated, expected); changed != tc.expect {
+			t.Errorf("%s, expect NamespaceConfigChanged to be %t, got %t", tc.description, tc.expect, changed)
+		} else if changed {
+			if _, changedAgain := equality.NamespaceConfigChanged(updated, expected); changedAgain {
+				t.Errorf("%s, NamespaceConfigChanged does not behave as a fixed point function", tc.description)
+			}
+		}
+	}
+}
+
+func TestMergedAnnotations(t *testing.T) {
+	testCases := []struct {
+		description string
+		current     map[string]string
+		expected    map[string]string
+		merged      map[string]string
+		changed     bool
+	}{
+		{
+			description: "no expected annotations",
+			current:     map[string]string{"foo": "bar"},
+			merged:      map[string]string{"foo": "bar"},
+			changed:     false,
+		},
+		{
+			description: "expected annotations present",
+			current:     map[string]string{"foo": "bar", "baz": "qux"},
+			expected:    map[string]string{"foo": "bar"},
+			merged:      map[string]string{"foo": "bar", "baz": "qux"},
+			changed:     false,
+		},
+		{
+			description: "expected annotation missing",
+			current:     map[string]string{"baz": "qux"},
+			expected:    map[string]string{"foo": "bar"},
+			merged:      map[string]string{"foo": "bar", "baz": "qux"},
+			changed:     true,
+		},
+		{
+			description: "expected annotation differs",
+			current:     map[string]string{"foo": "baz"},
+			expected:    map[string]string{"foo": "bar"},
+			merged:      map[string]string{"foo": "bar"},
+			changed:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		merged, changed := equality.MergedAnnotations(tc.current, tc.expected)
+		if changed != tc.changed {
+			t.Errorf("%s, expect MergedAnnotations to be %t, got %t", tc.description, tc.changed, changed)
+		}
+		if !apiequality.Semantic.DeepEqual(merged, tc.merged) {
+			t.Errorf("%s, expected annotations %v, got %v", tc.description, tc.merged, merged)
+		}
+	}
+}
+
+func TestMergedOwnerReferences(t *testing.T) {
+	ref := metav1.OwnerReference{APIVersion: "v1", Kind: "Contour", Name: "foo", UID: "foo-uid"}
+	other := metav1.OwnerReference{APIVersion: "v1", Kind: "Bar", Name: "bar", UID: "bar-uid"}
+	renamed := ref
+	renamed.Name = "baz"
+
+	testCases := []struct {
+		description string
+		current     []metav1.OwnerReference
+		expected    []metav1.OwnerReference
+		merged      []metav1.OwnerReference
+		changed     bool
+	}{
+		{
+			description: "no expected owner references",
+			current:     []metav1.OwnerReference{other},
+			merged:      []metav1.OwnerReference{other},
+			changed:     false,
+		},
+		{
+			description: "expected owner reference present",
+			current:     []metav1.OwnerReference{other, ref},
+			expected:    []metav1.OwnerReference{ref},
+			merged:      []metav1.OwnerReference{other, ref},
+			changed:     false,
+		},
+		{
+			description: "expected owner reference missing",
+			current:     []metav1.OwnerReference{other},
+			expected:    []metav1.OwnerReference{ref},
+			merged:      []metav1.OwnerReference{other, ref},
+			changed:     true,
+		},
+		{
+			description: "expected owner reference differs",
+			current:     []metav1.OwnerReference{renamed},
+			expected:    []metav1.OwnerReference{ref},
+			merged:      []metav1.OwnerReference{ref},
+			changed:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		merged, changed := equality.MergedOwnerReferences(tc.current, tc.expected)
+		if changed != tc.changed {
+			t.Errorf("%s, expect MergedOwnerReferences to be %t, got %t", tc.description, tc.changed, changed)
+		}
+		if !apiequality.Semantic.DeepEqual(merged, tc.merged) {
+			t.Errorf("%s, expected owner references %v, got %v", tc.description, tc.merged, merged)
+		}
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	current := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "envoy",
+			"resourceVersion": "1",
+			"labels":          map[string]interface{}{"app": "envoy"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"image": "envoy:v1"}},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(1)},
+	}
+	updated := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "envoy",
+			"resourceVersion": "2",
+			"labels":          map[string]interface{}{"app": "envoy", "owner": "contour"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"image": "envoy:v2"}},
+			},
+		},
+	}
+	expected := []string{"metadata.labels.owner", "spec.template.containers"}
+	if fields := equality.ChangedFields(current, updated); !apiequality.Semantic.DeepEqual(fields, expected) {
+		t.Errorf("expected changed fields %v, got %v", expected, fields)
+	}
+	if fields := equality.ChangedFields(current, current); len(fields) != 0 {
+		t.Errorf("expected no changed fields, got %v", fields)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package maintenance evaluates the maintenance windows of a Contour's update
+// policy.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+)
+
+// maxDuration is the maximum duration of a maintenance window.
+const maxDuration = 7 * 24 * time.Hour
+
+// Parse returns the start of window as an offset from midnight UTC and its
+// duration, or an error if window is invalid.
+func Parse(window operatorv1alpha1.MaintenanceWindow) (time.Duration, time.Duration, error) {
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maintenance window start %q: %w", window.Start, err)
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid maintenance window duration %q: %w", window.Duration, err)
+	}
+	if duration <= 0 || duration > maxDuration {
+		return 0, 0, fmt.Errorf("invalid maintenance window duration %q: must be greater than 0 and at most %s",
+			window.Duration, maxDuration)
+	}
+	offset := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	return offset, duration, nil
+}
+
+// Next returns true if now is inside one of windows, or otherwise how long
+// until the next window starts. Invalid windows are ignored.
+func Next(windows []operatorv1alpha1.MaintenanceWindow, now time.Time) (bool, time.Duration) {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var wait time.Duration
+	for _, w := range windows {
+		offset, duration, err := Parse(w)
+		if err != nil {
+			continue
+		}
+		// A window lasting up to a week may have started on any of the last 7 days.
+		for d := -7; d <= 7; d++ {
+			start := midnight.AddDate(0, 0, d).Add(offset)
+			if !startsOn(w, start.Weekday()) {
+				continue
+			}
+			switch {
+			case !now.Before(start) && now.Before(start.Add(duration)):
+				return true, 0
+			case start.After(now) && (wait == 0 || start.Sub(now) < wait):
+				wait = start.Sub(now)
+			}
+		}
+	}
+	return false, wait
+}
+
+// startsOn returns true if window starts on day.
+func startsOn(window operatorv1alpha1.MaintenanceWindow, day time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, d := range window.Days {
+		if string(d) == day.String() {
+			return true
+		}
+	}
+	return false
+}
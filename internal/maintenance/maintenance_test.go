@@ -0,0 +1,65 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+)
+
+func TestNext(t *testing.T) {
+	// 2021-06-07 is a Monday.
+	monday := time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC)
+	windows := []operatorv1alpha1.MaintenanceWindow{
+		{Start: "02:00", Duration: "2h"},
+		{Days: []operatorv1alpha1.Weekday{"Saturday"}, Start: "22:00", Duration: "26h"},
+	}
+
+	testCases := []struct {
+		description string
+		now         time.Time
+		open        bool
+		wait        time.Duration
+	}{
+		{
+			description: "before the daily window",
+			now:         monday.Add(time.Hour),
+			wait:        time.Hour,
+		},
+		{
+			description: "inside the daily window",
+			now:         monday.Add(3 * time.Hour),
+			open:        true,
+		},
+		{
+			description: "after the daily window",
+			now:         monday.Add(5 * time.Hour),
+			wait:        21 * time.Hour,
+		},
+		{
+			description: "inside the weekend window spanning midnight",
+			now:         monday.Add(-time.Hour),
+			open:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		open, wait := Next(windows, tc.now)
+		if open != tc.open || wait != tc.wait {
+			t.Errorf("%q: expected open %t and wait %s, got %t and %s", tc.description, tc.open, tc.wait, open, wait)
+		}
+	}
+}
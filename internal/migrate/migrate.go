@@ -0,0 +1,342 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// contourName is the name of the Contour Deployment, Service and ConfigMap
+	// of the example manifest.
+	contourName = "contour"
+	// envoyName is the name of the Envoy DaemonSet and Service of the example
+	// manifest.
+	envoyName = "envoy"
+	// certgenName is the name of the certgen ServiceAccount and Role of the
+	// example manifest.
+	certgenName = "contour-certgen"
+	// contourContainerName and envoyContainerName are the names of the Contour
+	// and Envoy containers of the example manifest.
+	contourContainerName = "contour"
+	envoyContainerName   = "envoy"
+)
+
+// Action is what happens to a resource of an existing installation once the
+// migrated Contour is created.
+type Action string
+
+const (
+	// AdoptAction means the resource is unowned and is adopted by the Contour.
+	AdoptAction Action = "adopt"
+	// CreateAction means the resource does not exist and is created by the operator.
+	CreateAction Action = "create"
+	// SkipAction means the resource is owned by another Contour and is left unchanged.
+	SkipAction Action = "skip"
+	// RemoveAction means the resource is not managed by the operator and should
+	// be removed once the migration is complete.
+	RemoveAction Action = "remove"
+)
+
+// Step is the Action for a resource of an existing installation.
+type Step struct {
+	Action    Action
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Plan is the Contour equivalent to an existing installation and the steps
+// taken to migrate the installation to it.
+type Plan struct {
+	Contour  *operatorv1alpha1.Contour
+	Steps    []Step
+	Warnings []string
+}
+
+// Options are the options of a migration.
+type Options struct {
+	// Namespace is the namespace of the existing installation.
+	Namespace string
+	// ContourName and ContourNamespace are the name and namespace of the
+	// generated Contour.
+	ContourName      string
+	ContourNamespace string
+}
+
+// Inspect returns the Plan for migrating the installation of the example
+// manifest in opts.Namespace to an operator-managed Contour.
+func Inspect(ctx context.Context, cli client.Client, opts Options) (*Plan, error) {
+	ns := opts.Namespace
+	deploy := &appsv1.Deployment{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: contourName}, deploy); err != nil {
+		return nil, fmt.Errorf("failed to get contour deployment %s/%s: %w", ns, contourName, err)
+	}
+	ds := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: envoyName}, ds); err != nil {
+		return nil, fmt.Errorf("failed to get envoy daemonset %s/%s: %w", ns, envoyName, err)
+	}
+	svc := &corev1.Service{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: envoyName}, svc); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get envoy service %s/%s: %w", ns, envoyName, err)
+		}
+		svc = nil
+	}
+
+	plan := &Plan{}
+	plan.Contour = desiredContour(opts, deploy, ds, svc, plan)
+	if err := plan.addSteps(ctx, cli, ns); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// desiredContour returns the Contour equivalent to deploy, ds and svc, adding
+// a warning to plan for each setting that is not migrated.
+func desiredContour(opts Options, deploy *appsv1.Deployment, ds *appsv1.DaemonSet, svc *corev1.Service, plan *Plan) *operatorv1alpha1.Contour {
+	cntr := &operatorv1alpha1.Contour{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: operatorv1alpha1.GroupVersion.String(),
+			Kind:       "Contour",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: opts.ContourNamespace,
+			Name:      opts.ContourName,
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{
+				Name: opts.Namespace,
+			},
+			AdoptExistingResources: true,
+		},
+	}
+	if deploy.Spec.Replicas != nil {
+		cntr.Spec.Replicas = *deploy.Spec.Replicas
+	}
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		if c.Name == contourContainerName {
+			plan.warnf("run the operator with --contour-image=%s to keep the contour image", c.Image)
+		}
+	}
+
+	envoy := &cntr.Spec.NetworkPublishing.Envoy
+	for _, c := range ds.Spec.Template.Spec.Containers {
+		if c.Name != envoyContainerName {
+			continue
+		}
+		plan.warnf("run the operator with --envoy-image=%s to keep the envoy image", c.Image)
+		for _, p := range c.Ports {
+			if p.Name == "http" || p.Name == "https" {
+				envoy.ContainerPorts = append(envoy.ContainerPorts, operatorv1alpha1.ContainerPort{
+					Name:       p.Name,
+					PortNumber: p.ContainerPort,
+				})
+			}
+		}
+	}
+
+	if svc == nil {
+		envoy.Type = operatorv1alpha1.ClusterIPServicePublishingType
+		plan.warnf("envoy service %s/%s does not exist, so envoy is published with a ClusterIP service",
+			opts.Namespace, envoyName)
+		return cntr
+	}
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		envoy.Type = operatorv1alpha1.NodePortServicePublishingType
+		for _, p := range svc.Spec.Ports {
+			if p.NodePort != 0 {
+				nodePort := p.NodePort
+				envoy.NodePorts = append(envoy.NodePorts, operatorv1alpha1.NodePort{Name: p.Name, PortNumber: &nodePort})
+			}
+		}
+	case corev1.ServiceTypeClusterIP:
+		envoy.Type = operatorv1alpha1.ClusterIPServicePublishingType
+	default:
+		envoy.Type = operatorv1alpha1.LoadBalancerServicePublishingType
+		envoy.LoadBalancer = loadBalancerStrategy(svc)
+	}
+
+	desired := objsvc.DesiredEnvoyService(cntr)
+	var dropped []string
+	for k := range svc.Annotations {
+		if _, ok := desired.Annotations[k]; !ok {
+			dropped = append(dropped, k)
+		}
+	}
+	sort.Strings(dropped)
+	for _, k := range dropped {
+		plan.warnf("envoy service annotation %s is not migrated and will be removed", k)
+	}
+	return cntr
+}
+
+// loadBalancerStrategy returns the load balancer strategy of svc, detecting
+// the provider and scope from its annotations.
+func loadBalancerStrategy(svc *corev1.Service) operatorv1alpha1.LoadBalancerStrategy {
+	strategy := operatorv1alpha1.LoadBalancerStrategy{
+		Scope: operatorv1alpha1.ExternalLoadBalancer,
+		ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+			Type: operatorv1alpha1.AWSLoadBalancerProvider,
+		},
+	}
+	for provider, annotations := range objsvc.InternalLBAnnotations {
+		for k, v := range annotations {
+			if svc.Annotations[k] == v {
+				strategy.Scope = operatorv1alpha1.InternalLoadBalancer
+				strategy.ProviderParameters.Type = provider
+			}
+		}
+	}
+	if strings.EqualFold(svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"], "nlb") {
+		strategy.ProviderParameters.Type = operatorv1alpha1.AWSLoadBalancerProvider
+		strategy.ProviderParameters.AWS = &operatorv1alpha1.AWSLoadBalancerParameters{
+			Type: operatorv1alpha1.AWSNetworkLoadBalancer,
+		}
+	}
+	return strategy
+}
+
+// addSteps adds a Step for each resource of the installation in ns.
+func (p *Plan) addSteps(ctx context.Context, cli client.Client, ns string) error {
+	managed := []struct {
+		kind string
+		name string
+		obj  client.Object
+	}{
+		{"Namespace", ns, &corev1.Namespace{}},
+		{"ServiceAccount", objects.ContourRbacName, &corev1.ServiceAccount{}},
+		{"ServiceAccount", objects.EnvoyRbacName, &corev1.ServiceAccount{}},
+		{"ServiceAccount", objects.CertGenRbacName, &corev1.ServiceAccount{}},
+		{"Role", objects.CertGenRbacName, &rbacv1.Role{}},
+		{"RoleBinding", objects.ContourRbacName, &rbacv1.RoleBinding{}},
+		{"ConfigMap", contourName, &corev1.ConfigMap{}},
+		{"Deployment", contourName, &appsv1.Deployment{}},
+		{"DaemonSet", envoyName, &appsv1.DaemonSet{}},
+		{"Service", contourName, &corev1.Service{}},
+		{"Service", envoyName, &corev1.Service{}},
+	}
+	for _, r := range managed {
+		key := types.NamespacedName{Namespace: ns, Name: r.name}
+		stepNs := ns
+		if r.kind == "Namespace" {
+			key.Namespace = ""
+			stepNs = ""
+		}
+		step := Step{Kind: r.kind, Namespace: stepNs, Name: r.name}
+		switch err := cli.Get(ctx, key, r.obj); {
+		case errors.IsNotFound(err):
+			step.Action = CreateAction
+		case err != nil:
+			return fmt.Errorf("failed to get %s %s: %w", r.kind, key, err)
+		case objcontour.Unowned(r.obj):
+			step.Action = AdoptAction
+		default:
+			step.Action = SkipAction
+		}
+		p.Steps = append(p.Steps, step)
+	}
+
+	// The operator names cluster-scoped RBAC for the namespace and the certgen
+	// Job for the contour version, so these are replaced instead of adopted.
+	for _, r := range []struct {
+		kind string
+		obj  client.Object
+	}{
+		{"ClusterRole", &rbacv1.ClusterRole{}},
+		{"ClusterRoleBinding", &rbacv1.ClusterRoleBinding{}},
+	} {
+		switch err := cli.Get(ctx, types.NamespacedName{Name: objects.ContourRbacName}, r.obj); {
+		case errors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("failed to get %s %s: %w", r.kind, objects.ContourRbacName, err)
+		case objcontour.Unowned(r.obj):
+			p.Steps = append(p.Steps, Step{Action: RemoveAction, Kind: r.kind, Name: objects.ContourRbacName})
+		}
+	}
+	jobs := &batchv1.JobList{}
+	if err := cli.List(ctx, jobs, client.InNamespace(ns)); err != nil {
+		return fmt.Errorf("failed to list jobs in namespace %s: %w", ns, err)
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if strings.HasPrefix(job.Name, certgenName) && objcontour.Unowned(job) {
+			p.Steps = append(p.Steps, Step{Action: RemoveAction, Kind: "Job", Namespace: ns, Name: job.Name})
+		}
+	}
+	return nil
+}
+
+// warnf adds a warning to p.
+func (p *Plan) warnf(format string, args ...interface{}) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Write writes p to w as the YAML manifest of its Contour, preceded by its
+// steps and warnings as comments.
+func (p *Plan) Write(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Migration plan:\n")
+	for _, s := range p.Steps {
+		name := s.Name
+		if s.Namespace != "" {
+			name = s.Namespace + "/" + s.Name
+		}
+		fmt.Fprintf(&b, "#   %-6s %s %s\n", s.Action, s.Kind, name)
+	}
+	for _, warning := range p.Warnings {
+		fmt.Fprintf(&b, "# WARNING: %s\n", warning)
+	}
+
+	data, err := yaml.Marshal(p.Contour)
+	if err != nil {
+		return fmt.Errorf("failed to marshal contour %s/%s: %w", p.Contour.Namespace, p.Contour.Name, err)
+	}
+	// Drop the empty status and creation timestamp.
+	manifest := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to unmarshal contour %s/%s: %w", p.Contour.Namespace, p.Contour.Name, err)
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	if data, err = yaml.Marshal(manifest); err != nil {
+		return fmt.Errorf("failed to marshal contour %s/%s: %w", p.Contour.Namespace, p.Contour.Name, err)
+	}
+	b.Write(data)
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
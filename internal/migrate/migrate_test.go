@@ -0,0 +1,143 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/operator"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInspect(t *testing.T) {
+	ns := "projectcontour"
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: ns, Name: name}
+	}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: meta(contourName),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: contourContainerName, Image: "ghcr.io/projectcontour/contour:v1.15.0"}},
+				},
+			},
+		},
+	}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: meta(envoyName),
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  envoyContainerName,
+						Image: "docker.io/envoyproxy/envoy:v1.18.3",
+						Ports: []corev1.ContainerPort{
+							{Name: "http", ContainerPort: 8080, HostPort: 80},
+							{Name: "https", ContainerPort: 8443, HostPort: 443},
+						},
+					}},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: meta(envoyName),
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, NodePort: 30080},
+				{Name: "https", Port: 443, NodePort: 30443},
+			},
+		},
+	}
+	owned := &corev1.ServiceAccount{ObjectMeta: meta(envoyName)}
+	owned.Labels = map[string]string{operatorv1alpha1.OwningContourNameLabel: "other"}
+	cli := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme()).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		deploy, ds, svc, owned,
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: contourName}},
+		&batchv1.Job{ObjectMeta: meta(certgenName + "-v1.15.0")},
+	).Build()
+
+	plan, err := Inspect(context.Background(), cli, Options{Namespace: ns, ContourName: "contour", ContourNamespace: "default"})
+	if err != nil {
+		t.Fatalf("failed with error: %#v", err)
+	}
+
+	cntr := plan.Contour
+	if cntr.Spec.Replicas != 3 {
+		t.Errorf("expected 3 replicas, got %d", cntr.Spec.Replicas)
+	}
+	if !cntr.Spec.AdoptExistingResources || cntr.Spec.Namespace.Name != ns {
+		t.Errorf("expected contour to adopt resources in namespace %s, got %#v", ns, cntr.Spec)
+	}
+	envoy := cntr.Spec.NetworkPublishing.Envoy
+	if envoy.Type != operatorv1alpha1.NodePortServicePublishingType || len(envoy.NodePorts) != 2 {
+		t.Errorf("expected nodeport publishing with 2 node ports, got %#v", envoy)
+	}
+	if len(envoy.ContainerPorts) != 2 || envoy.ContainerPorts[0].PortNumber != 8080 {
+		t.Errorf("expected container ports 8080 and 8443, got %#v", envoy.ContainerPorts)
+	}
+
+	expected := map[string]Action{
+		"Namespace/projectcontour":       AdoptAction,
+		"Deployment/contour":             AdoptAction,
+		"DaemonSet/envoy":                AdoptAction,
+		"Service/envoy":                  AdoptAction,
+		"ServiceAccount/envoy":           SkipAction,
+		"ServiceAccount/contour":         CreateAction,
+		"ClusterRole/contour":            RemoveAction,
+		"Job/contour-certgen-v1.15.0":    RemoveAction,
+		"ClusterRoleBinding/contour":     "",
+		"ConfigMap/contour":              CreateAction,
+		"RoleBinding/contour":            CreateAction,
+		"Role/contour-certgen":           CreateAction,
+		"ServiceAccount/contour-certgen": CreateAction,
+	}
+	actual := map[string]Action{}
+	for _, s := range plan.Steps {
+		actual[s.Kind+"/"+s.Name] = s.Action
+	}
+	for k, v := range expected {
+		if actual[k] != v {
+			t.Errorf("expected action %q for %s, got %q", v, k, actual[k])
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := plan.Write(out); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+	for _, s := range []string{"kind: Contour", "adoptExistingResources: true", "--contour-image=ghcr.io/projectcontour/contour:v1.15.0"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected plan to contain %q, got:\n%s", s, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "status:") {
+		t.Errorf("expected plan not to contain a status, got:\n%s", out.String())
+	}
+}
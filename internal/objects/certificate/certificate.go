@@ -0,0 +1,217 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultCertificateName is the name of the default certificate, its secret
+	// and its delegation.
+	defaultCertificateName = "default-certificate"
+)
+
+var (
+	// GroupVersionKind is the GroupVersionKind of a cert-manager Certificate.
+	GroupVersionKind = schema.GroupVersionKind{
+		Group:   "cert-manager.io",
+		Version: "v1",
+		Kind:    "Certificate",
+	}
+	// DelegationGroupVersionKind is the GroupVersionKind of a Contour
+	// TLSCertificateDelegation.
+	DelegationGroupVersionKind = schema.GroupVersionKind{
+		Group:   "projectcontour.io",
+		Version: "v1",
+		Kind:    "TLSCertificateDelegation",
+	}
+)
+
+// APIAvailable returns true if the cert-manager.io API group is served by the
+// cluster that cli is connected to.
+func APIAvailable(cli client.Client) (bool, error) {
+	return objutil.APIAvailable(cli, GroupVersionKind)
+}
+
+// SecretName returns the name of the secret of the default certificate of
+// contour, in the namespace of the contour's Contour and Envoy.
+func SecretName(contour *operatorv1alpha1.Contour) string {
+	return objcontour.ResourceName(contour, defaultCertificateName)
+}
+
+// EnsureDefaultCertificate ensures the cert-manager Certificate and the
+// TLSCertificateDelegation of the default certificate of contour exist, or are
+// deleted if contour has no default certificate.
+func EnsureDefaultCertificate(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	if contour.Spec.DefaultCertificate == nil {
+		return EnsureDefaultCertificateDeleted(ctx, cli, contour)
+	}
+	available, err := APIAvailable(cli)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("the default certificate requires cert-manager; the %s API group is not served by the cluster",
+			GroupVersionKind.Group)
+	}
+	for _, desired := range []*unstructured.Unstructured{DesiredCertificate(contour), DesiredDelegation(contour)} {
+		if err := ensure(ctx, cli, contour, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureDefaultCertificateDeleted ensures the cert-manager Certificate and the
+// TLSCertificateDelegation of the default certificate of contour are deleted if
+// Contour owner labels exist. The secret issued by cert-manager is kept, as
+// cert-manager does when a Certificate is deleted.
+func EnsureDefaultCertificateDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	for _, gvk := range []schema.GroupVersionKind{GroupVersionKind, DelegationGroupVersionKind} {
+		current, err := current(ctx, cli, gvk, contour)
+		if err != nil {
+			if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		if objcontour.IsOwned(current, contour) {
+			if err := cli.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete %s %s/%s: %w", gvk.Kind, current.GetNamespace(),
+					current.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DesiredCertificate returns the desired cert-manager Certificate of the
+// default certificate of contour.
+func DesiredCertificate(contour *operatorv1alpha1.Contour) *unstructured.Unstructured {
+	params := contour.Spec.DefaultCertificate
+	var dnsNames []interface{}
+	for _, name := range params.DNSNames {
+		dnsNames = append(dnsNames, name)
+	}
+	issuerRef := map[string]interface{}{
+		"name":  params.IssuerRef.Name,
+		"kind":  "Issuer",
+		"group": "cert-manager.io",
+	}
+	if params.IssuerRef.Kind != "" {
+		issuerRef["kind"] = params.IssuerRef.Kind
+	}
+	if params.IssuerRef.Group != "" {
+		issuerRef["group"] = params.IssuerRef.Group
+	}
+	cert := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"secretName": SecretName(contour),
+				"dnsNames":   dnsNames,
+				"issuerRef":  issuerRef,
+			},
+		},
+	}
+	cert.SetGroupVersionKind(GroupVersionKind)
+	setMetadata(cert, contour)
+	return cert
+}
+
+// DesiredDelegation returns the desired TLSCertificateDelegation of the secret
+// of the default certificate of contour to its root namespaces, or to all
+// namespaces if it has no root namespaces.
+func DesiredDelegation(contour *operatorv1alpha1.Contour) *unstructured.Unstructured {
+	targets := []interface{}{"*"}
+	if len(contour.Spec.RootNamespaces) > 0 {
+		targets = nil
+		for _, ns := range contour.Spec.RootNamespaces {
+			targets = append(targets, ns)
+		}
+	}
+	delegation := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"delegations": []interface{}{
+					map[string]interface{}{
+						"secretName":       SecretName(contour),
+						"targetNamespaces": targets,
+					},
+				},
+			},
+		},
+	}
+	delegation.SetGroupVersionKind(DelegationGroupVersionKind)
+	setMetadata(delegation, contour)
+	return delegation
+}
+
+// setMetadata sets the name, namespace and labels of obj for contour.
+func setMetadata(obj *unstructured.Unstructured, contour *operatorv1alpha1.Contour) {
+	obj.SetName(SecretName(contour))
+	obj.SetNamespace(contour.Spec.Namespace.Name)
+	obj.SetLabels(objcontour.OwnerLabels(contour))
+	objcontour.ApplyResourceMetadata(obj, contour)
+}
+
+// ensure creates desired, or updates it if it does not match the current
+// object owned by contour.
+func ensure(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, desired *unstructured.Unstructured) error {
+	kind := desired.GetKind()
+	current, err := current(ctx, cli, desired.GroupVersionKind(), contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := cli.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create %s %s/%s: %w", kind, desired.GetNamespace(), desired.GetName(), err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to get %s %s/%s: %w", kind, desired.GetNamespace(), desired.GetName(), err)
+	}
+	if !objcontour.IsOwned(current, contour) {
+		return nil
+	}
+	if updated, changed := equality.SpecChanged(current, desired); changed {
+		if err := cli.Update(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update %s %s/%s: %w", kind, desired.GetNamespace(), desired.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// current returns the current object of kind gvk of the default certificate
+// of contour.
+func current(ctx context.Context, cli client.Client, gvk schema.GroupVersionKind, contour *operatorv1alpha1.Contour) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(gvk)
+	key := types.NamespacedName{Namespace: contour.Spec.Namespace.Name, Name: SecretName(contour)}
+	if err := cli.Get(ctx, key, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
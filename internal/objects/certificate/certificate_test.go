@@ -0,0 +1,107 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureDefaultCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{GroupVersionKind, DelegationGroupVersionKind} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+	}
+	ctx := context.Background()
+	cli := &objutil.MappedClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Mapper: mapper}
+
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.DefaultCertificate = &operatorv1alpha1.DefaultCertificate{
+		IssuerRef: operatorv1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		DNSNames:  []string{"*.example.com"},
+	}
+	if err := EnsureDefaultCertificate(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := current(ctx, cli, GroupVersionKind, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName"); name != SecretName(cntr) {
+		t.Errorf("expected certificate secret %s, got %s", SecretName(cntr), name)
+	}
+	if kind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind"); kind != "ClusterIssuer" {
+		t.Errorf("expected certificate to be issued by a ClusterIssuer, got %s", kind)
+	}
+
+	// The delegation follows the root namespaces of the contour.
+	delegation, err := current(ctx, cli, DelegationGroupVersionKind, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTargetNamespaces(t, delegation, []interface{}{"*"})
+	cntr.Spec.RootNamespaces = []string{"team-a", "team-b"}
+	if err := EnsureDefaultCertificate(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if delegation, err = current(ctx, cli, DelegationGroupVersionKind, cntr); err != nil {
+		t.Fatal(err)
+	}
+	checkTargetNamespaces(t, delegation, []interface{}{"team-a", "team-b"})
+
+	cntr.Spec.DefaultCertificate = nil
+	if err := EnsureDefaultCertificate(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	for _, gvk := range []schema.GroupVersionKind{GroupVersionKind, DelegationGroupVersionKind} {
+		if _, err := current(ctx, cli, gvk, cntr); err == nil {
+			t.Errorf("expected %v to be deleted", gvk)
+		}
+	}
+}
+
+// checkTargetNamespaces checks the target namespaces of the delegation.
+func checkTargetNamespaces(t *testing.T, delegation *unstructured.Unstructured, expected []interface{}) {
+	t.Helper()
+	delegations, _, _ := unstructured.NestedSlice(delegation.Object, "spec", "delegations")
+	if len(delegations) != 1 {
+		t.Fatalf("expected 1 delegation, got %d", len(delegations))
+	}
+	targets, _, _ := unstructured.NestedSlice(delegations[0].(map[string]interface{}), "targetNamespaces")
+	if len(targets) != len(expected) {
+		t.Fatalf("expected target namespaces %v, got %v", expected, targets)
+	}
+	for i := range targets {
+		if targets[i] != expected[i] {
+			t.Errorf("expected target namespaces %v, got %v", expected, targets)
+		}
+	}
+}
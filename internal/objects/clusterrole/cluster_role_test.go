@@ -61,4 +61,12 @@ func TestDesiredClusterRole(t *testing.T) {
 		operatorv1alpha1.OwningContourNsLabel:   cntr.Namespace,
 	}
 	checkClusterRoleLabels(t, cr, ownerLabels)
+
+	// Contour watches namespaced resources in all namespaces, so root
+	// namespaces do not narrow the cluster role.
+	cntr.Spec.RootNamespaces = []string{"test-root-ns"}
+	scoped := desiredClusterRole(name, cntr)
+	if !apiequality.Semantic.DeepEqual(scoped.Rules, cr.Rules) {
+		t.Errorf("expected root namespaces to keep the cluster role rules, got %v", scoped.Rules)
+	}
 }
@@ -17,9 +17,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"text/template"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objcert "github.com/projectcontour/contour-operator/internal/objects/certificate"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 	objgw "github.com/projectcontour/contour-operator/internal/objects/gateway"
 	"github.com/projectcontour/contour-operator/pkg/labels"
@@ -38,8 +41,41 @@ const (
 	// [TODO] danehans: Remove and use contour.Name when
 	// https://github.com/projectcontour/contour/issues/2122 is fixed.
 	ContourCfgMapName = "contour"
+	// ContourCfgFileName is the key of the Contour configuration file in
+	// Contour's ConfigMap.
+	ContourCfgFileName = "contour.yaml"
 )
 
+// contourInstanceField is the name of the JSON access log field holding the
+// namespace/name of the Contour.
+const contourInstanceField = "contour_instance"
+
+// defaultJSONFields are Contour's default JSON access log fields, which are
+// extended when the contour_instance field is added without a field list.
+var defaultJSONFields = []string{
+	"@timestamp",
+	"authority",
+	"bytes_received",
+	"bytes_sent",
+	"downstream_local_address",
+	"downstream_remote_address",
+	"duration",
+	"method",
+	"path",
+	"protocol",
+	"request_id",
+	"requested_server_name",
+	"response_code",
+	"response_flags",
+	"uber_trace_id",
+	"upstream_cluster",
+	"upstream_host",
+	"upstream_local_address",
+	"upstream_service_time",
+	"user_agent",
+	"x_forwarded_for",
+}
+
 var contourCfgTemplate = template.Must(template.New("contour.yaml").Parse(`
 #
 # server:
@@ -64,7 +100,9 @@ gateway:
 # "Tranfer-Encoding: chunked" is also set.
 # disableAllowChunkedLength: false
 # Disable HTTPProxy permitInsecure field
-disablePermitInsecure: false
+disablePermitInsecure: false{{with .ServerHeaderTransformation}}
+# How Envoy handles the Server header of responses
+server-header-transformation: {{.}}{{end}}
 tls:
 # minimum TLS version that Contour will negotiate
 # minimum-protocol-version: "1.2"
@@ -78,19 +116,29 @@ tls:
 # Defines the Kubernetes name/namespace matching a secret to use
 # as the fallback certificate when requests which don't match the
 # SNI defined for a vhost.
-  fallback-certificate:
+  fallback-certificate:{{if .FallbackCertificateName}}
+    name: {{.FallbackCertificateName}}
+    namespace: {{.FallbackCertificateNamespace}}{{else}}
 #   name: fallback-secret-name
-#   namespace: projectcontour
+#   namespace: projectcontour{{end}}
   envoy-client-certificate:
 #   name: envoy-client-cert-secret-name
 #   namespace: projectcontour
-# The following config shows the defaults for the leader election.
+# The following config shows the defaults for the leader election.{{with .LeaderElection}}
+leaderelection:
+  configmap-name: {{.Name}}
+  configmap-namespace: {{.Namespace}}{{with .LeaseDuration}}
+  lease-duration: {{.}}{{end}}{{with .RenewDeadline}}
+  renew-deadline: {{.}}{{end}}{{with .RetryPeriod}}
+  retry-period: {{.}}{{end}}{{else}}
 # leaderelection:
 #   configmap-name: leader-elect
-#   configmap-namespace: projectcontour
+#   configmap-namespace: projectcontour{{end}}
 ### Logging options
 # Default setting
-accesslog-format: envoy
+accesslog-format: {{with .AccessLogFormat}}{{.}}{{else}}envoy{{end}}{{with .AccessLogLevel}}
+# Minimum response class of logged requests
+accesslog-level: {{.}}{{end}}
 # To enable JSON logging in Envoy
 # accesslog-format: json
 # The default fields that will be logged are specified below.
@@ -118,33 +166,68 @@ accesslog-format: envoy
 #   - "upstream_local_address"
 #   - "upstream_service_time"
 #   - "user_agent"
-#   - "x_forwarded_for"
+#   - "x_forwarded_for"{{with .JSONFields}}
+json-fields:{{range .}}
+  - {{printf "%q" .}}{{end}}{{end}}
 #
 # default-http-versions:
 # - "HTTP/2"
 # - "HTTP/1.1"
 #
-# The following shows the default proxy timeout settings.
+# The following shows the default proxy timeout settings.{{with .Timeouts}}
+timeouts:{{with .RequestTimeout}}
+  request-timeout: {{.}}{{end}}{{with .ConnectionIdleTimeout}}
+  connection-idle-timeout: {{.}}{{end}}{{with .StreamIdleTimeout}}
+  stream-idle-timeout: {{.}}{{end}}{{with .MaxConnectionDuration}}
+  max-connection-duration: {{.}}{{end}}{{with .DelayedCloseTimeout}}
+  delayed-close-timeout: {{.}}{{end}}{{with .ConnectionShutdownGracePeriod}}
+  connection-shutdown-grace-period: {{.}}{{end}}{{else}}
 # timeouts:
 #   request-timeout: infinity
 #   connection-idle-timeout: 60s
 #   stream-idle-timeout: 5m
 #   max-connection-duration: infinity
 #   delayed-close-timeout: 1s
-#   connection-shutdown-grace-period: 5s
+#   connection-shutdown-grace-period: 5s{{end}}
 #
-# Envoy cluster settings.
+# Envoy cluster settings.{{if or .DNSLookupFamily .CircuitBreakers}}
+cluster:{{with .DNSLookupFamily}}
+  dns-lookup-family: {{.}}{{end}}{{with .CircuitBreakers}}
+  circuit-breakers:{{with .MaxConnections}}
+    max-connections: {{.}}{{end}}{{with .MaxPendingRequests}}
+    max-pending-requests: {{.}}{{end}}{{with .MaxRequests}}
+    max-requests: {{.}}{{end}}{{with .MaxRetries}}
+    max-retries: {{.}}{{end}}{{with .PerHostMaxConnections}}
+    per-host-max-connections: {{.}}{{end}}{{end}}{{else}}
 # cluster:
 #   configure the cluster dns lookup family
 #   valid options are: auto (default), v4, v6
-#   dns-lookup-family: auto
+#   dns-lookup-family: auto{{end}}
 #
 # Envoy network settings.
 # network:
 #   Configure the number of additional ingress proxy hops from the
 #   right side of the x-forwarded-for HTTP header to trust.
 #   num-trusted-hops: 0
-`))
+#
+# Global header policies applied to all routes.{{with .Policy}}
+policy:{{with .RequestHeaders}}
+  request-headers:{{template "headers" .}}{{end}}{{with .ResponseHeaders}}
+  response-headers:{{template "headers" .}}{{end}}{{else}}
+# policy:
+#   request-headers:
+#     set:
+#       "X-Environment": "production"
+#     remove:
+#     - "X-Debug"
+#   response-headers:
+#     set: {}
+#     remove: []{{end}}
+{{define "headers"}}{{if .Set}}
+    set:{{range $name, $value := .Set}}
+      {{printf "%q" $name}}: {{printf "%q" $value}}{{end}}{{end}}{{if .Remove}}
+    remove:{{range .Remove}}
+    - {{printf "%q" .}}{{end}}{{end}}{{end}}`))
 
 // Config contains everything needed to manage a ConfigMap.
 type Config struct {
@@ -154,6 +237,15 @@ type Config struct {
 	Name string
 	// Labels are labels to apply to the ConfigMap.
 	Labels map[string]string
+	// ResourceLabels and ResourceAnnotations are added to the ConfigMap
+	// without overriding Labels.
+	ResourceLabels      map[string]string
+	ResourceAnnotations map[string]string
+	// OwnerReferences are the owner references of the ConfigMap.
+	OwnerReferences []metav1.OwnerReference
+	// Adopt is true if an existing ConfigMap without owner labels is managed
+	// as if it had Labels.
+	Adopt bool
 	// Contour contains Contour configuration parameters.
 	Contour contourConfig
 }
@@ -164,6 +256,51 @@ type contourConfig struct {
 	GatewayNamespace string
 	// GatewayName is the Gateway name Contour should watch.
 	GatewayName string
+	// LeaderElection is the leader election of Contour. If nil, Contour's
+	// defaults are used.
+	LeaderElection *leaderElection
+	// Policy is the global header policy Contour applies to all routes.
+	// If nil, no policy is rendered.
+	Policy *operatorv1alpha1.PolicyParameters
+	// Timeouts are the proxy timeouts of Contour. If nil, Contour's defaults
+	// are used.
+	Timeouts *operatorv1alpha1.TimeoutParameters
+	// CircuitBreakers are the default circuit breaker thresholds of upstream
+	// clusters. If nil, Envoy's defaults are used.
+	CircuitBreakers *operatorv1alpha1.CircuitBreakerParameters
+	// DNSLookupFamily is the IP address family used to resolve upstream
+	// names. If empty, Contour's default is used.
+	DNSLookupFamily operatorv1alpha1.DNSLookupFamilyType
+	// ServerHeaderTransformation is how Envoy handles the Server header of
+	// responses. If empty, Contour's default is used.
+	ServerHeaderTransformation operatorv1alpha1.ServerHeaderTransformationType
+	// AccessLogLevel is the minimum response class of logged requests. If
+	// empty, Contour's default is used.
+	AccessLogLevel operatorv1alpha1.AccessLogLevel
+	// AccessLogFormat is the format of access logs. If empty, Contour's
+	// default is used.
+	AccessLogFormat operatorv1alpha1.AccessLogFormat
+	// JSONFields are the fields of JSON access logs. If empty, Contour's
+	// default fields are used.
+	JSONFields []string
+	// FallbackCertificateName and FallbackCertificateNamespace are the name
+	// and namespace of the secret of the fallback certificate. If empty, no
+	// fallback certificate is used.
+	FallbackCertificateName      string
+	FallbackCertificateNamespace string
+}
+
+// leaderElection is the leader election of Contour.
+type leaderElection struct {
+	// Name and Namespace are the name and namespace of the ConfigMap Contour
+	// elects a leader with.
+	Name      string
+	Namespace string
+	// LeaseDuration, RenewDeadline and RetryPeriod are the durations of the
+	// leader election. If empty, Contour's defaults are used.
+	LeaseDuration string
+	RenewDeadline string
+	RetryPeriod   string
 }
 
 // NewConfig returns a Config with default fields set.
@@ -175,11 +312,116 @@ func NewConfig() *Config {
 func NewCfgForContour(contour *operatorv1alpha1.Contour) *Config {
 	cfg := NewConfig()
 	cfg.Namespace = contour.Spec.Namespace.Name
+	cfg.Name = objcontour.ResourceName(contour, ContourCfgMapName)
 	labels := objcontour.OwnerLabels(contour)
 	cfg.Labels = labels
+	cfg.ResourceLabels = contour.Spec.ResourceLabels
+	cfg.ResourceAnnotations = contour.Spec.ResourceAnnotations
+	cfg.OwnerReferences = objcontour.OwnerReferences(contour, cfg.Namespace)
+	cfg.Adopt = contour.Spec.AdoptExistingResources
+	cfg.Contour.Policy = policyForContour(contour)
+	cfg.Contour.Timeouts = contour.Spec.Timeouts
+	cfg.Contour.CircuitBreakers = contour.Spec.CircuitBreakers
+	cfg.Contour.ServerHeaderTransformation = contour.Spec.ServerHeaderTransformation
+	if log := contour.Spec.AccessLog; log != nil {
+		cfg.Contour.AccessLogLevel = log.Level
+		cfg.Contour.AccessLogFormat = log.Format
+		cfg.Contour.JSONFields = jsonFieldsForContour(contour)
+	}
+	if contour.Spec.Networking != nil {
+		cfg.Contour.DNSLookupFamily = contour.Spec.Networking.DNSLookupFamily
+	}
+	if contour.Spec.DefaultCertificate != nil {
+		cfg.Contour.FallbackCertificateName = objcert.SecretName(contour)
+		cfg.Contour.FallbackCertificateNamespace = contour.Spec.Namespace.Name
+	}
+	if le := contour.Spec.LeaderElection; le != nil || contour.Spec.Namespace.Shared {
+		key := objcontour.LeaderElectionConfigMap(contour)
+		cfg.Contour.LeaderElection = &leaderElection{Name: key.Name, Namespace: key.Namespace}
+		if le != nil {
+			cfg.Contour.LeaderElection.LeaseDuration = le.LeaseDuration
+			cfg.Contour.LeaderElection.RenewDeadline = le.RenewDeadline
+			cfg.Contour.LeaderElection.RetryPeriod = le.RetryPeriod
+		}
+	}
 	return cfg
 }
 
+// jsonFieldsForContour returns the JSON access log fields of contour, including
+// the contour_instance field if requested. If no fields are requested, nil is
+// returned so Contour's defaults are used.
+func jsonFieldsForContour(contour *operatorv1alpha1.Contour) []string {
+	log := contour.Spec.AccessLog
+	if log.Format != operatorv1alpha1.JSONAccessLogFormat {
+		return nil
+	}
+	fields := append([]string{}, log.JSONFields...)
+	if log.IncludeContourInstance {
+		if len(fields) == 0 {
+			fields = append(fields, defaultJSONFields...)
+		}
+		fields = append(fields, fmt.Sprintf("%s=%s/%s", contourInstanceField, contour.Namespace, contour.Name))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// policyForContour returns the global header policy of contour, including the
+// security response headers of contour that are not set by its response header
+// policy.
+func policyForContour(contour *operatorv1alpha1.Contour) *operatorv1alpha1.PolicyParameters {
+	sec := contour.Spec.Security
+	if sec == nil || sec.Headers == nil {
+		return contour.Spec.Policy
+	}
+	headers := map[string]string{}
+	if hsts := sec.Headers.HSTS; hsts != nil {
+		value := fmt.Sprintf("max-age=%d", hsts.MaxAgeSeconds)
+		if hsts.IncludeSubDomains {
+			value += "; includeSubDomains"
+		}
+		if hsts.Preload {
+			value += "; preload"
+		}
+		headers["Strict-Transport-Security"] = value
+	}
+	if sec.Headers.ContentTypeNoSniff {
+		headers["X-Content-Type-Options"] = "nosniff"
+	}
+	if sec.Headers.FrameOptions != "" {
+		headers["X-Frame-Options"] = sec.Headers.FrameOptions
+	}
+	if sec.Headers.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = sec.Headers.ReferrerPolicy
+	}
+	if len(headers) == 0 {
+		return contour.Spec.Policy
+	}
+
+	policy := &operatorv1alpha1.PolicyParameters{}
+	if contour.Spec.Policy != nil {
+		policy = contour.Spec.Policy.DeepCopy()
+	}
+	if policy.ResponseHeaders == nil {
+		policy.ResponseHeaders = &operatorv1alpha1.HeadersPolicy{}
+	}
+	if policy.ResponseHeaders.Set == nil {
+		policy.ResponseHeaders.Set = map[string]string{}
+	}
+	set := map[string]bool{}
+	for name := range policy.ResponseHeaders.Set {
+		set[http.CanonicalHeaderKey(name)] = true
+	}
+	for name, value := range headers {
+		if !set[name] {
+			policy.ResponseHeaders.Set[name] = value
+		}
+	}
+	return policy
+}
+
 // NewCfgForGateway returns a ConfigMap Config with default fields set for gw.
 func NewCfgForGateway(gw *gatewayv1alpha1.Gateway) *Config {
 	cfg := NewConfig()
@@ -191,13 +433,18 @@ func NewCfgForGateway(gw *gatewayv1alpha1.Gateway) *Config {
 	return cfg
 }
 
-// Ensure ensures that a ConfigMap exists for the given cfg.
+// Ensure ensures that a ConfigMap exists for the given cfg. An
+// InvalidConfigError is returned instead of writing a Contour configuration
+// file that does not match the configuration file schema of Contour.
 func Ensure(ctx context.Context, cli client.Client, cfg *Config) error {
 	desired, err := desired(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to build configmap: %w", err)
 	}
-	current, err := current(ctx, cli, cfg)
+	if err := Validate(desired.Data[ContourCfgFileName]); err != nil {
+		return err
+	}
+	current, err := Current(ctx, cli, cfg)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return create(ctx, cli, desired)
@@ -212,14 +459,14 @@ func Ensure(ctx context.Context, cli client.Client, cfg *Config) error {
 
 // Delete deletes a ConfigMap for the provided cfg, if the configured owner labels exist.
 func Delete(ctx context.Context, cli client.Client, cfg *Config) error {
-	cfgMap, err := current(ctx, cli, cfg)
+	cfgMap, err := Current(ctx, cli, cfg)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil
 		}
 		return err
 	}
-	if labels.Exist(cfgMap, cfg.Labels) {
+	if owned(cfgMap, cfg) {
 		if err := cli.Delete(ctx, cfgMap); err != nil {
 			if errors.IsNotFound(err) {
 				return nil
@@ -230,8 +477,8 @@ func Delete(ctx context.Context, cli client.Client, cfg *Config) error {
 	return nil
 }
 
-// current gets the ConfigMap for the provided cfg from the api server.
-func current(ctx context.Context, cli client.Client, cfg *Config) (*corev1.ConfigMap, error) {
+// Current gets the ConfigMap for the provided cfg from the api server.
+func Current(ctx context.Context, cli client.Client, cfg *Config) (*corev1.ConfigMap, error) {
 	current := &corev1.ConfigMap{}
 	key := types.NamespacedName{
 		Namespace: cfg.Namespace,
@@ -253,18 +500,26 @@ func desired(cfg *Config) (*corev1.ConfigMap, error) {
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      cfg.Name,
-			Namespace: cfg.Namespace,
-			Labels:    cfg.Labels,
+			Name:            cfg.Name,
+			Namespace:       cfg.Namespace,
+			Labels:          cfg.Labels,
+			OwnerReferences: cfg.OwnerReferences,
 		},
 		Data: map[string]string{
-			"contour.yaml": cfgFile.String(),
+			ContourCfgFileName: cfgFile.String(),
 		},
 	}
+	objcontour.ApplyMetadata(cm, cfg.ResourceLabels, cfg.ResourceAnnotations)
 
 	return cm, nil
 }
 
+// owned returns true if cm has the owner labels of cfg, or if cfg adopts
+// existing ConfigMaps and cm has no owner labels.
+func owned(cm *corev1.ConfigMap, cfg *Config) bool {
+	return labels.Exist(cm, cfg.Labels) || cfg.Adopt && objcontour.Unowned(cm)
+}
+
 // create creates a ConfigMap resource for the provided cm.
 func create(ctx context.Context, cli client.Client, cm *corev1.ConfigMap) error {
 	if err := cli.Create(ctx, cm); err != nil {
@@ -276,7 +531,7 @@ func create(ctx context.Context, cli client.Client, cm *corev1.ConfigMap) error
 // updateIfNeeded updates a ConfigMap if current does not match desired,
 // using cfg to verify the existence of owner labels.
 func updateIfNeeded(ctx context.Context, cli client.Client, cfg *Config, current, desired *corev1.ConfigMap) error {
-	if labels.Exist(current, cfg.Labels) {
+	if owned(current, cfg) {
 		changed, updated := cfgMapChanged(current, desired)
 		if !changed {
 			return nil
@@ -300,5 +555,20 @@ func cfgMapChanged(current, expected *corev1.ConfigMap) (bool, *corev1.ConfigMap
 		updated.Data = expected.Data
 	}
 
+	if !apiequality.Semantic.DeepEqual(current.Labels, expected.Labels) {
+		changed = true
+		updated.Labels = expected.Labels
+	}
+
+	if annotations, ok := equality.MergedAnnotations(current.Annotations, expected.Annotations); ok {
+		changed = true
+		updated.Annotations = annotations
+	}
+
+	if refs, ok := equality.MergedOwnerReferences(current.OwnerReferences, expected.OwnerReferences); ok {
+		changed = true
+		updated.OwnerReferences = refs
+	}
+
 	return changed, updated
 }
@@ -15,11 +15,13 @@ package configmap
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
@@ -125,6 +127,17 @@ accesslog-format: envoy
 #   Configure the number of additional ingress proxy hops from the
 #   right side of the x-forwarded-for HTTP header to trust.
 #   num-trusted-hops: 0
+#
+# Global header policies applied to all routes.
+# policy:
+#   request-headers:
+#     set:
+#       "X-Environment": "production"
+#     remove:
+#     - "X-Debug"
+#   response-headers:
+#     set: {}
+#     remove: []
 `
 	name := "test-contour-configmap"
 	cfg := objcontour.Config{
@@ -143,6 +156,272 @@ accesslog-format: envoy
 	}
 }
 
+func TestDesiredContourConfigmapPolicy(t *testing.T) {
+	expected := `
+# Global header policies applied to all routes.
+policy:
+  request-headers:
+    set:
+      "X-Environment": "production"
+      "X-Forwarded-Client": "%DOWNSTREAM_REMOTE_ADDRESS%"
+    remove:
+    - "X-Debug"
+  response-headers:
+    remove:
+    - "Server"
+`
+	name := "test-contour-configmap"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	cntr.Spec.Policy = &operatorv1alpha1.PolicyParameters{
+		RequestHeaders: &operatorv1alpha1.HeadersPolicy{
+			Set: map[string]string{
+				"X-Forwarded-Client": "%DOWNSTREAM_REMOTE_ADDRESS%",
+				"X-Environment":      "production",
+			},
+			Remove: []string{"X-Debug"},
+		},
+		ResponseHeaders: &operatorv1alpha1.HeadersPolicy{Remove: []string{"Server"}},
+	}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.HasSuffix(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected suffix:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapTimeouts(t *testing.T) {
+	expected := `
+# The following shows the default proxy timeout settings.
+timeouts:
+  connection-idle-timeout: 120s
+  stream-idle-timeout: 1h
+  max-connection-duration: infinity
+#
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Timeouts = &operatorv1alpha1.TimeoutParameters{
+		ConnectionIdleTimeout: "120s",
+		StreamIdleTimeout:     "1h",
+		MaxConnectionDuration: "infinity",
+	}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.Contains(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapLeaderElection(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Namespace.Shared = true
+	testCases := []struct {
+		description string
+		params      *operatorv1alpha1.LeaderElectionParameters
+		expected    string
+	}{
+		{
+			description: "shared namespace",
+			expected: `
+leaderelection:
+  configmap-name: test-leader-elect
+  configmap-namespace: projectcontour
+`,
+		},
+		{
+			description: "custom configmap and durations",
+			params: &operatorv1alpha1.LeaderElectionParameters{
+				ConfigMapName:      "edge-lock",
+				ConfigMapNamespace: "locks",
+				LeaseDuration:      "30s",
+				RetryPeriod:        "5s",
+			},
+			expected: `
+leaderelection:
+  configmap-name: edge-lock
+  configmap-namespace: locks
+  lease-duration: 30s
+  retry-period: 5s
+`,
+		},
+	}
+	for _, tc := range testCases {
+		cntr.Spec.LeaderElection = tc.params
+		cm, err := desired(NewCfgForContour(cntr))
+		if err != nil {
+			t.Fatalf("%q: invalid contour configmap: %v", tc.description, err)
+		}
+		if !strings.Contains(cm.Data["contour.yaml"], tc.expected) {
+			t.Errorf("%q: unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", tc.description,
+				cm.Data["contour.yaml"], tc.expected)
+		}
+	}
+}
+
+func TestDesiredContourConfigmapCluster(t *testing.T) {
+	expected := `
+# Envoy cluster settings.
+cluster:
+  dns-lookup-family: v6
+  circuit-breakers:
+    max-connections: 10000
+    max-requests: 20000
+    per-host-max-connections: 100
+#
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Networking = &operatorv1alpha1.NetworkingParameters{
+		DNSLookupFamily: operatorv1alpha1.IPv6DNSLookupFamily,
+	}
+	cntr.Spec.CircuitBreakers = &operatorv1alpha1.CircuitBreakerParameters{
+		MaxConnections:        10000,
+		MaxRequests:           20000,
+		PerHostMaxConnections: 100,
+	}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.Contains(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapServerHeader(t *testing.T) {
+	expected := `
+disablePermitInsecure: false
+# How Envoy handles the Server header of responses
+server-header-transformation: pass_through
+tls:
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.ServerHeaderTransformation = operatorv1alpha1.PassThroughServerHeader
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.Contains(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapDefaultCertificate(t *testing.T) {
+	expected := `
+  fallback-certificate:
+    name: default-certificate
+    namespace: projectcontour
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.DefaultCertificate = &operatorv1alpha1.DefaultCertificate{
+		IssuerRef: operatorv1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		DNSNames:  []string{"*.example.com"},
+	}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.Contains(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapAccessLog(t *testing.T) {
+	expected := `
+accesslog-format: envoy
+# Minimum response class of logged requests
+accesslog-level: error
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.AccessLog = &operatorv1alpha1.AccessLogParameters{Level: operatorv1alpha1.ErrorAccessLogLevel}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	if !strings.Contains(cm.Data["contour.yaml"], expected) {
+		t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], expected)
+	}
+}
+
+func TestDesiredContourConfigmapJSONFields(t *testing.T) {
+	expected := `
+accesslog-format: json
+`
+	expectedFields := `
+json-fields:
+  - "@timestamp"
+  - "response_code"
+  - "host=%REQ(:AUTHORITY)%"
+  - "contour_instance=test-ns/test"
+#
+`
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.AccessLog = &operatorv1alpha1.AccessLogParameters{
+		Format:                 operatorv1alpha1.JSONAccessLogFormat,
+		JSONFields:             []string{"@timestamp", "response_code", "host=%REQ(:AUTHORITY)%"},
+		IncludeContourInstance: true,
+	}
+	cm, err := desired(NewCfgForContour(cntr))
+	if err != nil {
+		t.Fatalf("invalid contour configmap: %v", err)
+	}
+	for _, s := range []string{expected, expectedFields} {
+		if !strings.Contains(cm.Data["contour.yaml"], s) {
+			t.Errorf("unexpected contour.yaml; got:\n%s\nexpected to contain:\n%s\n", cm.Data["contour.yaml"], s)
+		}
+	}
+
+	cntr.Spec.AccessLog.JSONFields = nil
+	fields := jsonFieldsForContour(cntr)
+	if len(fields) != len(defaultJSONFields)+1 || fields[len(fields)-1] != "contour_instance=test-ns/test" {
+		t.Errorf("expected default fields and the contour instance field, got %v", fields)
+	}
+}
+
+func TestPolicyForContour(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	if policy := policyForContour(cntr); policy != nil {
+		t.Errorf("expected no policy, got %#v", policy)
+	}
+
+	cntr.Spec.Policy = &operatorv1alpha1.PolicyParameters{
+		ResponseHeaders: &operatorv1alpha1.HeadersPolicy{
+			Set: map[string]string{"x-frame-options": "SAMEORIGIN"},
+		},
+	}
+	cntr.Spec.Security = &operatorv1alpha1.SecurityParameters{
+		Headers: &operatorv1alpha1.SecurityHeaders{
+			HSTS: &operatorv1alpha1.HSTSPolicy{
+				MaxAgeSeconds:     31536000,
+				IncludeSubDomains: true,
+				Preload:           true,
+			},
+			ContentTypeNoSniff: true,
+			FrameOptions:       "DENY",
+		},
+	}
+	expected := map[string]string{
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains; preload",
+		"X-Content-Type-Options":    "nosniff",
+		"x-frame-options":           "SAMEORIGIN",
+	}
+	policy := policyForContour(cntr)
+	if !apiequality.Semantic.DeepEqual(policy.ResponseHeaders.Set, expected) {
+		t.Errorf("expected response headers %v, got %v", expected, policy.ResponseHeaders.Set)
+	}
+	if len(cntr.Spec.Policy.ResponseHeaders.Set) != 1 {
+		t.Errorf("expected the policy of contour to be unmodified, got %v", cntr.Spec.Policy.ResponseHeaders.Set)
+	}
+}
+
 func TestDesiredGatewayConfigmap(t *testing.T) {
 	expected := `
 #
@@ -245,6 +524,17 @@ accesslog-format: envoy
 #   Configure the number of additional ingress proxy hops from the
 #   right side of the x-forwarded-for HTTP header to trust.
 #   num-trusted-hops: 0
+#
+# Global header policies applied to all routes.
+# policy:
+#   request-headers:
+#     set:
+#       "X-Environment": "production"
+#     remove:
+#     - "X-Debug"
+#   response-headers:
+#     set: {}
+#     remove: []
 `
 	gwCfg := NewCfgForGateway(&gatewayv1alpha1.Gateway{})
 	gwCfg.Contour.GatewayNamespace = "bar"
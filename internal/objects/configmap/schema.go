@@ -0,0 +1,256 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// InvalidConfigError is the error of a rendered Contour configuration file that
+// does not match the configuration file schema of Contour.
+type InvalidConfigError struct {
+	// Errors are the invalid fields of the configuration file, i.e.
+	// "timeouts.request-timeout: invalid duration \"1x\"".
+	Errors []string
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("invalid contour configuration: %s", strings.Join(e.Errors, "; "))
+}
+
+// field is the schema of a field of the Contour configuration file. A field
+// is a mapping of fields, a sequence of items or a scalar checked by value.
+type field struct {
+	// fields are the fields of a mapping by key.
+	fields map[string]*field
+	// items is the schema of the items of a sequence.
+	items *field
+	// stringMap is true for a mapping of arbitrary keys to strings.
+	stringMap bool
+	// value validates a scalar.
+	value func(v interface{}) error
+}
+
+func mapping(fields map[string]*field) *field { return &field{fields: fields} }
+
+func sequence(items *field) *field { return &field{items: items} }
+
+func scalar(value func(v interface{}) error) *field { return &field{value: value} }
+
+var (
+	stringField  = scalar(isString)
+	boolField    = scalar(isBool)
+	uint32Field  = scalar(isUint32)
+	secretField  = mapping(map[string]*field{"name": stringField, "namespace": stringField})
+	headersField = mapping(map[string]*field{
+		"set":    {stringMap: true},
+		"remove": sequence(stringField),
+	})
+)
+
+// contourCfgSchema is the schema of the configuration file of the Contour
+// releases managed by the operator, covering every field rendered by
+// contourCfgTemplate.
+var contourCfgSchema = mapping(map[string]*field{
+	"server": mapping(map[string]*field{
+		"xds-server-type": scalar(oneOf("contour", "envoy")),
+	}),
+	"gateway":                      secretField,
+	"incluster":                    boolField,
+	"kubeconfig":                   stringField,
+	"disableAllowChunkedLength":    boolField,
+	"disablePermitInsecure":        boolField,
+	"server-header-transformation": scalar(oneOf("overwrite", "append_if_absent", "pass_through")),
+	"tls": mapping(map[string]*field{
+		"minimum-protocol-version": scalar(oneOf("1.2", "1.3")),
+		"cipher-suites":            sequence(stringField),
+		"fallback-certificate":     secretField,
+		"envoy-client-certificate": secretField,
+	}),
+	"leaderelection": mapping(map[string]*field{
+		"configmap-name":      stringField,
+		"configmap-namespace": stringField,
+		"lease-duration":      scalar(isDuration(false)),
+		"renew-deadline":      scalar(isDuration(false)),
+		"retry-period":        scalar(isDuration(false)),
+	}),
+	"accesslog-format":      scalar(oneOf("envoy", "json")),
+	"accesslog-level":       scalar(oneOf("info", "error", "disabled")),
+	"json-fields":           sequence(scalar(isJSONField)),
+	"default-http-versions": sequence(scalar(oneOf("HTTP/1.1", "HTTP/2"))),
+	"timeouts": mapping(map[string]*field{
+		"request-timeout":                  scalar(isDuration(true)),
+		"connection-idle-timeout":          scalar(isDuration(true)),
+		"stream-idle-timeout":              scalar(isDuration(true)),
+		"max-connection-duration":          scalar(isDuration(true)),
+		"delayed-close-timeout":            scalar(isDuration(true)),
+		"connection-shutdown-grace-period": scalar(isDuration(false)),
+	}),
+	"cluster": mapping(map[string]*field{
+		"dns-lookup-family": scalar(oneOf("auto", "v4", "v6")),
+		"circuit-breakers": mapping(map[string]*field{
+			"max-connections":          uint32Field,
+			"max-pending-requests":     uint32Field,
+			"max-requests":             uint32Field,
+			"max-retries":              uint32Field,
+			"per-host-max-connections": uint32Field,
+		}),
+	}),
+	"network": mapping(map[string]*field{
+		"num-trusted-hops": uint32Field,
+	}),
+	"policy": mapping(map[string]*field{
+		"request-headers":  headersField,
+		"response-headers": headersField,
+	}),
+})
+
+// Validate validates the Contour configuration file data against the
+// configuration file schema of Contour, returning an InvalidConfigError if
+// data is not valid YAML or has unknown or invalid fields.
+func Validate(data string) error {
+	var cfg interface{}
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		return &InvalidConfigError{Errors: []string{err.Error()}}
+	}
+	var errs []string
+	contourCfgSchema.validate("", cfg, &errs)
+	if len(errs) > 0 {
+		return &InvalidConfigError{Errors: errs}
+	}
+	return nil
+}
+
+// validate appends the errors of v at path to errs. A null value is valid for
+// every field, since Contour uses the default of an empty field.
+func (f *field) validate(path string, v interface{}, errs *[]string) {
+	if v == nil {
+		return
+	}
+	invalid := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if path != "" {
+			msg = path + ": " + msg
+		}
+		*errs = append(*errs, msg)
+	}
+	switch {
+	case f.fields != nil || f.stringMap:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			invalid("expected a mapping")
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if f.stringMap {
+				stringField.validate(p, m[k], errs)
+				continue
+			}
+			child, ok := f.fields[k]
+			if !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: unknown field", p))
+				continue
+			}
+			child.validate(p, m[k], errs)
+		}
+	case f.items != nil:
+		s, ok := v.([]interface{})
+		if !ok {
+			invalid("expected a sequence")
+			return
+		}
+		for i, item := range s {
+			f.items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+		}
+	default:
+		if err := f.value(v); err != nil {
+			invalid("%v", err)
+		}
+	}
+}
+
+func isString(v interface{}) error {
+	if _, ok := v.(string); !ok {
+		return fmt.Errorf("expected a string, got %v", v)
+	}
+	return nil
+}
+
+func isBool(v interface{}) error {
+	if _, ok := v.(bool); !ok {
+		return fmt.Errorf("expected a boolean, got %v", v)
+	}
+	return nil
+}
+
+func isUint32(v interface{}) error {
+	n, ok := v.(float64)
+	if !ok || n < 0 || n > float64(^uint32(0)) || n != float64(uint32(n)) {
+		return fmt.Errorf("expected an unsigned 32-bit integer, got %v", v)
+	}
+	return nil
+}
+
+// oneOf returns a validator of a string that is one of values.
+func oneOf(values ...string) func(v interface{}) error {
+	return func(v interface{}) error {
+		s, _ := v.(string)
+		for _, value := range values {
+			if s == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s, got %v", strings.Join(values, ", "), v)
+	}
+}
+
+// isDuration returns a validator of a duration, or of "infinity" if infinite
+// is true.
+func isDuration(infinite bool) func(v interface{}) error {
+	return func(v interface{}) error {
+		s, ok := v.(string)
+		if ok && infinite && s == "infinity" {
+			return nil
+		}
+		if _, err := time.ParseDuration(s); !ok || err != nil {
+			return fmt.Errorf("invalid duration %v", v)
+		}
+		return nil
+	}
+}
+
+// isJSONField validates a JSON access log field, i.e. "method" or
+// "contour_instance=projectcontour/contour".
+func isJSONField(v interface{}) error {
+	s, ok := v.(string)
+	name := strings.SplitN(s, "=", 2)[0]
+	if !ok || name == "" || strings.HasSuffix(s, "=") {
+		return fmt.Errorf("invalid json field %v", v)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+func TestValidateRendered(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Namespace.Shared = true
+	cntr.Spec.Policy = &operatorv1alpha1.PolicyParameters{
+		RequestHeaders: &operatorv1alpha1.HeadersPolicy{
+			Set:    map[string]string{"X-Environment": "production"},
+			Remove: []string{"X-Debug"},
+		},
+	}
+	cntr.Spec.Timeouts = &operatorv1alpha1.TimeoutParameters{
+		RequestTimeout:                "infinity",
+		ConnectionIdleTimeout:         "120s",
+		ConnectionShutdownGracePeriod: "10s",
+	}
+	cntr.Spec.Networking = &operatorv1alpha1.NetworkingParameters{DNSLookupFamily: operatorv1alpha1.IPv6DNSLookupFamily}
+	cntr.Spec.CircuitBreakers = &operatorv1alpha1.CircuitBreakerParameters{MaxConnections: 10000}
+	cntr.Spec.ServerHeaderTransformation = operatorv1alpha1.PassThroughServerHeader
+	cntr.Spec.AccessLog = &operatorv1alpha1.AccessLogParameters{
+		Format:                 operatorv1alpha1.JSONAccessLogFormat,
+		Level:                  operatorv1alpha1.ErrorAccessLogLevel,
+		IncludeContourInstance: true,
+	}
+	cntr.Spec.DefaultCertificate = &operatorv1alpha1.DefaultCertificate{
+		IssuerRef: operatorv1alpha1.CertificateIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		DNSNames:  []string{"*.example.com"},
+	}
+	gw := &gatewayv1alpha1.Gateway{}
+	gw.Namespace, gw.Name = "projectcontour", "contour"
+
+	for _, cfg := range []*Config{NewConfig(), NewCfgForContour(cntr), NewCfgForGateway(gw)} {
+		cm, err := desired(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Validate(cm.Data[ContourCfgFileName]); err != nil {
+			t.Errorf("expected rendered configuration of %s to be valid, got %v", cfg.Name, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description string
+		data        string
+		expected    string
+	}{
+		{
+			description: "unknown field",
+			data:        "disablePermitInsecure: false\nunknown: true\n",
+			expected:    "unknown: unknown field",
+		},
+		{
+			description: "unknown nested field",
+			data:        "timeouts:\n  request-timeout: 1s\n  idle-timeout: 1s\n",
+			expected:    "timeouts.idle-timeout: unknown field",
+		},
+		{
+			description: "invalid duration",
+			data:        "timeouts:\n  connection-shutdown-grace-period: infinity\n",
+			expected:    "timeouts.connection-shutdown-grace-period: invalid duration infinity",
+		},
+		{
+			description: "invalid enum",
+			data:        "accesslog-format: xml\n",
+			expected:    "accesslog-format: expected one of envoy, json, got xml",
+		},
+		{
+			description: "negative threshold",
+			data:        "cluster:\n  circuit-breakers:\n    max-requests: -1\n",
+			expected:    "cluster.circuit-breakers.max-requests: expected an unsigned 32-bit integer, got -1",
+		},
+		{
+			description: "invalid json field",
+			data:        "json-fields:\n  - \"method\"\n  - \"host=\"\n",
+			expected:    "json-fields[1]: invalid json field host=",
+		},
+		{
+			description: "header value of a mapping",
+			data:        "policy:\n  request-headers:\n    set:\n      \"X-Nested\":\n        a: b\n",
+			expected:    "policy.request-headers.set.X-Nested: expected a string",
+		},
+		{
+			description: "invalid yaml",
+			data:        "tls:\n  - a\n b",
+			expected:    "invalid contour configuration",
+		},
+	}
+	for _, tc := range testCases {
+		err := Validate(tc.data)
+		if _, ok := err.(*InvalidConfigError); !ok {
+			t.Errorf("%q: expected an invalid configuration error, got %v", tc.description, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%q: expected error to contain %q, got %q", tc.description, tc.expected, err)
+		}
+	}
+}
+
+func TestEnsureInvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Timeouts = &operatorv1alpha1.TimeoutParameters{RequestTimeout: "forever"}
+	cfg := NewCfgForContour(cntr)
+
+	if _, ok := Ensure(ctx, cli, cfg).(*InvalidConfigError); !ok {
+		t.Fatalf("expected an invalid configuration error")
+	}
+	if _, err := Current(ctx, cli, cfg); !errors.IsNotFound(err) {
+		t.Errorf("expected the invalid configuration not to be written, got %v", err)
+	}
+}
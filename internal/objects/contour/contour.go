@@ -16,15 +16,47 @@ package contour
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	"github.com/projectcontour/contour-operator/pkg/labels"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// ResourceNamePlaceholder is replaced by the name of the resource in
+	// a resource name template, i.e. "envoy".
+	ResourceNamePlaceholder = "{resource}"
+	// ContourNamePlaceholder is replaced by the name of the Contour in
+	// a resource name template.
+	ContourNamePlaceholder = "{contour-name}"
+	// ContourNamespacePlaceholder is replaced by the namespace of the Contour
+	// in a resource name template.
+	ContourNamespacePlaceholder = "{contour-namespace}"
+	// CertificatesRotationAnnotation is the pod template annotation of the
+	// Contour and Envoy pods that restarts them after a rotation of the xDS
+	// certificates.
+	CertificatesRotationAnnotation = "contour.operator.projectcontour.io/certificates-rotation"
+	// RenderedByAnnotation is the annotation of the Contour Deployment and the
+	// Envoy DaemonSet holding the version of the operator that rendered them.
+	RenderedByAnnotation = "contour.operator.projectcontour.io/rendered-by"
+	// IstioSidecarInjectLabel is the pod label and annotation that disables
+	// the Istio sidecar injection of a pod when "false".
+	IstioSidecarInjectLabel = "sidecar.istio.io/inject"
+	// IstioInjectionLabel is the namespace label that disables the Istio
+	// sidecar injection of the pods of a namespace when "disabled".
+	IstioInjectionLabel = "istio-injection"
+	// LinkerdInjectAnnotation is the pod and namespace annotation that
+	// disables the Linkerd proxy injection when "disabled".
+	LinkerdInjectAnnotation = "linkerd.io/inject"
+)
+
 // Config is the configuration of a Contour.
 type Config struct {
 	Name         string
@@ -78,6 +110,33 @@ func New(cfg Config) *operatorv1alpha1.Contour {
 	return cntr
 }
 
+// Node ports of Envoy published by the LocalDevelopment preset.
+const (
+	LocalDevelopmentHTTPNodePort  = int32(30080)
+	LocalDevelopmentHTTPSNodePort = int32(30443)
+)
+
+// WithPublishingPreset returns contour with the network publishing its preset
+// expands to, which is a copy of contour if a preset is set. The expanded spec
+// is not persisted, so that the preset can be changed.
+func WithPublishingPreset(contour *operatorv1alpha1.Contour) *operatorv1alpha1.Contour {
+	if contour.Spec.NetworkPublishing.Preset != operatorv1alpha1.LocalDevelopmentPublishingPreset {
+		return contour
+	}
+	expanded := contour.DeepCopy()
+	envoy := &expanded.Spec.NetworkPublishing.Envoy
+	envoy.Type = operatorv1alpha1.NodePortServicePublishingType
+	// The load balancer defaulted by the CRD is not used by node ports.
+	envoy.LoadBalancer = operatorv1alpha1.LoadBalancerStrategy{}
+	if len(envoy.NodePorts) == 0 {
+		envoy.NodePorts = []operatorv1alpha1.NodePort{
+			{Name: "http", PortNumber: pointer.Int32Ptr(LocalDevelopmentHTTPNodePort)},
+			{Name: "https", PortNumber: pointer.Int32Ptr(LocalDevelopmentHTTPSNodePort)},
+		}
+	}
+	return expanded
+}
+
 // CurrentContour returns the current Contour for the provided ns/name.
 func CurrentContour(ctx context.Context, cli client.Client, ns, name string) (*operatorv1alpha1.Contour, error) {
 	cntr := &operatorv1alpha1.Contour{}
@@ -125,6 +184,78 @@ func OtherContoursExistInSpecNs(ctx context.Context, cli client.Client, contour
 	return false, nil
 }
 
+// OtherContoursInSpecNs returns the Contour objects other than contour with the
+// same spec.namespace.name as contour.
+func OtherContoursInSpecNs(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) ([]operatorv1alpha1.Contour, error) {
+	contours := &operatorv1alpha1.ContourList{}
+	if err := cli.List(ctx, contours); err != nil {
+		return nil, fmt.Errorf("failed to list contours: %w", err)
+	}
+	var others []operatorv1alpha1.Contour
+	for _, c := range contours.Items {
+		if c.Name == contour.Name && c.Namespace == contour.Namespace {
+			continue
+		}
+		if c.Spec.Namespace.Name == contour.Spec.Namespace.Name {
+			others = append(others, c)
+		}
+	}
+	return others, nil
+}
+
+// leaderElectionCfgMapName is the name of Contour's default leader election
+// ConfigMap.
+const leaderElectionCfgMapName = "leader-elect"
+
+// LeaderElectionConfigMap returns the namespace and name of the ConfigMap the
+// Contour replicas of contour elect a leader with.
+func LeaderElectionConfigMap(contour *operatorv1alpha1.Contour) types.NamespacedName {
+	key := types.NamespacedName{Namespace: contour.Spec.Namespace.Name, Name: leaderElectionCfgMapName}
+	if contour.Spec.Namespace.Shared {
+		// Contours sharing the namespace must not contend for the same lock.
+		key.Name = ResourceName(contour, leaderElectionCfgMapName)
+	}
+	if le := contour.Spec.LeaderElection; le != nil {
+		if le.ConfigMapName != "" {
+			key.Name = le.ConfigMapName
+		}
+		if le.ConfigMapNamespace != "" {
+			key.Namespace = le.ConfigMapNamespace
+		}
+	}
+	return key
+}
+
+// ResourceName returns the name of the resource named base that is generated
+// for contour. The name is rendered from the resource name template of contour
+// if set. Otherwise, if contour shares its spec namespace, base is prefixed with
+// the name of contour, i.e. "<contour-name>-envoy".
+func ResourceName(contour *operatorv1alpha1.Contour, base string) string {
+	switch {
+	case contour.Spec.ResourceNameTemplate != "":
+		return strings.NewReplacer(
+			ResourceNamePlaceholder, base,
+			ContourNamePlaceholder, contour.Name,
+			ContourNamespacePlaceholder, contour.Namespace,
+		).Replace(contour.Spec.ResourceNameTemplate)
+	case contour.Spec.Namespace.Shared:
+		return contour.Name + "-" + base
+	default:
+		return base
+	}
+}
+
+// CertsSecretName returns the name of the certificate secret named base that is
+// generated for contour. Certgen only supports suffixing the names of the secrets
+// it generates, so if contour shares its spec namespace, base is suffixed with
+// the name of contour, i.e. "envoycert-<contour-name>".
+func CertsSecretName(contour *operatorv1alpha1.Contour, base string) string {
+	if !contour.Spec.Namespace.Shared {
+		return base
+	}
+	return base + "-" + contour.Name
+}
+
 // OwningSelector returns a label selector using "contour.operator.projectcontour.io/owning-contour-name"
 // and "contour.operator.projectcontour.io/owning-contour-namespace" labels.
 func OwningSelector(contour *operatorv1alpha1.Contour) *metav1.LabelSelector {
@@ -164,6 +295,197 @@ func OwnerLabels(contour *operatorv1alpha1.Contour) map[string]string {
 	}
 }
 
+// ApplyResourceMetadata adds the resource labels and annotations of contour
+// to obj, along with the owner references of contour for the namespace of obj.
+// Labels and annotations already set on obj are not overridden.
+func ApplyResourceMetadata(obj metav1.Object, contour *operatorv1alpha1.Contour) {
+	ApplyMetadata(obj, contour.Spec.ResourceLabels, contour.Spec.ResourceAnnotations)
+	if refs := OwnerReferences(contour, obj.GetNamespace()); refs != nil {
+		obj.SetOwnerReferences(refs)
+	}
+}
+
+// ApplyCertificatesRotation annotates the pod template metadata obj with the
+// last rotation of the xDS certificates of contour, so that the pods are
+// restarted with the certificates of every rotation.
+func ApplyCertificatesRotation(obj metav1.Object, contour *operatorv1alpha1.Contour) {
+	if contour.Status.CertificatesRotation == "" {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[CertificatesRotationAnnotation] = contour.Status.CertificatesRotation
+	obj.SetAnnotations(annotations)
+}
+
+// ApplySidecarInjectionExclusion labels and annotates the pod template
+// metadata obj to exclude the pods from the sidecar injection of the Istio and
+// Linkerd service meshes, if contour is excluded from service meshes.
+func ApplySidecarInjectionExclusion(obj metav1.Object, contour *operatorv1alpha1.Contour) {
+	if !contour.Spec.ExcludeFromServiceMesh {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[IstioSidecarInjectLabel] = "false"
+	obj.SetLabels(labels)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[IstioSidecarInjectLabel] = "false"
+	annotations[LinkerdInjectAnnotation] = "disabled"
+	obj.SetAnnotations(annotations)
+}
+
+// ApplyRenderedBy annotates obj with the version of the running operator.
+func ApplyRenderedBy(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RenderedByAnnotation] = operatorconfig.Version
+	obj.SetAnnotations(annotations)
+}
+
+// RenderedByOtherVersion returns the version of the operator that rendered obj,
+// or an empty string if unknown, and true if it is not the version of the
+// running operator, i.e. obj was rendered before the operator was upgraded.
+func RenderedByOtherVersion(obj metav1.Object) (string, bool) {
+	version := obj.GetAnnotations()[RenderedByAnnotation]
+	return version, version != operatorconfig.Version
+}
+
+// defaultArchitectures are the node architectures of the Contour and Envoy
+// images of the operator.
+var defaultArchitectures = []operatorv1alpha1.NodeArchitecture{
+	operatorv1alpha1.AMD64NodeArchitecture,
+	operatorv1alpha1.ARM64NodeArchitecture,
+}
+
+// linuxOperatingSystem is the operating system of the Contour pods and the
+// default operating system of the Envoy pods.
+const linuxOperatingSystem = "linux"
+
+// NodeAffinity returns the node affinity of the Contour pods of contour,
+// requiring Linux nodes of the architectures of contour.
+func NodeAffinity(contour *operatorv1alpha1.Contour) *corev1.NodeAffinity {
+	return nodeAffinity(contour, []string{linuxOperatingSystem})
+}
+
+// EnvoyNodeAffinity returns the node affinity of the Envoy pods of contour,
+// requiring nodes of the Envoy operating systems and the architectures of
+// contour.
+func EnvoyNodeAffinity(contour *operatorv1alpha1.Contour) *corev1.NodeAffinity {
+	return nodeAffinity(contour, EnvoyOperatingSystems(contour))
+}
+
+// EnvoyOperatingSystems returns the node operating systems of the Envoy pods
+// of contour, defaulting to Linux.
+func EnvoyOperatingSystems(contour *operatorv1alpha1.Contour) []string {
+	if placement := contour.Spec.NodePlacement; placement != nil && placement.Envoy != nil &&
+		len(placement.Envoy.OperatingSystems) > 0 {
+		return placement.Envoy.OperatingSystems
+	}
+	return []string{linuxOperatingSystem}
+}
+
+// nodeAffinity returns a node affinity requiring nodes of the operating
+// systems oses and the architectures of contour.
+func nodeAffinity(contour *operatorv1alpha1.Contour, oses []string) *corev1.NodeAffinity {
+	archs := defaultArchitectures
+	if contour.Spec.NodePlacement != nil && len(contour.Spec.NodePlacement.Architectures) > 0 {
+		archs = contour.Spec.NodePlacement.Architectures
+	}
+	var values []string
+	for _, arch := range archs {
+		values = append(values, string(arch))
+	}
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: oses},
+						{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: values},
+					},
+				},
+			},
+		},
+	}
+}
+
+// OwnerReferences returns the owner references of a resource generated for
+// contour in namespace ns, or nil if contour does not use owner references or
+// ns is not the namespace of contour, since owner references can not cross
+// namespaces.
+func OwnerReferences(contour *operatorv1alpha1.Contour, ns string) []metav1.OwnerReference {
+	if contour.Spec.Ownership != operatorv1alpha1.OwnerReferencesOwnershipType || ns != contour.Namespace {
+		return nil
+	}
+	gvk := operatorv1alpha1.GroupVersion.WithKind("Contour")
+	return []metav1.OwnerReference{*metav1.NewControllerRef(contour, gvk)}
+}
+
+// IsOwned returns true if obj is owned by contour. An object with a controller
+// owner reference is owned only if the reference is to contour, otherwise the
+// owning contour labels must exist. If contour adopts existing resources, an
+// unowned object is owned as well.
+func IsOwned(obj client.Object, contour *operatorv1alpha1.Contour) bool {
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		return ref.UID == contour.UID
+	}
+	if contour.Spec.AdoptExistingResources && Unowned(obj) {
+		return true
+	}
+	return labels.Exist(obj, OwnerLabels(contour))
+}
+
+// Unowned returns true if obj has neither a controller owner reference nor
+// any of the owning contour labels.
+func Unowned(obj client.Object) bool {
+	if metav1.GetControllerOf(obj) != nil {
+		return false
+	}
+	for key := range OwnerLabels(&operatorv1alpha1.Contour{}) {
+		if _, found := obj.GetLabels()[key]; found {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyMetadata adds labels and annotations to obj. Labels and annotations
+// already set on obj are not overridden.
+func ApplyMetadata(obj metav1.Object, labels, annotations map[string]string) {
+	if merged := mergeMissing(obj.GetLabels(), labels); merged != nil {
+		obj.SetLabels(merged)
+	}
+	if merged := mergeMissing(obj.GetAnnotations(), annotations); merged != nil {
+		obj.SetAnnotations(merged)
+	}
+}
+
+// mergeMissing returns a copy of dst with the keys of src that dst does not
+// contain, or nil if src is empty.
+func mergeMissing(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(dst)+len(src))
+	for k, v := range src {
+		merged[k] = v
+	}
+	for k, v := range dst {
+		merged[k] = v
+	}
+	return merged
+}
+
 // MakeNodePorts returns a nodeport slice using the ports key as the nodeport name
 // and the ports value as the nodeport number.
 func MakeNodePorts(ports map[string]int) []operatorv1alpha1.NodePort {
@@ -0,0 +1,163 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsOwned(t *testing.T) {
+	cntr := New(Config{Name: "foo", Namespace: "foo-ns", SpecNs: "foo-ns"})
+	cntr.UID = "foo-uid"
+	other := New(Config{Name: "bar", Namespace: "foo-ns", SpecNs: "foo-ns"})
+	other.UID = "bar-uid"
+	gvk := operatorv1alpha1.GroupVersion.WithKind("Contour")
+
+	testCases := []struct {
+		description string
+		labels      map[string]string
+		owner       *operatorv1alpha1.Contour
+		adopt       bool
+		expected    bool
+	}{
+		{
+			description: "owner labels",
+			labels:      OwnerLabels(cntr),
+			expected:    true,
+		},
+		{
+			description: "owner labels of another contour",
+			labels:      OwnerLabels(other),
+			expected:    false,
+		},
+		{
+			description: "controller reference",
+			owner:       cntr,
+			expected:    true,
+		},
+		{
+			description: "owner labels and controller reference of another contour",
+			labels:      OwnerLabels(cntr),
+			owner:       other,
+			expected:    false,
+		},
+		{
+			description: "unowned",
+			expected:    false,
+		},
+		{
+			description: "unowned and adopted",
+			adopt:       true,
+			expected:    true,
+		},
+		{
+			description: "owner labels of another contour and adopted",
+			labels:      OwnerLabels(other),
+			adopt:       true,
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo-ns",
+				Name:      "contour",
+				Labels:    tc.labels,
+			},
+		}
+		if tc.owner != nil {
+			deploy.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(tc.owner, gvk)}
+		}
+		c := cntr.DeepCopy()
+		c.Spec.AdoptExistingResources = tc.adopt
+		if actual := IsOwned(deploy, c); actual != tc.expected {
+			t.Errorf("%q: expected IsOwned to be %t, got %t", tc.description, tc.expected, actual)
+		}
+	}
+}
+
+func TestRenderedByOtherVersion(t *testing.T) {
+	deploy := &appsv1.Deployment{}
+	if version, other := RenderedByOtherVersion(deploy); !other || version != "" {
+		t.Errorf("expected a deployment without annotation to be rendered by an unknown version, got %q, %t",
+			version, other)
+	}
+	ApplyRenderedBy(deploy)
+	if _, other := RenderedByOtherVersion(deploy); other {
+		t.Errorf("expected deployment to be rendered by the running operator")
+	}
+	deploy.Annotations[RenderedByAnnotation] = "v1.15.0"
+	if version, other := RenderedByOtherVersion(deploy); !other || version != "v1.15.0" {
+		t.Errorf("expected deployment to be rendered by v1.15.0, got %q, %t", version, other)
+	}
+}
+
+func TestApplySidecarInjectionExclusion(t *testing.T) {
+	cntr := New(Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	meta := &metav1.ObjectMeta{Labels: map[string]string{"app": "envoy"}}
+	ApplySidecarInjectionExclusion(meta, cntr)
+	if len(meta.Labels) != 1 || meta.Annotations != nil {
+		t.Errorf("expected no mesh labels or annotations by default, got %v and %v", meta.Labels, meta.Annotations)
+	}
+
+	cntr.Spec.ExcludeFromServiceMesh = true
+	ApplySidecarInjectionExclusion(meta, cntr)
+	if meta.Labels[IstioSidecarInjectLabel] != "false" || meta.Labels["app"] != "envoy" {
+		t.Errorf("expected the istio inject label to be added, got %v", meta.Labels)
+	}
+	if meta.Annotations[IstioSidecarInjectLabel] != "false" || meta.Annotations[LinkerdInjectAnnotation] != "disabled" {
+		t.Errorf("expected the mesh inject annotations, got %v", meta.Annotations)
+	}
+}
+
+func TestWithPublishingPreset(t *testing.T) {
+	cntr := New(Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	if WithPublishingPreset(cntr) != cntr {
+		t.Errorf("expected a contour without preset to be returned as is")
+	}
+
+	cntr.Spec.NetworkPublishing.Preset = operatorv1alpha1.LocalDevelopmentPublishingPreset
+	expanded := WithPublishingPreset(cntr)
+	envoy := expanded.Spec.NetworkPublishing.Envoy
+	if envoy.Type != operatorv1alpha1.NodePortServicePublishingType {
+		t.Errorf("expected network publishing type %s, got %s", operatorv1alpha1.NodePortServicePublishingType, envoy.Type)
+	}
+	if envoy.LoadBalancer.ProviderParameters.Type != "" {
+		t.Errorf("expected no load balancer, got %v", envoy.LoadBalancer)
+	}
+	if len(envoy.NodePorts) != 2 || *envoy.NodePorts[0].PortNumber != LocalDevelopmentHTTPNodePort ||
+		*envoy.NodePorts[1].PortNumber != LocalDevelopmentHTTPSNodePort {
+		t.Errorf("expected the node ports of the preset, got %v", envoy.NodePorts)
+	}
+	if cntr.Spec.NetworkPublishing.Envoy.Type != operatorv1alpha1.LoadBalancerServicePublishingType {
+		t.Errorf("expected the preset to be expanded on a copy of the contour")
+	}
+
+	port := int32(31080)
+	cntr.Spec.NetworkPublishing.Envoy.NodePorts = []operatorv1alpha1.NodePort{{Name: "http", PortNumber: &port}, {Name: "https"}}
+	if ports := WithPublishingPreset(cntr).Spec.NetworkPublishing.Envoy.NodePorts; *ports[0].PortNumber != port {
+		t.Errorf("expected the node ports of the contour to be kept, got %v", ports)
+	}
+}
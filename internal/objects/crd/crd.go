@@ -0,0 +1,112 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/projectcontour/contour-operator/internal/equality"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ContourVersionAnnotation is the annotation of a Contour CRD installed by the
+// operator holding the Contour version of the CRD.
+const ContourVersionAnnotation = "contour.operator.projectcontour.io/contour-version"
+
+// EnsureContourCRDs ensures the Contour CRDs, i.e. HTTPProxy, exist and match
+// the CRDs of the Contour version managed by the operator.
+func EnsureContourCRDs(ctx context.Context, cli client.Client) error {
+	desired, err := DesiredContourCRDs()
+	if err != nil {
+		return err
+	}
+	for _, crd := range desired {
+		current, err := currentCRD(ctx, cli, crd.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				if err := createCRD(ctx, cli, crd); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("failed to get crd %s: %w", crd.Name, err)
+		}
+		if err := updateCRDIfNeeded(ctx, cli, current, crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DesiredContourCRDs returns the Contour CRDs of the Contour version managed by
+// the operator.
+func DesiredContourCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(contourCRDs), 4096)
+	for {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := decoder.Decode(crd); err != nil {
+			if err == io.EOF {
+				return crds, nil
+			}
+			return nil, fmt.Errorf("failed to decode contour crds: %w", err)
+		}
+		if crd.Name == "" {
+			continue
+		}
+		// The status of the document is not part of the desired state.
+		crd.Status = apiextensionsv1.CustomResourceDefinitionStatus{}
+		if crd.Annotations == nil {
+			crd.Annotations = map[string]string{}
+		}
+		crd.Annotations[ContourVersionAnnotation] = contourCRDsVersion
+		crds = append(crds, crd)
+	}
+}
+
+// currentCRD returns the current CRD with the given name.
+func currentCRD(ctx context.Context, cli client.Client, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		return nil, err
+	}
+	return crd, nil
+}
+
+// createCRD creates a CRD resource for the provided crd.
+func createCRD(ctx context.Context, cli client.Client, crd *apiextensionsv1.CustomResourceDefinition) error {
+	if err := cli.Create(ctx, crd); err != nil {
+		return fmt.Errorf("failed to create crd %s: %w", crd.Name, err)
+	}
+	return nil
+}
+
+// updateCRDIfNeeded updates a CRD if current does not match desired.
+func updateCRDIfNeeded(ctx context.Context, cli client.Client, current, desired *apiextensionsv1.CustomResourceDefinition) error {
+	crd, updated := equality.CustomResourceDefinitionChanged(current, desired)
+	if updated {
+		if err := cli.Update(ctx, crd); err != nil {
+			return fmt.Errorf("failed to update crd %s: %w", crd.Name, err)
+		}
+	}
+	return nil
+}
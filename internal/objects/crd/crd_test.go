@@ -0,0 +1,44 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"testing"
+)
+
+func TestDesiredContourCRDs(t *testing.T) {
+	crds, err := DesiredContourCRDs()
+	if err != nil {
+		t.Fatalf("failed to get desired contour crds: %v", err)
+	}
+	expected := map[string]bool{
+		"extensionservices.projectcontour.io":         false,
+		"httpproxies.projectcontour.io":               false,
+		"tlscertificatedelegations.projectcontour.io": false,
+	}
+	for _, crd := range crds {
+		if _, ok := expected[crd.Name]; !ok {
+			t.Errorf("unexpected crd %s", crd.Name)
+		}
+		expected[crd.Name] = true
+		if crd.Annotations[ContourVersionAnnotation] != contourCRDsVersion {
+			t.Errorf("expected crd %s to have annotation %s=%s", crd.Name, ContourVersionAnnotation, contourCRDsVersion)
+		}
+	}
+	for name, found := range expected {
+		if !found {
+			t.Errorf("expected crd %s", name)
+		}
+	}
+}
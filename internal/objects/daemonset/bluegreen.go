@@ -0,0 +1,272 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EnvoyFleetLabel is the label of the Envoy pods of a blue-green rollout
+	// naming the fleet, i.e. DaemonSet, of the pods.
+	EnvoyFleetLabel = "contour.operator.projectcontour.io/envoy-fleet"
+	// BlueEnvoyFleet and GreenEnvoyFleet are the fleets of a blue-green rollout.
+	BlueEnvoyFleet  = "blue"
+	GreenEnvoyFleet = "green"
+	// envoySvcName is the name of the Envoy Service whose selector holds the
+	// active fleet of a blue-green rollout.
+	envoySvcName = "envoy"
+)
+
+// envoyFleets are the fleets of the Envoy pods of a contour, where the empty
+// fleet is the DaemonSet used by the other rollout strategies.
+var envoyFleets = []string{"", BlueEnvoyFleet, GreenEnvoyFleet}
+
+// EnvoyServiceSelector returns the selector of the Envoy Service of contour
+// given the current Envoy Service, which may be nil. A blue-green rollout keeps
+// the fleet selected by the current Service, which is only switched once the
+// other fleet is available, and starts with the blue fleet.
+func EnvoyServiceSelector(contour *operatorv1alpha1.Contour, current *corev1.Service) map[string]string {
+	selector := map[string]string{}
+	for k, v := range EnvoyDaemonSetPodSelector(contour).MatchLabels {
+		selector[k] = v
+	}
+	if !contour.EnvoyBlueGreenRollout() {
+		return selector
+	}
+	fleet := BlueEnvoyFleet
+	if current != nil {
+		fleet = current.Spec.Selector[EnvoyFleetLabel]
+	}
+	if fleet != "" {
+		selector[EnvoyFleetLabel] = fleet
+	}
+	return selector
+}
+
+// DesiredFleetDaemonSet returns the desired DaemonSet of the given fleet of a
+// blue-green rollout of contour. The pod templates of the fleets share the
+// template hash, which tells whether a fleet runs the desired template.
+func DesiredFleetDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImage, fleet string) *appsv1.DaemonSet {
+	ds := DesiredDaemonSet(contour, contourImage, envoyImage)
+	if fleet == "" {
+		return ds
+	}
+	setTemplateHash(ds)
+	ds.Name = fleetDaemonSetName(contour, fleet)
+	ds.Spec.Selector.MatchLabels[EnvoyFleetLabel] = fleet
+	labels := map[string]string{EnvoyFleetLabel: fleet}
+	for k, v := range ds.Spec.Template.Labels {
+		labels[k] = v
+	}
+	ds.Spec.Template.Labels = labels
+	return ds
+}
+
+// fleetDaemonSetName returns the name of the DaemonSet of the given fleet of
+// contour.
+func fleetDaemonSetName(contour *operatorv1alpha1.Contour, fleet string) string {
+	if fleet == "" {
+		return objcontour.ResourceName(contour, envoyDaemonSetName)
+	}
+	return objcontour.ResourceName(contour, envoyDaemonSetName+"-"+fleet)
+}
+
+// idleFleet returns the fleet a blue-green rollout provisions while active
+// serves traffic.
+func idleFleet(active string) string {
+	if active == BlueEnvoyFleet {
+		return GreenEnvoyFleet
+	}
+	return BlueEnvoyFleet
+}
+
+// fleetAvailable returns true if the pods of ds run its current template and
+// are available on every node ds is scheduled to.
+func fleetAvailable(ds *appsv1.DaemonSet) bool {
+	return ds.Status.ObservedGeneration >= ds.Generation &&
+		ds.Status.DesiredNumberScheduled > 0 &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled &&
+		ds.Status.NumberAvailable == ds.Status.DesiredNumberScheduled
+}
+
+// activeFleet returns the fleet selected by the Envoy Service of contour, or
+// the blue fleet if the Service does not exist.
+func activeFleet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (string, error) {
+	svc, err := currentEnvoySvc(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return BlueEnvoyFleet, nil
+		}
+		return "", fmt.Errorf("failed to get envoy service for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	return svc.Spec.Selector[EnvoyFleetLabel], nil
+}
+
+// currentEnvoySvc returns the current Envoy Service of contour.
+func currentEnvoySvc(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Service, error) {
+	svc := &corev1.Service{}
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      objcontour.ResourceName(contour, envoySvcName),
+	}
+	if err := cli.Get(ctx, key, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// currentFleetDaemonSet returns the current DaemonSet of the given fleet of
+// contour.
+func currentFleetDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, fleet string) (*appsv1.DaemonSet, error) {
+	ds := &appsv1.DaemonSet{}
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      fleetDaemonSetName(contour, fleet),
+	}
+	if err := cli.Get(ctx, key, ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// ensureFleetDaemonSet ensures the DaemonSet of the given fleet of contour is
+// desired, returning the current DaemonSet or nil if it was created.
+func ensureFleetDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, desired *appsv1.DaemonSet, fleet string) (*appsv1.DaemonSet, error) {
+	current, err := currentFleetDaemonSet(ctx, cli, contour, fleet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, createDaemonSet(ctx, cli, desired)
+		}
+		return nil, fmt.Errorf("failed to get daemonset %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	if err := updateDaemonSetIfNeeded(ctx, cli, contour, current, desired); err != nil {
+		return nil, fmt.Errorf("failed to update daemonset for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	return currentFleetDaemonSet(ctx, cli, contour, fleet)
+}
+
+// ensureBlueGreenDaemonSets ensures the Envoy fleets of a blue-green rollout of
+// contour. The active fleet, selected by the Envoy Service, is updated in place
+// if it runs the desired pod template. Otherwise the idle fleet is provisioned
+// from the desired template and, once available, the Envoy Service is switched
+// to it and the previously active fleet is deleted. Status changes of the idle
+// DaemonSet requeue contour while the idle fleet becomes available.
+func ensureBlueGreenDaemonSets(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, contourImage, envoyImage string) error {
+	active, err := activeFleet(ctx, cli, contour)
+	if err != nil {
+		return err
+	}
+	if active != "" {
+		desired := DesiredFleetDaemonSet(contour, contourImage, envoyImage, active)
+		current, err := currentFleetDaemonSet(ctx, cli, contour, active)
+		switch {
+		case errors.IsNotFound(err):
+			// No Envoy pods serve the Service, so there is nothing to switch over from.
+			if err := createDaemonSet(ctx, cli, desired); err != nil {
+				return err
+			}
+			return deleteIdleFleets(ctx, cli, contour, active)
+		case err != nil:
+			return fmt.Errorf("failed to get daemonset %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		// A deferred change keeps the active fleet instead of provisioning the idle fleet.
+		wait := deferTemplateChange(contour, current, desired, time.Now())
+		if current.Spec.Template.Annotations[envoyTemplateHashAnnotation] ==
+			desired.Spec.Template.Annotations[envoyTemplateHashAnnotation] {
+			if err := updateDaemonSetIfNeeded(ctx, cli, contour, current, desired); err != nil {
+				return fmt.Errorf("failed to update daemonset for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+			}
+			if err := deleteIdleFleets(ctx, cli, contour, active); err != nil {
+				return err
+			}
+			return deferredChangeError(contour, wait)
+		}
+	}
+
+	idle := idleFleet(active)
+	ds, err := ensureFleetDaemonSet(ctx, cli, contour, DesiredFleetDaemonSet(contour, contourImage, envoyImage, idle), idle)
+	if err != nil || ds == nil || !fleetAvailable(ds) {
+		return err
+	}
+	if err := switchEnvoyService(ctx, cli, contour, idle); err != nil {
+		return err
+	}
+	return deleteIdleFleets(ctx, cli, contour, idle)
+}
+
+// switchEnvoyService switches the selector of the Envoy Service of contour to
+// the pods of the given fleet.
+func switchEnvoyService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, fleet string) error {
+	svc, err := currentEnvoySvc(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get envoy service for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	if !objcontour.IsOwned(svc, contour) {
+		return nil
+	}
+	updated := svc.DeepCopy()
+	updated.Spec.Selector = EnvoyServiceSelector(contour, nil)
+	updated.Spec.Selector[EnvoyFleetLabel] = fleet
+	if err := cli.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update service %s/%s: %w", updated.Namespace, updated.Name, err)
+	}
+	return nil
+}
+
+// deleteIdleFleets deletes the Envoy DaemonSets of contour other than the
+// DaemonSet of the given fleet.
+func deleteIdleFleets(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, fleet string) error {
+	for _, f := range envoyFleets {
+		if f == fleet {
+			continue
+		}
+		if err := deleteFleetDaemonSet(ctx, cli, contour, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteFleetDaemonSet deletes the DaemonSet of the given fleet of contour if
+// Contour owner labels exist.
+func deleteFleetDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, fleet string) error {
+	ds, err := currentFleetDaemonSet(ctx, cli, contour, fleet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if objcontour.IsOwned(ds, contour) {
+		if err := cli.Delete(ctx, ds); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete daemonset %s/%s: %w", ds.Namespace, ds.Name, err)
+		}
+	}
+	return nil
+}
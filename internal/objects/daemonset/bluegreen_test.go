@@ -0,0 +1,96 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"context"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBlueGreenRollout(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.EnvoyRollout = &operatorv1alpha1.EnvoyRollout{Strategy: operatorv1alpha1.BlueGreenEnvoyRolloutStrategy}
+	contourImage := "ghcr.io/projectcontour/contour:test"
+
+	blue := DesiredFleetDaemonSet(cntr, contourImage, "docker.io/envoyproxy/envoy:old", BlueEnvoyFleet)
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cntr.Spec.Namespace.Name,
+			Name:      objcontour.ResourceName(cntr, envoySvcName),
+			Labels: map[string]string{
+				operatorv1alpha1.OwningContourNameLabel: cntr.Name,
+				operatorv1alpha1.OwningContourNsLabel:   cntr.Namespace,
+			},
+		},
+		Spec: corev1.ServiceSpec{Selector: EnvoyServiceSelector(cntr, nil)},
+	}
+	if got := svc.Spec.Selector[EnvoyFleetLabel]; got != BlueEnvoyFleet {
+		t.Fatalf("expected a new envoy service to select fleet %s, got %q", BlueEnvoyFleet, got)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(blue, svc).Build()
+
+	fleet := func() string {
+		current, err := currentEnvoySvc(ctx, cli, cntr)
+		if err != nil {
+			t.Fatalf("failed to get envoy service: %v", err)
+		}
+		return current.Spec.Selector[EnvoyFleetLabel]
+	}
+
+	// The green fleet is provisioned from the updated template while blue serves.
+	if err := EnsureDaemonSet(ctx, cli, cntr, contourImage, "docker.io/envoyproxy/envoy:new"); err != nil {
+		t.Fatalf("failed to ensure daemonset: %v", err)
+	}
+	green, err := currentFleetDaemonSet(ctx, cli, cntr, GreenEnvoyFleet)
+	if err != nil {
+		t.Fatalf("expected green fleet daemonset to exist: %v", err)
+	}
+	if got := green.Spec.Template.Labels[EnvoyFleetLabel]; got != GreenEnvoyFleet {
+		t.Errorf("expected green fleet pods to have label %s=%s, got %q", EnvoyFleetLabel, GreenEnvoyFleet, got)
+	}
+	if got := fleet(); got != BlueEnvoyFleet {
+		t.Errorf("expected envoy service to select fleet %s before green is available, got %q", BlueEnvoyFleet, got)
+	}
+
+	// Once green is available, the service switches to green and blue is removed.
+	green.Status.DesiredNumberScheduled = 3
+	green.Status.UpdatedNumberScheduled = 3
+	green.Status.NumberAvailable = 3
+	if err := cli.Update(ctx, green); err != nil {
+		t.Fatalf("failed to update green fleet daemonset: %v", err)
+	}
+	if err := EnsureDaemonSet(ctx, cli, cntr, contourImage, "docker.io/envoyproxy/envoy:new"); err != nil {
+		t.Fatalf("failed to ensure daemonset: %v", err)
+	}
+	if got := fleet(); got != GreenEnvoyFleet {
+		t.Errorf("expected envoy service to select fleet %s, got %q", GreenEnvoyFleet, got)
+	}
+	if _, err := currentFleetDaemonSet(ctx, cli, cntr, BlueEnvoyFleet); !errors.IsNotFound(err) {
+		t.Errorf("expected blue fleet daemonset to be deleted, got %v", err)
+	}
+	current, err := CurrentDaemonSet(ctx, cli, cntr)
+	if err != nil || current.Name != green.Name {
+		t.Errorf("expected current daemonset %s, got %v, %v", green.Name, current, err)
+	}
+}
@@ -17,20 +17,22 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	"github.com/projectcontour/contour-operator/internal/equality"
 	opintstr "github.com/projectcontour/contour-operator/internal/intstr"
+	"github.com/projectcontour/contour-operator/internal/maintenance"
 	objutil "github.com/projectcontour/contour-operator/internal/objects"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	"github.com/projectcontour/contour-operator/internal/objects/rollback"
 	objcfg "github.com/projectcontour/contour-operator/internal/objects/sharedconfig"
-	"github.com/projectcontour/contour-operator/pkg/labels"
+	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +43,8 @@ const (
 	// [TODO] danehans: Remove and use contour.Name + "-envoy" when
 	// https://github.com/projectcontour/contour/issues/2122 is fixed.
 	envoyDaemonSetName = "envoy"
+	// contourSvcName is the name of Contour's Service that Envoy connects to for xDS.
+	contourSvcName = "contour"
 	// EnvoyContainerName is the name of the Envoy container.
 	EnvoyContainerName = "envoy"
 	// ShutdownContainerName is the name of the Shutdown Manager container.
@@ -55,6 +59,9 @@ const (
 	envoyCertsVolName = "envoycert"
 	// envoyCertsVolMntDir is the directory name of the Envoy certificates volume.
 	envoyCertsVolMntDir = "certs"
+	// suspendedNodeLabel is the node label selected by the Envoy pods of a
+	// suspended contour.
+	suspendedNodeLabel = "contour.operator.projectcontour.io/suspended"
 	// envoyCertsSecretName is the name of the secret used as the certificate volume source.
 	envoyCertsSecretName = envoyCertsVolName
 	// envoyCfgVolName is the name of the Envoy configuration volume.
@@ -65,10 +72,35 @@ const (
 	envoyCfgFileName = "envoy.json"
 	// xdsResourceVersion is the version of the Envoy xdS resource types.
 	xdsResourceVersion = "v3"
+	// safeToEvictAnnotation determines whether the cluster autoscaler may evict
+	// a pod to scale down its node.
+	safeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	// safeToEvictLocalVolumesAnnotation lists the local volumes of a pod that do
+	// not block the cluster autoscaler from evicting it.
+	safeToEvictLocalVolumesAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict-local-volumes"
+	// enableDSEvictionAnnotation determines whether the cluster autoscaler
+	// evicts a DaemonSet pod before removing its node.
+	enableDSEvictionAnnotation = "cluster-autoscaler.kubernetes.io/enable-ds-eviction"
 )
 
+// applyClusterAutoscalerAnnotations sets the cluster autoscaler annotations of
+// the Envoy pod template meta for the eviction policy.
+func applyClusterAutoscalerAnnotations(meta *metav1.ObjectMeta, policy operatorv1alpha1.EvictionPolicy) {
+	switch policy {
+	case operatorv1alpha1.AllowEviction:
+		meta.Annotations[safeToEvictAnnotation] = "true"
+		meta.Annotations[safeToEvictLocalVolumesAnnotation] = envoyCfgVolName
+		meta.Annotations[enableDSEvictionAnnotation] = "true"
+	case operatorv1alpha1.ForbidEviction:
+		meta.Annotations[safeToEvictAnnotation] = "false"
+	}
+}
+
 // EnsureDaemonSet ensures a DaemonSet exists for the given contour.
 func EnsureDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, contourImage, envoyImage string) error {
+	if contour.EnvoyBlueGreenRollout() {
+		return ensureBlueGreenDaemonSets(ctx, cli, contour, contourImage, envoyImage)
+	}
 	desired := DesiredDaemonSet(contour, contourImage, envoyImage)
 	current, err := CurrentDaemonSet(ctx, cli, contour)
 	if err != nil {
@@ -81,27 +113,33 @@ func EnsureDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1
 	if differ {
 		return EnsureDaemonSetDeleted(ctx, cli, contour)
 	}
+	wait := deferTemplateChange(contour, current, desired, time.Now())
+	// A canary rollout halts on unhealthy pods instead of rolling back.
+	if !contour.EnvoyCanaryRollout() {
+		failure, err := rolloutFailure(ctx, cli, contour, current)
+		if err != nil {
+			return err
+		}
+		rollback.Apply(current, desired, &desired.Spec.Template, fleetAvailable(current), failure)
+	}
 	if err := updateDaemonSetIfNeeded(ctx, cli, contour, current, desired); err != nil {
 		return fmt.Errorf("failed to update daemonset for contour %s/%s: %w", contour.Namespace, contour.Name, err)
 	}
-	return nil
+	// The fleets of a previous blue-green rollout keep serving, since the Envoy
+	// Service selects their pods too, until the DaemonSet is available.
+	if fleetAvailable(current) {
+		if err := deleteIdleFleets(ctx, cli, contour, ""); err != nil {
+			return err
+		}
+	}
+	return deferredChangeError(contour, wait)
 }
 
-// EnsureDaemonSetDeleted ensures the DaemonSet for the provided contour is deleted
-// if Contour owner labels exist.
+// EnsureDaemonSetDeleted ensures the DaemonSets for the provided contour are
+// deleted if Contour owner labels exist.
 func EnsureDaemonSetDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
-	ds, err := CurrentDaemonSet(ctx, cli, contour)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
-		return err
-	}
-	if labels.Exist(ds, objcontour.OwnerLabels(contour)) {
-		if err := cli.Delete(ctx, ds); err != nil {
-			if errors.IsNotFound(err) {
-				return nil
-			}
+	for _, fleet := range envoyFleets {
+		if err := deleteFleetDaemonSet(ctx, cli, contour, fleet); err != nil {
 			return err
 		}
 	}
@@ -122,14 +160,35 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 		operatorv1alpha1.OwningContourNameLabel: contour.Name,
 	}
 
+	hostPorts := map[string]int32{}
+	for _, m := range contour.Spec.NetworkPublishing.Envoy.HostPorts {
+		hostPorts[m.ContainerPort] = m.HostPort
+	}
 	var ports []corev1.ContainerPort
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.ContainerPorts {
 		p := corev1.ContainerPort{
 			Name:          port.Name,
 			ContainerPort: port.PortNumber,
+			HostPort:      hostPorts[port.Name],
 			Protocol:      corev1.ProtocolTCP,
 		}
 		ports = append(ports, p)
+		if port.Name == "https" && contour.Spec.NetworkPublishing.Envoy.HTTP3 {
+			ports = append(ports, corev1.ContainerPort{
+				Name:          "http3",
+				ContainerPort: port.PortNumber,
+				HostPort:      hostPorts[port.Name],
+				Protocol:      corev1.ProtocolUDP,
+			})
+		}
+	}
+	for _, port := range contour.Spec.NetworkPublishing.Envoy.ExtraPorts {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.ContainerPort,
+			HostPort:      hostPorts[port.Name],
+			Protocol:      corev1.ProtocolTCP,
+		})
 	}
 
 	containers := []corev1.Container{
@@ -254,7 +313,7 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 			Args: []string{
 				"bootstrap",
 				filepath.Join("/", envoyCfgVolMntDir, envoyCfgFileName),
-				"--xds-address=contour",
+				fmt.Sprintf("--xds-address=%s", xdsAddress(contour)),
 				fmt.Sprintf("--xds-port=%d", objcfg.XDSPort),
 				fmt.Sprintf("--xds-resource-version=%s", xdsResourceVersion),
 				fmt.Sprintf("--resources-dir=%s", filepath.Join("/", envoyCfgVolMntDir, "resources")),
@@ -293,13 +352,13 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 	ds := &appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: contour.Spec.Namespace.Name,
-			Name:      envoyDaemonSetName,
+			Name:      objcontour.ResourceName(contour, envoyDaemonSetName),
 			Labels:    labels,
 		},
 		Spec: appsv1.DaemonSetSpec{
 			RevisionHistoryLimit: pointer.Int32Ptr(int32(10)),
 			// Ensure the deamonset adopts only its own pods.
-			Selector: EnvoyDaemonSetPodSelector(),
+			Selector: EnvoyDaemonSetPodSelector(contour),
 			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
 				Type: appsv1.RollingUpdateDaemonSetStrategyType,
 				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
@@ -315,7 +374,7 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 						"prometheus.io/port":   "8002",
 						"prometheus.io/path":   "/stats/prometheus",
 					},
-					Labels: EnvoyDaemonSetPodSelector().MatchLabels,
+					Labels: EnvoyDaemonSetPodSelector(contour).MatchLabels,
 				},
 				Spec: corev1.PodSpec{
 					Containers:     containers,
@@ -326,7 +385,7 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 							VolumeSource: corev1.VolumeSource{
 								Secret: &corev1.SecretVolumeSource{
 									DefaultMode: pointer.Int32Ptr(int32(420)),
-									SecretName:  envoyCertsSecretName,
+									SecretName:  objcontour.CertsSecretName(contour, envoyCertsSecretName),
 								},
 							},
 						},
@@ -337,11 +396,12 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 							},
 						},
 					},
-					ServiceAccountName:            objutil.EnvoyRbacName,
-					DeprecatedServiceAccount:      EnvoyContainerName,
+					ServiceAccountName:            objcontour.ResourceName(contour, objutil.EnvoyRbacName),
+					DeprecatedServiceAccount:      objcontour.ResourceName(contour, objutil.EnvoyRbacName),
 					AutomountServiceAccountToken:  pointer.BoolPtr(false),
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(int64(300)),
 					SecurityContext:               &corev1.PodSecurityContext{},
+					Affinity:                      &corev1.Affinity{NodeAffinity: objcontour.EnvoyNodeAffinity(contour)},
 					DNSPolicy:                     corev1.DNSClusterFirst,
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					SchedulerName:                 "default-scheduler",
@@ -358,20 +418,155 @@ func DesiredDaemonSet(contour *operatorv1alpha1.Contour, contourImage, envoyImag
 		ds.Spec.Template.Spec.Tolerations = contour.Spec.NodePlacement.Envoy.Tolerations
 	}
 
+	if contour.Suspended() {
+		// No node has the label, so the Envoy pods are removed from all nodes.
+		ds.Spec.Template.Spec.NodeSelector = map[string]string{suspendedNodeLabel: "true"}
+	}
+
+	if contour.Spec.ClusterAutoscaler != nil {
+		applyClusterAutoscalerAnnotations(&ds.Spec.Template.ObjectMeta, contour.Spec.ClusterAutoscaler.EnvoyEviction)
+	}
+
+	// Set before the template hash, so that the rollout of the restart is
+	// handled like any other change of the template.
+	objcontour.ApplyCertificatesRotation(&ds.Spec.Template.ObjectMeta, contour)
+	objcontour.ApplySidecarInjectionExclusion(&ds.Spec.Template.ObjectMeta, contour)
+
+	if contour.EnvoyCanaryRollout() {
+		// The operator replaces outdated pods during a canary rollout.
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType}
+		setTemplateHash(ds)
+	}
+
+	if contour.MaintenanceWindowsExist() {
+		// The template hash tells whether a change of the template is deferred.
+		setTemplateHash(ds)
+	}
+
+	objcontour.ApplyRenderedBy(ds)
+	objcontour.ApplyResourceMetadata(ds, contour)
 	return ds
 }
 
-// CurrentDaemonSet returns the current DaemonSet resource for the provided contour.
+// xdsAddress returns the address Envoy connects to Contour of contour by, the
+// name of the Contour service qualified by the spec namespace and cluster
+// domain of contour if a cluster domain is set.
+func xdsAddress(contour *operatorv1alpha1.Contour) string {
+	name := objcontour.ResourceName(contour, contourSvcName)
+	if contour.Spec.ClusterDomain == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", name, contour.Spec.Namespace.Name, contour.Spec.ClusterDomain)
+}
+
+// CurrentDaemonSet returns the current DaemonSet resource for the provided contour,
+// i.e. the DaemonSet of the active fleet of a blue-green rollout.
 func CurrentDaemonSet(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*appsv1.DaemonSet, error) {
-	ds := &appsv1.DaemonSet{}
-	key := types.NamespacedName{
-		Namespace: contour.Spec.Namespace.Name,
-		Name:      envoyDaemonSetName,
+	fleet := ""
+	if contour.EnvoyBlueGreenRollout() {
+		var err error
+		if fleet, err = activeFleet(ctx, cli, contour); err != nil {
+			return nil, err
+		}
+	}
+	return currentFleetDaemonSet(ctx, cli, contour, fleet)
+}
+
+// rolloutFailure returns why the rollout of ds failed, i.e. an Envoy pod is
+// crash looping before all pods are updated, or an empty string if the rollout
+// did not fail.
+func rolloutFailure(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, ds *appsv1.DaemonSet) (string, error) {
+	if fleetAvailable(ds) {
+		return "", nil
+	}
+	// Only the pods of the current template count, since the pods of a rolled
+	// back template may still be terminating.
+	hash, err := currentRevisionHash(ctx, cli, contour, ds)
+	if err != nil || hash == "" {
+		return "", err
+	}
+	labels := map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash}
+	for k, v := range EnvoyDaemonSetPodSelector(contour).MatchLabels {
+		labels[k] = v
+	}
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(ds.Namespace), client.MatchingLabels(labels)); err != nil {
+		return "", fmt.Errorf("failed to list envoy pods in namespace %s: %w", ds.Namespace, err)
+	}
+	return rollback.CrashLooping(pods.Items), nil
+}
+
+// currentRevisionHash returns the hash of the newest ControllerRevision of ds,
+// or an empty string if the DaemonSet controller did not observe the current
+// template of ds yet.
+func currentRevisionHash(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, ds *appsv1.DaemonSet) (string, error) {
+	if ds.Status.ObservedGeneration < ds.Generation {
+		return "", nil
+	}
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := cli.List(ctx, revisions, client.InNamespace(ds.Namespace),
+		client.MatchingLabels(EnvoyDaemonSetPodSelector(contour).MatchLabels)); err != nil {
+		return "", fmt.Errorf("failed to list envoy controller revisions in namespace %s: %w", ds.Namespace, err)
+	}
+	hash := ""
+	newest := int64(-1)
+	for i := range revisions.Items {
+		rev := &revisions.Items[i]
+		if !metav1.IsControlledBy(rev, ds) || rev.Revision <= newest {
+			continue
+		}
+		newest = rev.Revision
+		hash = rev.Labels[appsv1.DefaultDaemonSetUniqueLabelKey]
+	}
+	return hash, nil
+}
+
+// deferTemplateChange sets the pod template of desired to the template of
+// current if contour has maintenance windows, the templates differ and no
+// window is open, returning how long until the next window starts or zero if
+// the change of the template is not deferred. Suspending and resuming contour,
+// changes applied by a requested resync and restarts for a rotation of the xDS
+// certificates are never deferred, since the Contour pods already restarted
+// with the certificates of the rotation.
+func deferTemplateChange(contour *operatorv1alpha1.Contour, current, desired *appsv1.DaemonSet, now time.Time) time.Duration {
+	if !contour.MaintenanceWindowsExist() || current.Spec.Template.Annotations[envoyTemplateHashAnnotation] ==
+		desired.Spec.Template.Annotations[envoyTemplateHashAnnotation] {
+		return 0
 	}
-	if err := cli.Get(ctx, key, ds); err != nil {
-		return nil, err
+	if suspended(current) != suspended(desired) || contour.ResyncRequested() ||
+		current.Spec.Template.Annotations[objcontour.CertificatesRotationAnnotation] !=
+			desired.Spec.Template.Annotations[objcontour.CertificatesRotationAnnotation] {
+		return 0
+	}
+	open, wait := maintenance.Next(contour.Spec.UpdatePolicy.MaintenanceWindows, now)
+	if open || wait == 0 {
+		return 0
+	}
+	desired.Spec.Template = *current.Spec.Template.DeepCopy()
+	// The template is still rendered by the operator that rendered current.
+	if version, ok := current.Annotations[objcontour.RenderedByAnnotation]; ok {
+		desired.Annotations[objcontour.RenderedByAnnotation] = version
+	} else {
+		delete(desired.Annotations, objcontour.RenderedByAnnotation)
+	}
+	return wait
+}
+
+// suspended returns true if ds removes the Envoy pods of a suspended contour.
+func suspended(ds *appsv1.DaemonSet) bool {
+	_, ok := ds.Spec.Template.Spec.NodeSelector[suspendedNodeLabel]
+	return ok
+}
+
+// deferredChangeError returns a retryable error requeuing contour once its
+// deferred change of the Envoy pod template can be applied after wait, or nil
+// if wait is zero.
+func deferredChangeError(contour *operatorv1alpha1.Contour, wait time.Duration) error {
+	if wait == 0 {
+		return nil
 	}
-	return ds, nil
+	return retryable.New(fmt.Errorf("envoy pod template change of contour %s/%s is deferred until the next "+
+		"maintenance window", contour.Namespace, contour.Name), wait)
 }
 
 // createDaemonSet creates a DaemonSet resource for the provided ds.
@@ -385,7 +580,7 @@ func createDaemonSet(ctx context.Context, cli client.Client, ds *appsv1.DaemonSe
 // updateDaemonSetIfNeeded updates a DaemonSet if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateDaemonSetIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *appsv1.DaemonSet) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		ds, updated := equality.DaemonsetConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, ds); err != nil {
@@ -398,14 +593,15 @@ func updateDaemonSetIfNeeded(ctx context.Context, cli client.Client, contour *op
 }
 
 // EnvoyDaemonSetPodSelector returns a label selector using "app: envoy" as the
-// key/value pair.
+// key/value pair, prefixing the value with the name of contour if it shares its
+// spec namespace.
 //
 // TODO [danehans]: Update to use "contour.operator.projectcontour.io/daemonset-envoy"
 // when https://github.com/projectcontour/contour/issues/1821 is fixed.
-func EnvoyDaemonSetPodSelector() *metav1.LabelSelector {
+func EnvoyDaemonSetPodSelector(contour *operatorv1alpha1.Contour) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			"app": "envoy",
+			"app": objcontour.ResourceName(contour, "envoy"),
 		},
 	}
 }
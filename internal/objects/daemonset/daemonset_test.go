@@ -14,8 +14,10 @@
 package daemonset
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
@@ -24,6 +26,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func checkDaemonSetHasEnvVar(t *testing.T, ds *appsv1.DaemonSet, container, name string) {
@@ -198,3 +203,346 @@ func TestNodePlacementDaemonSet(t *testing.T) {
 	checkDaemonSetHasNodeSelector(t, ds, selectors)
 	checkDaemonSetHasTolerations(t, ds, tolerations)
 }
+
+func TestNodeAffinityDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "arch-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	testCases := []struct {
+		description string
+		archs       []operatorv1alpha1.NodeArchitecture
+		oses        []string
+		expected    []string
+		expectedOS  []string
+	}{
+		{
+			description: "default architectures and operating system",
+			expected:    []string{"amd64", "arm64"},
+			expectedOS:  []string{"linux"},
+		},
+		{
+			description: "amd64 only",
+			archs:       []operatorv1alpha1.NodeArchitecture{operatorv1alpha1.AMD64NodeArchitecture},
+			expected:    []string{"amd64"},
+			expectedOS:  []string{"linux"},
+		},
+		{
+			description: "windows operating system",
+			oses:        []string{"windows"},
+			expected:    []string{"amd64", "arm64"},
+			expectedOS:  []string{"windows"},
+		},
+	}
+	for _, tc := range testCases {
+		cntr.Spec.NodePlacement = &operatorv1alpha1.NodePlacement{
+			Architectures: tc.archs,
+			Envoy:         &operatorv1alpha1.EnvoyNodePlacement{OperatingSystems: tc.oses},
+		}
+		ds := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+		affinity := ds.Spec.Template.Spec.Affinity
+		if affinity == nil || affinity.NodeAffinity == nil ||
+			affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			t.Fatalf("%q: expected daemonset to have a required node affinity", tc.description)
+		}
+		terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		expected := []corev1.NodeSelectorRequirement{
+			{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: tc.expectedOS},
+			{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: tc.expected},
+		}
+		if len(terms) != 1 || !apiequality.Semantic.DeepEqual(terms[0].MatchExpressions, expected) {
+			t.Errorf("%q: expected node selector terms %v, got %v", tc.description, expected, terms)
+		}
+	}
+}
+
+func TestHostPortsDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "hostports-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.NodePortServicePublishingType,
+	})
+	ds := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	container := checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	for _, port := range container.Ports {
+		if port.HostPort != 0 {
+			t.Errorf("expected port %s without a host port, got %d", port.Name, port.HostPort)
+		}
+	}
+
+	cntr.Spec.NetworkPublishing.Envoy.HTTP3 = true
+	cntr.Spec.NetworkPublishing.Envoy.HostPorts = []operatorv1alpha1.EnvoyHostPortMapping{
+		{ContainerPort: "https", HostPort: 443},
+	}
+	ds = DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	container = checkDaemonSetHasContainer(t, ds, EnvoyContainerName, true)
+	expected := map[string]int32{"http": 0, "https": 443, "http3": 443}
+	for _, port := range container.Ports {
+		if port.HostPort != expected[port.Name] {
+			t.Errorf("expected port %s with host port %d, got %d", port.Name, expected[port.Name], port.HostPort)
+		}
+		if port.ContainerPort == port.HostPort {
+			t.Errorf("expected port %s with a container port distinct from its host port", port.Name)
+		}
+	}
+}
+
+func TestSuspendedDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "suspend-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.UpdatePolicy = &operatorv1alpha1.UpdatePolicy{
+		MaintenanceWindows: []operatorv1alpha1.MaintenanceWindow{{Days: []operatorv1alpha1.Weekday{"Monday"},
+			Start: "02:00", Duration: "1h"}},
+	}
+	// A Wednesday, outside of the maintenance window.
+	now := time.Date(2021, time.June, 9, 12, 0, 0, 0, time.UTC)
+	current := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+
+	cntr.Annotations = map[string]string{operatorv1alpha1.SuspendAnnotation: "true"}
+	suspendedDS := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	checkDaemonSetHasNodeSelector(t, suspendedDS, map[string]string{suspendedNodeLabel: "true"})
+	if wait := deferTemplateChange(cntr, current, suspendedDS.DeepCopy(), now); wait != 0 {
+		t.Errorf("expected suspending not to be deferred, got %v", wait)
+	}
+
+	cntr.Annotations = nil
+	if wait := deferTemplateChange(cntr, suspendedDS, current.DeepCopy(), now); wait != 0 {
+		t.Errorf("expected resuming not to be deferred, got %v", wait)
+	}
+
+	cntr.Spec.NodePlacement = &operatorv1alpha1.NodePlacement{
+		Envoy: &operatorv1alpha1.EnvoyNodePlacement{NodeSelector: map[string]string{"node-role": "envoy"}},
+	}
+	changed := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if wait := deferTemplateChange(cntr, current, changed, now); wait == 0 {
+		t.Errorf("expected other changes to be deferred")
+	}
+}
+
+func TestClusterAutoscalerDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "autoscaler-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	ds := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if _, ok := ds.Spec.Template.Annotations[safeToEvictAnnotation]; ok {
+		t.Errorf("expected no cluster autoscaler annotations by default")
+	}
+
+	testCases := map[operatorv1alpha1.EvictionPolicy]map[string]string{
+		operatorv1alpha1.AllowEviction: {
+			safeToEvictAnnotation:             "true",
+			safeToEvictLocalVolumesAnnotation: envoyCfgVolName,
+			enableDSEvictionAnnotation:        "true",
+		},
+		operatorv1alpha1.ForbidEviction: {
+			safeToEvictAnnotation: "false",
+		},
+	}
+	for policy, expected := range testCases {
+		cntr.Spec.ClusterAutoscaler = &operatorv1alpha1.ClusterAutoscalerParameters{EnvoyEviction: policy}
+		ds := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+		for k, v := range expected {
+			if ds.Spec.Template.Annotations[k] != v {
+				t.Errorf("%s: expected annotation %s=%s, got %q", policy, k, v, ds.Spec.Template.Annotations[k])
+			}
+		}
+		if policy == operatorv1alpha1.ForbidEviction {
+			if _, ok := ds.Spec.Template.Annotations[enableDSEvictionAnnotation]; ok {
+				t.Errorf("%s: expected no %s annotation", policy, enableDSEvictionAnnotation)
+			}
+		}
+	}
+}
+
+func TestRenderedByDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "rendered-by-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	current := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if _, other := objcontour.RenderedByOtherVersion(current); other {
+		t.Errorf("expected daemonset to be rendered by the running operator, got %q",
+			current.Annotations[objcontour.RenderedByAnnotation])
+	}
+
+	// A change rendered by an upgraded operator deferred to a maintenance
+	// window keeps the version of the operator that rendered the template.
+	current.Annotations[objcontour.RenderedByAnnotation] = "v1.15.0"
+	cntr.Spec.UpdatePolicy = &operatorv1alpha1.UpdatePolicy{
+		MaintenanceWindows: []operatorv1alpha1.MaintenanceWindow{{Days: []operatorv1alpha1.Weekday{"Monday"},
+			Start: "02:00", Duration: "1h"}},
+	}
+	now := time.Date(2021, time.June, 9, 12, 0, 0, 0, time.UTC)
+	current.Spec.Template.Annotations[envoyTemplateHashAnnotation] = "outdated"
+	desired := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if wait := deferTemplateChange(cntr, current, desired, now); wait == 0 {
+		t.Fatalf("expected the change to be deferred")
+	}
+	if version, other := objcontour.RenderedByOtherVersion(desired); !other || version != "v1.15.0" {
+		t.Errorf("expected deferred daemonset to be rendered by v1.15.0, got %q", version)
+	}
+}
+
+func TestResyncDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "resync-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.UpdatePolicy = &operatorv1alpha1.UpdatePolicy{
+		MaintenanceWindows: []operatorv1alpha1.MaintenanceWindow{{Days: []operatorv1alpha1.Weekday{"Monday"},
+			Start: "02:00", Duration: "1h"}},
+	}
+	// A Wednesday, outside of the maintenance window.
+	now := time.Date(2021, time.June, 9, 12, 0, 0, 0, time.UTC)
+	// The current template was changed manually.
+	current := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	current.Spec.Template.Annotations[envoyTemplateHashAnnotation] = "manual"
+
+	cntr.Annotations = map[string]string{operatorv1alpha1.ResyncAnnotation: "2021-06-09T12:00:00Z"}
+	desired := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if wait := deferTemplateChange(cntr, current, desired, now); wait != 0 {
+		t.Errorf("expected a requested resync not to be deferred, got %v", wait)
+	}
+
+	cntr.Status.Resync = "2021-06-09T12:00:00Z"
+	if wait := deferTemplateChange(cntr, current, desired, now); wait == 0 {
+		t.Errorf("expected changes after a completed resync to be deferred")
+	}
+}
+
+func TestCertificatesRotationDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "rotation-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.UpdatePolicy = &operatorv1alpha1.UpdatePolicy{
+		MaintenanceWindows: []operatorv1alpha1.MaintenanceWindow{{Days: []operatorv1alpha1.Weekday{"Monday"},
+			Start: "02:00", Duration: "1h"}},
+	}
+	// A Wednesday, outside of the maintenance window.
+	now := time.Date(2021, time.June, 9, 12, 0, 0, 0, time.UTC)
+	current := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+
+	// Rotations requested by a user and re-issues of expiring certificates.
+	for _, rotation := range []string{"r1", "expiry-20210610T120000Z"} {
+		cntr.Status.CertificatesRotation = rotation
+		desired := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+		if wait := deferTemplateChange(cntr, current, desired, now); wait != 0 {
+			t.Errorf("expected rotation %q not to be deferred, got %v", rotation, wait)
+		}
+		if got := desired.Spec.Template.Annotations[objcontour.CertificatesRotationAnnotation]; got != rotation {
+			t.Errorf("expected pod template annotated with rotation %q, got %q", rotation, got)
+		}
+		current = desired
+	}
+
+	// Other changes after the rotation are deferred.
+	current.Spec.Template.Annotations[envoyTemplateHashAnnotation] = "outdated"
+	desired := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+	if wait := deferTemplateChange(cntr, current, desired, now); wait == 0 {
+		t.Errorf("expected changes after a completed rotation to be deferred")
+	}
+}
+
+func TestClusterDomainDaemonSet(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "domain-test", Namespace: "default", SpecNs: "projectcontour"})
+	expected := map[string]string{
+		"":          "--xds-address=contour",
+		"corp.test": "--xds-address=contour.projectcontour.svc.corp.test",
+	}
+	for domain, arg := range expected {
+		cntr.Spec.ClusterDomain = domain
+		ds := DesiredDaemonSet(cntr, config.DefaultContourImage, config.DefaultEnvoyImage)
+		found := false
+		for _, a := range ds.Spec.Template.Spec.InitContainers[0].Args {
+			found = found || a == arg
+		}
+		if !found {
+			t.Errorf("expected init container arg %q for cluster domain %q, got %v", arg, domain,
+				ds.Spec.Template.Spec.InitContainers[0].Args)
+		}
+	}
+}
+
+func TestRolloutFailure(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	// Template B crash looped and was rolled back to template A, then template
+	// C was pushed while the pods of B still terminate.
+	ds := DesiredDaemonSet(cntr, "ghcr.io/projectcontour/contour:test", "docker.io/envoyproxy/envoy:c")
+	ds.UID = "ds-uid"
+	ds.Generation = 3
+	ds.Status = appsv1.DaemonSetStatus{
+		ObservedGeneration:     3,
+		DesiredNumberScheduled: 3,
+		UpdatedNumberScheduled: 1,
+	}
+	selector := EnvoyDaemonSetPodSelector(cntr).MatchLabels
+	revision := func(hash string, rev int64) *appsv1.ControllerRevision {
+		labels := map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash}
+		for k, v := range selector {
+			labels[k] = v
+		}
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ds.Namespace,
+				Name:            ds.Name + "-" + hash,
+				Labels:          labels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ds, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))},
+			},
+			Revision: rev,
+		}
+	}
+	pod := func(hash string, crashLooping bool) *corev1.Pod {
+		labels := map[string]string{appsv1.DefaultDaemonSetUniqueLabelKey: hash}
+		for k, v := range selector {
+			labels[k] = v
+		}
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ds.Namespace, Name: "envoy-" + hash, Labels: labels},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: EnvoyContainerName}},
+			},
+		}
+		if crashLooping {
+			p.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+		}
+		return p
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		revision("b", 2), revision("a", 3), revision("c", 4), pod("b", true), pod("c", false)).Build()
+	failure, err := rolloutFailure(ctx, cli, cntr, ds)
+	if err != nil {
+		t.Fatalf("failed to check rollout: %v", err)
+	}
+	if failure != "" {
+		t.Errorf("expected the pods of a rolled back template to be ignored, got failure %q", failure)
+	}
+
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		revision("b", 2), revision("a", 3), revision("c", 4), pod("c", true)).Build()
+	failure, err = rolloutFailure(ctx, cli, cntr, ds)
+	if err != nil {
+		t.Fatalf("failed to check rollout: %v", err)
+	}
+	if failure == "" {
+		t.Error("expected a crash looping pod of the current template to fail the rollout")
+	}
+}
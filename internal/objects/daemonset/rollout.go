@@ -0,0 +1,275 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// envoyTemplateHashAnnotation is the annotation of the Envoy pod template
+	// holding a hash of the template, which tells updated pods apart during a
+	// canary rollout.
+	envoyTemplateHashAnnotation = "contour.operator.projectcontour.io/envoy-template-hash"
+	// rolloutRecheckPeriod is how often a waiting canary rollout is rechecked.
+	rolloutRecheckPeriod = 10 * time.Second
+	// defaultCanaryPercentage, defaultCanaryPauseSeconds and defaultCanaryMaxRestarts
+	// are the defaults of a canary rollout without canary parameters.
+	defaultCanaryPercentage   = 10
+	defaultCanaryPauseSeconds = 300
+	defaultCanaryMaxRestarts  = 2
+)
+
+// RolloutStatus is the state of the canary rollout of the Envoy pods.
+type RolloutStatus struct {
+	// Halted is true if the rollout is halted due to an unhealthy updated pod.
+	Halted bool
+	// Message describes why the rollout is halted.
+	Message string
+}
+
+// rolloutPlan is the next step of a canary rollout.
+type rolloutPlan struct {
+	// status is the state of the rollout.
+	status RolloutStatus
+	// delete are the outdated pods to delete, so the DaemonSet recreates them
+	// from the current template.
+	delete []corev1.Pod
+	// wait is how long to wait before rechecking the rollout, or zero if the
+	// rollout is complete or halted.
+	wait time.Duration
+}
+
+// setTemplateHash sets the template hash annotation of the pod template of ds.
+func setTemplateHash(ds *appsv1.DaemonSet) {
+	tmpl := ds.Spec.Template.DeepCopy()
+	delete(tmpl.Annotations, envoyTemplateHashAnnotation)
+	// Marshalling a pod template can not fail.
+	data, _ := json.Marshal(tmpl)
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	if ds.Spec.Template.Annotations == nil {
+		ds.Spec.Template.Annotations = map[string]string{}
+	}
+	ds.Spec.Template.Annotations[envoyTemplateHashAnnotation] = fmt.Sprintf("%x", h.Sum32())
+}
+
+// canaryRollout returns the canary rollout parameters of contour, using the
+// defaults for unset parameters.
+func canaryRollout(contour *operatorv1alpha1.Contour) operatorv1alpha1.EnvoyCanaryRollout {
+	canary := operatorv1alpha1.EnvoyCanaryRollout{
+		Percentage:   defaultCanaryPercentage,
+		PauseSeconds: defaultCanaryPauseSeconds,
+		MaxRestarts:  defaultCanaryMaxRestarts,
+	}
+	if c := contour.Spec.EnvoyRollout.Canary; c != nil {
+		canary = *c.DeepCopy()
+		if canary.Percentage == 0 {
+			canary.Percentage = defaultCanaryPercentage
+		}
+	}
+	return canary
+}
+
+// EnsureRollout ensures the canary rollout of the Envoy pods of contour makes
+// progress, deleting outdated pods so the DaemonSet recreates them. A retryable
+// error is returned while the rollout waits for updated pods to become healthy.
+func EnsureRollout(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	if !contour.EnvoyCanaryRollout() {
+		return nil
+	}
+	plan, err := currentRolloutPlan(ctx, cli, contour)
+	if err != nil || plan == nil {
+		return err
+	}
+	for i := range plan.delete {
+		pod := &plan.delete[i]
+		if err := cli.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete envoy pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	if plan.wait > 0 {
+		return retryable.New(fmt.Errorf("envoy rollout of contour %s/%s is in progress", contour.Namespace,
+			contour.Name), plan.wait)
+	}
+	return nil
+}
+
+// CurrentRolloutStatus returns the state of the canary rollout of the Envoy pods
+// of contour, or nil if contour does not use a canary rollout or its DaemonSet
+// does not exist.
+func CurrentRolloutStatus(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*RolloutStatus, error) {
+	if !contour.EnvoyCanaryRollout() {
+		return nil, nil
+	}
+	plan, err := currentRolloutPlan(ctx, cli, contour)
+	if err != nil || plan == nil {
+		return nil, err
+	}
+	return &plan.status, nil
+}
+
+// currentRolloutPlan returns the next step of the canary rollout of contour from
+// the current DaemonSet, Envoy pods and nodes, or nil if the DaemonSet does not
+// exist.
+func currentRolloutPlan(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*rolloutPlan, error) {
+	ds, err := CurrentDaemonSet(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get daemonset for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(ds.Namespace),
+		client.MatchingLabels(EnvoyDaemonSetPodSelector(contour).MatchLabels)); err != nil {
+		return nil, fmt.Errorf("failed to list envoy pods in namespace %s: %w", ds.Namespace, err)
+	}
+	nodeLabels := map[string]map[string]string{}
+	if len(canaryRollout(contour).NodeSelector) > 0 {
+		for _, pod := range pods.Items {
+			name := pod.Spec.NodeName
+			if _, ok := nodeLabels[name]; ok || name == "" {
+				continue
+			}
+			node := &corev1.Node{}
+			if err := cli.Get(ctx, types.NamespacedName{Name: name}, node); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get node %s: %w", name, err)
+			}
+			nodeLabels[name] = node.Labels
+		}
+	}
+	plan := planRollout(contour, ds, pods.Items, nodeLabels, time.Now())
+	return &plan, nil
+}
+
+// planRollout returns the next step of the canary rollout of contour given the
+// DaemonSet ds, its pods, the labels of the nodes of the pods and the current
+// time now.
+func planRollout(contour *operatorv1alpha1.Contour, ds *appsv1.DaemonSet, pods []corev1.Pod,
+	nodeLabels map[string]map[string]string, now time.Time) rolloutPlan {
+	canary := canaryRollout(contour)
+	hash := ds.Spec.Template.Annotations[envoyTemplateHashAnnotation]
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Spec.NodeName < pods[j].Spec.NodeName })
+	var canaries, others []corev1.Pod
+	if len(canary.NodeSelector) > 0 {
+		selector := labels.SelectorFromSet(canary.NodeSelector)
+		for _, pod := range pods {
+			if selector.Matches(labels.Set(nodeLabels[pod.Spec.NodeName])) {
+				canaries = append(canaries, pod)
+			} else {
+				others = append(others, pod)
+			}
+		}
+	} else {
+		n := (len(pods)*int(canary.Percentage) + 99) / 100
+		canaries, others = pods[:n], pods[n:]
+	}
+
+	updated := func(pod corev1.Pod) bool {
+		return pod.Annotations[envoyTemplateHashAnnotation] == hash
+	}
+	var readySince time.Time
+	for _, pod := range pods {
+		if !updated(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, c := range pod.Status.ContainerStatuses {
+			if c.RestartCount > canary.MaxRestarts {
+				return rolloutPlan{status: RolloutStatus{
+					Halted: true,
+					Message: fmt.Sprintf("Updated envoy pod %s container %s restarted %d times.", pod.Name, c.Name,
+						c.RestartCount),
+				}}
+			}
+		}
+	}
+
+	var plan rolloutPlan
+	for _, pod := range canaries {
+		switch {
+		case pod.DeletionTimestamp != nil:
+			plan.wait = rolloutRecheckPeriod
+		case !updated(pod):
+			plan.delete = append(plan.delete, pod)
+			plan.wait = rolloutRecheckPeriod
+		default:
+			since, ready := podReadySince(pod)
+			if !ready {
+				plan.wait = rolloutRecheckPeriod
+			} else if since.After(readySince) {
+				readySince = since
+			}
+		}
+	}
+	if plan.wait > 0 {
+		return plan
+	}
+	if remaining := readySince.Add(time.Duration(canary.PauseSeconds) * time.Second).Sub(now); remaining > 0 {
+		plan.wait = remaining
+		return plan
+	}
+
+	// Replace the remaining outdated pods with the unavailability of a rolling update.
+	budget := (len(pods) + 9) / 10
+	for _, pod := range others {
+		if _, ready := podReadySince(pod); !ready || pod.DeletionTimestamp != nil {
+			budget--
+		}
+	}
+	for _, pod := range others {
+		if updated(pod) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		plan.wait = rolloutRecheckPeriod
+		if _, ready := podReadySince(pod); ready {
+			if budget <= 0 {
+				continue
+			}
+			budget--
+		}
+		plan.delete = append(plan.delete, pod)
+	}
+	return plan
+}
+
+// podReadySince returns the time pod became ready and true, or false if pod
+// is not ready.
+func podReadySince(pod corev1.Pod) (time.Time, bool) {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return c.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,112 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonset
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlanRollout(t *testing.T) {
+	now := time.Now()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.EnvoyRollout = &operatorv1alpha1.EnvoyRollout{
+		Strategy: operatorv1alpha1.CanaryEnvoyRolloutStrategy,
+		Canary: &operatorv1alpha1.EnvoyCanaryRollout{
+			Percentage:   20,
+			PauseSeconds: 60,
+			MaxRestarts:  1,
+		},
+	}
+	ds := DesiredDaemonSet(cntr, "ghcr.io/projectcontour/contour:test", "docker.io/envoyproxy/envoy:test")
+	if ds.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType {
+		t.Fatalf("expected update strategy %s, got %s", appsv1.OnDeleteDaemonSetStrategyType, ds.Spec.UpdateStrategy.Type)
+	}
+	hash := ds.Spec.Template.Annotations[envoyTemplateHashAnnotation]
+	if hash == "" {
+		t.Fatalf("expected pod template to have annotation %s", envoyTemplateHashAnnotation)
+	}
+
+	// pods returns 10 ready pods, the first updated of which are updated and
+	// became ready at readyAt.
+	pods := func(updated int, readyAt time.Time) []corev1.Pod {
+		var pods []corev1.Pod
+		for i := 0; i < 10; i++ {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf("envoy-%d", i),
+					Annotations: map[string]string{envoyTemplateHashAnnotation: "outdated"},
+				},
+				Spec: corev1.PodSpec{NodeName: fmt.Sprintf("node-%d", i)},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{
+						Type:               corev1.PodReady,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+					}},
+				},
+			}
+			if i < updated {
+				pod.Annotations[envoyTemplateHashAnnotation] = hash
+				pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(readyAt)
+			}
+			pods = append(pods, pod)
+		}
+		return pods
+	}
+	names := func(pods []corev1.Pod) []string {
+		var names []string
+		for _, p := range pods {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	plan := planRollout(cntr, ds, pods(0, now), nil, now)
+	if got := names(plan.delete); len(got) != 2 || got[0] != "envoy-0" || got[1] != "envoy-1" {
+		t.Errorf("expected canary pods envoy-0 and envoy-1 to be deleted, got %v", got)
+	}
+
+	plan = planRollout(cntr, ds, pods(2, now.Add(-30*time.Second)), nil, now)
+	if len(plan.delete) != 0 || plan.wait != 30*time.Second {
+		t.Errorf("expected the rollout to pause for 30s, got %v and wait %s", names(plan.delete), plan.wait)
+	}
+
+	plan = planRollout(cntr, ds, pods(2, now.Add(-time.Minute)), nil, now)
+	if got := names(plan.delete); len(got) != 1 || got[0] != "envoy-2" {
+		t.Errorf("expected pod envoy-2 to be deleted, got %v", got)
+	}
+
+	crashing := pods(2, now)
+	crashing[1].Status.ContainerStatuses = []corev1.ContainerStatus{{Name: EnvoyContainerName, RestartCount: 2}}
+	plan = planRollout(cntr, ds, crashing, nil, now)
+	if !plan.status.Halted || len(plan.delete) != 0 {
+		t.Errorf("expected the rollout to halt, got %#v", plan)
+	}
+
+	cntr.Spec.EnvoyRollout.Canary.NodeSelector = map[string]string{"canary": "true"}
+	nodeLabels := map[string]map[string]string{"node-5": {"canary": "true"}}
+	plan = planRollout(cntr, ds, pods(0, now), nodeLabels, now)
+	if got := names(plan.delete); len(got) != 1 || got[0] != "envoy-5" {
+		t.Errorf("expected canary pod envoy-5 to be deleted, got %v", got)
+	}
+}
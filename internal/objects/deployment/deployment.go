@@ -17,6 +17,8 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	"github.com/projectcontour/contour-operator/internal/equality"
@@ -24,8 +26,9 @@ import (
 	objutil "github.com/projectcontour/contour-operator/internal/objects"
 	objcm "github.com/projectcontour/contour-operator/internal/objects/configmap"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	"github.com/projectcontour/contour-operator/internal/objects/rollback"
 	objcfg "github.com/projectcontour/contour-operator/internal/objects/sharedconfig"
-	"github.com/projectcontour/contour-operator/pkg/labels"
+	"github.com/projectcontour/contour-operator/internal/release"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -42,11 +45,14 @@ const (
 	// [TODO] danehans: Remove and use contour.Name + "-contour" when
 	// https://github.com/projectcontour/contour/issues/2122 is fixed.
 	contourDeploymentName = "contour"
+	// envoySvcName is the name of Envoy's Service.
+	envoySvcName = "envoy"
 	// contourContainerName is the name of the Contour container.
 	contourContainerName = "contour"
 	// contourNsEnvVar is the name of the contour namespace environment variable.
 	contourNsEnvVar = "CONTOUR_NAMESPACE"
 	// contourPodEnvVar is the name of the contour pod name environment variable.
+	// Contour uses it as its leader election identity.
 	contourPodEnvVar = "POD_NAME"
 	// contourCertsVolName is the name of the contour certificates volume.
 	contourCertsVolName = "contourcert"
@@ -64,6 +70,12 @@ const (
 	metricsPort = 8000
 	// debugPort is the network port number of Contour's debug service.
 	debugPort = 6060
+	// progressDeadlineExceededReason is the reason of the Progressing condition
+	// of a Deployment whose rollout exceeded its progress deadline.
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+	// revisionAnnotation is the annotation of a ReplicaSet holding its revision
+	// of the pod template of its Deployment.
+	revisionAnnotation = "deployment.kubernetes.io/revision"
 )
 
 // EnsureDeployment ensures a deployment using image exists for the given contour.
@@ -82,6 +94,11 @@ func EnsureDeployment(ctx context.Context, cli client.Client, contour *operatorv
 	if differ {
 		return EnsureDeploymentDeleted(ctx, cli, contour)
 	}
+	failure, err := rolloutFailure(ctx, cli, contour, current)
+	if err != nil {
+		return err
+	}
+	rollback.Apply(current, desired, &desired.Spec.Template, rolloutComplete(current), failure)
 	if err := updateDeploymentIfNeeded(ctx, cli, contour, current, desired); err != nil {
 		return fmt.Errorf("failed to update deployment %s/%s: %w", desired.Namespace, desired.Name, err)
 	}
@@ -99,7 +116,7 @@ func EnsureDeploymentDeleted(ctx context.Context, cli client.Client, contour *op
 		return err
 	}
 
-	if labels.Exist(deploy, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(deploy, contour) {
 		if err := cli.Delete(ctx, deploy); err != nil {
 			if errors.IsNotFound(err) {
 				return nil
@@ -123,6 +140,9 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 		fmt.Sprintf("--contour-cert-file=%s", filepath.Join("/", contourCertsVolMntDir, "tls.crt")),
 		fmt.Sprintf("--contour-key-file=%s", filepath.Join("/", contourCertsVolMntDir, "tls.key")),
 		fmt.Sprintf("--config-path=%s", filepath.Join("/", contourCfgVolMntDir, contourCfgFileName)),
+		// The kubelet expands the downward API variables, so the namespace is
+		// correct whatever the names of the pods and their namespace.
+		fmt.Sprintf("--envoy-service-namespace=$(%s)", contourNsEnvVar),
 	}
 	// Pass the insecure/secure flags to Contour if using non-default ports.
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.ContainerPorts {
@@ -136,6 +156,32 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 	if contour.Spec.IngressClassName != nil {
 		args = append(args, fmt.Sprintf("--ingress-class-name=%s", *contour.Spec.IngressClassName))
 	}
+	if contour.Spec.Namespace.Shared {
+		// Contour defaults to the unprefixed Envoy service name.
+		args = append(args, fmt.Sprintf("--envoy-service-name=%s", objcontour.ResourceName(contour, envoySvcName)))
+	}
+	if len(contour.Spec.RootNamespaces) > 0 {
+		args = append(args, fmt.Sprintf("--root-namespaces=%s", strings.Join(contour.Spec.RootNamespaces, ",")))
+	}
+	if kc := contour.Spec.KubernetesClient; kc != nil {
+		if kc.QPS > 0 {
+			args = append(args, fmt.Sprintf("--kubernetes-client-qps=%d", kc.QPS))
+		}
+		if kc.Burst > 0 {
+			args = append(args, fmt.Sprintf("--kubernetes-client-burst=%d", kc.Burst))
+		}
+	}
+	if logging := contour.Spec.Logging; logging != nil {
+		if logging.Debug {
+			args = append(args, "--debug")
+		}
+		if logging.KubernetesVerbosity > 0 {
+			args = append(args, fmt.Sprintf("--kubernetes-debug=%d", logging.KubernetesVerbosity))
+		}
+	}
+	if le := contour.Spec.LeaderElection; le != nil && le.DisableForSingleReplica && contour.Spec.Replicas == 1 {
+		args = append(args, "--disable-leader-election")
+	}
 	container := corev1.Container{
 		Name:            contourContainerName,
 		Image:           image,
@@ -224,7 +270,7 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 	deploy := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: contour.Spec.Namespace.Name,
-			Name:      contourDeploymentName,
+			Name:      objcontour.ResourceName(contour, contourDeploymentName),
 			Labels:    makeDeploymentLabels(contour),
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -232,7 +278,7 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 			Replicas:                &contour.Spec.Replicas,
 			RevisionHistoryLimit:    pointer.Int32Ptr(int32(10)),
 			// Ensure the deployment adopts only its own pods.
-			Selector: ContourDeploymentPodSelector(),
+			Selector: ContourDeploymentPodSelector(contour),
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RollingUpdateDeploymentStrategyType,
 				RollingUpdate: &appsv1.RollingUpdateDeployment{
@@ -248,12 +294,13 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 						"prometheus.io/scrape": "true",
 						"prometheus.io/port":   fmt.Sprintf("%d", metricsPort),
 					},
-					Labels: ContourDeploymentPodSelector().MatchLabels,
+					Labels: ContourDeploymentPodSelector(contour).MatchLabels,
 				},
 				Spec: corev1.PodSpec{
 					// TODO [danehans]: Readdress anti-affinity when https://github.com/projectcontour/contour/issues/2997
 					// is resolved.
 					Affinity: &corev1.Affinity{
+						NodeAffinity: objcontour.NodeAffinity(contour),
 						PodAntiAffinity: &corev1.PodAntiAffinity{
 							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
 								{
@@ -261,7 +308,7 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 									PodAffinityTerm: corev1.PodAffinityTerm{
 										TopologyKey: "kubernetes.io/hostname",
 										LabelSelector: &metav1.LabelSelector{
-											MatchLabels: ContourDeploymentPodSelector().MatchLabels,
+											MatchLabels: ContourDeploymentPodSelector(contour).MatchLabels,
 										},
 									},
 								},
@@ -275,7 +322,7 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 							VolumeSource: corev1.VolumeSource{
 								Secret: &corev1.SecretVolumeSource{
 									DefaultMode: pointer.Int32Ptr(int32(420)),
-									SecretName:  contourCertsSecretName,
+									SecretName:  objcontour.CertsSecretName(contour, contourCertsSecretName),
 								},
 							},
 						},
@@ -286,7 +333,7 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 									LocalObjectReference: corev1.LocalObjectReference{
 										// [TODO] danehans: Update to contour.Name when
 										// projectcontour/contour/issues/2122 is fixed.
-										Name: objcm.ContourCfgMapName,
+										Name: objcontour.ResourceName(contour, objcm.ContourCfgMapName),
 									},
 									Items: []corev1.KeyToPath{
 										{
@@ -300,8 +347,8 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 						},
 					},
 					DNSPolicy:                     corev1.DNSClusterFirst,
-					DeprecatedServiceAccount:      objutil.ContourRbacName,
-					ServiceAccountName:            objutil.ContourRbacName,
+					DeprecatedServiceAccount:      objcontour.ResourceName(contour, objutil.ContourRbacName),
+					ServiceAccountName:            objcontour.ResourceName(contour, objutil.ContourRbacName),
 					RestartPolicy:                 corev1.RestartPolicyAlways,
 					SchedulerName:                 "default-scheduler",
 					SecurityContext:               objutil.NewUnprivilegedPodSecurity(),
@@ -319,6 +366,14 @@ func DesiredDeployment(contour *operatorv1alpha1.Contour, image string) *appsv1.
 		deploy.Spec.Template.Spec.Tolerations = contour.Spec.NodePlacement.Contour.Tolerations
 	}
 
+	if contour.Suspended() {
+		deploy.Spec.Replicas = pointer.Int32Ptr(0)
+	}
+
+	objcontour.ApplyCertificatesRotation(&deploy.Spec.Template.ObjectMeta, contour)
+	objcontour.ApplySidecarInjectionExclusion(&deploy.Spec.Template.ObjectMeta, contour)
+	objcontour.ApplyRenderedBy(deploy)
+	objcontour.ApplyResourceMetadata(deploy, contour)
 	return deploy
 }
 
@@ -327,7 +382,7 @@ func CurrentDeployment(ctx context.Context, cli client.Client, contour *operator
 	deploy := &appsv1.Deployment{}
 	key := types.NamespacedName{
 		Namespace: contour.Spec.Namespace.Name,
-		Name:      contourDeploymentName,
+		Name:      objcontour.ResourceName(contour, contourDeploymentName),
 	}
 	if err := cli.Get(ctx, key, deploy); err != nil {
 		return nil, err
@@ -335,6 +390,88 @@ func CurrentDeployment(ctx context.Context, cli client.Client, contour *operator
 	return deploy, nil
 }
 
+// RunningVersion returns the Contour version of deploy, i.e. the image tag of
+// its Contour container, once the rollout of deploy is complete. An empty
+// string is returned while pods of different versions may be running.
+func RunningVersion(deploy *appsv1.Deployment) string {
+	if !rolloutComplete(deploy) {
+		return ""
+	}
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		if c.Name == contourContainerName {
+			return release.VersionFromImage(c.Image)
+		}
+	}
+	return ""
+}
+
+// rolloutComplete returns true if all pods of deploy run its current template
+// and are available.
+func rolloutComplete(deploy *appsv1.Deployment) bool {
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == deploy.Status.Replicas &&
+		deploy.Status.AvailableReplicas == deploy.Status.Replicas
+}
+
+// rolloutFailure returns why the rollout of deploy failed, i.e. it exceeded
+// its progress deadline or a Contour pod is crash looping, or an empty string
+// if the rollout did not fail.
+func rolloutFailure(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, deploy *appsv1.Deployment) (string, error) {
+	if rolloutComplete(deploy) {
+		return "", nil
+	}
+	for _, c := range deploy.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse &&
+			c.Reason == progressDeadlineExceededReason {
+			return fmt.Sprintf("Deployment %s exceeded its progress deadline.", deploy.Name), nil
+		}
+	}
+	// Only the pods of the current template count, since the pods of a rolled
+	// back template may still be terminating.
+	hash, err := currentTemplateHash(ctx, cli, contour, deploy)
+	if err != nil || hash == "" {
+		return "", err
+	}
+	labels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}
+	for k, v := range ContourDeploymentPodSelector(contour).MatchLabels {
+		labels[k] = v
+	}
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(deploy.Namespace), client.MatchingLabels(labels)); err != nil {
+		return "", fmt.Errorf("failed to list contour pods in namespace %s: %w", deploy.Namespace, err)
+	}
+	return rollback.CrashLooping(pods.Items), nil
+}
+
+// currentTemplateHash returns the pod template hash of the newest ReplicaSet of
+// deploy, or an empty string if the Deployment controller did not observe the
+// current template of deploy yet.
+func currentTemplateHash(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, deploy *appsv1.Deployment) (string, error) {
+	if deploy.Status.ObservedGeneration < deploy.Generation {
+		return "", nil
+	}
+	rsList := &appsv1.ReplicaSetList{}
+	if err := cli.List(ctx, rsList, client.InNamespace(deploy.Namespace),
+		client.MatchingLabels(ContourDeploymentPodSelector(contour).MatchLabels)); err != nil {
+		return "", fmt.Errorf("failed to list contour replicasets in namespace %s: %w", deploy.Namespace, err)
+	}
+	hash := ""
+	newest := int64(-1)
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, deploy) {
+			continue
+		}
+		revision, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil || revision <= newest {
+			continue
+		}
+		newest = revision
+		hash = rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	}
+	return hash, nil
+}
+
 // createDeployment creates a Deployment resource for the provided deploy.
 func createDeployment(ctx context.Context, cli client.Client, deploy *appsv1.Deployment) error {
 	if err := cli.Create(ctx, deploy); err != nil {
@@ -346,7 +483,7 @@ func createDeployment(ctx context.Context, cli client.Client, deploy *appsv1.Dep
 // updateDeploymentIfNeeded updates a Deployment if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateDeploymentIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *appsv1.Deployment) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		deploy, updated := equality.DeploymentConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, deploy); err != nil {
@@ -371,14 +508,15 @@ func makeDeploymentLabels(contour *operatorv1alpha1.Contour) map[string]string {
 }
 
 // ContourDeploymentPodSelector returns a label selector using "app: contour" as the
-// key/value pair.
+// key/value pair, prefixing the value with the name of contour if it shares its
+// spec namespace.
 //
 // TODO [danehans]: Update to use "contour.operator.projectcontour.io/deployment-contour"
 // when https://github.com/projectcontour/contour/issues/1821 is fixed.
-func ContourDeploymentPodSelector() *metav1.LabelSelector {
+func ContourDeploymentPodSelector(contour *operatorv1alpha1.Contour) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			"app": "contour",
+			"app": objcontour.ResourceName(contour, "contour"),
 		},
 	}
 }
@@ -14,6 +14,7 @@
 package deployment
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -25,6 +26,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func checkDeploymentHasEnvVar(t *testing.T, deploy *appsv1.Deployment, name string) {
@@ -119,6 +123,7 @@ func TestDesiredDeployment(t *testing.T) {
 	cntr := objcontour.New(cfg)
 	icName := "test-ic"
 	cntr.Spec.IngressClassName = &icName
+	cntr.Spec.RootNamespaces = []string{"root-ns-1", "root-ns-2"}
 	// Change the default ports to test Envoy service port args.
 	insecurePort := objcfg.EnvoyInsecureContainerPort
 	securePort := objcfg.EnvoySecureContainerPort
@@ -153,8 +158,207 @@ func TestDesiredDeployment(t *testing.T) {
 
 	arg := fmt.Sprintf("--ingress-class-name=%s", *cntr.Spec.IngressClassName)
 	checkContainerHasArg(t, container, arg)
+	checkContainerHasArg(t, container, "--root-namespaces=root-ns-1,root-ns-2")
+	checkContainerHasArg(t, container, fmt.Sprintf("--envoy-service-namespace=$(%s)", contourNsEnvVar))
 	checkDeploymentHasNodeSelector(t, deploy, nil)
 	checkDeploymentHasTolerations(t, deploy, nil)
+	if _, ok := deploy.Spec.Template.Annotations[objcontour.CertificatesRotationAnnotation]; ok {
+		t.Errorf("expected no certificates rotation annotation without a rotation")
+	}
+
+	// A rotation of the certificates restarts the pods.
+	cntr.Status.CertificatesRotation = "2021-06-07T12:00:00Z"
+	deploy = DesiredDeployment(cntr, testContourImage)
+	if rotation := deploy.Spec.Template.Annotations[objcontour.CertificatesRotationAnnotation]; rotation != cntr.Status.CertificatesRotation {
+		t.Errorf("expected certificates rotation annotation %q, got %q", cntr.Status.CertificatesRotation, rotation)
+	}
+
+	cntr.Spec.KubernetesClient = &operatorv1alpha1.KubernetesClientParameters{QPS: 100, Burst: 200}
+	deploy = DesiredDeployment(cntr, testContourImage)
+	container = checkDeploymentHasContainer(t, deploy, contourContainerName, true)
+	checkContainerHasArg(t, container, "--kubernetes-client-qps=100")
+	checkContainerHasArg(t, container, "--kubernetes-client-burst=200")
+
+	cntr.Spec.Logging = &operatorv1alpha1.LoggingParameters{Debug: true, KubernetesVerbosity: 6}
+	deploy = DesiredDeployment(cntr, testContourImage)
+	container = checkDeploymentHasContainer(t, deploy, contourContainerName, true)
+	checkContainerHasArg(t, container, "--debug")
+	checkContainerHasArg(t, container, "--kubernetes-debug=6")
+
+	cntr.Spec.Replicas = 1
+	cntr.Spec.LeaderElection = &operatorv1alpha1.LeaderElectionParameters{DisableForSingleReplica: true}
+	deploy = DesiredDeployment(cntr, testContourImage)
+	container = checkDeploymentHasContainer(t, deploy, contourContainerName, true)
+	checkContainerHasArg(t, container, "--disable-leader-election")
+
+	cntr.Annotations = map[string]string{operatorv1alpha1.SuspendAnnotation: "true"}
+	deploy = DesiredDeployment(cntr, testContourImage)
+	if *deploy.Spec.Replicas != 0 {
+		t.Errorf("expected a suspended contour to have no replicas, got %d", *deploy.Spec.Replicas)
+	}
+}
+
+func TestDesiredDeploymentSharedNamespace(t *testing.T) {
+	name := "deploy-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	cntr.Spec.Namespace.Shared = true
+
+	deploy := DesiredDeployment(cntr, config.DefaultContourImage)
+	if expected := name + "-contour"; deploy.Name != expected {
+		t.Errorf("expected deployment name %s, got %s", expected, deploy.Name)
+	}
+	if expected := name + "-contour"; deploy.Spec.Selector.MatchLabels["app"] != expected {
+		t.Errorf("expected deployment selector app=%s, got %v", expected, deploy.Spec.Selector.MatchLabels)
+	}
+	if expected := name + "-contour"; deploy.Spec.Template.Spec.ServiceAccountName != expected {
+		t.Errorf("expected service account %s, got %s", expected, deploy.Spec.Template.Spec.ServiceAccountName)
+	}
+	for _, vol := range deploy.Spec.Template.Spec.Volumes {
+		switch {
+		case vol.Secret != nil && vol.Secret.SecretName != "contourcert-"+name:
+			t.Errorf("expected secret contourcert-%s, got %s", name, vol.Secret.SecretName)
+		case vol.ConfigMap != nil && vol.ConfigMap.Name != name+"-contour":
+			t.Errorf("expected configmap %s-contour, got %s", name, vol.ConfigMap.Name)
+		}
+	}
+	container := checkDeploymentHasContainer(t, deploy, contourContainerName, true)
+	checkContainerHasArg(t, container, fmt.Sprintf("--envoy-service-name=%s-envoy", name))
+
+	cntr.Spec.ResourceNameTemplate = "acme-{resource}-{contour-name}"
+	deploy = DesiredDeployment(cntr, config.DefaultContourImage)
+	if expected := "acme-contour-" + name; deploy.Name != expected {
+		t.Errorf("expected deployment name %s, got %s", expected, deploy.Name)
+	}
+}
+
+func TestDesiredDeploymentOwnerReferences(t *testing.T) {
+	name := "deploy-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      fmt.Sprintf("%s-ns", name),
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	cntr.UID = "deploy-test-uid"
+
+	deploy := DesiredDeployment(cntr, config.DefaultContourImage)
+	if len(deploy.OwnerReferences) != 0 {
+		t.Errorf("expected no owner references, got %v", deploy.OwnerReferences)
+	}
+
+	cntr.Spec.Ownership = operatorv1alpha1.OwnerReferencesOwnershipType
+	deploy = DesiredDeployment(cntr, config.DefaultContourImage)
+	if !metav1.IsControlledBy(deploy, cntr) {
+		t.Errorf("expected deployment to be controlled by contour, got %v", deploy.OwnerReferences)
+	}
+	if !objcontour.IsOwned(deploy, cntr) {
+		t.Errorf("expected deployment to be owned by contour")
+	}
+	other := cntr.DeepCopy()
+	other.UID = "other-uid"
+	if objcontour.IsOwned(deploy, other) {
+		t.Errorf("expected deployment not to be owned by a contour with another UID")
+	}
+
+	cntr.Spec.Namespace.Name = "projectcontour"
+	deploy = DesiredDeployment(cntr, config.DefaultContourImage)
+	if len(deploy.OwnerReferences) != 0 {
+		t.Errorf("expected no owner references across namespaces, got %v", deploy.OwnerReferences)
+	}
+}
+
+func TestRunningVersion(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	deploy := DesiredDeployment(cntr, "docker.io/projectcontour/contour:v1.15.1")
+	deploy.Generation = 2
+	deploy.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           2,
+		UpdatedReplicas:    2,
+		AvailableReplicas:  2,
+	}
+	if v := RunningVersion(deploy); v != "v1.15.1" {
+		t.Errorf("expected running version v1.15.1, got %q", v)
+	}
+
+	deploy.Status.UpdatedReplicas = 1
+	if v := RunningVersion(deploy); v != "" {
+		t.Errorf("expected no running version during rollout, got %q", v)
+	}
+}
+
+func TestRolloutFailure(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	// Template B crash looped and was rolled back to template A, then template
+	// C was pushed while the pods of B still terminate.
+	deploy := DesiredDeployment(cntr, "docker.io/projectcontour/contour:c")
+	deploy.UID = "deploy-uid"
+	deploy.Generation = 3
+	deploy.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 3,
+		Replicas:           3,
+		UpdatedReplicas:    1,
+	}
+	selector := ContourDeploymentPodSelector(cntr).MatchLabels
+	replicaSet := func(hash, revision string) *appsv1.ReplicaSet {
+		labels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}
+		for k, v := range selector {
+			labels[k] = v
+		}
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       deploy.Namespace,
+				Name:            deploy.Name + "-" + hash,
+				Labels:          labels,
+				Annotations:     map[string]string{revisionAnnotation: revision},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deploy, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+			},
+		}
+	}
+	pod := func(hash string, crashLooping bool) *corev1.Pod {
+		labels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: hash}
+		for k, v := range selector {
+			labels[k] = v
+		}
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: deploy.Namespace, Name: "contour-" + hash, Labels: labels},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: contourContainerName}},
+			},
+		}
+		if crashLooping {
+			p.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+		}
+		return p
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		replicaSet("b", "2"), replicaSet("a", "3"), replicaSet("c", "4"), pod("b", true), pod("c", false)).Build()
+	failure, err := rolloutFailure(ctx, cli, cntr, deploy)
+	if err != nil {
+		t.Fatalf("failed to check rollout: %v", err)
+	}
+	if failure != "" {
+		t.Errorf("expected the pods of a rolled back template to be ignored, got failure %q", failure)
+	}
+
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		replicaSet("b", "2"), replicaSet("a", "3"), replicaSet("c", "4"), pod("c", true)).Build()
+	failure, err = rolloutFailure(ctx, cli, cntr, deploy)
+	if err != nil {
+		t.Fatalf("failed to check rollout: %v", err)
+	}
+	if failure == "" {
+		t.Error("expected a crash looping pod of the current template to fail the rollout")
+	}
 }
 
 func TestNodePlacementDeployment(t *testing.T) {
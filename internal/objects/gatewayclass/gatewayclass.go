@@ -56,6 +56,38 @@ func IsController(gc *gatewayv1alpha1.GatewayClass) bool {
 	return gc.Spec.Controller == operatorv1alpha1.GatewayClassControllerRef
 }
 
+// Claimant returns the contour of contours, the Contours referencing gc by
+// spec.gatewayClassRef, that the GatewayClass is managed for: the contour of
+// the parametersRef of gc if it is one of contours, otherwise the oldest of
+// contours, breaking ties by namespace and name. gc is nil if the GatewayClass
+// does not exist. Claimant returns nil if contours is empty.
+func Claimant(gc *gatewayv1alpha1.GatewayClass, contours []operatorv1alpha1.Contour) *operatorv1alpha1.Contour {
+	var claimant *operatorv1alpha1.Contour
+	for i := range contours {
+		c := &contours[i]
+		if gc != nil && gc.Spec.ParametersRef != nil && gc.Spec.ParametersRef.Namespace != nil &&
+			*gc.Spec.ParametersRef.Namespace == c.Namespace && gc.Spec.ParametersRef.Name == c.Name {
+			return c
+		}
+		if claimant == nil || olderContour(c, claimant) {
+			claimant = c
+		}
+	}
+	return claimant
+}
+
+// olderContour returns true if a was created before b, or if both were created
+// at the same time and the namespace and name of a sort before those of b.
+func olderContour(a, b *operatorv1alpha1.Contour) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
 // ParameterRefExists returns true if a GatewayClass exists with a parametersRef
 // ns/name that matches the provided ns/name.
 func ParameterRefExists(ctx context.Context, cli client.Client, name, ns string) (*gatewayv1alpha1.GatewayClass, bool, error) {
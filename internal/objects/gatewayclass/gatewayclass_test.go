@@ -0,0 +1,69 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayclass
+
+import (
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+func TestClaimant(t *testing.T) {
+	now := time.Now()
+	contour := func(ns, name string, created time.Time) operatorv1alpha1.Contour {
+		return operatorv1alpha1.Contour{ObjectMeta: metav1.ObjectMeta{
+			Namespace:         ns,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+	ns := "b"
+	gc := &gatewayv1alpha1.GatewayClass{Spec: gatewayv1alpha1.GatewayClassSpec{
+		ParametersRef: &gatewayv1alpha1.ParametersReference{Name: "newer", Namespace: &ns},
+	}}
+	testCases := map[string]struct {
+		gc       *gatewayv1alpha1.GatewayClass
+		contours []operatorv1alpha1.Contour
+		expected string
+	}{
+		"no contours": {},
+		"the oldest contour without a gatewayclass": {
+			contours: []operatorv1alpha1.Contour{contour("b", "newer", now), contour("b", "older", now.Add(-time.Hour))},
+			expected: "b/older",
+		},
+		"the name breaks ties": {
+			contours: []operatorv1alpha1.Contour{contour("b", "x", now), contour("a", "y", now), contour("a", "x", now)},
+			expected: "a/x",
+		},
+		"the contour of the parametersRef": {
+			gc:       gc,
+			contours: []operatorv1alpha1.Contour{contour("b", "older", now.Add(-time.Hour)), contour("b", "newer", now)},
+			expected: "b/newer",
+		},
+	}
+	for name, tc := range testCases {
+		claimant := Claimant(tc.gc, tc.contours)
+		actual := ""
+		if claimant != nil {
+			actual = claimant.Namespace + "/" + claimant.Name
+		}
+		if actual != tc.expected {
+			t.Errorf("%s: expected claimant %q, got %q", name, tc.expected, actual)
+		}
+	}
+}
@@ -15,7 +15,10 @@ package job
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"strings"
 	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
@@ -23,7 +26,7 @@ import (
 	objutil "github.com/projectcontour/contour-operator/internal/objects"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 	"github.com/projectcontour/contour-operator/internal/operator/config"
-	labels "github.com/projectcontour/contour-operator/pkg/labels"
+	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -38,28 +41,69 @@ import (
 const (
 	jobContainerName = "contour"
 	jobNsEnvVar      = "CONTOUR_NAMESPACE"
+	// defaultBackoffLimit is the default number of retries of the certgen Job.
+	defaultBackoffLimit = int32(1)
 )
 
 var (
-	// certgenJobName is the name of Certgen's Job resource.
-	// [TODO] danehans: Remove and use contour.Name + "-certgen" when
-	// https://github.com/projectcontour/contour/issues/2122 is fixed.
+	// CertsSecretNames are the base names of the TLS secrets generated by certgen.
+	CertsSecretNames = []string{"cacert", "contourcert", "envoycert"}
+	// certgenJobName is the base name of Certgen's Job resource, see
+	// objcontour.ResourceName.
 	certgenJobName = "contour-certgen-" + objutil.TagFromImage(config.DefaultContourImage)
 )
 
-// EnsureJob ensures that a Job exists for the given contour.
-// TODO [danehans]: The real dependency is whether the TLS secrets are present.
-// The method should first check for the secrets, then use certgen as a secret
-// generating strategy.
+// JobName returns the name of the certgen Job of contour.
+func JobName(contour *operatorv1alpha1.Contour) string {
+	return objcontour.ResourceName(contour, certgenJobName)
+}
+
+// IsCertsSecret returns true if name is the name of a TLS secret generated by
+// certgen for any contour, i.e. a base name of CertsSecretNames optionally
+// suffixed with the name of the contour.
+func IsCertsSecret(name string) bool {
+	for _, base := range CertsSecretNames {
+		if name == base || strings.HasPrefix(name, base+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureJob ensures that a Job exists for the given contour while its TLS
+// secrets do not exist, and that a finished Job is deleted once the TTL of the
+// contour's certgen parameters expires. A retryable error is returned while a
+// finished Job is kept.
 func EnsureJob(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, image string) error {
 	desired := DesiredJob(contour, image)
 	current, err := currentJob(ctx, cli, contour)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			// The Job of existing certificates was deleted once finished.
+			exist, err := CertificatesExist(ctx, cli, contour)
+			if err != nil || exist {
+				return err
+			}
 			return createJob(ctx, cli, desired)
 		}
 		return fmt.Errorf("failed to get job %s/%s: %w", desired.Namespace, desired.Name, err)
 	}
+	if finished, wait := cleanupWait(contour, current, time.Now()); finished {
+		if !objcontour.IsOwned(current, contour) {
+			return nil
+		}
+		if wait > 0 {
+			return retryable.New(fmt.Errorf("finished job %s/%s is kept for %s", current.Namespace, current.Name,
+				wait.Round(time.Second)), wait)
+		}
+		// Delete the pods of the Job as well, so that their logs are not kept
+		// longer than the Job.
+		if err := cli.Delete(ctx, current, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %s/%s: %w", current.Namespace, current.Name, err)
+		}
+		return nil
+	}
 	if err := recreateJobIfNeeded(ctx, cli, contour, current, desired); err != nil {
 		return fmt.Errorf("failed to recreate job %s/%s: %w", desired.Namespace, desired.Name, err)
 	}
@@ -76,7 +120,7 @@ func EnsureJobDeleted(ctx context.Context, cli client.Client, contour *operatorv
 		}
 		return err
 	}
-	if labels.Exist(job, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(job, contour) {
 		if err := cli.Delete(ctx, job); err != nil {
 			if !errors.IsNotFound(err) {
 				return err
@@ -87,12 +131,139 @@ func EnsureJobDeleted(ctx context.Context, cli client.Client, contour *operatorv
 	return nil
 }
 
+// EnsureCertificatesDeleted ensures the TLS secrets generated by the certgen Job
+// of the provided contour are deleted.
+func EnsureCertificatesDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	for _, name := range CertsSecretNames {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: contour.Spec.Namespace.Name,
+				Name:      objcontour.CertsSecretName(contour, name),
+			},
+		}
+		if err := cli.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// RotateCertificates starts the rotation of the xDS certificates of contour,
+// deleting its TLS secrets and recreating its certgen Job using image so that
+// the certificates are regenerated.
+func RotateCertificates(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, image string) error {
+	if err := EnsureCertificatesDeleted(ctx, cli, contour); err != nil {
+		return err
+	}
+	desired := DesiredJob(contour, image)
+	current, err := currentJob(ctx, cli, contour)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("failed to get job %s/%s: %w", desired.Namespace, desired.Name, err)
+	default:
+		// Delete the pods of the Job as well, since a running certgen pod could
+		// write the secrets after they are deleted.
+		if err := cli.Delete(ctx, current, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %s/%s: %w", current.Namespace, current.Name, err)
+		}
+	}
+	// Retry is needed since the object may still be getting deleted.
+	return retryJobCreate(ctx, cli, desired, time.Second*3)
+}
+
+// CertificatesExist returns true if the TLS secrets generated by the certgen Job
+// of the provided contour exist.
+func CertificatesExist(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (bool, error) {
+	for _, name := range CertsSecretNames {
+		key := types.NamespacedName{
+			Namespace: contour.Spec.Namespace.Name,
+			Name:      objcontour.CertsSecretName(contour, name),
+		}
+		if err := cli.Get(ctx, key, &corev1.Secret{}); err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get secret %s/%s: %w", key.Namespace, key.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// CertificatesExpiry returns the earliest expiry of the certificates and CAs of
+// the TLS secrets generated by the certgen Job of the provided contour, or nil
+// if a secret does not exist, i.e. while the certificates are regenerated.
+func CertificatesExpiry(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*time.Time, error) {
+	var expiry *time.Time
+	for _, name := range CertsSecretNames {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{
+			Namespace: contour.Spec.Namespace.Name,
+			Name:      objcontour.CertsSecretName(contour, name),
+		}
+		if err := cli.Get(ctx, key, secret); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", key.Namespace, key.Name, err)
+		}
+		for _, k := range []string{corev1.TLSCertKey, corev1.ServiceAccountRootCAKey} {
+			data := secret.Data[k]
+			if len(data) == 0 {
+				continue
+			}
+			block, _ := pem.Decode(data)
+			if block == nil {
+				return nil, fmt.Errorf("no PEM certificate in %s of secret %s/%s", k, key.Namespace, key.Name)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate %s of secret %s/%s: %w", k, key.Namespace, key.Name, err)
+			}
+			if expiry == nil || cert.NotAfter.Before(*expiry) {
+				notAfter := cert.NotAfter
+				expiry = &notAfter
+			}
+		}
+	}
+	return expiry, nil
+}
+
+// cleanupWait returns true if job has completed or failed, and the time
+// remaining at now before it is deleted according to the TTL of the certgen
+// parameters of contour.
+func cleanupWait(contour *operatorv1alpha1.Contour, job *batchv1.Job, now time.Time) (bool, time.Duration) {
+	var finishedAt *metav1.Time
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			finishedAt = &cond.LastTransitionTime
+			break
+		}
+	}
+	if finishedAt == nil {
+		return false, 0
+	}
+	if job.Status.CompletionTime != nil {
+		finishedAt = job.Status.CompletionTime
+	}
+	var ttl int32
+	if certgen := contour.Spec.Certgen; certgen != nil && certgen.TTLSecondsAfterFinished != nil {
+		ttl = *certgen.TTLSecondsAfterFinished
+	}
+	wait := finishedAt.Add(time.Duration(ttl) * time.Second).Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return true, wait
+}
+
 // currentJob returns the current Job resource named name for the provided contour.
 func currentJob(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*batchv1.Job, error) {
 	current := &batchv1.Job{}
 	key := types.NamespacedName{
 		Namespace: contour.Spec.Namespace.Name,
-		Name:      certgenJobName,
+		Name:      JobName(contour),
 	}
 	err := cli.Get(ctx, key, current)
 	if err != nil {
@@ -129,16 +300,38 @@ func DesiredJob(contour *operatorv1alpha1.Contour, image string) *batchv1.Job {
 		TerminationMessagePath:   "/dev/termination-log",
 		TerminationMessagePolicy: "File",
 	}
+	backoffLimit, ttl := defaultBackoffLimit, int32(0)
+	certgen := contour.Spec.Certgen
+	if certgen != nil {
+		if certgen.BackoffLimit != nil {
+			backoffLimit = *certgen.BackoffLimit
+		}
+		if certgen.TTLSecondsAfterFinished != nil {
+			ttl = *certgen.TTLSecondsAfterFinished
+		}
+		if certgen.Resources != nil {
+			container.Resources = *certgen.Resources
+		}
+	}
+	if contour.Spec.Namespace.Shared {
+		// Keep the secrets of Contours sharing the namespace apart,
+		// see objcontour.CertsSecretName.
+		container.Command = append(container.Command, fmt.Sprintf("--secrets-name-suffix=-%s", contour.Name))
+	}
 	spec := corev1.PodSpec{
 		Containers:                    []corev1.Container{container},
-		DeprecatedServiceAccount:      objutil.CertGenRbacName,
-		ServiceAccountName:            objutil.CertGenRbacName,
+		DeprecatedServiceAccount:      objcontour.ResourceName(contour, objutil.CertGenRbacName),
+		ServiceAccountName:            objcontour.ResourceName(contour, objutil.CertGenRbacName),
 		SecurityContext:               objutil.NewUnprivilegedPodSecurity(),
 		RestartPolicy:                 corev1.RestartPolicyNever,
 		DNSPolicy:                     corev1.DNSClusterFirst,
 		SchedulerName:                 "default-scheduler",
 		TerminationGracePeriodSeconds: pointer.Int64Ptr(int64(30)),
 	}
+	if certgen != nil && certgen.NodePlacement != nil {
+		spec.NodeSelector = certgen.NodePlacement.NodeSelector
+		spec.Tolerations = certgen.NodePlacement.Tolerations
+	}
 	// TODO [danehans] certgen needs to be updated to match these labels.
 	// See https://github.com/projectcontour/contour/issues/1821 for details.
 	labels := map[string]string{
@@ -153,16 +346,17 @@ func DesiredJob(contour *operatorv1alpha1.Contour, image string) *batchv1.Job {
 	}
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      certgenJobName,
+			Name:      JobName(contour),
 			Namespace: contour.Spec.Namespace.Name,
 			Labels:    labels,
 		},
 		Spec: batchv1.JobSpec{
 			Parallelism:  pointer.Int32Ptr(int32(1)),
 			Completions:  pointer.Int32Ptr(int32(1)),
-			BackoffLimit: pointer.Int32Ptr(int32(1)),
-			// Make job eligible to for immediate deletion (feature gate dependent).
-			TTLSecondsAfterFinished: pointer.Int32Ptr(int32(0)),
+			BackoffLimit: pointer.Int32Ptr(backoffLimit),
+			// Make job eligible for deletion by the TTL controller as well
+			// (feature gate dependent), see EnsureJob.
+			TTLSecondsAfterFinished: pointer.Int32Ptr(ttl),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: objcontour.OwningSelector(contour).MatchLabels,
@@ -171,13 +365,15 @@ func DesiredJob(contour *operatorv1alpha1.Contour, image string) *batchv1.Job {
 			},
 		},
 	}
+	objcontour.ApplySidecarInjectionExclusion(&job.Spec.Template.ObjectMeta, contour)
+	objcontour.ApplyResourceMetadata(job, contour)
 	return job
 }
 
 // recreateJobIfNeeded recreates a Job if current doesn't match desired,
 // using contour to verify the existence of owner labels.
 func recreateJobIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *batchv1.Job) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		updated, changed := equality.JobConfigChanged(current, desired)
 		if !changed {
 			return nil
@@ -14,15 +14,33 @@
 package job
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func checkJobHasEnvVar(t *testing.T, job *batchv1.Job, name string) {
@@ -72,3 +90,200 @@ func TestDesiredJob(t *testing.T) {
 	checkContainerHasImage(t, container, operatorconfig.DefaultContourImage)
 	checkJobHasEnvVar(t, job, jobNsEnvVar)
 }
+
+func TestDesiredJobCertgen(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "certgen-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	job := DesiredJob(cntr, operatorconfig.DefaultContourImage)
+	if *job.Spec.BackoffLimit != defaultBackoffLimit || *job.Spec.TTLSecondsAfterFinished != 0 {
+		t.Errorf("expected the default backoff limit and ttl, got %d and %d", *job.Spec.BackoffLimit,
+			*job.Spec.TTLSecondsAfterFinished)
+	}
+
+	cntr.Spec.Certgen = &operatorv1alpha1.CertgenParameters{
+		BackoffLimit:            pointer.Int32Ptr(3),
+		TTLSecondsAfterFinished: pointer.Int32Ptr(600),
+		NodePlacement: &operatorv1alpha1.ContourNodePlacement{
+			NodeSelector: map[string]string{"node-role": "infra"},
+			Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}},
+		},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+		},
+	}
+	job = DesiredJob(cntr, operatorconfig.DefaultContourImage)
+	if *job.Spec.BackoffLimit != 3 || *job.Spec.TTLSecondsAfterFinished != 600 {
+		t.Errorf("expected backoff limit 3 and ttl 600, got %d and %d", *job.Spec.BackoffLimit,
+			*job.Spec.TTLSecondsAfterFinished)
+	}
+	spec := job.Spec.Template.Spec
+	if spec.NodeSelector["node-role"] != "infra" || len(spec.Tolerations) != 1 {
+		t.Errorf("expected the node placement of the certgen parameters, got %v and %v", spec.NodeSelector,
+			spec.Tolerations)
+	}
+	if cpu := spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; cpu.String() != "10m" {
+		t.Errorf("expected a cpu request of 10m, got %s", cpu.String())
+	}
+}
+
+func TestEnsureJobCleanup(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "cleanup-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	finished := metav1.NewTime(time.Now().Add(-time.Minute))
+	job := DesiredJob(cntr, operatorconfig.DefaultContourImage)
+	job.Status = batchv1.JobStatus{
+		CompletionTime: &finished,
+		Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: finished},
+		},
+	}
+	objs := []client.Object{job}
+	for _, name := range CertsSecretNames {
+		objs = append(objs, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: name}})
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	// The finished job is kept until the ttl expires.
+	cntr.Spec.Certgen = &operatorv1alpha1.CertgenParameters{TTLSecondsAfterFinished: pointer.Int32Ptr(3600)}
+	err := EnsureJob(ctx, cli, cntr, operatorconfig.DefaultContourImage)
+	if _, ok := err.(retryable.Error); !ok {
+		t.Fatalf("expected a retryable error, got %v", err)
+	}
+	if _, err := currentJob(ctx, cli, cntr); err != nil {
+		t.Fatalf("expected the job to be kept: %v", err)
+	}
+
+	cntr.Spec.Certgen = nil
+	if err := EnsureJob(ctx, cli, cntr, operatorconfig.DefaultContourImage); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := currentJob(ctx, cli, cntr); !errors.IsNotFound(err) {
+		t.Fatalf("expected the job to be deleted, got %v", err)
+	}
+	// The job is not recreated since the certificates exist.
+	if err := EnsureJob(ctx, cli, cntr, operatorconfig.DefaultContourImage); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := currentJob(ctx, cli, cntr); !errors.IsNotFound(err) {
+		t.Errorf("expected the job not to be recreated, got %v", err)
+	}
+}
+
+func TestRotateCertificates(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "rotate-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	objs := []client.Object{DesiredJob(cntr, operatorconfig.DefaultContourImage)}
+	for _, name := range CertsSecretNames {
+		objs = append(objs, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: name}})
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	exist, err := CertificatesExist(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exist {
+		t.Fatalf("expected the certificates to exist")
+	}
+	if err := RotateCertificates(ctx, cli, cntr, operatorconfig.DefaultContourImage); err != nil {
+		t.Fatal(err)
+	}
+	if exist, err = CertificatesExist(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if exist {
+		t.Errorf("expected the certificates to be deleted")
+	}
+	if _, err := currentJob(ctx, cli, cntr); err != nil {
+		t.Errorf("expected the job to be recreated: %v", err)
+	}
+}
+
+func certificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "contour"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestIsCertsSecret(t *testing.T) {
+	expected := map[string]bool{
+		"cacert":              true,
+		"contourcert":         true,
+		"envoycert-contour-a": true,
+		"envoycerts":          false,
+		"default-token-abcde": false,
+	}
+	for name, want := range expected {
+		if got := IsCertsSecret(name); got != want {
+			t.Errorf("expected IsCertsSecret(%q) to be %t, got %t", name, want, got)
+		}
+	}
+}
+
+func TestCertificatesExpiry(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "expiry-test", Namespace: "default", SpecNs: "projectcontour"})
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	expiry, err := CertificatesExpiry(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expiry != nil {
+		t.Errorf("expected no expiry without certificates, got %v", expiry)
+	}
+
+	first := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range CertsSecretNames {
+		notAfter := first.Add(time.Duration(len(CertsSecretNames)-i) * 24 * time.Hour)
+		data := map[string][]byte{corev1.TLSCertKey: certificate(t, notAfter)}
+		if name == "envoycert" {
+			// The CA of the certificate expires first.
+			data[corev1.ServiceAccountRootCAKey] = certificate(t, first)
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: name}, Data: data}
+		if err := cli.Create(ctx, secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expiry, err = CertificatesExpiry(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if expiry == nil || !expiry.Equal(first) {
+		t.Errorf("expected expiry %v, got %v", first, expiry)
+	}
+}
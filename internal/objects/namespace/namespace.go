@@ -16,21 +16,63 @@ package namespace
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	"github.com/projectcontour/contour-operator/internal/equality"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
-	"github.com/projectcontour/contour-operator/pkg/labels"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// namespaceCoreList is a list of namespace names that should not be removed.
-var namespaceCoreList = []string{"contour-operator", "default", "kube-system"}
+// namespaceCoreList is a list of namespace names that should not be removed,
+// in addition to the namespace of the operator.
+var namespaceCoreList = []string{"default", "kube-system"}
+
+// maxReportedWorkloads is the maximum number of unowned workloads listed by
+// the message of an UnownedWorkloadsError.
+const maxReportedWorkloads = 5
+
+// workloadKinds are the kinds of the objects that keep the spec namespace of a
+// contour from being removed, unless they are owned by the contour or
+// controlled by another object, i.e. the Envoy pods of the Envoy DaemonSet.
+var workloadKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+}
+
+// UnownedWorkloadsError is the error of removing a namespace that contains
+// workloads not owned by the contour the namespace belongs to.
+type UnownedWorkloadsError struct {
+	// Namespace is the name of the namespace.
+	Namespace string
+	// Workloads are the unowned workloads, i.e. "Deployment/app".
+	Workloads []string
+}
+
+func (e *UnownedWorkloadsError) Error() string {
+	workloads := e.Workloads
+	more := ""
+	if len(workloads) > maxReportedWorkloads {
+		more = fmt.Sprintf(" and %d more", len(workloads)-maxReportedWorkloads)
+		workloads = workloads[:maxReportedWorkloads]
+	}
+	return fmt.Sprintf("namespace %s contains workloads not owned by the contour: %s%s", e.Namespace,
+		strings.Join(workloads, ", "), more)
+}
 
 // EnsureNamespace ensures the namespace for the provided name exists.
 func EnsureNamespace(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
@@ -52,13 +94,17 @@ func EnsureNamespace(ctx context.Context, cli client.Client, contour *operatorv1
 
 // EnsureNamespaceDeleted ensures the namespace for the provided contour is removed,
 // bypassing deletion if any of the following conditions apply:
-//   - RemoveOnDeletion is unspecified or set to false.
+//   - The namespace is retained, i.e. RemoveOnDeletion is unspecified or set to false.
 //   - Another contour exists in the same namespace.
-//   - The namespace of contour matches a name in namespaceCoreList.
+//   - The namespace of contour matches a name in namespaceCoreList or is the
+//     namespace of the operator, operatorNs.
 //   - The namespace does not contain the Contour owner labels.
-func EnsureNamespaceDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+//
+// An UnownedWorkloadsError is returned instead of removing a namespace that
+// contains workloads not owned by contour.
+func EnsureNamespaceDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, operatorNs string) error {
 	name := contour.Spec.Namespace.Name
-	if !contour.Spec.Namespace.RemoveOnDeletion {
+	if contour.RetainsNamespace() || name == operatorNs {
 		return nil
 	}
 	for _, ns := range namespaceCoreList {
@@ -73,12 +119,19 @@ func EnsureNamespaceDeleted(ctx context.Context, cli client.Client, contour *ope
 		}
 		return err
 	}
-	if labels.Exist(ns, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(ns, contour) {
 		contoursExist, err := objcontour.OtherContoursExistInSpecNs(ctx, cli, contour)
 		if err != nil {
 			return fmt.Errorf("failed to verify if contours exist in namespace %s: %w", name, err)
 		}
 		if !contoursExist {
+			workloads, err := unownedWorkloads(ctx, cli, contour)
+			if err != nil {
+				return fmt.Errorf("failed to verify workloads of namespace %s: %w", name, err)
+			}
+			if len(workloads) > 0 {
+				return &UnownedWorkloadsError{Namespace: name, Workloads: workloads}
+			}
 			if err := cli.Delete(ctx, ns); err != nil {
 				if errors.IsNotFound(err) {
 					return nil
@@ -90,9 +143,72 @@ func EnsureNamespaceDeleted(ctx context.Context, cli client.Client, contour *ope
 	return nil
 }
 
+// unownedWorkloads returns the kind and name of the objects of workloadKinds in
+// the spec namespace of contour that are neither owned by contour nor
+// controlled by another object. Kinds the cluster does not serve are skipped.
+// The objects are read from the API server, since the operator does not cache
+// most of the kinds.
+func unownedWorkloads(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) ([]string, error) {
+	var workloads []string
+	for _, gvk := range workloadKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := cli.List(ctx, list, client.InNamespace(contour.Spec.Namespace.Name)); err != nil {
+			if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s objects: %w", gvk.Kind, err)
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if metav1.GetControllerOf(obj) != nil || objcontour.IsOwned(obj, contour) {
+				continue
+			}
+			workloads = append(workloads, fmt.Sprintf("%s/%s", gvk.Kind, obj.GetName()))
+		}
+	}
+	return workloads, nil
+}
+
+// Terminating returns the spec namespace of contour if it is owned by contour
+// and terminating, i.e. after it was removed by EnsureNamespaceDeleted, or nil
+// if the namespace is retained or does not exist.
+func Terminating(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Namespace, error) {
+	if contour.RetainsNamespace() {
+		return nil, nil
+	}
+	ns, err := currentSpecNsName(ctx, cli, contour.Spec.Namespace.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", contour.Spec.Namespace.Name, err)
+	}
+	if ns.DeletionTimestamp == nil || !objcontour.IsOwned(ns, contour) {
+		return nil, nil
+	}
+	return ns, nil
+}
+
+// TerminationBlockers returns what keeps the terminating namespace ns from
+// being removed: its finalizers and the messages of the conditions the
+// namespace controller reports while content or finalizers remain.
+func TerminationBlockers(ns *corev1.Namespace) []string {
+	var blockers []string
+	if len(ns.Finalizers) > 0 {
+		blockers = append(blockers, fmt.Sprintf("finalizers %s", strings.Join(ns.Finalizers, ", ")))
+	}
+	for _, c := range ns.Status.Conditions {
+		if c.Status == corev1.ConditionTrue && c.Message != "" {
+			blockers = append(blockers, c.Message)
+		}
+	}
+	return blockers
+}
+
 // DesiredNamespace returns the desired Namespace resource for the provided contour.
 func DesiredNamespace(contour *operatorv1alpha1.Contour) *corev1.Namespace {
-	return &corev1.Namespace{
+	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: contour.Spec.Namespace.Name,
 			Labels: map[string]string{
@@ -101,6 +217,13 @@ func DesiredNamespace(contour *operatorv1alpha1.Contour) *corev1.Namespace {
 			},
 		},
 	}
+	if contour.Spec.ExcludeFromServiceMesh {
+		ns.Labels[objcontour.IstioInjectionLabel] = "disabled"
+		ns.Annotations = map[string]string{objcontour.LinkerdInjectAnnotation: "disabled"}
+	}
+	objcontour.ApplyMetadata(ns, contour.Spec.Namespace.Labels, contour.Spec.Namespace.Annotations)
+	objcontour.ApplyResourceMetadata(ns, contour)
+	return ns
 }
 
 // createNamespace creates a Namespace resource for the provided ns.
@@ -126,7 +249,7 @@ func currentSpecNsName(ctx context.Context, cli client.Client, name string) (*co
 // updateNamespaceIfNeeded updates a Namespace if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateNamespaceIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *corev1.Namespace) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		ns, updated := equality.NamespaceConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, ns); err != nil {
@@ -14,6 +14,7 @@
 package namespace
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -22,6 +23,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func checkNamespaceName(t *testing.T, ns *corev1.Namespace, expected string) {
@@ -62,3 +67,114 @@ func TestDesiredNamespace(t *testing.T) {
 	}
 	checkNamespaceLabels(t, ns, ownerLabels)
 }
+
+func TestDesiredNamespaceMetadata(t *testing.T) {
+	cntrName := "ns-test"
+	cfg := objcontour.Config{
+		Name:        cntrName,
+		Namespace:   fmt.Sprintf("%s-ns", cntrName),
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	cntr.Spec.Namespace.Labels = map[string]string{
+		"pod-security.kubernetes.io/enforce":    "baseline",
+		operatorv1alpha1.OwningContourNameLabel: "foo",
+	}
+	cntr.Spec.Namespace.Annotations = map[string]string{"team": "edge"}
+	ns := DesiredNamespace(cntr)
+	checkNamespaceLabels(t, ns, map[string]string{
+		"pod-security.kubernetes.io/enforce":    "baseline",
+		operatorv1alpha1.OwningContourNameLabel: cntr.Name,
+		operatorv1alpha1.OwningContourNsLabel:   cntr.Namespace,
+	})
+	if ns.Annotations["team"] != "edge" {
+		t.Errorf("namespace has unexpected %q annotations", ns.Annotations)
+	}
+}
+
+func TestDesiredNamespaceExcludeFromServiceMesh(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "ns-test", Namespace: "ns-test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.ExcludeFromServiceMesh = true
+	ns := DesiredNamespace(cntr)
+	if ns.Labels[objcontour.IstioInjectionLabel] != "disabled" {
+		t.Errorf("expected istio injection to be disabled, got labels %v", ns.Labels)
+	}
+	if ns.Annotations[objcontour.LinkerdInjectAnnotation] != "disabled" {
+		t.Errorf("expected linkerd injection to be disabled, got annotations %v", ns.Annotations)
+	}
+}
+
+func TestTerminating(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "ns-test", Namespace: "ns-test-ns", SpecNs: "projectcontour", RemoveNs: true})
+	ns := DesiredNamespace(cntr)
+	now := metav1.Now()
+	ns.DeletionTimestamp = &now
+	ns.Finalizers = []string{"example.com/cleanup"}
+	ns.Status.Conditions = []corev1.NamespaceCondition{{
+		Type:    corev1.NamespaceFinalizersRemaining,
+		Status:  corev1.ConditionTrue,
+		Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances",
+	}}
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(ns).Build()
+
+	terminating, err := Terminating(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if terminating == nil {
+		t.Fatalf("expected namespace %s to be terminating", ns.Name)
+	}
+	blockers := TerminationBlockers(terminating)
+	if len(blockers) != 2 || blockers[0] != "finalizers example.com/cleanup" {
+		t.Errorf("expected the finalizers and remaining content to block termination, got %v", blockers)
+	}
+
+	// A retained namespace is not waited for.
+	cntr.Spec.Namespace.RemoveOnDeletion = false
+	if terminating, err = Terminating(ctx, cli, cntr); err != nil || terminating != nil {
+		t.Errorf("expected a retained namespace not to be terminating, got %v, %v", terminating, err)
+	}
+}
+
+func TestEnsureNamespaceDeletedUnownedWorkloads(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "ns-test", Namespace: "ns-test-ns", SpecNs: "projectcontour", RemoveNs: true})
+	ns := DesiredNamespace(cntr)
+	owned := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "envoy", Namespace: ns.Name, Labels: objcontour.OwnerLabels(cntr)}}
+	controlled := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "envoy-abc", Namespace: ns.Name}}
+	isController := true
+	controlled.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "envoy", Controller: &isController}}
+	user := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: ns.Name}}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns, owned, controlled, user).Build()
+
+	err := EnsureNamespaceDeleted(ctx, cli, cntr, "contour-operator")
+	unowned, ok := err.(*UnownedWorkloadsError)
+	if !ok {
+		t.Fatalf("expected an UnownedWorkloadsError, got %v", err)
+	}
+	if len(unowned.Workloads) != 1 || unowned.Workloads[0] != "Pod/app" {
+		t.Errorf("expected only the user pod to block the removal, got %v", unowned.Workloads)
+	}
+	if _, err := currentSpecNsName(ctx, cli, ns.Name); err != nil {
+		t.Errorf("expected namespace %s not to be removed, got %v", ns.Name, err)
+	}
+
+	if err := cli.Delete(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureNamespaceDeleted(ctx, cli, cntr, "contour-operator"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := currentSpecNsName(ctx, cli, ns.Name); err == nil {
+		t.Errorf("expected namespace %s to be removed", ns.Name)
+	}
+}
@@ -14,9 +14,13 @@
 package objects
 
 import (
+	"fmt"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // NewUnprivilegedPodSecurity makes a a non-root PodSecurityContext object
@@ -41,3 +45,28 @@ func TagFromImage(image string) string {
 	}
 	return ""
 }
+
+// APIAvailable returns true if the API group and version of gvk is served
+// by the cluster that cli is connected to.
+func APIAvailable(cli client.Client, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := cli.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get rest mapping for %s: %w", gvk, err)
+	}
+	return true, nil
+}
+
+// MappedClient is a client using Mapper as its RESTMapper, i.e. to make the
+// optional APIs of a fake client available.
+type MappedClient struct {
+	client.Client
+	Mapper meta.RESTMapper
+}
+
+// RESTMapper returns the RESTMapper of c.
+func (c *MappedClient) RESTMapper() meta.RESTMapper {
+	return c.Mapper
+}
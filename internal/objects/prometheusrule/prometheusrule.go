@@ -0,0 +1,237 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusrule
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ruleName is the name of the PrometheusRule of a Contour.
+	ruleName = "contour-alerts"
+	// contourDeploymentName is the name of Contour's Deployment.
+	contourDeploymentName = "contour"
+	// envoyDaemonSetName is the name of Envoy's DaemonSet.
+	envoyDaemonSetName = "envoy"
+
+	// defaultSeverity is the severity label of the alerts if unspecified.
+	defaultSeverity = "warning"
+	// defaultEnvoyNotReadyPercent is the percentage of nodes Envoy must not be
+	// ready on for the EnvoyNotReady alert to fire if unspecified.
+	defaultEnvoyNotReadyPercent = 10
+	// defaultCertificateExpiryDays is the number of days before a certificate
+	// of Envoy expires for the EnvoyCertificateExpiring alert to fire if
+	// unspecified.
+	defaultCertificateExpiryDays = 14
+	// defaultServerErrorPercent is the percentage of 5xx responses for the
+	// EnvoyServerErrors alert to fire if unspecified.
+	defaultServerErrorPercent = 5
+)
+
+// GroupVersionKind is the GroupVersionKind of a prometheus-operator
+// PrometheusRule.
+var GroupVersionKind = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PrometheusRule",
+}
+
+// APIAvailable returns true if the monitoring.coreos.com API group is served by
+// the cluster that cli is connected to.
+func APIAvailable(cli client.Client) (bool, error) {
+	return objutil.APIAvailable(cli, GroupVersionKind)
+}
+
+// EnsurePrometheusRule ensures the PrometheusRule of the alerts of contour
+// exists, or is deleted if contour has no alerts.
+func EnsurePrometheusRule(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	if contour.Spec.Alerts == nil {
+		return EnsurePrometheusRuleDeleted(ctx, cli, contour)
+	}
+	available, err := APIAvailable(cli)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("alerts require the prometheus operator; the %s API group is not served by the cluster",
+			GroupVersionKind.Group)
+	}
+	desired := DesiredPrometheusRule(contour)
+	current, err := currentPrometheusRule(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := cli.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create prometheusrule %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to get prometheusrule %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+	}
+	if !objcontour.IsOwned(current, contour) {
+		return nil
+	}
+	if updated, changed := equality.SpecChanged(current, desired); changed {
+		if err := cli.Update(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update prometheusrule %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// EnsurePrometheusRuleDeleted ensures the PrometheusRule of contour is deleted
+// if Contour owner labels exist.
+func EnsurePrometheusRuleDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	rule, err := currentPrometheusRule(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if objcontour.IsOwned(rule, contour) {
+		if err := cli.Delete(ctx, rule); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DesiredPrometheusRule returns the desired PrometheusRule of the alerts of
+// contour. The alerts select the metrics of the Contour Deployment, the Envoy
+// DaemonSet and the Envoy pods of contour, and are labeled with the namespace
+// and name of contour.
+func DesiredPrometheusRule(contour *operatorv1alpha1.Contour) *unstructured.Unstructured {
+	params := contour.Spec.Alerts
+	severity := defaultSeverity
+	if params.Severity != "" {
+		severity = params.Severity
+	}
+	notReady := int32(defaultEnvoyNotReadyPercent)
+	if params.EnvoyNotReadyPercent != 0 {
+		notReady = params.EnvoyNotReadyPercent
+	}
+	expiryDays := int32(defaultCertificateExpiryDays)
+	if params.CertificateExpiryDays != 0 {
+		expiryDays = params.CertificateExpiryDays
+	}
+	serverErrors := int32(defaultServerErrorPercent)
+	if params.ServerErrorPercent != 0 {
+		serverErrors = params.ServerErrorPercent
+	}
+
+	ns := contour.Spec.Namespace.Name
+	deployment := objcontour.ResourceName(contour, contourDeploymentName)
+	daemonSet := objcontour.ResourceName(contour, envoyDaemonSetName)
+	// The Envoy pods are named after their DaemonSet.
+	envoyPods := fmt.Sprintf(`namespace=%q,pod=~"%s-.*"`, ns, daemonSet)
+	instance := fmt.Sprintf("%s/%s", contour.Namespace, contour.Name)
+
+	rules := []interface{}{
+		alert(contour, severity, "ContourUnavailable", "5m",
+			fmt.Sprintf(`kube_deployment_status_replicas_available{namespace=%q,deployment=%q} < 1`, ns, deployment),
+			fmt.Sprintf("Contour of %s is unavailable", instance),
+			fmt.Sprintf("No replica of Deployment %s/%s of Contour %s has been available for 5 minutes.",
+				ns, deployment, instance)),
+		alert(contour, severity, "EnvoyNotReady", "10m",
+			fmt.Sprintf(`100 * kube_daemonset_status_number_unavailable{namespace=%[1]q,daemonset=%[2]q} `+
+				`/ kube_daemonset_status_desired_number_scheduled{namespace=%[1]q,daemonset=%[2]q} > %[3]d`,
+				ns, daemonSet, notReady),
+			fmt.Sprintf("Envoy of %s is not ready on %d%% of nodes", instance, notReady),
+			fmt.Sprintf("Envoy of Contour %s has not been ready on more than %d%% of nodes for 10 minutes.",
+				instance, notReady)),
+		alert(contour, severity, "EnvoyCertificateExpiring", "1h",
+			fmt.Sprintf(`min(envoy_server_days_until_first_cert_expiring{%s}) < %d`, envoyPods, expiryDays),
+			fmt.Sprintf("A certificate of Envoy of %s is expiring", instance),
+			fmt.Sprintf("A certificate of Envoy of Contour %s, i.e. its xDS client certificate, expires in less "+
+				"than %d days.", instance, expiryDays)),
+		alert(contour, severity, "EnvoyServerErrors", "5m",
+			fmt.Sprintf(`100 * sum(rate(envoy_http_downstream_rq_xx{%[1]s,envoy_response_code_class="5"}[5m])) `+
+				`/ sum(rate(envoy_http_downstream_rq_total{%[1]s}[5m])) > %[2]d`, envoyPods, serverErrors),
+			fmt.Sprintf("Envoy of %s responds with 5xx errors", instance),
+			fmt.Sprintf("More than %d%% of the responses of Envoy of Contour %s have had a 5xx status for 5 minutes.",
+				serverErrors, instance)),
+	}
+
+	rule := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name":  fmt.Sprintf("contour-%s-%s", contour.Namespace, contour.Name),
+						"rules": rules,
+					},
+				},
+			},
+		},
+	}
+	rule.SetGroupVersionKind(GroupVersionKind)
+	rule.SetNamespace(ns)
+	rule.SetName(objcontour.ResourceName(contour, ruleName))
+	labels := map[string]string{}
+	for k, v := range params.RuleLabels {
+		labels[k] = v
+	}
+	for k, v := range objcontour.OwnerLabels(contour) {
+		labels[k] = v
+	}
+	rule.SetLabels(labels)
+	objcontour.ApplyResourceMetadata(rule, contour)
+	return rule
+}
+
+// alert returns the alerting rule named name of contour, firing with severity
+// when expr holds for duration.
+func alert(contour *operatorv1alpha1.Contour, severity, name, duration, expr, summary, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"alert": name,
+		"expr":  expr,
+		"for":   duration,
+		"labels": map[string]interface{}{
+			"severity":          severity,
+			"contour_namespace": contour.Namespace,
+			"contour_name":      contour.Name,
+		},
+		"annotations": map[string]interface{}{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+}
+
+// currentPrometheusRule returns the current PrometheusRule of contour.
+func currentPrometheusRule(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(GroupVersionKind)
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      objcontour.ResourceName(contour, ruleName),
+	}
+	if err := cli.Get(ctx, key, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
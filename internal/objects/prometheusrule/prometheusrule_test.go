@@ -0,0 +1,147 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusrule
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDesiredPrometheusRule(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Namespace.Shared = true
+	cntr.Spec.Alerts = &operatorv1alpha1.AlertParameters{
+		RuleLabels:            map[string]string{"prometheus": "k8s"},
+		Severity:              "critical",
+		EnvoyNotReadyPercent:  25,
+		CertificateExpiryDays: 7,
+	}
+	rule := DesiredPrometheusRule(cntr)
+	if rule.GetNamespace() != "projectcontour" || rule.GetName() != "test-contour-alerts" {
+		t.Errorf("expected prometheusrule projectcontour/test-contour-alerts, got %s/%s", rule.GetNamespace(), rule.GetName())
+	}
+	if rule.GetLabels()["prometheus"] != "k8s" || !objcontour.IsOwned(rule, cntr) {
+		t.Errorf("expected the rule labels and owner labels, got %v", rule.GetLabels())
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 rule group, got %d", len(groups))
+	}
+	group := groups[0].(map[string]interface{})
+	if group["name"] != "contour-test-ns-test" {
+		t.Errorf("expected rule group contour-test-ns-test, got %v", group["name"])
+	}
+	envoyPods := `namespace="projectcontour",pod=~"test-envoy-.*"`
+	expected := []struct {
+		alert, duration, expr string
+	}{
+		{"ContourUnavailable", "5m",
+			`kube_deployment_status_replicas_available{namespace="projectcontour",deployment="test-contour"} < 1`},
+		{"EnvoyNotReady", "10m",
+			`100 * kube_daemonset_status_number_unavailable{namespace="projectcontour",daemonset="test-envoy"} ` +
+				`/ kube_daemonset_status_desired_number_scheduled{namespace="projectcontour",daemonset="test-envoy"} > 25`},
+		{"EnvoyCertificateExpiring", "1h",
+			`min(envoy_server_days_until_first_cert_expiring{` + envoyPods + `}) < 7`},
+		{"EnvoyServerErrors", "5m",
+			`100 * sum(rate(envoy_http_downstream_rq_xx{` + envoyPods + `,envoy_response_code_class="5"}[5m])) ` +
+				`/ sum(rate(envoy_http_downstream_rq_total{` + envoyPods + `}[5m])) > 5`},
+	}
+	rules, _, _ := unstructured.NestedSlice(group, "rules")
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d alerts, got %d", len(expected), len(rules))
+	}
+	for i, e := range expected {
+		r := rules[i].(map[string]interface{})
+		if r["alert"] != e.alert {
+			t.Errorf("expected alert %d to be %s, got %v", i, e.alert, r["alert"])
+			continue
+		}
+		if r["expr"] != e.expr {
+			t.Errorf("unexpected expression of alert %s:\nexpected: %s\ngot: %v", e.alert, e.expr, r["expr"])
+		}
+		if r["for"] != e.duration {
+			t.Errorf("expected alert %s to fire after %s, got %v", e.alert, e.duration, r["for"])
+		}
+		labels := map[string]interface{}{"severity": "critical", "contour_namespace": "test-ns", "contour_name": "test"}
+		if !apiequality.Semantic.DeepEqual(r["labels"], labels) {
+			t.Errorf("unexpected labels of alert %s: %v", e.alert, r["labels"])
+		}
+		annotations, _ := r["annotations"].(map[string]interface{})
+		if !strings.Contains(annotations["summary"].(string), "test-ns/test") ||
+			!strings.Contains(annotations["description"].(string), "test-ns/test") {
+			t.Errorf("expected the annotations of alert %s to name the contour, got %v", e.alert, annotations)
+		}
+	}
+}
+
+func TestEnsurePrometheusRule(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Alerts = &operatorv1alpha1.AlertParameters{}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	// Alerts fail without the prometheus-operator API.
+	cli := &objutil.MappedClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Mapper: meta.NewDefaultRESTMapper(nil)}
+	if err := EnsurePrometheusRule(ctx, cli, cntr); err == nil {
+		t.Errorf("expected alerts to require the prometheus-operator API")
+	}
+
+	scheme.AddKnownTypeWithName(GroupVersionKind, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(GroupVersionKind.GroupVersion().WithKind("PrometheusRuleList"), &unstructured.UnstructuredList{})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(GroupVersionKind, meta.RESTScopeNamespace)
+	cli = &objutil.MappedClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Mapper: mapper}
+	if err := EnsurePrometheusRule(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	cntr.Spec.Alerts.Severity = "critical"
+	if err := EnsurePrometheusRule(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	rule, err := currentPrometheusRule(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+	for _, r := range rules {
+		r := r.(map[string]interface{})
+		if severity, _, _ := unstructured.NestedString(r, "labels", "severity"); severity != "critical" {
+			t.Errorf("expected the severity of alert %s to be updated, got %q", r["alert"], severity)
+		}
+	}
+
+	cntr.Spec.Alerts = nil
+	if err := EnsurePrometheusRule(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := currentPrometheusRule(ctx, cli, cntr); err == nil {
+		t.Errorf("expected the prometheusrule to be deleted")
+	}
+}
@@ -24,7 +24,6 @@ import (
 	objrole "github.com/projectcontour/contour-operator/internal/objects/role"
 	objrb "github.com/projectcontour/contour-operator/internal/objects/rolebinding"
 	objsa "github.com/projectcontour/contour-operator/internal/objects/serviceaccount"
-	"github.com/projectcontour/contour-operator/pkg/labels"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -47,33 +46,35 @@ const (
 // provided contour.
 func EnsureRBAC(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
 	ns := contour.Spec.Namespace.Name
-	names := []string{ContourRbacName, EnvoyRbacName, CertGenRbacName}
+	contourName := objcontour.ResourceName(contour, ContourRbacName)
+	certGenName := objcontour.ResourceName(contour, CertGenRbacName)
+	names := []string{contourName, objcontour.ResourceName(contour, EnvoyRbacName), certGenName}
 	certSvcAct := &corev1.ServiceAccount{}
 	for _, name := range names {
 		svcAct, err := objsa.EnsureServiceAccount(ctx, cli, name, contour)
 		if err != nil {
 			return fmt.Errorf("failed to ensure service account %s/%s: %w", ns, name, err)
 		}
-		if svcAct.Name == CertGenRbacName {
+		if svcAct.Name == certGenName {
 			certSvcAct = svcAct
 		}
 	}
 	// ClusterRole and ClusterRoleBinding resources are namespace-named to allow ownership
 	// from individual instances of Contour.
-	nsName := fmt.Sprintf("%s-%s", ContourRbacName, contour.Spec.Namespace.Name)
+	nsName := clusterRBACName(contour)
 	cr, err := objcr.EnsureClusterRole(ctx, cli, nsName, contour)
 	if err != nil {
-		return fmt.Errorf("failed to ensure cluster role %s: %w", ContourRbacName, err)
+		return fmt.Errorf("failed to ensure cluster role %s: %w", nsName, err)
 	}
-	if err := objcrb.EnsureClusterRoleBinding(ctx, cli, nsName, cr.Name, ContourRbacName, contour); err != nil {
-		return fmt.Errorf("failed to ensure cluster role binding %s: %w", ContourRbacName, err)
+	if err := objcrb.EnsureClusterRoleBinding(ctx, cli, nsName, cr.Name, contourName, contour); err != nil {
+		return fmt.Errorf("failed to ensure cluster role binding %s: %w", nsName, err)
 	}
-	certRole, err := objrole.EnsureRole(ctx, cli, CertGenRbacName, contour)
+	certRole, err := objrole.EnsureRole(ctx, cli, certGenName, contour)
 	if err != nil {
-		return fmt.Errorf("failed to ensure role %s/%s: %w", ns, CertGenRbacName, err)
+		return fmt.Errorf("failed to ensure role %s/%s: %w", ns, certGenName, err)
 	}
-	if err := objrb.EnsureRoleBinding(ctx, cli, ContourRbacName, certSvcAct.Name, certRole.Name, contour); err != nil {
-		return fmt.Errorf("failed to ensure role binding %s/%s: %w", ns, ContourRbacName, err)
+	if err := objrb.EnsureRoleBinding(ctx, cli, contourName, certSvcAct.Name, certRole.Name, contour); err != nil {
+		return fmt.Errorf("failed to ensure role binding %s/%s: %w", ns, contourName, err)
 	}
 	return nil
 }
@@ -89,8 +90,12 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 		return fmt.Errorf("failed to verify if contours contoursExist in namespace %s: %w",
 			contour.Spec.Namespace.Name, err)
 	}
-	if !contoursExist {
-		cntrRoleBind, err := objrb.CurrentRoleBinding(ctx, cli, ns, ContourRbacName)
+	// The namespaced resources of a Contour sharing its namespace are
+	// named for the Contour, so they are never used by other Contours.
+	if !contoursExist || contour.Spec.Namespace.Shared {
+		contourName := objcontour.ResourceName(contour, ContourRbacName)
+		certGenName := objcontour.ResourceName(contour, CertGenRbacName)
+		cntrRoleBind, err := objrb.CurrentRoleBinding(ctx, cli, ns, contourName)
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				return err
@@ -99,7 +104,7 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 		if cntrRoleBind != nil {
 			objectsToDelete = append(objectsToDelete, cntrRoleBind)
 		}
-		cntrRole, err := objrole.CurrentRole(ctx, cli, ns, ContourRbacName)
+		cntrRole, err := objrole.CurrentRole(ctx, cli, ns, contourName)
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				return err
@@ -108,7 +113,7 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 		if cntrRole != nil {
 			objectsToDelete = append(objectsToDelete, cntrRole)
 		}
-		certRole, err := objrole.CurrentRole(ctx, cli, ns, CertGenRbacName)
+		certRole, err := objrole.CurrentRole(ctx, cli, ns, certGenName)
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				return err
@@ -117,7 +122,7 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 		if certRole != nil {
 			objectsToDelete = append(objectsToDelete, certRole)
 		}
-		names := []string{ContourRbacName, EnvoyRbacName, CertGenRbacName}
+		names := []string{contourName, objcontour.ResourceName(contour, EnvoyRbacName), certGenName}
 		for _, name := range names {
 			svcAct, err := objsa.CurrentServiceAccount(ctx, cli, ns, name)
 			if err != nil {
@@ -134,10 +139,10 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 	if err != nil {
 		return fmt.Errorf("failed to verify if contours exist in any namespace: %w", err)
 	}
-	if !contoursExist {
+	if !contoursExist || contour.Spec.Namespace.Shared {
 		// ClusterRole and ClusterRoleBinding resources are namespace-named to allow ownership
 		// from individual instances of Contour.
-		nsName := fmt.Sprintf("%s-%s", ContourRbacName, contour.Spec.Namespace.Name)
+		nsName := clusterRBACName(contour)
 		crb, err := objcrb.CurrentClusterRoleBinding(ctx, cli, nsName)
 		if err != nil {
 			if !errors.IsNotFound(err) {
@@ -161,7 +166,7 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 		kind := object.GetObjectKind().GroupVersionKind().Kind
 		namespace := object.(metav1.Object).GetNamespace()
 		name := object.(metav1.Object).GetName()
-		if labels.Exist(object, objcontour.OwnerLabels(contour)) {
+		if objcontour.IsOwned(object, contour) {
 			if err := cli.Delete(ctx, object); err != nil {
 				if errors.IsNotFound(err) {
 					continue
@@ -172,3 +177,9 @@ func EnsureRBACDeleted(ctx context.Context, cli client.Client, contour *operator
 	}
 	return utilerrors.NewAggregate(errs)
 }
+
+// clusterRBACName returns the name of the ClusterRole and ClusterRoleBinding of
+// contour, i.e. "contour-<spec-namespace>".
+func clusterRBACName(contour *operatorv1alpha1.Contour) string {
+	return objcontour.ResourceName(contour, fmt.Sprintf("%s-%s", ContourRbacName, contour.Spec.Namespace.Name))
+}
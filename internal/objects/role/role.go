@@ -20,7 +20,6 @@ import (
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	equality "github.com/projectcontour/contour-operator/internal/equality"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
-	"github.com/projectcontour/contour-operator/pkg/labels"
 
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -29,6 +28,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	securityGroupName = "security.openshift.io"
+)
+
 // EnsureRole ensures a Role resource exists with the provided name/ns
 // and contour namespace/name for the owning contour labels.
 func EnsureRole(ctx context.Context, cli client.Client, name string, contour *operatorv1alpha1.Contour) (*rbacv1.Role, error) {
@@ -51,6 +54,29 @@ func EnsureRole(ctx context.Context, cli client.Client, name string, contour *op
 	return updated, nil
 }
 
+// EnsureSCCRole ensures a Role resource exists with the provided name that
+// grants use of the SecurityContextConstraints named scc, using contour
+// namespace/name for the owning contour labels.
+func EnsureSCCRole(ctx context.Context, cli client.Client, name, scc string, contour *operatorv1alpha1.Contour) (*rbacv1.Role, error) {
+	desired := desiredSCCRole(name, scc, contour)
+	current, err := CurrentRole(ctx, cli, contour.Spec.Namespace.Name, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			updated, err := createRole(ctx, cli, desired)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create role %s/%s: %w", desired.Namespace, desired.Name, err)
+			}
+			return updated, nil
+		}
+		return nil, fmt.Errorf("failed to get role %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	updated, err := updateRoleIfNeeded(ctx, cli, contour, current, desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update role %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	return updated, nil
+}
+
 // desiredRole constructs an instance of the desired ClusterRole resource with the
 // provided ns/name and contour namespace/name for the owning contour labels.
 func desiredRole(name string, contour *operatorv1alpha1.Contour) *rbacv1.Role {
@@ -75,6 +101,35 @@ func desiredRole(name string, contour *operatorv1alpha1.Contour) *rbacv1.Role {
 		operatorv1alpha1.OwningContourNameLabel: contour.Name,
 		operatorv1alpha1.OwningContourNsLabel:   contour.Namespace,
 	}
+	objcontour.ApplyResourceMetadata(role, contour)
+	return role
+}
+
+// desiredSCCRole constructs an instance of the desired Role resource with the
+// provided name, granting use of the SecurityContextConstraints named scc and
+// using contour namespace/name for the owning contour labels.
+func desiredSCCRole(name, scc string, contour *operatorv1alpha1.Contour) *rbacv1.Role {
+	role := &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Role",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: contour.Spec.Namespace.Name,
+			Name:      name,
+		},
+	}
+	use := rbacv1.PolicyRule{
+		Verbs:         []string{"use"},
+		APIGroups:     []string{securityGroupName},
+		Resources:     []string{"securitycontextconstraints"},
+		ResourceNames: []string{scc},
+	}
+	role.Rules = []rbacv1.PolicyRule{use}
+	role.Labels = map[string]string{
+		operatorv1alpha1.OwningContourNameLabel: contour.Name,
+		operatorv1alpha1.OwningContourNsLabel:   contour.Namespace,
+	}
+	objcontour.ApplyResourceMetadata(role, contour)
 	return role
 }
 
@@ -103,7 +158,7 @@ func createRole(ctx context.Context, cli client.Client, role *rbacv1.Role) (*rba
 // updateRoleIfNeeded updates a Role resource if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateRoleIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *rbacv1.Role) (*rbacv1.Role, error) {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		role, updated := equality.RoleConfigChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, role); err != nil {
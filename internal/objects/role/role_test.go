@@ -62,3 +62,31 @@ func TestDesiredRole(t *testing.T) {
 	}
 	checkRoleLabels(t, role, ownerLabels)
 }
+
+func TestDesiredSCCRole(t *testing.T) {
+	name := "scc-role-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		RemoveNs:    false,
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	role := desiredSCCRole(name, "test-scc", cntr)
+	checkRoleName(t, role, name)
+	ownerLabels := map[string]string{
+		operatorv1alpha1.OwningContourNameLabel: cntr.Name,
+		operatorv1alpha1.OwningContourNsLabel:   cntr.Namespace,
+	}
+	checkRoleLabels(t, role, ownerLabels)
+	expected := []rbacv1.PolicyRule{{
+		Verbs:         []string{"use"},
+		APIGroups:     []string{"security.openshift.io"},
+		Resources:     []string{"securitycontextconstraints"},
+		ResourceNames: []string{"test-scc"},
+	}}
+	if !apiequality.Semantic.DeepEqual(role.Rules, expected) {
+		t.Errorf("role has unexpected %v rules", role.Rules)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollback reverts the pod template of a workload, i.e. the Contour
+// Deployment or Envoy DaemonSet, to its last known-good template when the
+// rollout of a desired template fails.
+package rollback
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// desiredTemplateHashAnnotation is the annotation of a workload holding a
+	// hash of the pod template rendered from the contour.
+	desiredTemplateHashAnnotation = "contour.operator.projectcontour.io/desired-template-hash"
+	// failedTemplateHashAnnotation is the annotation of a workload holding a
+	// hash of the rendered pod template whose rollout failed.
+	failedTemplateHashAnnotation = "contour.operator.projectcontour.io/failed-template-hash"
+	// lastGoodTemplateAnnotation is the annotation of a workload holding the
+	// rendered pod template of the last complete rollout.
+	lastGoodTemplateAnnotation = "contour.operator.projectcontour.io/last-good-template"
+	// rollbackReasonAnnotation is the annotation of a workload holding why the
+	// rollout of its rendered pod template failed.
+	rollbackReasonAnnotation = "contour.operator.projectcontour.io/rollback-reason"
+	// crashLoopBackOffReason is the waiting reason of a crash looping container.
+	crashLoopBackOffReason = "CrashLoopBackOff"
+)
+
+// Apply sets the pod template tmpl of the desired workload, rendered from the
+// contour, to the last known-good template of the current workload if the
+// rollout of tmpl failed, i.e. failure is not empty. A failed template stays
+// rolled back until the rendered template changes. The last known-good
+// template is recorded once the rollout of the rendered template is complete.
+func Apply(current, desired metav1.Object, tmpl *corev1.PodTemplateSpec, complete bool, failure string) {
+	annotations := current.GetAnnotations()
+	hash := templateHash(tmpl)
+	set := map[string]string{}
+	// Keep the rollback state of current, since the desired workload may
+	// replace the annotations of current.
+	for _, k := range []string{failedTemplateHashAnnotation, lastGoodTemplateAnnotation, rollbackReasonAnnotation} {
+		if v, ok := annotations[k]; ok {
+			set[k] = v
+		}
+	}
+	set[desiredTemplateHashAnnotation] = hash
+
+	lastGood := &corev1.PodTemplateSpec{}
+	good := json.Unmarshal([]byte(annotations[lastGoodTemplateAnnotation]), lastGood) == nil
+	rolledBack := annotations[failedTemplateHashAnnotation] == hash
+	if !rolledBack && failure != "" && good && annotations[desiredTemplateHashAnnotation] == hash &&
+		templateHash(lastGood) != hash {
+		rolledBack = true
+		set[failedTemplateHashAnnotation] = hash
+		set[rollbackReasonAnnotation] = failure
+	}
+	switch {
+	case rolledBack && good:
+		*tmpl = *lastGood
+	case complete && annotations[desiredTemplateHashAnnotation] == hash:
+		// Marshalling a pod template can not fail.
+		data, _ := json.Marshal(tmpl)
+		set[lastGoodTemplateAnnotation] = string(data)
+	}
+
+	merged := desired.GetAnnotations()
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	for k, v := range set {
+		merged[k] = v
+	}
+	desired.SetAnnotations(merged)
+}
+
+// RolledBack returns why the rollout of the rendered pod template of the
+// workload obj failed and true if obj runs its last known-good template.
+func RolledBack(obj metav1.Object) (string, bool) {
+	annotations := obj.GetAnnotations()
+	hash, ok := annotations[desiredTemplateHashAnnotation]
+	if !ok || annotations[failedTemplateHashAnnotation] != hash {
+		return "", false
+	}
+	return annotations[rollbackReasonAnnotation], true
+}
+
+// CrashLooping returns a message naming the first crash looping container of
+// pods, or an empty string if no container is crash looping.
+func CrashLooping(pods []corev1.Pod) string {
+	for _, pod := range pods {
+		for _, c := range pod.Status.ContainerStatuses {
+			if c.State.Waiting != nil && c.State.Waiting.Reason == crashLoopBackOffReason {
+				return fmt.Sprintf("Pod %s container %s is crash looping.", pod.Name, c.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// templateHash returns a hash of pod template tmpl.
+func templateHash(tmpl *corev1.PodTemplateSpec) string {
+	// Marshalling a pod template can not fail.
+	data, _ := json.Marshal(tmpl)
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	return fmt.Sprintf("%x", h.Sum32())
+}
@@ -0,0 +1,79 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollback
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestApply(t *testing.T) {
+	template := func(image string) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "contour", Image: image}}},
+		}
+	}
+	// reconcile applies the rendered template of image to current the way the
+	// operator does, keeping the annotations of current, and returns the
+	// updated workload.
+	reconcile := func(current *appsv1.Deployment, image string, complete bool, failure string) *appsv1.Deployment {
+		desired := &appsv1.Deployment{}
+		desired.Annotations = map[string]string{}
+		for k, v := range current.Annotations {
+			desired.Annotations[k] = v
+		}
+		desired.Spec.Template = template(image)
+		Apply(current, desired, &desired.Spec.Template, complete, failure)
+		return desired
+	}
+	image := func(deploy *appsv1.Deployment) string {
+		return deploy.Spec.Template.Spec.Containers[0].Image
+	}
+
+	current := reconcile(&appsv1.Deployment{}, "contour:v1", false, "")
+	current = reconcile(current, "contour:v1", true, "")
+	if _, ok := current.Annotations[lastGoodTemplateAnnotation]; !ok {
+		t.Fatalf("expected the template of a complete rollout to be recorded")
+	}
+
+	// The rollout of v2 fails and is rolled back to v1.
+	current = reconcile(current, "contour:v2", false, "")
+	if image(current) != "contour:v2" {
+		t.Fatalf("expected template contour:v2, got %s", image(current))
+	}
+	current = reconcile(current, "contour:v2", false, "Pod contour-0 container contour is crash looping.")
+	if image(current) != "contour:v1" {
+		t.Fatalf("expected rollback to template contour:v1, got %s", image(current))
+	}
+	if reason, ok := RolledBack(current); !ok || reason != "Pod contour-0 container contour is crash looping." {
+		t.Errorf("expected workload to be rolled back, got %q, %t", reason, ok)
+	}
+
+	// The failed template stays rolled back while the rollout of v1 completes.
+	current = reconcile(current, "contour:v2", true, "")
+	if image(current) != "contour:v1" {
+		t.Errorf("expected template contour:v1 to be kept, got %s", image(current))
+	}
+
+	// A new rendered template is rolled out.
+	current = reconcile(current, "contour:v3", false, "")
+	if image(current) != "contour:v3" {
+		t.Errorf("expected template contour:v3, got %s", image(current))
+	}
+	if _, ok := RolledBack(current); ok {
+		t.Errorf("expected workload not to be rolled back")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// envoyRouteName is the base name of Envoy's Route, see objcontour.ResourceName.
+	envoyRouteName = "envoy"
+	// envoySvcName is the name of Envoy's Service that the Route sends traffic to.
+	envoySvcName = "envoy"
+	// envoySvcHTTPSPortName is the name of Envoy's HTTPS Service port.
+	envoySvcHTTPSPortName = "https"
+)
+
+// GroupVersionKind is the GroupVersionKind of an OpenShift Route.
+var GroupVersionKind = schema.GroupVersionKind{
+	Group:   "route.openshift.io",
+	Version: "v1",
+	Kind:    "Route",
+}
+
+// APIAvailable returns true if the route.openshift.io API group is served
+// by the cluster that cli is connected to.
+func APIAvailable(cli client.Client) (bool, error) {
+	return objutil.APIAvailable(cli, GroupVersionKind)
+}
+
+// EnsureRoute ensures that an Envoy Route exists for the given contour.
+func EnsureRoute(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	desired := DesiredRoute(contour)
+	current, err := currentRoute(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return createRoute(ctx, cli, desired)
+		}
+		return fmt.Errorf("failed to get route %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+	}
+	if err := updateRouteIfNeeded(ctx, cli, contour, current, desired); err != nil {
+		return fmt.Errorf("failed to update route %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+	}
+	return nil
+}
+
+// EnsureRouteDeleted ensures that an Envoy Route for the provided contour
+// is deleted if Contour owner labels exist.
+func EnsureRouteDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	route, err := currentRoute(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	if objcontour.IsOwned(route, contour) {
+		if err := cli.Delete(ctx, route); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// DesiredRoute generates the desired Envoy Route for the given contour.
+func DesiredRoute(contour *operatorv1alpha1.Contour) *unstructured.Unstructured {
+	termination := operatorv1alpha1.PassthroughRouteTermination
+	params := contour.Spec.NetworkPublishing.Envoy.Route
+	if params != nil && params.Termination != "" {
+		termination = params.Termination
+	}
+	tls := map[string]interface{}{
+		"termination": strings.ToLower(string(termination)),
+	}
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   objcontour.ResourceName(contour, envoySvcName),
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": envoySvcHTTPSPortName,
+		},
+		"tls": tls,
+	}
+	if params != nil {
+		if params.Hostname != nil {
+			spec["host"] = *params.Hostname
+		}
+		if termination == operatorv1alpha1.ReencryptRouteTermination && params.DestinationCACertificate != nil {
+			tls["destinationCACertificate"] = *params.DestinationCACertificate
+		}
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(GroupVersionKind)
+	route.SetNamespace(contour.Spec.Namespace.Name)
+	route.SetName(objcontour.ResourceName(contour, envoyRouteName))
+	route.SetLabels(map[string]string{
+		operatorv1alpha1.OwningContourNameLabel: contour.Name,
+		operatorv1alpha1.OwningContourNsLabel:   contour.Namespace,
+	})
+	route.Object["spec"] = spec
+	objcontour.ApplyResourceMetadata(route, contour)
+	return route
+}
+
+// currentRoute returns the current Envoy Route for the provided contour.
+func currentRoute(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(GroupVersionKind)
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      objcontour.ResourceName(contour, envoyRouteName),
+	}
+	err := cli.Get(ctx, key, current)
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// createRoute creates a Route resource for the provided route.
+func createRoute(ctx context.Context, cli client.Client, route *unstructured.Unstructured) error {
+	if err := cli.Create(ctx, route); err != nil {
+		return fmt.Errorf("failed to create route %s/%s: %w", route.GetNamespace(), route.GetName(), err)
+	}
+	return nil
+}
+
+// updateRouteIfNeeded updates an Envoy Route if current does not match desired,
+// using contour to verify the existence of owner labels.
+func updateRouteIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *unstructured.Unstructured) error {
+	if objcontour.IsOwned(current, contour) {
+		route, updated := equality.RouteConfigChanged(current, desired)
+		if updated {
+			if err := cli.Update(ctx, route); err != nil {
+				return fmt.Errorf("failed to update route %s/%s: %w", route.GetNamespace(), route.GetName(), err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
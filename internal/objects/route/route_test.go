@@ -0,0 +1,84 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func checkRouteHasStringField(t *testing.T, route *unstructured.Unstructured, expected string, fields ...string) {
+	t.Helper()
+
+	val, found, err := unstructured.NestedString(route.Object, fields...)
+	if err != nil {
+		t.Fatalf("failed to get route field %v: %v", fields, err)
+	}
+	if found && val == expected {
+		return
+	}
+
+	t.Errorf("route has unexpected %v value %q", fields, val)
+}
+
+func checkRouteMissingField(t *testing.T, route *unstructured.Unstructured, fields ...string) {
+	t.Helper()
+
+	if _, found, _ := unstructured.NestedFieldNoCopy(route.Object, fields...); !found {
+		return
+	}
+
+	t.Errorf("route has unexpected field %v", fields)
+}
+
+func TestDesiredRoute(t *testing.T) {
+	name := "route-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		RemoveNs:    false,
+		NetworkType: operatorv1alpha1.RoutePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	route := DesiredRoute(cntr)
+	if route.GroupVersionKind() != GroupVersionKind {
+		t.Errorf("route has unexpected group version kind %s", route.GroupVersionKind())
+	}
+	if route.GetNamespace() != cfg.SpecNs || route.GetName() != envoyRouteName {
+		t.Errorf("route has unexpected namespace/name %s/%s", route.GetNamespace(), route.GetName())
+	}
+	checkRouteHasStringField(t, route, "Service", "spec", "to", "kind")
+	checkRouteHasStringField(t, route, envoySvcName, "spec", "to", "name")
+	checkRouteHasStringField(t, route, envoySvcHTTPSPortName, "spec", "port", "targetPort")
+	checkRouteHasStringField(t, route, "passthrough", "spec", "tls", "termination")
+	checkRouteMissingField(t, route, "spec", "host")
+
+	// Set reencrypt termination with a custom hostname and destination CA.
+	cntr.Spec.NetworkPublishing.Envoy.Route = &operatorv1alpha1.RouteParameters{
+		Termination:              operatorv1alpha1.ReencryptRouteTermination,
+		Hostname:                 pointer.StringPtr("www.example.com"),
+		DestinationCACertificate: pointer.StringPtr("test-ca"),
+	}
+	route = DesiredRoute(cntr)
+	checkRouteHasStringField(t, route, "reencrypt", "spec", "tls", "termination")
+	checkRouteHasStringField(t, route, "test-ca", "spec", "tls", "destinationCACertificate")
+	checkRouteHasStringField(t, route, "www.example.com", "spec", "host")
+}
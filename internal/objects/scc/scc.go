@@ -0,0 +1,243 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objrole "github.com/projectcontour/contour-operator/internal/objects/role"
+	objrb "github.com/projectcontour/contour-operator/internal/objects/rolebinding"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// envoySCCRbacName is the name used for the Role and RoleBinding that grant
+	// Envoy's service account use of its SecurityContextConstraints.
+	envoySCCRbacName = "envoy-scc"
+)
+
+// GroupVersionKind is the GroupVersionKind of an OpenShift SecurityContextConstraints.
+var GroupVersionKind = schema.GroupVersionKind{
+	Group:   "security.openshift.io",
+	Version: "v1",
+	Kind:    "SecurityContextConstraints",
+}
+
+// APIAvailable returns true if the security.openshift.io API group is served
+// by the cluster that cli is connected to.
+func APIAvailable(cli client.Client) (bool, error) {
+	return objutil.APIAvailable(cli, GroupVersionKind)
+}
+
+// EnvoySCCName returns the name of the SecurityContextConstraints used by
+// Envoy pods of the provided contour.
+func EnvoySCCName(contour *operatorv1alpha1.Contour) string {
+	if sccNameSet(contour) {
+		return *contour.Spec.SecurityContextConstraints.Name
+	}
+	// The SecurityContextConstraints resource is namespace-named to allow
+	// ownership from individual instances of Contour.
+	return objcontour.ResourceName(contour, fmt.Sprintf("contour-envoy-%s", contour.Spec.Namespace.Name))
+}
+
+// EnsureSCC ensures Envoy's service account is granted use of a SecurityContextConstraints
+// for the provided contour, creating the SecurityContextConstraints if the contour does not
+// reference an existing one. EnsureSCC is a no-op if the security.openshift.io API group is
+// not served by the cluster.
+func EnsureSCC(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	available, err := APIAvailable(cli)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return nil
+	}
+	name := EnvoySCCName(contour)
+	if !sccNameSet(contour) {
+		if err := ensureManagedSCC(ctx, cli, contour); err != nil {
+			return err
+		}
+	}
+	rbacName := objcontour.ResourceName(contour, envoySCCRbacName)
+	role, err := objrole.EnsureSCCRole(ctx, cli, rbacName, name, contour)
+	if err != nil {
+		return fmt.Errorf("failed to ensure role %s/%s: %w", contour.Spec.Namespace.Name, rbacName, err)
+	}
+	svcAct := objcontour.ResourceName(contour, objutil.EnvoyRbacName)
+	if err := objrb.EnsureRoleBinding(ctx, cli, rbacName, svcAct, role.Name, contour); err != nil {
+		return fmt.Errorf("failed to ensure role binding %s/%s: %w", contour.Spec.Namespace.Name, rbacName, err)
+	}
+	return nil
+}
+
+// EnsureSCCDeleted ensures the SecurityContextConstraints resources for the provided
+// contour are deleted if Contour owner labels exist. A SecurityContextConstraints
+// referenced by the contour is never deleted.
+func EnsureSCCDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	available, err := APIAvailable(cli)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return nil
+	}
+	ns := contour.Spec.Namespace.Name
+	var objectsToDelete []client.Object
+	rbacName := objcontour.ResourceName(contour, envoySCCRbacName)
+	rb, err := objrb.CurrentRoleBinding(ctx, cli, ns, rbacName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		objectsToDelete = append(objectsToDelete, rb)
+	}
+	role, err := objrole.CurrentRole(ctx, cli, ns, rbacName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		objectsToDelete = append(objectsToDelete, role)
+	}
+	if !sccNameSet(contour) {
+		scc, err := currentSCC(ctx, cli, EnvoySCCName(contour))
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+		} else {
+			objectsToDelete = append(objectsToDelete, scc)
+		}
+	}
+	for _, object := range objectsToDelete {
+		if objcontour.IsOwned(object, contour) {
+			if err := cli.Delete(ctx, object); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to delete %s %s: %w", object.GetObjectKind().GroupVersionKind().Kind,
+					object.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DesiredSCC returns the desired SecurityContextConstraints for the provided contour.
+// The SecurityContextConstraints permits Envoy's hostPorts and user IDs while
+// otherwise matching the OpenShift "restricted" SecurityContextConstraints.
+func DesiredSCC(contour *operatorv1alpha1.Contour) *unstructured.Unstructured {
+	scc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"allowHostDirVolumePlugin": false,
+			"allowHostIPC":             false,
+			"allowHostNetwork":         false,
+			"allowHostPID":             false,
+			"allowHostPorts":           true,
+			"allowPrivilegedContainer": false,
+			"readOnlyRootFilesystem":   false,
+			"requiredDropCapabilities": []interface{}{"KILL", "MKNOD", "SETUID", "SETGID"},
+			"runAsUser": map[string]interface{}{
+				"type": "RunAsAny",
+			},
+			"seLinuxContext": map[string]interface{}{
+				"type": "MustRunAs",
+			},
+			"fsGroup": map[string]interface{}{
+				"type": "RunAsAny",
+			},
+			"supplementalGroups": map[string]interface{}{
+				"type": "RunAsAny",
+			},
+			"volumes": []interface{}{"configMap", "downwardAPI", "emptyDir", "projected", "secret"},
+		},
+	}
+	scc.SetGroupVersionKind(GroupVersionKind)
+	scc.SetName(EnvoySCCName(contour))
+	scc.SetLabels(map[string]string{
+		operatorv1alpha1.OwningContourNameLabel: contour.Name,
+		operatorv1alpha1.OwningContourNsLabel:   contour.Namespace,
+	})
+	objcontour.ApplyResourceMetadata(scc, contour)
+	return scc
+}
+
+// ensureManagedSCC ensures the operator-managed SecurityContextConstraints exists
+// for the provided contour.
+func ensureManagedSCC(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	desired := DesiredSCC(contour)
+	current, err := currentSCC(ctx, cli, desired.GetName())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return createSCC(ctx, cli, desired)
+		}
+		return fmt.Errorf("failed to get security context constraints %s: %w", desired.GetName(), err)
+	}
+	if err := updateSCCIfNeeded(ctx, cli, contour, current, desired); err != nil {
+		return fmt.Errorf("failed to update security context constraints %s: %w", desired.GetName(), err)
+	}
+	return nil
+}
+
+// currentSCC returns the current SecurityContextConstraints for the provided name.
+func currentSCC(ctx context.Context, cli client.Client, name string) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(GroupVersionKind)
+	key := types.NamespacedName{Name: name}
+	if err := cli.Get(ctx, key, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// createSCC creates a SecurityContextConstraints resource for the provided scc.
+func createSCC(ctx context.Context, cli client.Client, scc *unstructured.Unstructured) error {
+	if err := cli.Create(ctx, scc); err != nil {
+		return fmt.Errorf("failed to create security context constraints %s: %w", scc.GetName(), err)
+	}
+	return nil
+}
+
+// updateSCCIfNeeded updates a SecurityContextConstraints resource if current does
+// not match desired, using contour to verify the existence of owner labels.
+func updateSCCIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *unstructured.Unstructured) error {
+	if objcontour.IsOwned(current, contour) {
+		scc, updated := equality.SecurityContextConstraintsChanged(current, desired)
+		if updated {
+			if err := cli.Update(ctx, scc); err != nil {
+				return fmt.Errorf("failed to update security context constraints %s: %w", scc.GetName(), err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// sccNameSet returns true if the provided contour references an existing
+// SecurityContextConstraints.
+func sccNameSet(contour *operatorv1alpha1.Contour) bool {
+	return contour.Spec.SecurityContextConstraints != nil &&
+		contour.Spec.SecurityContextConstraints.Name != nil
+}
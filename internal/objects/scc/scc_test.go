@@ -0,0 +1,86 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"fmt"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func checkSCCHasBoolField(t *testing.T, scc *unstructured.Unstructured, field string, expected bool) {
+	t.Helper()
+
+	val, found, err := unstructured.NestedBool(scc.Object, field)
+	if err != nil {
+		t.Fatalf("failed to get security context constraints field %s: %v", field, err)
+	}
+	if found && val == expected {
+		return
+	}
+
+	t.Errorf("security context constraints has unexpected %s value %t", field, val)
+}
+
+func TestEnvoySCCName(t *testing.T) {
+	name := "scc-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		RemoveNs:    false,
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	if got := EnvoySCCName(cntr); got != "contour-envoy-projectcontour" {
+		t.Errorf("unexpected security context constraints name %q", got)
+	}
+	cntr.Spec.SecurityContextConstraints = &operatorv1alpha1.SecurityContextConstraints{
+		Name: pointer.StringPtr("existing-scc"),
+	}
+	if got := EnvoySCCName(cntr); got != "existing-scc" {
+		t.Errorf("unexpected security context constraints name %q", got)
+	}
+}
+
+func TestDesiredSCC(t *testing.T) {
+	name := "scc-test"
+	cfg := objcontour.Config{
+		Name:        name,
+		Namespace:   fmt.Sprintf("%s-ns", name),
+		SpecNs:      "projectcontour",
+		RemoveNs:    false,
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	scc := DesiredSCC(cntr)
+	if scc.GroupVersionKind() != GroupVersionKind {
+		t.Errorf("security context constraints has unexpected group version kind %s", scc.GroupVersionKind())
+	}
+	if scc.GetName() != EnvoySCCName(cntr) {
+		t.Errorf("security context constraints has unexpected name %s", scc.GetName())
+	}
+	checkSCCHasBoolField(t, scc, "allowHostPorts", true)
+	checkSCCHasBoolField(t, scc, "allowHostNetwork", false)
+	checkSCCHasBoolField(t, scc, "allowPrivilegedContainer", false)
+	runAsUser, _, _ := unstructured.NestedString(scc.Object, "runAsUser", "type")
+	if runAsUser != "RunAsAny" {
+		t.Errorf("security context constraints has unexpected runAsUser type %q", runAsUser)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// additionalEnvoySvcLabel is the label of an additional Envoy Service, set to
+// the name of the Service in the contour.
+const additionalEnvoySvcLabel = "contour.operator.projectcontour.io/additional-envoy-service"
+
+// defaultEnvoyServicePorts are the ports of an additional Envoy Service that
+// does not specify its ports.
+var defaultEnvoyServicePorts = []operatorv1alpha1.EnvoyServicePort{
+	{Name: "http", Port: EnvoyServiceHTTPPort},
+	{Name: "https", Port: EnvoyServiceHTTPSPort},
+}
+
+// EnsureAdditionalEnvoyServices ensures that the additional Envoy Services of
+// the given contour exist, deleting the additional Envoy Services removed from
+// the contour. A NodePortConflictError is returned if a node port of a Service
+// is allocated to another Service.
+func EnsureAdditionalEnvoyServices(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	keep := map[string]bool{}
+	for i := range contour.Spec.NetworkPublishing.Envoy.AdditionalServices {
+		params := &contour.Spec.NetworkPublishing.Envoy.AdditionalServices[i]
+		desired := DesiredAdditionalEnvoyService(contour, params)
+		keep[desired.Name] = true
+		current := &corev1.Service{}
+		if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+			if errors.IsNotFound(err) {
+				if err := nodePortConflict(ctx, cli, desired, createService(ctx, cli, desired)); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("failed to get service %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		desired.Spec.Selector = objds.EnvoyServiceSelector(contour, current)
+		if err := updateEnvoyServiceIfNeeded(ctx, cli, additionalServiceContour(contour, params), current, desired); err != nil {
+			return nodePortConflict(ctx, cli, desired, fmt.Errorf("failed to update service %s/%s: %w",
+				desired.Namespace, desired.Name, err))
+		}
+	}
+	return deleteAdditionalEnvoyServices(ctx, cli, contour, keep)
+}
+
+// EnsureAdditionalEnvoyServicesDeleted ensures that the additional Envoy
+// Services of the provided contour are deleted if Contour owner labels exist.
+func EnsureAdditionalEnvoyServicesDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	return deleteAdditionalEnvoyServices(ctx, cli, contour, nil)
+}
+
+// deleteAdditionalEnvoyServices deletes the additional Envoy Services of the
+// provided contour that are not named in keep.
+func deleteAdditionalEnvoyServices(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, keep map[string]bool) error {
+	svcs := &corev1.ServiceList{}
+	if err := cli.List(ctx, svcs, client.InNamespace(contour.Spec.Namespace.Name),
+		client.MatchingLabels(objcontour.OwnerLabels(contour)), client.HasLabels{additionalEnvoySvcLabel}); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		if keep[svc.Name] || !objcontour.IsOwned(svc, contour) {
+			continue
+		}
+		if err := cli.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete service %s/%s: %w", svc.Namespace, svc.Name, err)
+		}
+	}
+	return nil
+}
+
+// DesiredAdditionalEnvoyService generates the desired additional Envoy Service
+// of the given contour for params.
+func DesiredAdditionalEnvoyService(contour *operatorv1alpha1.Contour, params *operatorv1alpha1.AdditionalEnvoyService) *corev1.Service {
+	svc := DesiredEnvoyService(additionalServiceContour(contour, params))
+	svc.Name = objcontour.ResourceName(contour, envoySvcName+"-"+params.Name)
+	svc.Labels[additionalEnvoySvcLabel] = params.Name
+	ports := params.Ports
+	if len(ports) == 0 {
+		ports = defaultEnvoyServicePorts
+	}
+	var published []corev1.ServicePort
+	for _, port := range ports {
+		for _, p := range svc.Spec.Ports {
+			if p.Name != port.Name {
+				continue
+			}
+			p.Port, p.NodePort = port.Port, 0
+			if port.NodePort != nil && svc.Spec.Type == corev1.ServiceTypeNodePort {
+				p.NodePort = *port.NodePort
+			}
+			published = append(published, p)
+		}
+	}
+	svc.Spec.Ports = published
+	for name, value := range params.Annotations {
+		svc.Annotations[name] = value
+	}
+	return svc
+}
+
+// additionalServiceContour returns a copy of contour publishing Envoy using
+// params, without the node ports and external IPs of the Envoy Service.
+func additionalServiceContour(contour *operatorv1alpha1.Contour, params *operatorv1alpha1.AdditionalEnvoyService) *operatorv1alpha1.Contour {
+	c := contour.DeepCopy()
+	envoy := &c.Spec.NetworkPublishing.Envoy
+	envoy.Type = params.Type
+	envoy.LoadBalancer = params.LoadBalancer
+	envoy.HTTPService = nil
+	envoy.AdditionalServices = nil
+	envoy.NodePorts = nil
+	envoy.ExternalIPs = nil
+	envoy.ServicePorts = nil
+	return c
+}
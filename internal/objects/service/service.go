@@ -24,11 +24,12 @@ import (
 	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
 	objdeploy "github.com/projectcontour/contour-operator/internal/objects/deployment"
 	objcfg "github.com/projectcontour/contour-operator/internal/objects/sharedconfig"
-	"github.com/projectcontour/contour-operator/pkg/labels"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,6 +44,9 @@ const (
 	// Contours/ns when https://github.com/projectcontour/contour/issues/2122 is fixed.
 	// envoySvcName is the name of Envoy's Service.
 	envoySvcName = "envoy"
+	// envoyHTTPSvcName is the name of the Service publishing Envoy's HTTP
+	// network endpoint on its own.
+	envoyHTTPSvcName = "envoy-http"
 	// awsLbBackendProtoAnnotation is a Service annotation that places the AWS ELB into
 	// "TCP" mode so that it does not do HTTP negotiation for HTTPS connections at the
 	// ELB edge. The downside of this is the remote IP address of all connections will
@@ -54,6 +58,10 @@ const (
 	// balancer type. See the following for additional details:
 	// https://kubernetes.io/docs/concepts/services-networking/service/#aws-nlb-support
 	awsLBTypeAnnotation = "service.beta.kubernetes.io/aws-load-balancer-type"
+	// topologyAwareHintsAnnotation is a Service annotation used to enable topology
+	// aware hints for its endpoints. For additional details, see:
+	// https://kubernetes.io/docs/concepts/services-networking/topology-aware-hints/
+	topologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
 	// awsLBProxyProtocolAnnotation is used to enable the PROXY protocol for an AWS Classic
 	// load balancer. For additional details, see:
 	// https://kubernetes.io/docs/concepts/services-networking/service/#proxy-protocol-support-on-aws
@@ -62,6 +70,20 @@ const (
 	// assign Load Balancer IP based on Allocation IDs of AWS Elastic IP resources when
 	// load balancer scope is set to "External"
 	awsLBAllocationIDsAnnotation = "service.beta.kubernetes.io/aws-load-balancer-eip-allocations"
+	// awsLBSubnetsAnnotation is a Service annotation used to specify the subnets
+	// an AWS Network Load Balancer is placed in.
+	awsLBSubnetsAnnotation = "service.beta.kubernetes.io/aws-load-balancer-subnets"
+	// awsLBTargetTypeAnnotation is a Service annotation used to specify the target
+	// type of an AWS Network Load Balancer. For additional details, see:
+	// https://kubernetes-sigs.github.io/aws-load-balancer-controller/latest/guide/service/nlb/
+	awsLBTargetTypeAnnotation = "service.beta.kubernetes.io/aws-load-balancer-nlb-target-type"
+	// awsLBSSLCertAnnotation is a Service annotation used to specify the ARN of the
+	// certificate an AWS load balancer uses to terminate TLS. For additional details, see:
+	// https://kubernetes.io/docs/concepts/services-networking/service/#ssl-support-on-aws
+	awsLBSSLCertAnnotation = "service.beta.kubernetes.io/aws-load-balancer-ssl-cert"
+	// awsLBSSLPortsAnnotation is a Service annotation used to specify the Service
+	// ports that an AWS load balancer terminates TLS for.
+	awsLBSSLPortsAnnotation = "service.beta.kubernetes.io/aws-load-balancer-ssl-ports"
 	// awsInternalLBAnnotation is the annotation used on a service to specify an AWS
 	// load balancer as being internal.
 	awsInternalLBAnnotation = "service.beta.kubernetes.io/aws-load-balancer-internal"
@@ -75,6 +97,16 @@ const (
 	// azureInternalLBAnnotation is the annotation used on a service to specify an Azure
 	// load balancer as being internal.
 	azureInternalLBAnnotation = "service.beta.kubernetes.io/azure-load-balancer-internal"
+	// azurePIPNameAnnotation is a Service annotation that provides capability to assign
+	// Load Balancer IP based on the name of a Public IP Azure resource when load balancer
+	// scope is set to "External".
+	azurePIPNameAnnotation = "service.beta.kubernetes.io/azure-pip-name"
+	// metalLBAddressPoolAnnotation is a Service annotation that specifies the MetalLB
+	// address pool to allocate the load balancer IP from.
+	metalLBAddressPoolAnnotation = "metallb.universe.tf/address-pool"
+	// metalLBAllowSharedIPAnnotation is a Service annotation that allows a MetalLB
+	// load balancer IP to be shared by Services using the same sharing key.
+	metalLBAllowSharedIPAnnotation = "metallb.universe.tf/allow-shared-ip"
 	// gcpLBSubnetAnnotation is a Service annotation that provides capability to assign
 	// Load Balancer IP to specified subnet when load balancer scope is set to "Internal".
 	gcpLBSubnetAnnotation = "networking.gke.io/internal-load-balancer-subnet"
@@ -84,6 +116,13 @@ const (
 	// gcpLBTypeAnnotation is the annotation used on a service to specify a GCP load balancer
 	// type for GKE version 1.17 and later.
 	gcpLBTypeAnnotation = "networking.gke.io/load-balancer-type"
+	// gcpLBGlobalAccessAnnotation is a Service annotation used to allow clients from
+	// any region to access a GCP internal load balancer.
+	gcpLBGlobalAccessAnnotation = "networking.gke.io/internal-load-balancer-allow-global-access"
+	// gcpNEGAnnotation is a Service annotation used to create standalone network
+	// endpoint groups for the Service ports. For additional details, see:
+	// https://cloud.google.com/kubernetes-engine/docs/how-to/standalone-neg
+	gcpNEGAnnotation = "cloud.google.com/neg"
 	// EnvoyServiceHTTPPort is the HTTP port number of the Envoy service.
 	EnvoyServiceHTTPPort = int32(80)
 	// EnvoyServiceHTTPSPort is the HTTPS port number of the Envoy service.
@@ -133,22 +172,112 @@ func EnsureContourService(ctx context.Context, cli client.Client, contour *opera
 	return nil
 }
 
+// NodePortConflictError is the error of creating or updating the Envoy Service
+// of a contour with a node port that is allocated to another Service.
+type NodePortConflictError struct {
+	// Port is the conflicting node port.
+	Port int32
+	// Service is the namespace and name of the Service the node port is
+	// allocated to, i.e. "default/app".
+	Service string
+}
+
+func (e *NodePortConflictError) Error() string {
+	return fmt.Sprintf("node port %d is already allocated to service %s", e.Port, e.Service)
+}
+
 // EnsureEnvoyService ensures that an Envoy Service exists for the given contour.
+// A NodePortConflictError is returned if a node port of the Service is
+// allocated to another Service.
 func EnsureEnvoyService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
 	desired := DesiredEnvoyService(contour)
-	current, err := currentEnvoyService(ctx, cli, contour)
+	current, err := CurrentEnvoyService(ctx, cli, contour)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return createService(ctx, cli, desired)
+			return nodePortConflict(ctx, cli, desired, createService(ctx, cli, desired))
 		}
 		return fmt.Errorf("failed to get service %s/%s: %w", desired.Namespace, desired.Name, err)
 	}
+	// The daemonset package switches the selector during a blue-green rollout.
+	desired.Spec.Selector = objds.EnvoyServiceSelector(contour, current)
 	if err := updateEnvoyServiceIfNeeded(ctx, cli, contour, current, desired); err != nil {
-		return fmt.Errorf("failed to update service %s/%s: %w", desired.Namespace, desired.Name, err)
+		return nodePortConflict(ctx, cli, desired, fmt.Errorf("failed to update service %s/%s: %w",
+			desired.Namespace, desired.Name, err))
+	}
+	return nil
+}
+
+// EnsureEnvoyHTTPService ensures that an Envoy HTTP Service exists for the
+// given contour if it publishes Envoy's HTTP network endpoint on a separate
+// Service, or that it is deleted otherwise. A NodePortConflictError is returned
+// if a node port of the Service is allocated to another Service.
+func EnsureEnvoyHTTPService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	desired := DesiredEnvoyHTTPService(contour)
+	if desired == nil {
+		return EnsureEnvoyHTTPServiceDeleted(ctx, cli, contour)
+	}
+	current, err := currentEnvoyHTTPService(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nodePortConflict(ctx, cli, desired, createService(ctx, cli, desired))
+		}
+		return fmt.Errorf("failed to get service %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	desired.Spec.Selector = objds.EnvoyServiceSelector(contour, current)
+	if err := updateEnvoyServiceIfNeeded(ctx, cli, httpServiceContour(contour), current, desired); err != nil {
+		return nodePortConflict(ctx, cli, desired, fmt.Errorf("failed to update service %s/%s: %w",
+			desired.Namespace, desired.Name, err))
 	}
 	return nil
 }
 
+// nodePortConflict returns a NodePortConflictError identifying the Service a
+// node port of svc is allocated to if err is the API server rejecting svc as
+// invalid, i.e. because the node port is already allocated, or err otherwise.
+// The Services are read from the API server, since the operator only caches
+// the Services it manages.
+func nodePortConflict(ctx context.Context, cli client.Client, svc *corev1.Service, err error) error {
+	if err == nil || !errors.IsInvalid(err) {
+		return err
+	}
+	wanted := map[int32]bool{}
+	for _, p := range svc.Spec.Ports {
+		if p.NodePort != 0 {
+			wanted[p.NodePort] = true
+		}
+	}
+	if svc.Spec.HealthCheckNodePort != 0 {
+		wanted[svc.Spec.HealthCheckNodePort] = true
+	}
+	if len(wanted) == 0 {
+		return err
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ServiceList"))
+	if err := cli.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	for _, item := range list.Items {
+		if item.GetNamespace() == svc.Namespace && item.GetName() == svc.Name {
+			continue
+		}
+		other := &corev1.Service{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, other); err != nil {
+			return fmt.Errorf("failed to convert service %s/%s: %w", item.GetNamespace(), item.GetName(), err)
+		}
+		ports := []int32{other.Spec.HealthCheckNodePort}
+		for _, p := range other.Spec.Ports {
+			ports = append(ports, p.NodePort)
+		}
+		for _, port := range ports {
+			if port != 0 && wanted[port] {
+				return &NodePortConflictError{Port: port, Service: other.Namespace + "/" + other.Name}
+			}
+		}
+	}
+	return err
+}
+
 // EnsureContourServiceDeleted ensures that a Contour Service for the
 // provided contour is deleted if Contour owner labels exist.
 func EnsureContourServiceDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
@@ -159,7 +288,7 @@ func EnsureContourServiceDeleted(ctx context.Context, cli client.Client, contour
 		}
 		return err
 	}
-	if labels.Exist(svc, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(svc, contour) {
 		if err := cli.Delete(ctx, svc); err != nil {
 			if errors.IsNotFound(err) {
 				return nil
@@ -173,14 +302,35 @@ func EnsureContourServiceDeleted(ctx context.Context, cli client.Client, contour
 // EnsureEnvoyServiceDeleted ensures that an Envoy Service for the
 // provided contour is deleted.
 func EnsureEnvoyServiceDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
-	svc, err := currentEnvoyService(ctx, cli, contour)
+	svc, err := CurrentEnvoyService(ctx, cli, contour)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if objcontour.IsOwned(svc, contour) {
+		if err := cli.Delete(ctx, svc); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureEnvoyHTTPServiceDeleted ensures that an Envoy HTTP Service for the
+// provided contour is deleted if Contour owner labels exist.
+func EnsureEnvoyHTTPServiceDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	svc, err := currentEnvoyHTTPService(ctx, cli, contour)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil
 		}
 		return err
 	}
-	if labels.Exist(svc, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(svc, contour) {
 		if err := cli.Delete(ctx, svc); err != nil {
 			if errors.IsNotFound(err) {
 				return nil
@@ -197,7 +347,7 @@ func DesiredContourService(contour *operatorv1alpha1.Contour) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: contour.Spec.Namespace.Name,
-			Name:      contourSvcName,
+			Name:      objcontour.ResourceName(contour, contourSvcName),
 			Labels: map[string]string{
 				operatorv1alpha1.OwningContourNameLabel: contour.Name,
 				operatorv1alpha1.OwningContourNsLabel:   contour.Namespace,
@@ -206,22 +356,31 @@ func DesiredContourService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
 				{
-					Name:       "xds",
-					Port:       xdsPort,
-					Protocol:   corev1.ProtocolTCP,
-					TargetPort: intstr.IntOrString{IntVal: xdsPort},
+					Name:        "xds",
+					Port:        xdsPort,
+					Protocol:    corev1.ProtocolTCP,
+					TargetPort:  intstr.IntOrString{IntVal: xdsPort},
+					AppProtocol: appProtocol(contour.Spec.NetworkPublishing.AppProtocols.XDS, "https"),
 				},
 			},
-			Selector:        objdeploy.ContourDeploymentPodSelector().MatchLabels,
+			Selector:        objdeploy.ContourDeploymentPodSelector(contour).MatchLabels,
 			Type:            corev1.ServiceTypeClusterIP,
 			SessionAffinity: corev1.ServiceAffinityNone,
 		},
 	}
+	objcontour.ApplyResourceMetadata(svc, contour)
 	return svc
 }
 
 // DesiredEnvoyService generates the desired Envoy Service for the given contour.
 func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
+	tlsTermination := awsTLSTermination(&contour.Spec)
+	var httpContainerPort int32
+	for _, port := range contour.Spec.NetworkPublishing.Envoy.ContainerPorts {
+		if port.Name == "http" {
+			httpContainerPort = port.PortNumber
+		}
+	}
 	var ports []corev1.ServicePort
 	for _, port := range contour.Spec.NetworkPublishing.Envoy.ContainerPorts {
 		var p corev1.ServicePort
@@ -232,24 +391,59 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 			break
 		case port.Name == "http":
 			httpFound = true
-			p.Name = port.Name
-			p.Port = EnvoyServiceHTTPPort
+			p.Name, p.Port = EnvoyServicePort(contour, port.Name)
 			p.Protocol = corev1.ProtocolTCP
 			p.TargetPort = intstr.IntOrString{IntVal: port.PortNumber}
+			p.AppProtocol = appProtocol(contour.Spec.NetworkPublishing.AppProtocols.HTTP, "http")
 			ports = append(ports, p)
 		case port.Name == "https":
 			httpsFound = true
-			p.Name = port.Name
-			p.Port = EnvoyServiceHTTPSPort
+			p.Name, p.Port = EnvoyServicePort(contour, port.Name)
 			p.Protocol = corev1.ProtocolTCP
 			p.TargetPort = intstr.IntOrString{IntVal: port.PortNumber}
+			p.AppProtocol = appProtocol(contour.Spec.NetworkPublishing.AppProtocols.HTTPS, "https")
+			if tlsTermination != nil {
+				// TLS is terminated by the load balancer, so Envoy serves plain HTTP.
+				p.TargetPort = intstr.IntOrString{IntVal: httpContainerPort}
+				p.AppProtocol = appProtocol(contour.Spec.NetworkPublishing.AppProtocols.HTTPS, "http")
+			}
 			ports = append(ports, p)
 		}
 	}
+	if contour.Spec.NetworkPublishing.Envoy.HTTPService != nil {
+		// Envoy's HTTP network endpoint is published by the Envoy HTTP Service.
+		httpsName, _ := EnvoyServicePort(contour, "https")
+		ports = servicePorts(ports, httpsName)
+	}
+	if contour.Spec.NetworkPublishing.Envoy.HTTP3 {
+		_, httpsPort := EnvoyServicePort(contour, "https")
+		for _, port := range contour.Spec.NetworkPublishing.Envoy.ContainerPorts {
+			if port.Name == "https" {
+				ports = append(ports, corev1.ServicePort{
+					Name:       "http3",
+					Port:       httpsPort,
+					Protocol:   corev1.ProtocolUDP,
+					TargetPort: intstr.IntOrString{IntVal: port.PortNumber},
+				})
+			}
+		}
+	}
+	for _, port := range contour.Spec.NetworkPublishing.Envoy.ExtraPorts {
+		p := corev1.ServicePort{
+			Name:       port.Name,
+			Port:       port.ServicePort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.IntOrString{IntVal: port.ContainerPort},
+		}
+		if port.NodePort != nil && contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.NodePortServicePublishingType {
+			p.NodePort = *port.NodePort
+		}
+		ports = append(ports, p)
+	}
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   contour.Spec.Namespace.Name,
-			Name:        envoySvcName,
+			Name:        objcontour.ResourceName(contour, envoySvcName),
 			Annotations: map[string]string{},
 			Labels: map[string]string{
 				operatorv1alpha1.OwningContourNameLabel: contour.Name,
@@ -258,10 +452,26 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		},
 		Spec: corev1.ServiceSpec{
 			Ports:           ports,
-			Selector:        objds.EnvoyDaemonSetPodSelector().MatchLabels,
+			Selector:        objds.EnvoyServiceSelector(contour, nil),
 			SessionAffinity: corev1.ServiceAffinityNone,
 		},
 	}
+	if contour.Spec.NetworkPublishing.Envoy.SessionAffinity == corev1.ServiceAffinityClientIP {
+		svc.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		// The API server defaults the timeout, so it's set to avoid updating the Service.
+		timeout := corev1.DefaultClientIPServiceAffinitySeconds
+		if cfg := contour.Spec.NetworkPublishing.Envoy.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil &&
+			cfg.ClientIP.TimeoutSeconds != nil {
+			timeout = *cfg.ClientIP.TimeoutSeconds
+		}
+		svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+		}
+	}
+	svc.Spec.InternalTrafficPolicy = contour.Spec.NetworkPublishing.Envoy.InternalTrafficPolicy
+	if contour.Spec.NetworkPublishing.Envoy.TopologyAwareHints {
+		svc.Annotations[topologyAwareHintsAnnotation] = "auto"
+	}
 
 	// Add AWS LB annotations based on the network publishing strategy and provider type.
 	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
@@ -270,9 +480,37 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		if isELB(&contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters) {
 			svc.Annotations[awsLbBackendProtoAnnotation] = "tcp"
 			svc.Annotations[awsLBProxyProtocolAnnotation] = "*"
+			if tlsTermination != nil && tlsTermination.BackendProtocol == operatorv1alpha1.AWSHTTPBackendProtocol {
+				// The PROXY protocol is not supported by HTTP listeners.
+				svc.Annotations[awsLbBackendProtoAnnotation] = "http"
+				delete(svc.Annotations, awsLBProxyProtocolAnnotation)
+			}
 		} else {
 			// Annotate the service for an NLB.
 			svc.Annotations[awsLBTypeAnnotation] = "nlb"
+			if aws := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS; aws != nil {
+				if len(aws.Subnets) > 0 {
+					svc.Annotations[awsLBSubnetsAnnotation] = strings.Join(aws.Subnets, ",")
+				}
+				switch aws.TargetType {
+				case operatorv1alpha1.AWSNetworkLoadBalancerInstanceTarget:
+					svc.Annotations[awsLBTargetTypeAnnotation] = "instance"
+				case operatorv1alpha1.AWSNetworkLoadBalancerIPTarget:
+					// IP targets are only supported by the AWS Load Balancer Controller,
+					// which manages NLBs for Services of type "external".
+					svc.Annotations[awsLBTypeAnnotation] = "external"
+					svc.Annotations[awsLBTargetTypeAnnotation] = "ip"
+				}
+			}
+		}
+	}
+
+	// Add the TLS termination annotations if specified by AWS provider parameters.
+	if tlsTermination != nil {
+		svc.Annotations[awsLBSSLCertAnnotation] = tlsTermination.CertificateARN
+		svc.Annotations[awsLBSSLPortsAnnotation], _ = EnvoyServicePort(contour, "https")
+		if !isELB(&contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters) {
+			svc.Annotations[awsLbBackendProtoAnnotation] = "tcp"
 		}
 	}
 
@@ -286,6 +524,11 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		svc.Annotations[azureLBResourceGroupAnnotation] = *contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure.ResourceGroup
 	}
 
+	// Add the PublicIPName annotation if specified by Azure provider parameters.
+	if publicIPNameNeeded(&contour.Spec) {
+		svc.Annotations[azurePIPNameAnnotation] = *contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure.PublicIPName
+	}
+
 	// Add the Subnet annotation if specified by provider parameters.
 	if subnetNeeded(&contour.Spec) {
 		if contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.AzureLoadBalancerProvider {
@@ -295,6 +538,35 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		}
 	}
 
+	// Add the GCP global access and NEG annotations if specified by GCP provider parameters.
+	if gcp := gcpParameters(&contour.Spec); gcp != nil {
+		if gcp.GlobalAccess && contour.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope == operatorv1alpha1.InternalLoadBalancer {
+			svc.Annotations[gcpLBGlobalAccessAnnotation] = "true"
+		}
+		if gcp.NetworkEndpointGroups {
+			_, httpPort := EnvoyServicePort(contour, "http")
+			_, httpsPort := EnvoyServicePort(contour, "https")
+			svc.Annotations[gcpNEGAnnotation] = fmt.Sprintf(`{"exposed_ports":{"%d":{},"%d":{}}}`, httpPort, httpsPort)
+		}
+	}
+
+	// Add the MetalLB annotations if specified by MetalLB provider parameters.
+	if metalLB := metalLBParameters(&contour.Spec); metalLB != nil {
+		if metalLB.AddressPool != nil {
+			svc.Annotations[metalLBAddressPoolAnnotation] = *metalLB.AddressPool
+		}
+		if metalLB.SharingKey != nil {
+			svc.Annotations[metalLBAllowSharedIPAnnotation] = *metalLB.SharingKey
+		}
+	}
+
+	// Add the annotations specified by Generic provider parameters.
+	if generic := genericParameters(&contour.Spec); generic != nil {
+		for name, value := range generic.Annotations {
+			svc.Annotations[name] = value
+		}
+	}
+
 	// Add LoadBalancerIP parameter if specified by provider parameters.
 	if loadBalancerAddressNeeded(&contour.Spec) {
 		if contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.AzureLoadBalancerProvider {
@@ -312,6 +584,10 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 	switch epType {
 	case operatorv1alpha1.LoadBalancerServicePublishingType:
 		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		if port := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.HealthCheckNodePort; port != nil {
+			svc.Spec.HealthCheckNodePort = *port
+		}
+		svc.Spec.AllocateLoadBalancerNodePorts = contour.Spec.NetworkPublishing.Envoy.LoadBalancer.AllocateLoadBalancerNodePorts
 		isInternal := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope == operatorv1alpha1.InternalLoadBalancer
 		if isInternal {
 			provider := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type
@@ -322,11 +598,13 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		}
 	case operatorv1alpha1.NodePortServicePublishingType:
 		svc.Spec.Type = corev1.ServiceTypeNodePort
+		svc.Spec.ExternalIPs = contour.Spec.NetworkPublishing.Envoy.ExternalIPs
 		if len(contour.Spec.NetworkPublishing.Envoy.NodePorts) > 0 {
 			for _, p := range contour.Spec.NetworkPublishing.Envoy.NodePorts {
 				if p.PortNumber != nil {
+					name, _ := EnvoyServicePort(contour, p.Name)
 					for i, q := range svc.Spec.Ports {
-						if q.Name == p.Name {
+						if q.Name == name {
 							svc.Spec.Ports[i].NodePort = *p.PortNumber
 						}
 					}
@@ -335,16 +613,88 @@ func DesiredEnvoyService(contour *operatorv1alpha1.Contour) *corev1.Service {
 		}
 	case operatorv1alpha1.ClusterIPServicePublishingType:
 		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		svc.Spec.ExternalIPs = contour.Spec.NetworkPublishing.Envoy.ExternalIPs
+	case operatorv1alpha1.RoutePublishingType:
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
 	}
+	objcontour.ApplyResourceMetadata(svc, contour)
 	return svc
 }
 
+// DesiredEnvoyHTTPService generates the desired Envoy HTTP Service for the
+// given contour, or nil if the contour publishes Envoy's HTTP network endpoint
+// on the Envoy Service.
+func DesiredEnvoyHTTPService(contour *operatorv1alpha1.Contour) *corev1.Service {
+	if contour.Spec.NetworkPublishing.Envoy.HTTPService == nil {
+		return nil
+	}
+	svc := DesiredEnvoyService(httpServiceContour(contour))
+	svc.Name = objcontour.ResourceName(contour, envoyHTTPSvcName)
+	httpName, _ := EnvoyServicePort(contour, "http")
+	svc.Spec.Ports = servicePorts(svc.Spec.Ports, httpName)
+	// TLS is never terminated by the load balancer of the HTTP network endpoint.
+	delete(svc.Annotations, awsLBSSLCertAnnotation)
+	delete(svc.Annotations, awsLBSSLPortsAnnotation)
+	return svc
+}
+
+// httpServiceContour returns a copy of contour publishing Envoy using the
+// parameters of its Envoy HTTP Service.
+func httpServiceContour(contour *operatorv1alpha1.Contour) *operatorv1alpha1.Contour {
+	c := contour.DeepCopy()
+	envoy := &c.Spec.NetworkPublishing.Envoy
+	if envoy.HTTPService != nil {
+		envoy.Type = envoy.HTTPService.Type
+		envoy.LoadBalancer = envoy.HTTPService.LoadBalancer
+		envoy.HTTPService = nil
+	}
+	return c
+}
+
+// EnvoyServicePort returns the name and port number of the port of the Envoy
+// Service publishing the container port named containerPort of contour, either
+// "http" or "https".
+func EnvoyServicePort(contour *operatorv1alpha1.Contour, containerPort string) (string, int32) {
+	for _, p := range contour.Spec.NetworkPublishing.Envoy.ServicePorts {
+		if p.ContainerPort == containerPort {
+			return p.Name, p.Port
+		}
+	}
+	if containerPort == "http" {
+		return containerPort, EnvoyServiceHTTPPort
+	}
+	return containerPort, EnvoyServiceHTTPSPort
+}
+
+// appProtocol returns the application protocol override of a Service port, or
+// def if override is nil. Nil is returned for an empty application protocol.
+func appProtocol(override *string, def string) *string {
+	if override != nil {
+		def = *override
+	}
+	if def == "" {
+		return nil
+	}
+	return &def
+}
+
+// servicePorts returns the ports of ports named name.
+func servicePorts(ports []corev1.ServicePort, name string) []corev1.ServicePort {
+	var filtered []corev1.ServicePort
+	for _, p := range ports {
+		if p.Name == name {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
 // currentContourService returns the current Contour Service for the provided contour.
 func currentContourService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Service, error) {
 	current := &corev1.Service{}
 	key := types.NamespacedName{
 		Namespace: contour.Spec.Namespace.Name,
-		Name:      contourSvcName,
+		Name:      objcontour.ResourceName(contour, contourSvcName),
 	}
 	err := cli.Get(ctx, key, current)
 	if err != nil {
@@ -353,12 +703,12 @@ func currentContourService(ctx context.Context, cli client.Client, contour *oper
 	return current, nil
 }
 
-// currentEnvoyService returns the current Envoy Service for the provided contour.
-func currentEnvoyService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Service, error) {
+// CurrentEnvoyService returns the current Envoy Service for the provided contour.
+func CurrentEnvoyService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Service, error) {
 	current := &corev1.Service{}
 	key := types.NamespacedName{
 		Namespace: contour.Spec.Namespace.Name,
-		Name:      envoySvcName,
+		Name:      objcontour.ResourceName(contour, envoySvcName),
 	}
 	err := cli.Get(ctx, key, current)
 	if err != nil {
@@ -367,6 +717,19 @@ func currentEnvoyService(ctx context.Context, cli client.Client, contour *operat
 	return current, nil
 }
 
+// currentEnvoyHTTPService returns the current Envoy HTTP Service for the provided contour.
+func currentEnvoyHTTPService(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*corev1.Service, error) {
+	current := &corev1.Service{}
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      objcontour.ResourceName(contour, envoyHTTPSvcName),
+	}
+	if err := cli.Get(ctx, key, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
 // createService creates a Service resource for the provided svc.
 func createService(ctx context.Context, cli client.Client, svc *corev1.Service) error {
 	if err := cli.Create(ctx, svc); err != nil {
@@ -377,7 +740,7 @@ func createService(ctx context.Context, cli client.Client, svc *corev1.Service)
 
 // updateContourServiceIfNeeded updates a Contour Service if current does not match desired.
 func updateContourServiceIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *corev1.Service) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		_, updated := equality.ClusterIPServiceChanged(current, desired)
 		if updated {
 			if err := cli.Update(ctx, desired); err != nil {
@@ -392,7 +755,7 @@ func updateContourServiceIfNeeded(ctx context.Context, cli client.Client, contou
 // updateEnvoyServiceIfNeeded updates an Envoy Service if current does not match desired,
 // using contour to verify the existence of owner labels.
 func updateEnvoyServiceIfNeeded(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, current, desired *corev1.Service) error {
-	if labels.Exist(current, objcontour.OwnerLabels(contour)) {
+	if objcontour.IsOwned(current, contour) {
 		// Using the Service returned by the equality pkg instead of the desired
 		// parameter since clusterIP is immutable.
 		var updated *corev1.Service
@@ -400,7 +763,7 @@ func updateEnvoyServiceIfNeeded(ctx context.Context, cli client.Client, contour
 		switch contour.Spec.NetworkPublishing.Envoy.Type {
 		case operatorv1alpha1.NodePortServicePublishingType:
 			updated, needed = equality.NodePortServiceChanged(current, desired)
-		case operatorv1alpha1.ClusterIPServicePublishingType:
+		case operatorv1alpha1.ClusterIPServicePublishingType, operatorv1alpha1.RoutePublishingType:
 			updated, needed = equality.ClusterIPServiceChanged(current, desired)
 		// Add additional network publishing types as they are introduced.
 		default:
@@ -423,6 +786,47 @@ func isELB(params *operatorv1alpha1.ProviderLoadBalancerParameters) bool {
 		(params.AWS == nil || params.AWS.Type == operatorv1alpha1.AWSClassicLoadBalancer)
 }
 
+// awsTLSTermination returns the AWS TLS termination parameters of the provided
+// spec, or nil if TLS is not terminated by an AWS load balancer.
+func awsTLSTermination(spec *operatorv1alpha1.ContourSpec) *operatorv1alpha1.AWSTLSTermination {
+	if spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.AWSLoadBalancerProvider &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS != nil {
+		return spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS.TLSTermination
+	}
+	return nil
+}
+
+// gcpParameters returns the GCP provider parameters of the provided spec, or nil
+// if Envoy is not published using a GCP load balancer.
+func gcpParameters(spec *operatorv1alpha1.ContourSpec) *operatorv1alpha1.GCPLoadBalancerParameters {
+	if spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.GCPLoadBalancerProvider {
+		return spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.GCP
+	}
+	return nil
+}
+
+// metalLBParameters returns the MetalLB provider parameters of the provided spec,
+// or nil if Envoy is not published using a MetalLB load balancer.
+func metalLBParameters(spec *operatorv1alpha1.ContourSpec) *operatorv1alpha1.MetalLBLoadBalancerParameters {
+	if spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.MetalLBLoadBalancerProvider {
+		return spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.MetalLB
+	}
+	return nil
+}
+
+// genericParameters returns the Generic provider parameters of the provided spec,
+// or nil if Envoy is not published using a Generic load balancer.
+func genericParameters(spec *operatorv1alpha1.ContourSpec) *operatorv1alpha1.GenericLoadBalancerParameters {
+	if spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.GenericLoadBalancerProvider {
+		return spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Generic
+	}
+	return nil
+}
+
 // allocationIDsNeeded returns true if "service.beta.kubernetes.io/aws-load-balancer-eip-allocations"
 // annotation is needed based on the provided spec.
 func allocationIDsNeeded(spec *operatorv1alpha1.ContourSpec) bool {
@@ -444,6 +848,16 @@ func resourceGroupNeeded(spec *operatorv1alpha1.ContourSpec) bool {
 		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure.ResourceGroup != nil
 }
 
+// publicIPNameNeeded returns true if "service.beta.kubernetes.io/azure-pip-name"
+// annotation is needed based on the provided spec.
+func publicIPNameNeeded(spec *operatorv1alpha1.ContourSpec) bool {
+	return spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type == operatorv1alpha1.AzureLoadBalancerProvider &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure != nil &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.Scope == "External" &&
+		spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure.PublicIPName != nil
+}
+
 // subnetNeeded returns true if "service.beta.kubernetes.io/azure-load-balancer-internal-subnet" or
 // "networking.gke.io/internal-load-balancer-subnet" annotation is needed based
 // on the provided spec.
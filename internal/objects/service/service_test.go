@@ -14,8 +14,10 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"testing"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
@@ -23,9 +25,45 @@ import (
 	objcfg "github.com/projectcontour/contour-operator/internal/objects/sharedconfig"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+// allocatingClient is a client that rejects creating Services with a node port
+// of another Service, as the API server does.
+type allocatingClient struct {
+	client.Client
+}
+
+func (c *allocatingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if svc, ok := obj.(*corev1.Service); ok {
+		services := &corev1.ServiceList{}
+		if err := c.List(ctx, services); err != nil {
+			return err
+		}
+		for _, other := range services.Items {
+			for _, p := range other.Spec.Ports {
+				for i, q := range svc.Spec.Ports {
+					if q.NodePort != 0 && q.NodePort == p.NodePort {
+						path := field.NewPath("spec", "ports").Index(i).Child("nodePort")
+						return errors.NewInvalid(schema.GroupKind{Kind: "Service"}, svc.Name, field.ErrorList{
+							field.Invalid(path, q.NodePort, "provided port is already allocated"),
+						})
+					}
+				}
+			}
+		}
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
 func checkServiceHasPort(t *testing.T, svc *corev1.Service, port int32) {
 	t.Helper()
 
@@ -82,6 +120,16 @@ func checkServiceHasPortProtocol(t *testing.T, svc *corev1.Service, protocol cor
 	t.Errorf("service is missing port protocol %q", protocol)
 }
 
+func checkServiceHasAnnotationValue(t *testing.T, svc *corev1.Service, key, expected string) {
+	t.Helper()
+
+	if val, ok := svc.Annotations[key]; ok && val == expected {
+		return
+	}
+
+	t.Errorf("service has unexpected %q annotation value %q", key, svc.Annotations[key])
+}
+
 func checkServiceHasAnnotations(t *testing.T, svc *corev1.Service, expectedKeys ...string) {
 	t.Helper()
 
@@ -133,6 +181,14 @@ func checkServiceHasLoadBalancerAddress(t *testing.T, svc *corev1.Service, addre
 	}
 }
 
+func checkServiceHasExternalIPs(t *testing.T, svc *corev1.Service, ips []string) {
+	t.Helper()
+
+	if !apiequality.Semantic.DeepEqual(svc.Spec.ExternalIPs, ips) {
+		t.Errorf("service has external ips %v, expected %v", svc.Spec.ExternalIPs, ips)
+	}
+}
+
 func TestDesiredContourService(t *testing.T) {
 	name := "svc-test"
 	cfg := objcontour.Config{
@@ -151,6 +207,32 @@ func TestDesiredContourService(t *testing.T) {
 	checkServiceHasPortProtocol(t, svc, corev1.ProtocolTCP)
 }
 
+func TestDesiredServiceResourceMetadata(t *testing.T) {
+	cfg := objcontour.Config{
+		Name:        "svc-test",
+		Namespace:   "svc-test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	}
+	cntr := objcontour.New(cfg)
+	cntr.Spec.ResourceLabels = map[string]string{
+		"cost-center":                           "edge",
+		operatorv1alpha1.OwningContourNameLabel: "foo",
+	}
+	cntr.Spec.ResourceAnnotations = map[string]string{"backup.example.com/exclude": "true"}
+	for _, svc := range []*corev1.Service{DesiredContourService(cntr), DesiredEnvoyService(cntr)} {
+		if svc.Labels["cost-center"] != "edge" {
+			t.Errorf("service %s is missing resource label, got labels %v", svc.Name, svc.Labels)
+		}
+		if svc.Labels[operatorv1alpha1.OwningContourNameLabel] != cntr.Name {
+			t.Errorf("service %s owner label was overridden, got labels %v", svc.Name, svc.Labels)
+		}
+		if svc.Annotations["backup.example.com/exclude"] != "true" {
+			t.Errorf("service %s is missing resource annotation, got annotations %v", svc.Name, svc.Annotations)
+		}
+	}
+}
+
 func TestDesiredEnvoyService(t *testing.T) {
 	name := "svc-test"
 	loadBalancerAddress := "1.2.3.4"
@@ -180,6 +262,12 @@ func TestDesiredEnvoyService(t *testing.T) {
 	checkServiceHasPortName(t, svc, "https")
 	checkServiceHasPortProtocol(t, svc, corev1.ProtocolTCP)
 
+	// Check external IPs are set for a NodePort service.
+	externalIPs := []string{"192.0.2.10", "2001:db8::10"}
+	cntr.Spec.NetworkPublishing.Envoy.ExternalIPs = externalIPs
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasExternalIPs(t, svc, externalIPs)
+
 	// Check LB annotations for the different provider types, starting with AWS ELB (the default
 	// if AWS provider params are not passed).
 	cntr.Spec.NetworkPublishing.Envoy.Type = operatorv1alpha1.LoadBalancerServicePublishingType
@@ -187,6 +275,16 @@ func TestDesiredEnvoyService(t *testing.T) {
 	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type = operatorv1alpha1.AWSLoadBalancerProvider
 	svc = DesiredEnvoyService(cntr)
 	checkServiceHasType(t, svc, corev1.ServiceTypeLoadBalancer)
+	checkServiceHasExternalIPs(t, svc, nil)
+
+	// Check the health check node port is set if specified.
+	healthCheckNodePort := int32(30900)
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.HealthCheckNodePort = &healthCheckNodePort
+	svc = DesiredEnvoyService(cntr)
+	if svc.Spec.HealthCheckNodePort != healthCheckNodePort {
+		t.Errorf("service has health check node port %d, expected %d", svc.Spec.HealthCheckNodePort, healthCheckNodePort)
+	}
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.HealthCheckNodePort = nil
 	checkServiceHasExternalTrafficPolicy(t, svc, corev1.ServiceExternalTrafficPolicyTypeLocal)
 	checkServiceHasAnnotations(t, svc, awsLbBackendProtoAnnotation, awsLBProxyProtocolAnnotation)
 
@@ -209,6 +307,51 @@ func TestDesiredEnvoyService(t *testing.T) {
 	// NLBs should not have PROXY protocol or backend protocol annotations.
 	checkServiceHasAnnotations(t, svc, awsLBTypeAnnotation, awsLBAllocationIDsAnnotation)
 
+	// Check AWS NLB subnets and target type.
+	subnets := []string{"subnet-0123456789", "subnet-1234567890"}
+	nlbParams.AWS.Subnets = subnets
+	nlbParams.AWS.TargetType = operatorv1alpha1.AWSNetworkLoadBalancerInstanceTarget
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = nlbParams
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotations(t, svc, awsLBTypeAnnotation, awsLBAllocationIDsAnnotation, awsLBSubnetsAnnotation,
+		awsLBTargetTypeAnnotation)
+	checkServiceHasAnnotationValue(t, svc, awsLBSubnetsAnnotation, strings.Join(subnets, ","))
+	checkServiceHasAnnotationValue(t, svc, awsLBTargetTypeAnnotation, "instance")
+	checkServiceHasAnnotationValue(t, svc, awsLBTypeAnnotation, "nlb")
+	nlbParams.AWS.TargetType = operatorv1alpha1.AWSNetworkLoadBalancerIPTarget
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotationValue(t, svc, awsLBTargetTypeAnnotation, "ip")
+	checkServiceHasAnnotationValue(t, svc, awsLBTypeAnnotation, "external")
+	nlbParams.AWS.Subnets = nil
+	nlbParams.AWS.TargetType = ""
+
+	// Check TLS termination at an AWS NLB.
+	certARN := "arn:aws:acm:us-east-1:123456789012:certificate/test"
+	nlbParams.AWS.TLSTermination = &operatorv1alpha1.AWSTLSTermination{CertificateARN: certARN}
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotations(t, svc, awsLBTypeAnnotation, awsLBAllocationIDsAnnotation, awsLBSSLCertAnnotation,
+		awsLBSSLPortsAnnotation, awsLbBackendProtoAnnotation)
+	checkServiceHasAnnotationValue(t, svc, awsLBSSLCertAnnotation, certARN)
+	checkServiceHasAnnotationValue(t, svc, awsLBSSLPortsAnnotation, "https")
+	checkServiceHasAnnotationValue(t, svc, awsLbBackendProtoAnnotation, "tcp")
+	for _, p := range svc.Spec.Ports {
+		if p.TargetPort.IntVal != objcfg.EnvoyInsecureContainerPort {
+			t.Errorf("service port %q has unexpected target port %d", p.Name, p.TargetPort.IntVal)
+		}
+	}
+	nlbParams.AWS.TLSTermination = nil
+
+	// Check TLS termination at an AWS Classic ELB using an HTTP backend.
+	elbParams.AWS.TLSTermination = &operatorv1alpha1.AWSTLSTermination{
+		CertificateARN:  certARN,
+		BackendProtocol: operatorv1alpha1.AWSHTTPBackendProtocol,
+	}
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = elbParams
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotations(t, svc, awsLbBackendProtoAnnotation, awsLBSSLCertAnnotation, awsLBSSLPortsAnnotation)
+	checkServiceHasAnnotationValue(t, svc, awsLbBackendProtoAnnotation, "http")
+	elbParams.AWS.TLSTermination = nil
+
 	// Check Azure external load balancer type.
 	azureParams := operatorv1alpha1.ProviderLoadBalancerParameters{
 		Type:  operatorv1alpha1.AzureLoadBalancerProvider,
@@ -219,6 +362,13 @@ func TestDesiredEnvoyService(t *testing.T) {
 	checkServiceHasLoadBalancerAddress(t, svc, loadBalancerAddress)
 	checkServiceHasAnnotations(t, svc, azureLBResourceGroupAnnotation)
 
+	// Check Azure external load balancer with a public IP name.
+	pipName := "envoy-pip"
+	azureParams.Azure.PublicIPName = &pipName
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotations(t, svc, azureLBResourceGroupAnnotation, azurePIPNameAnnotation)
+	checkServiceHasAnnotationValue(t, svc, azurePIPNameAnnotation, pipName)
+
 	// Check GCP external load balancer type.
 	gcpParams := operatorv1alpha1.ProviderLoadBalancerParameters{
 		Type: operatorv1alpha1.GCPLoadBalancerProvider,
@@ -228,6 +378,29 @@ func TestDesiredEnvoyService(t *testing.T) {
 	svc = DesiredEnvoyService(cntr)
 	checkServiceHasLoadBalancerAddress(t, svc, loadBalancerAddress)
 
+	// Check a MetalLB load balancer.
+	addressPool := "envoy-pool"
+	sharingKey := "envoy"
+	metalLBParams := operatorv1alpha1.ProviderLoadBalancerParameters{
+		Type:    operatorv1alpha1.MetalLBLoadBalancerProvider,
+		MetalLB: &operatorv1alpha1.MetalLBLoadBalancerParameters{AddressPool: &addressPool, SharingKey: &sharingKey},
+	}
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = metalLBParams
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotationValue(t, svc, metalLBAddressPoolAnnotation, addressPool)
+	checkServiceHasAnnotationValue(t, svc, metalLBAllowSharedIPAnnotation, sharingKey)
+
+	// Check a Generic provider load balancer.
+	genericParams := operatorv1alpha1.ProviderLoadBalancerParameters{
+		Type: operatorv1alpha1.GenericLoadBalancerProvider,
+		Generic: &operatorv1alpha1.GenericLoadBalancerParameters{
+			Annotations: map[string]string{"service.beta.kubernetes.io/do-loadbalancer-protocol": "tcp"},
+		},
+	}
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = genericParams
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotationValue(t, svc, "service.beta.kubernetes.io/do-loadbalancer-protocol", "tcp")
+
 	// Test an internal ELB
 	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope = operatorv1alpha1.InternalLoadBalancer
 	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = elbParams
@@ -254,9 +427,354 @@ func TestDesiredEnvoyService(t *testing.T) {
 	checkServiceHasLoadBalancerAddress(t, svc, loadBalancerAddress)
 	checkServiceHasAnnotations(t, svc, gcpLBTypeAnnotation, gcpLBTypeAnnotationLegacy, gcpLBSubnetAnnotation)
 
+	// Test GCP global access and standalone NEGs for an internal GCP LB.
+	gcpParamsInternal.GCP.GlobalAccess = true
+	gcpParamsInternal.GCP.NetworkEndpointGroups = true
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasAnnotations(t, svc, gcpLBTypeAnnotation, gcpLBTypeAnnotationLegacy, gcpLBSubnetAnnotation,
+		gcpLBGlobalAccessAnnotation, gcpNEGAnnotation)
+	checkServiceHasAnnotationValue(t, svc, gcpLBGlobalAccessAnnotation, "true")
+	checkServiceHasAnnotationValue(t, svc, gcpNEGAnnotation, `{"exposed_ports":{"80":{},"443":{}}}`)
+
 	// Set network publishing type to ClusterIPService and verify the service type is as expected.
 	cntr.Spec.NetworkPublishing.Envoy.Type = operatorv1alpha1.ClusterIPServicePublishingType
 	svc = DesiredEnvoyService(cntr)
 	checkServiceHasType(t, svc, corev1.ServiceTypeClusterIP)
 	checkServiceHasAnnotations(t, svc) // passing no keys means we expect no annotations
+	checkServiceHasExternalIPs(t, svc, externalIPs)
+
+	// Set network publishing type to Route and verify a ClusterIP service is used.
+	cntr.Spec.NetworkPublishing.Envoy.Type = operatorv1alpha1.RoutePublishingType
+	svc = DesiredEnvoyService(cntr)
+	checkServiceHasType(t, svc, corev1.ServiceTypeClusterIP)
+	checkServiceHasAnnotations(t, svc)
+}
+
+func TestEnsureEnvoyServiceNodePortConflict(t *testing.T) {
+	ctx := context.Background()
+	other := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: "http", Port: 80, NodePort: EnvoyNodePortHTTPPort}},
+		},
+	}
+	cli := &allocatingClient{Client: fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(other).Build()}
+	httpPort, httpsPort := EnvoyNodePortHTTPPort, EnvoyNodePortHTTPSPort
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.NodePortServicePublishingType,
+		NodePorts: []operatorv1alpha1.NodePort{
+			{Name: "http", PortNumber: &httpPort},
+			{Name: "https", PortNumber: &httpsPort},
+		},
+	})
+
+	err := EnsureEnvoyService(ctx, cli, cntr)
+	conflict, ok := err.(*NodePortConflictError)
+	if !ok {
+		t.Fatalf("expected a node port conflict, got %v", err)
+	}
+	if conflict.Port != EnvoyNodePortHTTPPort || conflict.Service != "default/app" {
+		t.Errorf("expected node port %d allocated to service default/app, got %v", EnvoyNodePortHTTPPort, conflict)
+	}
+
+	// The envoy service is created once the node port is released.
+	if err := cli.Delete(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureEnvoyService(ctx, cli, cntr); err != nil {
+		t.Fatalf("expected the envoy service to be created, got %v", err)
+	}
+}
+
+func TestEnsureEnvoyHTTPService(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type = operatorv1alpha1.AWSLoadBalancerProvider
+	cntr.Spec.NetworkPublishing.Envoy.HTTPService = &operatorv1alpha1.HTTPServiceParameters{
+		Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+		LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+			Scope: operatorv1alpha1.InternalLoadBalancer,
+			ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.AWSLoadBalancerProvider,
+			},
+		},
+	}
+
+	if err := EnsureEnvoyService(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureEnvoyHTTPService(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	svc, err := CurrentEnvoyService(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.Spec.Ports) != 1 || svc.Spec.Ports[0].Name != "https" {
+		t.Errorf("expected the envoy service to only publish https, got %v", svc.Spec.Ports)
+	}
+	if _, ok := svc.Annotations[awsInternalLBAnnotation]; ok {
+		t.Errorf("expected an external load balancer for the envoy service")
+	}
+	httpSvc, err := currentEnvoyHTTPService(ctx, cli, cntr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(httpSvc.Spec.Ports) != 1 || httpSvc.Spec.Ports[0].Name != "http" || httpSvc.Spec.Ports[0].Port != EnvoyServiceHTTPPort {
+		t.Errorf("expected the envoy http service to only publish http, got %v", httpSvc.Spec.Ports)
+	}
+	if httpSvc.Spec.Type != corev1.ServiceTypeLoadBalancer || httpSvc.Annotations[awsInternalLBAnnotation] == "" {
+		t.Errorf("expected an internal load balancer for the envoy http service, got %v", httpSvc.Annotations)
+	}
+
+	// The envoy http service is deleted once http is published by the envoy service.
+	cntr.Spec.NetworkPublishing.Envoy.HTTPService = nil
+	if err := EnsureEnvoyHTTPService(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := currentEnvoyHTTPService(ctx, cli, cntr); !errors.IsNotFound(err) {
+		t.Errorf("expected the envoy http service to be deleted, got %v", err)
+	}
+}
+
+func TestEnsureAdditionalEnvoyServices(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.NetworkPublishing.Envoy.AdditionalServices = []operatorv1alpha1.AdditionalEnvoyService{
+		{
+			Name: "internal",
+			Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+			LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+				Scope: operatorv1alpha1.InternalLoadBalancer,
+				ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+					Type: operatorv1alpha1.AWSLoadBalancerProvider,
+				},
+			},
+			Annotations: map[string]string{"example.com/team": "edge"},
+		},
+		{
+			Name:  "mesh",
+			Type:  operatorv1alpha1.ClusterIPServicePublishingType,
+			Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "https", Port: 8443}},
+		},
+	}
+
+	if err := EnsureAdditionalEnvoyServices(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	internal := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: "projectcontour", Name: "envoy-internal"}, internal); err != nil {
+		t.Fatal(err)
+	}
+	if internal.Spec.Type != corev1.ServiceTypeLoadBalancer || len(internal.Spec.Ports) != 2 {
+		t.Errorf("expected a load balancer service publishing http and https, got %v", internal.Spec)
+	}
+	checkServiceHasAnnotations(t, internal, awsInternalLBAnnotation, awsLbBackendProtoAnnotation, awsLBProxyProtocolAnnotation,
+		"example.com/team")
+	mesh := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: "projectcontour", Name: "envoy-mesh"}, mesh); err != nil {
+		t.Fatal(err)
+	}
+	if mesh.Spec.Type != corev1.ServiceTypeClusterIP || len(mesh.Spec.Ports) != 1 || mesh.Spec.Ports[0].Port != 8443 {
+		t.Errorf("expected a cluster IP service publishing https on port 8443, got %v", mesh.Spec)
+	}
+
+	// A service removed from the contour is deleted.
+	cntr.Spec.NetworkPublishing.Envoy.AdditionalServices = cntr.Spec.NetworkPublishing.Envoy.AdditionalServices[:1]
+	if err := EnsureAdditionalEnvoyServices(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(mesh), mesh); !errors.IsNotFound(err) {
+		t.Errorf("expected service envoy-mesh to be deleted, got %v", err)
+	}
+
+	if err := EnsureAdditionalEnvoyServicesDeleted(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(internal), internal); !errors.IsNotFound(err) {
+		t.Errorf("expected service envoy-internal to be deleted, got %v", err)
+	}
+}
+
+func TestDesiredEnvoyServiceExtraPorts(t *testing.T) {
+	nodePort := int32(30432)
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.NodePortServicePublishingType,
+	})
+	cntr.Spec.NetworkPublishing.Envoy.ExtraPorts = []operatorv1alpha1.EnvoyExtraPort{
+		{Name: "postgres", ContainerPort: 15432, ServicePort: 5432, NodePort: &nodePort},
+	}
+	svc := DesiredEnvoyService(cntr)
+	expected := corev1.ServicePort{
+		Name:       "postgres",
+		Protocol:   corev1.ProtocolTCP,
+		Port:       5432,
+		TargetPort: intstr.IntOrString{IntVal: 15432},
+		NodePort:   nodePort,
+	}
+	if len(svc.Spec.Ports) != 3 || !apiequality.Semantic.DeepEqual(svc.Spec.Ports[2], expected) {
+		t.Errorf("expected extra port %v, got %v", expected, svc.Spec.Ports)
+	}
+
+	// An additional service publishes the extra port without the node port.
+	additional := DesiredAdditionalEnvoyService(cntr, &operatorv1alpha1.AdditionalEnvoyService{
+		Name:  "db",
+		Type:  operatorv1alpha1.NodePortServicePublishingType,
+		Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "postgres", Port: 5432}},
+	})
+	expected.NodePort = 0
+	if len(additional.Spec.Ports) != 1 || !apiequality.Semantic.DeepEqual(additional.Spec.Ports[0], expected) {
+		t.Errorf("expected extra port %v, got %v", expected, additional.Spec.Ports)
+	}
+}
+
+func TestDesiredEnvoyServiceHTTP3(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.NodePortServicePublishingType,
+	})
+	cntr.Spec.NetworkPublishing.Envoy.HTTP3 = true
+	svc := DesiredEnvoyService(cntr)
+	expected := corev1.ServicePort{
+		Name:       "http3",
+		Protocol:   corev1.ProtocolUDP,
+		Port:       EnvoyServiceHTTPSPort,
+		TargetPort: intstr.IntOrString{IntVal: objcfg.EnvoySecureContainerPort},
+	}
+	if len(svc.Spec.Ports) != 3 || !apiequality.Semantic.DeepEqual(svc.Spec.Ports[2], expected) {
+		t.Errorf("expected http3 port %v, got %v", expected, svc.Spec.Ports)
+	}
+}
+
+func TestDesiredEnvoyServiceSessionAffinity(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinity = corev1.ServiceAffinityClientIP
+	svc := DesiredEnvoyService(cntr)
+	if svc.Spec.SessionAffinity != corev1.ServiceAffinityClientIP ||
+		*svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds != corev1.DefaultClientIPServiceAffinitySeconds {
+		t.Errorf("expected ClientIP session affinity with the default timeout, got %v", svc.Spec)
+	}
+
+	timeout := int32(600)
+	cntr.Spec.NetworkPublishing.Envoy.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+		ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+	}
+	svc = DesiredEnvoyService(cntr)
+	if *svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds != timeout {
+		t.Errorf("expected session affinity timeout %d, got %v", timeout, svc.Spec.SessionAffinityConfig)
+	}
+}
+
+func TestDesiredEnvoyServiceTrafficPolicy(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.ClusterIPServicePublishingType,
+	})
+	policy := corev1.ServiceInternalTrafficPolicyLocal
+	cntr.Spec.NetworkPublishing.Envoy.InternalTrafficPolicy = &policy
+	cntr.Spec.NetworkPublishing.Envoy.TopologyAwareHints = true
+	svc := DesiredEnvoyService(cntr)
+	if svc.Spec.InternalTrafficPolicy == nil || *svc.Spec.InternalTrafficPolicy != policy {
+		t.Errorf("expected internal traffic policy %s, got %v", policy, svc.Spec.InternalTrafficPolicy)
+	}
+	checkServiceHasAnnotations(t, svc, topologyAwareHintsAnnotation)
+}
+
+func TestServiceAppProtocols(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	protocols := func(svc *corev1.Service) map[string]string {
+		found := map[string]string{}
+		for _, p := range svc.Spec.Ports {
+			if p.AppProtocol != nil {
+				found[p.Name] = *p.AppProtocol
+			}
+		}
+		return found
+	}
+	expected := map[string]string{"http": "http", "https": "https"}
+	if actual := protocols(DesiredEnvoyService(cntr)); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected envoy service app protocols %v, got %v", expected, actual)
+	}
+	expected = map[string]string{"xds": "https"}
+	if actual := protocols(DesiredContourService(cntr)); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected contour service app protocols %v, got %v", expected, actual)
+	}
+
+	h2c, none := "kubernetes.io/h2c", ""
+	cntr.Spec.NetworkPublishing.AppProtocols.HTTP = &h2c
+	cntr.Spec.NetworkPublishing.AppProtocols.HTTPS = &none
+	expected = map[string]string{"http": h2c}
+	if actual := protocols(DesiredEnvoyService(cntr)); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("expected envoy service app protocols %v, got %v", expected, actual)
+	}
+}
+
+func TestDesiredEnvoyServicePorts(t *testing.T) {
+	httpNodePort := int32(30080)
+	cntr := objcontour.New(objcontour.Config{
+		Name:        "test",
+		Namespace:   "test-ns",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.NodePortServicePublishingType,
+		NodePorts:   []operatorv1alpha1.NodePort{{Name: "http", PortNumber: &httpNodePort}, {Name: "https"}},
+	})
+	cntr.Spec.NetworkPublishing.Envoy.ServicePorts = []operatorv1alpha1.EnvoyServicePortMapping{
+		{ContainerPort: "http", Name: "web", Port: 8080},
+		{ContainerPort: "https", Name: "web-tls", Port: 8443},
+	}
+	svc := DesiredEnvoyService(cntr)
+	expected := map[string]int32{"web": 8080, "web-tls": 8443}
+	for _, p := range svc.Spec.Ports {
+		if expected[p.Name] != p.Port {
+			t.Errorf("expected service port %s on port %d, got %d", p.Name, expected[p.Name], p.Port)
+		}
+		if p.Name == "web" && p.NodePort != httpNodePort {
+			t.Errorf("expected node port %d of service port web, got %d", httpNodePort, p.NodePort)
+		}
+	}
+	if len(svc.Spec.Ports) != len(expected) {
+		t.Errorf("expected service ports %v, got %v", expected, svc.Spec.Ports)
+	}
+
+	// The envoy http service publishes the renamed http port.
+	cntr.Spec.NetworkPublishing.Envoy.HTTPService = &operatorv1alpha1.HTTPServiceParameters{
+		Type: operatorv1alpha1.ClusterIPServicePublishingType,
+	}
+	httpSvc := DesiredEnvoyHTTPService(cntr)
+	if len(httpSvc.Spec.Ports) != 1 || httpSvc.Spec.Ports[0].Name != "web" || httpSvc.Spec.Ports[0].Port != 8080 {
+		t.Errorf("expected the envoy http service to publish port web on 8080, got %v", httpSvc.Spec.Ports)
+	}
 }
@@ -0,0 +1,186 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vpa
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// contourName is the name of Contour's Deployment and VerticalPodAutoscaler.
+	contourName = "contour"
+	// contourContainerName is the name of the Contour container.
+	contourContainerName = "contour"
+	// envoyName is the name of Envoy's DaemonSet and VerticalPodAutoscaler.
+	envoyName = "envoy"
+	// envoyContainerName is the name of the Envoy container.
+	envoyContainerName = "envoy"
+)
+
+// GroupVersionKind is the GroupVersionKind of a VerticalPodAutoscaler.
+var GroupVersionKind = schema.GroupVersionKind{
+	Group:   "autoscaling.k8s.io",
+	Version: "v1",
+	Kind:    "VerticalPodAutoscaler",
+}
+
+// APIAvailable returns true if the autoscaling.k8s.io API group is served by
+// the cluster that cli is connected to.
+func APIAvailable(cli client.Client) (bool, error) {
+	return objutil.APIAvailable(cli, GroupVersionKind)
+}
+
+// EnsureVPAs ensures the VerticalPodAutoscalers of the Contour Deployment and
+// the Envoy DaemonSet of contour exist, or are deleted if contour has no
+// VerticalPodAutoscaler parameters.
+func EnsureVPAs(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	if contour.Spec.VerticalPodAutoscaler == nil {
+		return EnsureVPAsDeleted(ctx, cli, contour)
+	}
+	available, err := APIAvailable(cli)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("vertical pod autoscalers require the %s API group to be served by the cluster",
+			GroupVersionKind.Group)
+	}
+	for _, desired := range DesiredVPAs(contour) {
+		if err := ensureVPA(ctx, cli, contour, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnsureVPAsDeleted ensures the VerticalPodAutoscalers of contour are deleted
+// if Contour owner labels exist.
+func EnsureVPAsDeleted(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	for _, name := range []string{contourName, envoyName} {
+		vpa, err := currentVPA(ctx, cli, contour, name)
+		if err != nil {
+			if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		if objcontour.IsOwned(vpa, contour) {
+			if err := cli.Delete(ctx, vpa); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete verticalpodautoscaler %s/%s: %w", vpa.GetNamespace(), vpa.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// DesiredVPAs returns the desired VerticalPodAutoscalers of the contour
+// container of the Contour Deployment and of the envoy container of the Envoy
+// DaemonSet of contour. The resources of the other containers, i.e. the Envoy
+// shutdown manager, are not autoscaled.
+func DesiredVPAs(contour *operatorv1alpha1.Contour) []*unstructured.Unstructured {
+	mode := operatorv1alpha1.VerticalPodAutoscalerUpdateModeOff
+	if m := contour.Spec.VerticalPodAutoscaler.UpdateMode; m != "" {
+		mode = m
+	}
+	return []*unstructured.Unstructured{
+		desiredVPA(contour, contourName, "Deployment", contourContainerName, mode),
+		desiredVPA(contour, envoyName, "DaemonSet", envoyContainerName, mode),
+	}
+}
+
+// desiredVPA returns the desired VerticalPodAutoscaler named name of contour
+// for the container of the workload named name of kind.
+func desiredVPA(contour *operatorv1alpha1.Contour, name, kind, container string,
+	mode operatorv1alpha1.VerticalPodAutoscalerUpdateMode) *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       kind,
+					"name":       objcontour.ResourceName(contour, name),
+				},
+				"updatePolicy": map[string]interface{}{
+					"updateMode": string(mode),
+				},
+				"resourcePolicy": map[string]interface{}{
+					"containerPolicies": []interface{}{
+						map[string]interface{}{"containerName": container},
+						map[string]interface{}{"containerName": "*", "mode": "Off"},
+					},
+				},
+			},
+		},
+	}
+	vpa.SetGroupVersionKind(GroupVersionKind)
+	vpa.SetNamespace(contour.Spec.Namespace.Name)
+	vpa.SetName(objcontour.ResourceName(contour, name))
+	vpa.SetLabels(objcontour.OwnerLabels(contour))
+	objcontour.ApplyResourceMetadata(vpa, contour)
+	return vpa
+}
+
+// ensureVPA creates desired, or updates it if it does not match the current
+// VerticalPodAutoscaler owned by contour.
+func ensureVPA(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, desired *unstructured.Unstructured) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(GroupVersionKind)
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if errors.IsNotFound(err) {
+			if err := cli.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create verticalpodautoscaler %s/%s: %w", desired.GetNamespace(),
+					desired.GetName(), err)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to get verticalpodautoscaler %s/%s: %w", desired.GetNamespace(), desired.GetName(), err)
+	}
+	if !objcontour.IsOwned(current, contour) {
+		return nil
+	}
+	if updated, changed := equality.SpecChanged(current, desired); changed {
+		if err := cli.Update(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update verticalpodautoscaler %s/%s: %w", desired.GetNamespace(),
+				desired.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// currentVPA returns the current VerticalPodAutoscaler named name of contour.
+func currentVPA(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, name string) (*unstructured.Unstructured, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(GroupVersionKind)
+	key := types.NamespacedName{
+		Namespace: contour.Spec.Namespace.Name,
+		Name:      objcontour.ResourceName(contour, name),
+	}
+	if err := cli.Get(ctx, key, current); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
@@ -0,0 +1,146 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vpa
+
+import (
+	"context"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// expectedVPASpec returns the spec of the VerticalPodAutoscaler of container
+// of the workload named name of kind, updated with mode.
+func expectedVPASpec(kind, name, container, mode string) map[string]interface{} {
+	return map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       kind,
+			"name":       name,
+		},
+		"updatePolicy": map[string]interface{}{
+			"updateMode": mode,
+		},
+		"resourcePolicy": map[string]interface{}{
+			"containerPolicies": []interface{}{
+				map[string]interface{}{"containerName": container},
+				map[string]interface{}{"containerName": "*", "mode": "Off"},
+			},
+		},
+	}
+}
+
+func TestDesiredVPAs(t *testing.T) {
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.Namespace.Shared = true
+	cntr.Spec.VerticalPodAutoscaler = &operatorv1alpha1.VerticalPodAutoscalerParameters{
+		UpdateMode: operatorv1alpha1.VerticalPodAutoscalerUpdateModeInitial,
+	}
+	vpas := DesiredVPAs(cntr)
+	expected := []struct {
+		name string
+		spec map[string]interface{}
+	}{
+		{"test-contour", expectedVPASpec("Deployment", "test-contour", "contour", "Initial")},
+		{"test-envoy", expectedVPASpec("DaemonSet", "test-envoy", "envoy", "Initial")},
+	}
+	if len(vpas) != len(expected) {
+		t.Fatalf("expected %d verticalpodautoscalers, got %d", len(expected), len(vpas))
+	}
+	for i, e := range expected {
+		vpa := vpas[i]
+		if vpa.GroupVersionKind() != GroupVersionKind {
+			t.Errorf("expected verticalpodautoscaler %s to be a %s, got %s", e.name, GroupVersionKind, vpa.GroupVersionKind())
+		}
+		if vpa.GetNamespace() != "projectcontour" || vpa.GetName() != e.name {
+			t.Errorf("expected verticalpodautoscaler projectcontour/%s, got %s/%s", e.name, vpa.GetNamespace(), vpa.GetName())
+		}
+		if !objcontour.IsOwned(vpa, cntr) {
+			t.Errorf("expected verticalpodautoscaler %s to be owned by the contour", e.name)
+		}
+		spec, _, _ := unstructured.NestedMap(vpa.Object, "spec")
+		if !apiequality.Semantic.DeepEqual(spec, e.spec) {
+			t.Errorf("unexpected spec of verticalpodautoscaler %s:\nexpected: %v\ngot: %v", e.name, e.spec, spec)
+		}
+	}
+
+	cntr.Spec.VerticalPodAutoscaler.UpdateMode = ""
+	for _, vpa := range DesiredVPAs(cntr) {
+		if mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode"); mode != "Off" {
+			t.Errorf("expected verticalpodautoscaler %s to default to update mode Off, got %q", vpa.GetName(), mode)
+		}
+	}
+}
+
+func TestEnsureVPAs(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	cntr.Spec.VerticalPodAutoscaler = &operatorv1alpha1.VerticalPodAutoscalerParameters{}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	// Vertical pod autoscalers fail without the autoscaling.k8s.io API.
+	cli := &objutil.MappedClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Mapper: meta.NewDefaultRESTMapper(nil)}
+	if err := EnsureVPAs(ctx, cli, cntr); err == nil {
+		t.Errorf("expected vertical pod autoscalers to require the autoscaling.k8s.io API")
+	}
+
+	scheme.AddKnownTypeWithName(GroupVersionKind, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(GroupVersionKind.GroupVersion().WithKind("VerticalPodAutoscalerList"),
+		&unstructured.UnstructuredList{})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(GroupVersionKind, meta.RESTScopeNamespace)
+	cli = &objutil.MappedClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Mapper: mapper}
+	if err := EnsureVPAs(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	cntr.Spec.VerticalPodAutoscaler.UpdateMode = operatorv1alpha1.VerticalPodAutoscalerUpdateModeAuto
+	if err := EnsureVPAs(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []struct{ kind, name, container string }{
+		{"Deployment", contourName, contourContainerName},
+		{"DaemonSet", envoyName, envoyContainerName},
+	} {
+		vpa, err := currentVPA(ctx, cli, cntr, e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec, _, _ := unstructured.NestedMap(vpa.Object, "spec")
+		if expected := expectedVPASpec(e.kind, e.name, e.container, "Auto"); !apiequality.Semantic.DeepEqual(spec, expected) {
+			t.Errorf("unexpected spec of updated verticalpodautoscaler %s:\nexpected: %v\ngot: %v", e.name, expected, spec)
+		}
+	}
+
+	cntr.Spec.VerticalPodAutoscaler = nil
+	if err := EnsureVPAs(ctx, cli, cntr); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{contourName, envoyName} {
+		if _, err := currentVPA(ctx, cli, cntr, name); err == nil {
+			t.Errorf("expected verticalpodautoscaler %s to be deleted", name)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package changes records the updates of the resources managed for a Contour
+// as events on the Contour, summarizing the fields that drifted and were
+// repaired, i.e. to audit what keeps modifying them.
+package changes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/equality"
+	"github.com/projectcontour/contour-operator/internal/operator/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// UpdatedReason is the reason of the events of updated resources.
+	UpdatedReason = "Updated"
+	// maxFields is the maximum number of changed fields listed in an event.
+	maxFields = 5
+)
+
+// Client returns a client.Client that updates objects with cli and records an
+// event on contour for each update, listing the fields of the object that
+// changed and the manager that last changed it, if known.
+func Client(cli client.Client, recorder record.EventRecorder, contour *operatorv1alpha1.Contour) client.Client {
+	return &recordingClient{Client: cli, recorder: recorder, contour: contour}
+}
+
+// recordingClient is a client that records the updates of objects as events
+// on contour.
+type recordingClient struct {
+	client.Client
+	recorder record.EventRecorder
+	contour  *operatorv1alpha1.Contour
+}
+
+// Update updates obj and records an event of the update on the contour of c.
+func (c *recordingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	// The current object is read before the update, i.e. from the cache, to
+	// summarize the update. Failing to read it does not fail the update.
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if ok {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			current = nil
+		}
+	}
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	message, err := Summary(c.Scheme(), current, obj)
+	if err != nil || message == "" {
+		return nil
+	}
+	c.recorder.Event(c.contour, corev1.EventTypeNormal, UpdatedReason, message)
+	if kind, err := kind(c.Scheme(), obj); err == nil && Drifted(current, obj) {
+		metrics.DriftRepairs.WithLabelValues(c.contour.Namespace, c.contour.Name, kind).Inc()
+	}
+	return nil
+}
+
+// Drifted returns true if current, the object before it was updated to
+// updated, was last changed by another manager than the manager of the
+// update, i.e. the update repaired a manual edit rather than applying a change
+// of the desired state.
+func Drifted(current, updated client.Object) bool {
+	before := lastManager(current)
+	return before != "" && before != lastManager(updated)
+}
+
+// kind returns the kind of obj, looking it up in scheme if obj has no kind.
+func kind(scheme *runtime.Scheme, obj client.Object) (string, error) {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind, nil
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return "", err
+	}
+	return gvk.Kind, nil
+}
+
+// Summary returns a summary of the update of current to updated, i.e.
+// "Updated DaemonSet projectcontour/envoy: repaired spec.template.spec.containers,
+// last changed by kubectl-edit", or an empty string if no field changed.
+func Summary(scheme *runtime.Scheme, current, updated client.Object) (string, error) {
+	currentContent, err := content(current)
+	if err != nil {
+		return "", err
+	}
+	updatedContent, err := content(updated)
+	if err != nil {
+		return "", err
+	}
+	fields := equality.ChangedFields(currentContent, updatedContent)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if len(fields) > maxFields {
+		fields = append(fields[:maxFields], fmt.Sprintf("and %d more", len(fields)-maxFields))
+	}
+	kind, err := kind(scheme, updated)
+	if err != nil {
+		return "", err
+	}
+	message := fmt.Sprintf("Updated %s %s: repaired %s", kind, name(updated), strings.Join(fields, ", "))
+	if manager := lastManager(current); manager != "" {
+		message += ", last changed by " + manager
+	}
+	return message, nil
+}
+
+// content returns the unstructured content of obj.
+func content(obj client.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// name returns the namespaced name of obj, or its name if it is cluster-scoped.
+func name(obj client.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// lastManager returns the field manager that last changed obj, or an empty
+// string if the managed fields of obj are unknown.
+func lastManager(obj client.Object) string {
+	var last metav1.ManagedFieldsEntry
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time != nil && (last.Time == nil || last.Time.Before(entry.Time)) {
+			last = entry
+		}
+	}
+	return last.Manager
+}
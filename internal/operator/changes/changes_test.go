@@ -0,0 +1,124 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	cntr := objcontour.New(objcontour.Config{Name: "test", Namespace: "test-ns", SpecNs: "projectcontour"})
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "projectcontour", Name: "envoy"},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "envoy", Image: "envoy:v1"}}},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	cli := Client(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ds).Build(), recorder, cntr)
+
+	updated := &appsv1.DaemonSet{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(ds), updated); err != nil {
+		t.Fatal(err)
+	}
+	updated.Spec.Template.Spec.Containers[0].Image = "envoy:v2"
+	if err := cli.Update(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-recorder.Events:
+		expected := "Normal Updated Updated DaemonSet projectcontour/envoy: repaired spec.template.spec.containers"
+		if !strings.HasPrefix(event, expected) {
+			t.Errorf("expected event %q, got %q", expected, event)
+		}
+	default:
+		t.Fatalf("expected an event of the update")
+	}
+
+	// An update that changes nothing is not recorded.
+	if err := cli.Update(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("expected no event, got %q", event)
+	default:
+	}
+}
+
+func TestSummaryLastManager(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	current := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "projectcontour",
+			Name:      "envoy",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "contour-operator", Time: &earlier},
+				{Manager: "kubectl-edit", Time: &now},
+			},
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+	}
+	updated := current.DeepCopy()
+	updated.Spec.Type = corev1.ServiceTypeLoadBalancer
+	message, err := Summary(scheme.Scheme, current, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Updated Service projectcontour/envoy: repaired spec.type, last changed by kubectl-edit"
+	if message != expected {
+		t.Errorf("expected summary %q, got %q", expected, message)
+	}
+}
+
+func TestDrifted(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	managedBy := func(manager string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "contour-operator", Time: &earlier},
+			{Manager: manager, Time: &now},
+		}}}
+	}
+	testCases := map[string]struct {
+		current, updated client.Object
+		expected         bool
+	}{
+		"edited by another manager": {managedBy("kubectl-edit"), managedBy("contour-operator"), true},
+		"changed by the operator":   {managedBy("contour-operator"), managedBy("contour-operator"), false},
+		"unknown managers":          {&corev1.ConfigMap{}, &corev1.ConfigMap{}, false},
+	}
+	for name, tc := range testCases {
+		if drifted := Drifted(tc.current, tc.updated); drifted != tc.expected {
+			t.Errorf("%s: expected drifted %t, got %t", name, tc.expected, drifted)
+		}
+	}
+}
@@ -13,14 +13,54 @@
 
 package config
 
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
 const (
-	DefaultContourImage           = "docker.io/projectcontour/contour:main"
-	DefaultEnvoyImage             = "docker.io/envoyproxy/envoy:v1.18.3"
-	DefaultMetricsAddr            = ":8080"
-	DefaultEnableLeaderElection   = false
-	DefaultEnableLeaderElectionID = "0d879e31.projectcontour.io"
+	DefaultContourImage                  = "docker.io/projectcontour/contour:main"
+	DefaultEnvoyImage                    = "docker.io/envoyproxy/envoy:v1.18.3"
+	DefaultMetricsAddr                   = ":8080"
+	DefaultHealthProbeAddr               = ":8081"
+	DefaultEnableLeaderElection          = false
+	DefaultEnableLeaderElectionID        = "0d879e31.projectcontour.io"
+	DefaultLeaderElectionLeaseDuration   = 15 * time.Second
+	DefaultLeaderElectionRenewDeadline   = 10 * time.Second
+	DefaultLeaderElectionRetryPeriod     = 2 * time.Second
+	DefaultLeaderElectionReleaseOnCancel = true
+	DefaultDrainTimeout                  = 30 * time.Second
+	DefaultKubeAPIQPS                    = 20.0
+	DefaultKubeAPIBurst                  = 30
+	DefaultOperatorNamespace             = "contour-operator"
+
+	// ContourImageEnvVar is the environment variable used as the default
+	// Contour image.
+	ContourImageEnvVar = "CONTOUR_IMAGE"
+
+	// EnvoyImageEnvVar is the environment variable used as the default
+	// Envoy image.
+	EnvoyImageEnvVar = "ENVOY_IMAGE"
+
+	// WatchNamespacesEnvVar is the environment variable used as the default
+	// list of watch namespaces, i.e. populated using the downward API.
+	WatchNamespacesEnvVar = "WATCH_NAMESPACES"
+
+	// OperatorNamespaceEnvVar is the environment variable used as the namespace
+	// the operator runs in, i.e. populated using the downward API.
+	OperatorNamespaceEnvVar = "OPERATOR_NAMESPACE"
 )
 
+// serviceAccountNamespaceFile is the file of the namespace of the service
+// account mounted into the operator pod.
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Version is the version of the operator, set when building a release with
+// -ldflags "-X github.com/projectcontour/contour-operator/internal/operator/config.Version=<version>".
+var Version = "dev"
+
 // Config is configuration of the operator.
 type Config struct {
 	// ContourImage is the container image for the Contour container(s) managed
@@ -31,10 +71,47 @@ type Config struct {
 	// by the operator.
 	EnvoyImage string
 
+	// RegistryMirror is the registry, and optional path prefix, that replaces
+	// the registry of the Contour and Envoy images managed by the operator, i.e.
+	// "registry.example.com/mirror" runs "docker.io/envoyproxy/envoy:v1.18.3" as
+	// "registry.example.com/mirror/envoyproxy/envoy:v1.18.3" in air-gapped
+	// clusters. If empty, images are pulled from their registry.
+	RegistryMirror string
+
 	// MetricsBindAddress is the TCP address that the operator should bind to for
 	// serving prometheus metrics. It can be set to "0" to disable the metrics serving.
 	MetricsBindAddress string
 
+	// MetricsCertFile is the path of the PEM-encoded certificate used to serve
+	// metrics over TLS. If set along with MetricsKeyFile, metrics are only
+	// served over TLS.
+	MetricsCertFile string
+
+	// MetricsKeyFile is the path of the PEM-encoded key of MetricsCertFile.
+	MetricsKeyFile string
+
+	// MetricsClientCAFile is the path of the PEM-encoded CA bundle used to
+	// authenticate metrics clients by certificate. Requires TLS.
+	MetricsClientCAFile string
+
+	// MetricsTokenAuth determines whether metrics clients may authenticate with
+	// a bearer token that is authorized to get the "/metrics" non-resource URL.
+	// Requires TLS.
+	MetricsTokenAuth bool
+
+	// HealthProbeBindAddress is the TCP address that the operator should bind to for
+	// serving the /healthz and /readyz endpoints. It can be set to "0" to disable
+	// serving health probes.
+	HealthProbeBindAddress string
+
+	// KubeAPIQPS is the maximum number of queries per second the operator sends
+	// to the Kubernetes API server.
+	KubeAPIQPS float32
+
+	// KubeAPIBurst is the maximum burst of queries the operator sends to the
+	// Kubernetes API server above KubeAPIQPS.
+	KubeAPIBurst int
+
 	// LeaderElection determines whether or not to use leader election when starting
 	// the operator.
 	LeaderElection bool
@@ -42,15 +119,121 @@ type Config struct {
 	// LeaderElectionID determines the name of the configmap that leader election will
 	// use for holding the leader lock.
 	LeaderElectionID string
+
+	// LeaderElectionNamespace determines the namespace in which the leader election
+	// resource will be created. If empty, OperatorNamespace is used.
+	LeaderElectionNamespace string
+
+	// OperatorNamespace is the namespace the operator runs in. It holds the
+	// leader election resource by default and is never removed with a Contour.
+	OperatorNamespace string
+
+	// LeaseDuration is the duration that non-leader operators will wait to force
+	// acquire leadership.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the duration that the leading operator will retry refreshing
+	// leadership before giving up.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the duration operators wait between leader election actions.
+	RetryPeriod time.Duration
+
+	// LeaderElectionReleaseOnCancel determines whether or not the leading operator
+	// releases the leader lock when it is stopped, so another replica acquires
+	// leadership without waiting for LeaseDuration to expire.
+	LeaderElectionReleaseOnCancel bool
+
+	// WebhookPort is the port the validating webhook of Contours is served at
+	// by every operator replica, leader or not. If 0, the webhook is not served.
+	WebhookPort int
+
+	// WebhookCertDir is the directory of the tls.crt and tls.key files of the
+	// webhook server. If empty, the default of the manager is used.
+	WebhookCertDir string
+
+	// DrainTimeout is how long in-flight reconciles are given to finish when
+	// the operator is stopped, i.e. to update the status of Contours.
+	DrainTimeout time.Duration
+
+	// ConfigFile is the path of the operator configuration file. If set, values
+	// in the file take precedence over ContourImage and EnvoyImage, and the file
+	// is reloaded when it changes.
+	ConfigFile string
+
+	// DisableContourController determines whether or not the Contour controller
+	// is started, i.e. to run the operator in Gateway API only mode.
+	DisableContourController bool
+
+	// DisableGatewayControllers determines whether or not the GatewayClass and
+	// Gateway controllers are started, i.e. to run the operator in Contour only mode.
+	DisableGatewayControllers bool
+
+	// WatchNamespaces is the list of namespaces the operator watches for Contours
+	// and manages resources in. If empty, all namespaces are watched.
+	WatchNamespaces []string
+
+	// SkipContourCRDs determines whether or not the operator installs and
+	// upgrades the Contour CRDs, i.e. HTTPProxy. Set it on clusters where the
+	// CRDs are managed elsewhere.
+	SkipContourCRDs bool
+
+	// ResolveImageDigests determines whether or not the Contour and Envoy images
+	// are pinned to the digest of their tag before they are rolled out.
+	ResolveImageDigests bool
+
+	// ImageVerificationKeyFiles are the paths of PEM-encoded cosign public keys.
+	// If set, the Contour and Envoy images must have a cosign signature that
+	// verifies with one of the keys before they are rolled out, and are pinned
+	// to the verified digest.
+	ImageVerificationKeyFiles []string
+
+	// RegistryCredentialsFile is the path of a docker config file with the
+	// credentials of the registries, including RegistryMirror, that images are
+	// resolved and verified with. If empty, registries are accessed anonymously.
+	RegistryCredentialsFile string
+
+	// TracingEndpoint is the host and port of the OTLP/HTTP collector that the
+	// spans of reconciles are exported to. If empty, reconciles are not traced.
+	TracingEndpoint string
+
+	// TracingInsecure determines whether or not spans are exported to
+	// TracingEndpoint without TLS.
+	TracingInsecure bool
 }
 
 // New returns an operator config using default values.
 func New() *Config {
 	return &Config{
-		ContourImage:       DefaultContourImage,
-		EnvoyImage:         DefaultEnvoyImage,
-		MetricsBindAddress: DefaultMetricsAddr,
-		LeaderElection:     DefaultEnableLeaderElection,
-		LeaderElectionID:   DefaultEnableLeaderElectionID,
+		ContourImage:                  DefaultContourImage,
+		EnvoyImage:                    DefaultEnvoyImage,
+		MetricsBindAddress:            DefaultMetricsAddr,
+		HealthProbeBindAddress:        DefaultHealthProbeAddr,
+		KubeAPIQPS:                    DefaultKubeAPIQPS,
+		KubeAPIBurst:                  DefaultKubeAPIBurst,
+		LeaderElection:                DefaultEnableLeaderElection,
+		LeaderElectionID:              DefaultEnableLeaderElectionID,
+		LeaseDuration:                 DefaultLeaderElectionLeaseDuration,
+		RenewDeadline:                 DefaultLeaderElectionRenewDeadline,
+		RetryPeriod:                   DefaultLeaderElectionRetryPeriod,
+		LeaderElectionReleaseOnCancel: DefaultLeaderElectionReleaseOnCancel,
+		DrainTimeout:                  DefaultDrainTimeout,
+		OperatorNamespace:             DefaultOperatorNamespace,
+	}
+}
+
+// DetectNamespace returns the namespace the operator runs in: the value of
+// OperatorNamespaceEnvVar if set, else the namespace of the service account
+// of the operator pod, else DefaultOperatorNamespace, i.e. when the operator
+// runs outside of a cluster.
+func DetectNamespace() string {
+	if ns := strings.TrimSpace(os.Getenv(OperatorNamespaceEnvVar)); ns != "" {
+		return ns
+	}
+	if data, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
 	}
+	return DefaultOperatorNamespace
 }
@@ -0,0 +1,46 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectNamespace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "namespace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(file string) { serviceAccountNamespaceFile = file }(serviceAccountNamespaceFile)
+	serviceAccountNamespaceFile = filepath.Join(dir, "namespace")
+	defer os.Unsetenv(OperatorNamespaceEnvVar)
+
+	if ns := DetectNamespace(); ns != DefaultOperatorNamespace {
+		t.Errorf("expected namespace %s out of cluster, got %s", DefaultOperatorNamespace, ns)
+	}
+	if err := ioutil.WriteFile(serviceAccountNamespaceFile, []byte("operators\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ns := DetectNamespace(); ns != "operators" {
+		t.Errorf("expected namespace of the service account operators, got %s", ns)
+	}
+	os.Setenv(OperatorNamespaceEnvVar, "platform")
+	if ns := DetectNamespace(); ns != "platform" {
+		t.Errorf("expected namespace of %s platform, got %s", OperatorNamespaceEnvVar, ns)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectcontour/contour-operator/internal/parse"
+
+	"github.com/docker/distribution/reference"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// DefaultConfigFileSyncPeriod is how often the configuration file is checked for changes.
+const DefaultConfigFileSyncPeriod = 10 * time.Second
+
+// File is the operator configuration file. Fields that are unset in the file
+// fall back to the values provided by command-line flags.
+type File struct {
+	// ContourImage is the container image for the Contour container(s) managed
+	// by the operator.
+	ContourImage string `json:"contourImage,omitempty"`
+
+	// EnvoyImage is the container image for the Envoy container(s) managed
+	// by the operator.
+	EnvoyImage string `json:"envoyImage,omitempty"`
+
+	// RegistryMirror is the registry, and optional path prefix, that replaces
+	// the registry of the Contour and Envoy images managed by the operator.
+	RegistryMirror string `json:"registryMirror,omitempty"`
+}
+
+// LoadFile reads and parses the configuration file at path, returning an error
+// if the file can not be read or contains invalid values.
+func LoadFile(path string) (*File, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return parseFile(data)
+}
+
+// parseFile parses data as a configuration file.
+func parseFile(data []byte) (*File, error) {
+	f := &File{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return f, nil
+	}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	for _, image := range []string{f.ContourImage, f.EnvoyImage} {
+		if image == "" {
+			continue
+		}
+		if err := parse.Image(image); err != nil {
+			return nil, fmt.Errorf("invalid image reference %q: %w", image, err)
+		}
+	}
+	if f.RegistryMirror != "" {
+		if err := validMirror(f.RegistryMirror); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Live holds the operator defaults that may change while the operator runs,
+// i.e. when the configuration file is reloaded. Live is safe for concurrent use.
+type Live struct {
+	mu           sync.RWMutex
+	base         Config
+	file         File
+	subscribers  []chan event.GenericEvent
+	subscriberMu sync.Mutex
+}
+
+// NewLive returns a Live using cfg for any default not provided by a
+// configuration file.
+func NewLive(cfg *Config) *Live {
+	return &Live{base: *cfg}
+}
+
+// ContourImage returns the container image for the Contour container(s) managed
+// by the operator.
+func (l *Live) ContourImage() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.file.ContourImage != "" {
+		return l.file.ContourImage
+	}
+	return l.base.ContourImage
+}
+
+// EnvoyImage returns the container image for the Envoy container(s) managed
+// by the operator.
+func (l *Live) EnvoyImage() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.file.EnvoyImage != "" {
+		return l.file.EnvoyImage
+	}
+	return l.base.EnvoyImage
+}
+
+// Mirror returns image pulled from the registry mirror of the operator, or
+// image if no registry mirror is configured.
+func (l *Live) Mirror(image string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.file.RegistryMirror != "" {
+		return MirrorImage(image, l.file.RegistryMirror)
+	}
+	return MirrorImage(image, l.base.RegistryMirror)
+}
+
+// MirrorImage returns image with its registry replaced by mirror, keeping its
+// repository path and tag or digest. Image is returned unchanged if mirror is
+// empty, image is invalid or image is already pulled from mirror.
+func MirrorImage(image, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	if mirror == "" || strings.HasPrefix(image, mirror+"/") {
+		return image
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	mirrored := mirror + "/" + reference.Path(named)
+	if tagged, ok := named.(reference.Tagged); ok {
+		mirrored += ":" + tagged.Tag()
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		mirrored += "@" + canonical.Digest().String()
+	}
+	return mirrored
+}
+
+// validMirror returns an error if mirror is not a registry host with an
+// optional path prefix, i.e. "registry.example.com:5000/mirror".
+func validMirror(mirror string) error {
+	named, err := reference.ParseNamed(strings.TrimSuffix(mirror, "/") + "/image")
+	if err != nil || reference.Domain(named) == "" {
+		return fmt.Errorf("invalid registry mirror %q: must be a registry host with an optional path prefix", mirror)
+	}
+	return nil
+}
+
+// Subscribe returns a channel that receives an event each time the operator
+// defaults change. The event does not reference an object.
+func (l *Live) Subscribe() <-chan event.GenericEvent {
+	l.subscriberMu.Lock()
+	defer l.subscriberMu.Unlock()
+	ch := make(chan event.GenericEvent, 1)
+	l.subscribers = append(l.subscribers, ch)
+	return ch
+}
+
+// Update replaces the configuration file values of l with f and notifies
+// subscribers of the change.
+func (l *Live) Update(f *File) {
+	l.mu.Lock()
+	l.file = *f
+	l.mu.Unlock()
+
+	l.subscriberMu.Lock()
+	defer l.subscriberMu.Unlock()
+	for _, ch := range l.subscribers {
+		// A pending notification already causes a resync, so don't block.
+		select {
+		case ch <- event.GenericEvent{}:
+		default:
+		}
+	}
+}
+
+// FileWatcher reloads the configuration file into its Live when the contents
+// of the file change. FileWatcher polls the file instead of watching filesystem
+// events so that updates to a mounted ConfigMap are detected.
+type FileWatcher struct {
+	// Path is the path of the configuration file.
+	Path string
+	// Live receives the configuration file values.
+	Live *Live
+	// Period is how often the file is checked for changes.
+	Period time.Duration
+	// Log is used to log reload results.
+	Log logr.Logger
+
+	last []byte
+}
+
+// Load loads the configuration file into the Live of w, returning an error if
+// the file can not be loaded.
+func (w *FileWatcher) Load() error {
+	data, err := ioutil.ReadFile(w.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", w.Path, err)
+	}
+	f, err := parseFile(data)
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", w.Path, err)
+	}
+	w.last = data
+	w.Live.Update(f)
+	return nil
+}
+
+// Start polls the configuration file until ctx is done, reloading it when its
+// contents change. An invalid file is logged and the previous values are kept.
+func (w *FileWatcher) Start(ctx context.Context) error {
+	period := w.Period
+	if period <= 0 {
+		period = DefaultConfigFileSyncPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			data, err := ioutil.ReadFile(w.Path)
+			if err != nil {
+				w.Log.Error(err, "failed to read config file", "path", w.Path)
+				continue
+			}
+			if bytes.Equal(data, w.last) {
+				continue
+			}
+			f, err := parseFile(data)
+			if err != nil {
+				w.Log.Error(err, "failed to reload config file; keeping previous configuration", "path", w.Path)
+				w.last = data
+				continue
+			}
+			w.last = data
+			w.Live.Update(f)
+			w.Log.Info("reloaded config file", "path", w.Path)
+		}
+	}
+}
+
+// NeedLeaderElection returns false since every operator replica requires
+// the current configuration.
+func (w *FileWatcher) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	testCases := []struct {
+		description string
+		data        string
+		expected    bool
+	}{
+		{
+			description: "empty file",
+			data:        "",
+			expected:    true,
+		},
+		{
+			description: "valid images",
+			data: `contourImage: docker.io/projectcontour/contour:v1.15.0
+envoyImage: docker.io/envoyproxy/envoy:v1.18.3
+`,
+			expected: true,
+		},
+		{
+			description: "invalid image reference",
+			data:        "contourImage: docker.io/projectcontour/contour:$tag\n",
+			expected:    false,
+		},
+		{
+			description: "valid registry mirror",
+			data:        "registryMirror: registry.example.com:5000/mirror\n",
+			expected:    true,
+		},
+		{
+			description: "registry mirror without registry host",
+			data:        "registryMirror: mirror\n",
+			expected:    false,
+		},
+		{
+			description: "invalid yaml",
+			data:        "contourImage: [\n",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := parseFile([]byte(tc.data))
+		switch {
+		case err != nil && tc.expected:
+			t.Fatalf("%q: %v", tc.description, err)
+		case err == nil && !tc.expected:
+			t.Fatalf("%q: expected an error but received nil", tc.description)
+		}
+	}
+}
+
+func TestLive(t *testing.T) {
+	live := NewLive(New())
+	if live.ContourImage() != DefaultContourImage {
+		t.Fatalf("expected contour image %s, got %s", DefaultContourImage, live.ContourImage())
+	}
+
+	changes := live.Subscribe()
+	image := "docker.io/projectcontour/contour:v1.15.0"
+	live.Update(&File{ContourImage: image})
+	if live.ContourImage() != image {
+		t.Fatalf("expected contour image %s, got %s", image, live.ContourImage())
+	}
+	if live.EnvoyImage() != DefaultEnvoyImage {
+		t.Fatalf("expected envoy image %s, got %s", DefaultEnvoyImage, live.EnvoyImage())
+	}
+	select {
+	case <-changes:
+	default:
+		t.Fatalf("expected a change notification")
+	}
+}
+
+func TestMirrorImage(t *testing.T) {
+	testCases := []struct {
+		image, mirror, expected string
+	}{
+		{
+			image:    "docker.io/envoyproxy/envoy:v1.18.3",
+			expected: "docker.io/envoyproxy/envoy:v1.18.3",
+		},
+		{
+			image:    "docker.io/envoyproxy/envoy:v1.18.3",
+			mirror:   "registry.example.com/mirror/",
+			expected: "registry.example.com/mirror/envoyproxy/envoy:v1.18.3",
+		},
+		{
+			image:    "ghcr.io/projectcontour/contour@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+			mirror:   "registry.example.com:5000",
+			expected: "registry.example.com:5000/projectcontour/contour@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+		},
+		{
+			image:    "registry.example.com/mirror/envoyproxy/envoy:v1.18.3",
+			mirror:   "registry.example.com/mirror",
+			expected: "registry.example.com/mirror/envoyproxy/envoy:v1.18.3",
+		},
+	}
+	for _, tc := range testCases {
+		if actual := MirrorImage(tc.image, tc.mirror); actual != tc.expected {
+			t.Errorf("expected image %s with mirror %q to be %s, got %s", tc.image, tc.mirror, tc.expected, actual)
+		}
+	}
+}
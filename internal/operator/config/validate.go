@@ -0,0 +1,140 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/projectcontour/contour-operator/internal/parse"
+
+	"github.com/docker/distribution/reference"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// dockerHubRegistry is the registry API host of images without a domain,
+// i.e. "docker.io/envoyproxy/envoy".
+const dockerHubRegistry = "registry-1.docker.io"
+
+// Validate returns an error describing every invalid value of c, i.e. an
+// unparseable image reference, so the operator fails at startup instead of
+// producing broken Deployments and DaemonSets.
+func (c *Config) Validate() error {
+	var errs []error
+
+	images := []struct {
+		name, flag, envVar, value string
+	}{
+		{"contour", "--contour-image", ContourImageEnvVar, c.ContourImage},
+		{"envoy", "--envoy-image", EnvoyImageEnvVar, c.EnvoyImage},
+	}
+	for _, image := range images {
+		// Parse will not handle short digests.
+		if err := parse.Image(image.value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s image %q: %v; set %s or %s to a valid image reference",
+				image.name, image.value, err, image.flag, image.envVar))
+		}
+	}
+
+	if c.RegistryMirror != "" {
+		if err := validMirror(c.RegistryMirror); err != nil {
+			errs = append(errs, fmt.Errorf("%v; set --registry-mirror to a valid registry", err))
+		}
+	}
+
+	if c.RegistryCredentialsFile != "" && !c.ResolveImageDigests && len(c.ImageVerificationKeyFiles) == 0 {
+		errs = append(errs, fmt.Errorf("--registry-credentials-file requires --resolve-image-digests or "+
+			"--image-verification-keys"))
+	}
+
+	if c.DisableContourController && c.DisableGatewayControllers {
+		errs = append(errs, fmt.Errorf("all controllers are disabled; unset --disable-contour-controller "+
+			"or --disable-gateway-controllers"))
+	}
+
+	if (c.MetricsCertFile == "") != (c.MetricsKeyFile == "") {
+		errs = append(errs, fmt.Errorf("--metrics-tls-cert-file and --metrics-tls-key-file must be set together"))
+	}
+	if c.MetricsCertFile == "" && (c.MetricsClientCAFile != "" || c.MetricsTokenAuth) {
+		errs = append(errs, fmt.Errorf("--metrics-client-ca-file and --metrics-token-auth require "+
+			"--metrics-tls-cert-file and --metrics-tls-key-file"))
+	}
+
+	if c.LeaderElection && (c.LeaseDuration <= c.RenewDeadline || c.RenewDeadline <= c.RetryPeriod) {
+		errs = append(errs, fmt.Errorf("leader election lease duration %s must be greater than renew deadline "+
+			"%s, and renew deadline greater than retry period %s", c.LeaseDuration, c.RenewDeadline, c.RetryPeriod))
+	}
+
+	if msgs := validation.IsDNS1123Label(c.OperatorNamespace); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid --operator-namespace %q: %s", c.OperatorNamespace,
+			strings.Join(msgs, ", ")))
+	}
+
+	if c.WebhookPort < 0 || c.WebhookPort > 65535 {
+		errs = append(errs, fmt.Errorf("--webhook-port %d must be between 0 and 65535", c.WebhookPort))
+	}
+
+	if c.KubeAPIQPS <= 0 || c.KubeAPIBurst <= 0 {
+		errs = append(errs, fmt.Errorf("--kube-api-qps %v and --kube-api-burst %d must be positive",
+			c.KubeAPIQPS, c.KubeAPIBurst))
+	}
+
+	if c.DrainTimeout < 0 {
+		errs = append(errs, fmt.Errorf("--drain-timeout %s must not be negative", c.DrainTimeout))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// VerifyRegistries returns an error for each image of c whose registry does not
+// respond to a request for its API root using cli.
+func (c *Config) VerifyRegistries(ctx context.Context, cli *http.Client) error {
+	var errs []error
+	for _, image := range []string{c.ContourImage, c.EnvoyImage} {
+		if err := registryReachable(ctx, cli, MirrorImage(image, c.RegistryMirror)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// registryReachable returns an error if the registry of image does not respond
+// to a request for its API root. Any response, including an authentication
+// challenge, means the registry is reachable.
+func registryReachable(ctx context.Context, cli *http.Client, image string) error {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return fmt.Errorf("failed to parse image %q: %w", image, err)
+	}
+	registry := reference.Domain(named)
+	if registry == "docker.io" {
+		registry = dockerHubRegistry
+	}
+	req, err := http.NewRequest(http.MethodGet, "https://"+registry+"/v2/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request for registry %s: %w", registry, err)
+	}
+	resp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("registry %s of image %q is unreachable: %w", registry, image, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("registry %s of image %q returned status %d", registry, image, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description string
+		mutate      func(c *Config)
+		expected    bool
+	}{
+		{
+			description: "default config",
+			mutate:      func(c *Config) {},
+			expected:    true,
+		},
+		{
+			description: "invalid contour image",
+			mutate:      func(c *Config) { c.ContourImage = "docker.io/projectcontour/contour:$tag" },
+			expected:    false,
+		},
+		{
+			description: "invalid envoy image",
+			mutate:      func(c *Config) { c.EnvoyImage = "Envoy" },
+			expected:    false,
+		},
+		{
+			description: "registry credentials without digests or verification",
+			mutate:      func(c *Config) { c.RegistryCredentialsFile = "config.json" },
+			expected:    false,
+		},
+		{
+			description: "registry credentials with digests",
+			mutate: func(c *Config) {
+				c.RegistryCredentialsFile = "config.json"
+				c.ResolveImageDigests = true
+			},
+			expected: true,
+		},
+		{
+			description: "all controllers disabled",
+			mutate: func(c *Config) {
+				c.DisableContourController = true
+				c.DisableGatewayControllers = true
+			},
+			expected: false,
+		},
+		{
+			description: "metrics cert without key",
+			mutate:      func(c *Config) { c.MetricsCertFile = "tls.crt" },
+			expected:    false,
+		},
+		{
+			description: "metrics token auth without tls",
+			mutate:      func(c *Config) { c.MetricsTokenAuth = true },
+			expected:    false,
+		},
+		{
+			description: "renew deadline exceeds lease duration",
+			mutate: func(c *Config) {
+				c.LeaderElection = true
+				c.RenewDeadline = 20 * time.Second
+			},
+			expected: false,
+		},
+		{
+			description: "invalid operator namespace",
+			mutate:      func(c *Config) { c.OperatorNamespace = "Operators" },
+			expected:    false,
+		},
+		{
+			description: "webhook port out of range",
+			mutate:      func(c *Config) { c.WebhookPort = 70000 },
+			expected:    false,
+		},
+		{
+			description: "zero kube api qps",
+			mutate:      func(c *Config) { c.KubeAPIQPS = 0 },
+			expected:    false,
+		},
+		{
+			description: "negative drain timeout",
+			mutate:      func(c *Config) { c.DrainTimeout = -time.Second },
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := New()
+		tc.mutate(c)
+		err := c.Validate()
+		switch {
+		case err != nil && tc.expected:
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		case err == nil && !tc.expected:
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestRegistryReachable(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// Registries challenge anonymous requests for the API root.
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	registry := strings.TrimPrefix(srv.URL, "https://")
+	if err := registryReachable(context.Background(), srv.Client(), registry+"/projectcontour/contour:main"); err != nil {
+		t.Fatalf("expected registry %s to be reachable: %v", registry, err)
+	}
+
+	srv.Close()
+	if err := registryReachable(context.Background(), srv.Client(), registry+"/projectcontour/contour:main"); err == nil {
+		t.Fatalf("expected registry %s to be unreachable", registry)
+	}
+}
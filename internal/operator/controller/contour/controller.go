@@ -16,9 +16,12 @@ package contour
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcert "github.com/projectcontour/contour-operator/internal/objects/certificate"
 	objcm "github.com/projectcontour/contour-operator/internal/objects/configmap"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
@@ -26,69 +29,216 @@ import (
 	objgc "github.com/projectcontour/contour-operator/internal/objects/gatewayclass"
 	objjob "github.com/projectcontour/contour-operator/internal/objects/job"
 	objns "github.com/projectcontour/contour-operator/internal/objects/namespace"
+	objrule "github.com/projectcontour/contour-operator/internal/objects/prometheusrule"
+	objroute "github.com/projectcontour/contour-operator/internal/objects/route"
+	objscc "github.com/projectcontour/contour-operator/internal/objects/scc"
 	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+	objvpa "github.com/projectcontour/contour-operator/internal/objects/vpa"
+	"github.com/projectcontour/contour-operator/internal/operator/changes"
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	"github.com/projectcontour/contour-operator/internal/operator/drain"
+	"github.com/projectcontour/contour-operator/internal/operator/metrics"
 	"github.com/projectcontour/contour-operator/internal/operator/status"
+	"github.com/projectcontour/contour-operator/internal/operator/tracing"
+	"github.com/projectcontour/contour-operator/internal/registry"
+	"github.com/projectcontour/contour-operator/internal/release"
 	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
+	"github.com/projectcontour/contour-operator/internal/upgrade"
 	"github.com/projectcontour/contour-operator/pkg/validation"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
 const (
 	controllerName = "contour_controller"
+	// imageVerificationRetryPeriod is how long to wait before retrying to
+	// resolve or verify the images of a contour.
+	imageVerificationRetryPeriod = time.Minute
+	// certificatesRotationRetryPeriod is how often to check whether certgen
+	// regenerated the certificates of a rotation.
+	certificatesRotationRetryPeriod = 5 * time.Second
+	// conditionChangedReason is the reason of the events of the transitions of
+	// the status conditions of a contour.
+	conditionChangedReason = "ConditionChanged"
+	// namespaceTerminatingRetryPeriod is how often to check whether the spec
+	// namespace of a deleted contour was removed.
+	namespaceTerminatingRetryPeriod = 10 * time.Second
+	// namespaceTerminatingTimeout is how long the spec namespace of a deleted
+	// contour may terminate before it is reported as stuck and the contour is
+	// released.
+	namespaceTerminatingTimeout = 5 * time.Minute
+	// namespaceRemovalBlockedRetryPeriod is how often to check whether the
+	// unowned workloads blocking the removal of the spec namespace of a
+	// deleted contour were removed.
+	namespaceRemovalBlockedRetryPeriod = time.Minute
+	// nodePortConflictRetryPeriod is how often to retry ensuring the Envoy
+	// service of a contour with a node port allocated to another service.
+	nodePortConflictRetryPeriod = 30 * time.Second
+	// certificatesReissueWindow is how long before the first xDS certificate of
+	// a contour expires the certificates are re-issued. Certgen generates
+	// certificates valid for a year, and contours are reconciled at least
+	// every sync period of the manager.
+	certificatesReissueWindow = 30 * 24 * time.Hour
 )
 
 // Config holds all the things necessary for the controller to run.
 type Config struct {
-	// ContourImage is the name of the Contour container image.
-	ContourImage string
-	// EnvoyImage is the name of the Envoy container image.
-	EnvoyImage string
+	// Defaults holds the operator defaults, i.e. the Contour and Envoy container
+	// images, which may change while the controller runs.
+	Defaults *operatorconfig.Live
+	// WatchNamespaces is the list of namespaces the operator is restricted to.
+	// If empty, the operator is not restricted to any namespaces.
+	WatchNamespaces []string
+	// DrainTimeout is how long in-flight reconciles are given to finish
+	// when the operator is stopped.
+	DrainTimeout time.Duration
+	// KubernetesVersion is the version of the cluster, i.e. "v1.21.1", used by
+	// pre-upgrade checks. If empty, the Kubernetes version is not checked.
+	KubernetesVersion string
+	// Images pins the Contour and Envoy images to their digest and verifies
+	// their signatures before they are rolled out, if enabled.
+	Images *registry.Pinner
+	// OperatorNamespace is the namespace the operator runs in, which is never
+	// removed with a Contour.
+	OperatorNamespace string
 }
 
 // reconciler reconciles a Contour object.
 type reconciler struct {
-	config Config
-	client client.Client
-	log    logr.Logger
+	config   Config
+	client   client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+
+	// conditionsLock protects conditions.
+	conditionsLock sync.Mutex
+	// conditions are the status conditions of the contours last observed by
+	// the reconciler, to record their transitions.
+	conditions map[types.NamespacedName][]metav1.Condition
 }
 
 // New creates the contour controller from mgr and cfg. The controller will be pre-configured
 // to watch for Contour objects across all namespaces.
 func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 	r := &reconciler{
-		config: cfg,
-		client: mgr.GetClient(),
-		log:    ctrl.Log.WithName(controllerName),
+		config:     cfg,
+		client:     mgr.GetClient(),
+		recorder:   mgr.GetEventRecorderFor(controllerName),
+		log:        ctrl.Log.WithName(controllerName),
+		conditions: map[types.NamespacedName][]metav1.Condition{},
 	}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler: drain.Reconciler(tracing.Reconciler(controllerName, r), cfg.DrainTimeout),
+	})
 	if err != nil {
 		return nil, err
 	}
 	if err := c.Watch(&source.Kind{Type: &operatorv1alpha1.Contour{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return nil, err
 	}
-	// Watch the Contour deployment and Envoy daemonset to properly surface Contour status conditions.
+	// Watch the contours claiming a GatewayClass to resolve conflicting claims.
+	if err := c.Watch(&source.Kind{Type: &operatorv1alpha1.Contour{}}, r.enqueueRequestForGatewayClassClaimants()); err != nil {
+		return nil, err
+	}
+	// Watch the Contour deployment, Envoy daemonset and Envoy service to properly surface
+	// Contour status conditions.
 	if err := c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, r.enqueueRequestForOwningContour()); err != nil {
 		return nil, err
 	}
 	if err := c.Watch(&source.Kind{Type: &appsv1.DaemonSet{}}, r.enqueueRequestForOwningContour()); err != nil {
 		return nil, err
 	}
+	if err := c.Watch(&source.Kind{Type: &corev1.Service{}}, r.enqueueRequestForOwningContour()); err != nil {
+		return nil, err
+	}
+	// Watch the certgen Job to delete it once finished.
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, r.enqueueRequestForOwningContour()); err != nil {
+		return nil, err
+	}
+	// Watch the secrets of the certificates generated by certgen to track their
+	// expiry. Other secrets are filtered before listing the contours.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.enqueueRequestForCertificates(),
+		predicate.NewPredicateFuncs(func(o client.Object) bool { return objjob.IsCertsSecret(o.GetName()) })); err != nil {
+		return nil, err
+	}
+	// Resync all contours when the operator defaults change.
+	if err := c.Watch(&source.Channel{Source: cfg.Defaults.Subscribe()}, r.enqueueRequestForAllContours()); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
+// WatchGatewayClasses configures c, a controller created by New from mgr, to
+// reconcile the contours referencing a GatewayClass when the GatewayClass
+// changes, i.e. when it is created after the contours. It must only be called
+// if the Gateway API is served by the cluster.
+func WatchGatewayClasses(c controller.Controller, mgr manager.Manager) error {
+	return c.Watch(&source.Kind{Type: &gatewayv1alpha1.GatewayClass{}},
+		enqueueRequestForGatewayClassRef(mgr.GetClient(), ctrl.Log.WithName(controllerName)))
+}
+
+// enqueueRequestForGatewayClassRef returns an event handler that maps
+// GatewayClass events to the Contour objects referencing the GatewayClass by
+// spec.gatewayClassRef.
+func enqueueRequestForGatewayClassRef(cli client.Client, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+		return requestsForGatewayClassRef(cli, log, a.GetName())
+	})
+}
+
+// enqueueRequestForGatewayClassClaimants returns an event handler that maps
+// events of a Contour referencing a GatewayClass to the Contour objects
+// referencing the same GatewayClass, so that another claimant of the
+// GatewayClass takes it over when the Contour is deleted or stops claiming it.
+func (r *reconciler) enqueueRequestForGatewayClassClaimants() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+		contour, ok := a.(*operatorv1alpha1.Contour)
+		if !ok || !contour.GatewayClassSet() {
+			return []reconcile.Request{}
+		}
+		return requestsForGatewayClassRef(r.client, r.log, *contour.Spec.GatewayClassRef)
+	})
+}
+
+// requestsForGatewayClassRef returns the requests of the Contour objects
+// referencing the GatewayClass named name by spec.gatewayClassRef.
+func requestsForGatewayClassRef(cli client.Client, log logr.Logger, name string) []reconcile.Request {
+	contours, err := objcontour.GatewayClassRefsExist(context.Background(), cli, name)
+	if err != nil {
+		log.Error(err, "failed to list contours", "gatewayclass", name)
+		return []reconcile.Request{}
+	}
+	var requests []reconcile.Request
+	for _, c := range contours {
+		log.Info("queueing contour", "namespace", c.Namespace, "name", c.Name, "gatewayclass", name)
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: c.Namespace,
+				Name:      c.Name,
+			},
+		})
+	}
+	return requests
+}
+
 // enqueueRequestForOwningContour returns an event handler that maps events to
 // objects containing Contour owner labels.
 func (r *reconciler) enqueueRequestForOwningContour() handler.EventHandler {
@@ -111,6 +261,60 @@ func (r *reconciler) enqueueRequestForOwningContour() handler.EventHandler {
 	})
 }
 
+// enqueueRequestForCertificates returns an event handler that maps events of
+// the secrets generated by the certgen Job of a Contour to the Contour.
+func (r *reconciler) enqueueRequestForCertificates() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+		contours := &operatorv1alpha1.ContourList{}
+		if err := r.client.List(context.Background(), contours); err != nil {
+			r.log.Error(err, "failed to list contours")
+			return []reconcile.Request{}
+		}
+		var requests []reconcile.Request
+		for i := range contours.Items {
+			c := &contours.Items[i]
+			if c.Spec.Namespace.Name != a.GetNamespace() {
+				continue
+			}
+			for _, base := range objjob.CertsSecretNames {
+				if objcontour.CertsSecretName(c, base) == a.GetName() {
+					r.log.Info("queueing contour", "namespace", c.Namespace, "name", c.Name, "secret", a.GetName())
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Namespace: c.Namespace,
+							Name:      c.Name,
+						},
+					})
+					break
+				}
+			}
+		}
+		return requests
+	})
+}
+
+// enqueueRequestForAllContours returns an event handler that maps events to
+// all Contour objects.
+func (r *reconciler) enqueueRequestForAllContours() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ client.Object) []reconcile.Request {
+		contours := &operatorv1alpha1.ContourList{}
+		if err := r.client.List(context.Background(), contours); err != nil {
+			r.log.Error(err, "failed to list contours")
+			return []reconcile.Request{}
+		}
+		var requests []reconcile.Request
+		for _, c := range contours.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: c.Namespace,
+					Name:      c.Name,
+				},
+			})
+		}
+		return requests
+	})
+}
+
 // Reconcile reconciles watched objects and attempts to make the current state of
 // the object match the desired state.
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -120,6 +324,8 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	contour := &operatorv1alpha1.Contour{}
 	if err := r.client.Get(ctx, req.NamespacedName, contour); err != nil {
 		if errors.IsNotFound(err) {
+			r.forgetConditions(req.NamespacedName)
+			metrics.XDSCertificateExpiry.DeleteLabelValues(req.Namespace, req.Name)
 			// Sync gatewayclass status if this contour is referenced by any gatewayclasses.
 			gc, exists, err := objgc.ParameterRefExists(ctx, r.client, req.Name, req.Namespace)
 			if err != nil {
@@ -154,12 +360,16 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// Error reading the object, so requeue the request.
 		return ctrl.Result{}, fmt.Errorf("failed to get contour %s: %w", req, err)
 	}
+	r.observeConditions(req.NamespacedName, contour)
 	// The contour is safe to process, so ensure current state matches desired state.
 	desired := contour.ObjectMeta.DeletionTimestamp.IsZero()
 	if desired {
 		if err := validation.Contour(ctx, r.client, contour); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to validate contour %s/%s: %w", contour.Namespace, contour.Name, err)
 		}
+		if err := validation.WatchedNamespace(contour, r.config.WatchNamespaces); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to validate contour %s/%s: %w", contour.Namespace, contour.Name, err)
+		}
 		switch {
 		case contour.GatewayClassSet():
 			if err := r.ensureContourForGatewayClass(ctx, contour); err != nil {
@@ -181,7 +391,9 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				r.log.Info("finalized contour", "namespace", contour.Namespace, "name", contour.Name)
 			} else {
 				r.log.Info("contour finalized", "namespace", contour.Namespace, "name", contour.Name)
-				if err := r.ensureContour(ctx, contour); err != nil {
+				// The resources are ensured from the network publishing the preset
+				// of the contour expands to.
+				if err := r.ensureContour(ctx, objcontour.WithPublishingPreset(contour)); err != nil {
 					switch e := err.(type) {
 					case retryable.Error:
 						r.log.Error(e, "got retryable error; requeueing", "after", e.After())
@@ -214,10 +426,44 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// observeConditions exports the status conditions of the contour named key as
+// metrics and records an event for each condition that transitioned since the
+// conditions of the contour were last observed. Conditions observed for the
+// first time since the operator started are not recorded as transitions.
+func (r *reconciler) observeConditions(key types.NamespacedName, contour *operatorv1alpha1.Contour) {
+	r.conditionsLock.Lock()
+	defer r.conditionsLock.Unlock()
+	previous, observed := r.conditions[key]
+	current := contour.Status.Conditions
+	metrics.SetContourConditions(key.Namespace, key.Name, previous, current)
+	r.conditions[key] = current
+	if !observed {
+		return
+	}
+	for _, c := range status.Transitions(previous, current) {
+		eventType := corev1.EventTypeNormal
+		if status.Abnormal(c) {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recorder.Eventf(contour, eventType, conditionChangedReason, "Condition %s changed to %s: %s: %s",
+			c.Type, c.Status, c.Reason, c.Message)
+	}
+}
+
+// forgetConditions deletes the metrics of the conditions of the deleted contour
+// named key.
+func (r *reconciler) forgetConditions(key types.NamespacedName) {
+	r.conditionsLock.Lock()
+	defer r.conditionsLock.Unlock()
+	metrics.DeleteContourConditions(key.Namespace, key.Name, r.conditions[key])
+	delete(r.conditions, key)
+}
+
 // ensureContour ensures all necessary resources exist for the given contour.
 func (r *reconciler) ensureContour(ctx context.Context, contour *operatorv1alpha1.Contour) error {
 	var errs []error
-	cli := r.client
+	// Updates of the managed resources are recorded as events on the contour.
+	cli := changes.Client(r.client, r.recorder, contour)
 
 	handleResult := func(resource string, err error) {
 		if err != nil {
@@ -227,8 +473,14 @@ func (r *reconciler) ensureContour(ctx context.Context, contour *operatorv1alpha
 		}
 	}
 
+	// ensure traces ensuring resource with fn in a span of the reconcile.
+	ensure := func(resource string, fn func() error) {
+		handleResult(resource, tracing.Ensure(ctx, resource, fn))
+	}
+
+	var conditions []metav1.Condition
 	syncContourStatus := func() error {
-		if err := status.SyncContour(ctx, cli, contour); err != nil {
+		if err := status.SyncContour(ctx, cli, contour, conditions...); err != nil {
 			errs = append(errs, fmt.Errorf("failed to sync status for contour %s/%s: %w", contour.Namespace, contour.Name, err))
 		} else {
 			r.log.Info("synced status for contour", "namespace", contour.Namespace, "name", contour.Name)
@@ -236,30 +488,283 @@ func (r *reconciler) ensureContour(ctx context.Context, contour *operatorv1alpha
 		return retryable.NewMaybeRetryableAggregate(errs)
 	}
 
-	handleResult("namespace", objns.EnsureNamespace(ctx, cli, contour))
-	handleResult("rbac", objutil.EnsureRBAC(ctx, cli, contour))
+	ensure("namespace", func() error { return objns.EnsureNamespace(ctx, cli, contour) })
+	ensure("rbac", func() error { return objutil.EnsureRBAC(ctx, cli, contour) })
 
 	if len(errs) > 0 {
 		return syncContourStatus()
 	}
 
-	contourImage := r.config.ContourImage
-	envoyImage := r.config.EnvoyImage
+	contourImage, envoyImage, upgradeBlocked := r.images(ctx, contour)
+	conditions = append(conditions, upgradeBlocked)
+	contourImage, envoyImage = r.config.Defaults.Mirror(contourImage), r.config.Defaults.Mirror(envoyImage)
+	if r.config.Images.Enabled() {
+		var err error
+		contourImage, envoyImage, err = r.config.Images.PinImages(ctx, contourImage, envoyImage)
+		conditions = append(conditions, status.ComputeImagesVerifiedCondition(err))
+		if err != nil {
+			// Fail the rollout instead of rolling out images that can not be verified.
+			errs = append(errs, retryable.New(fmt.Errorf("failed to verify images for contour %s/%s: %w",
+				contour.Namespace, contour.Name, err), imageVerificationRetryPeriod))
+			return syncContourStatus()
+		}
+	}
 
-	handleResult("configmap", objcm.Ensure(ctx, cli, objcm.NewCfgForContour(contour)))
-	handleResult("job", objjob.EnsureJob(ctx, cli, contour, contourImage))
-	handleResult("deployment", objdeploy.EnsureDeployment(ctx, cli, contour, contourImage))
-	handleResult("daemonset", objds.EnsureDaemonSet(ctx, cli, contour, contourImage, envoyImage))
-	handleResult("contour service", objsvc.EnsureContourService(ctx, cli, contour))
+	ensure("default certificate", func() error { return objcert.EnsureDefaultCertificate(ctx, cli, contour) })
+	// Rendering an invalid configuration refuses the rollout, since Contour
+	// would not start with it.
+	err := tracing.Ensure(ctx, "configmap", func() error { return objcm.Ensure(ctx, cli, objcm.NewCfgForContour(contour)) })
+	if invalid, ok := err.(*objcm.InvalidConfigError); ok {
+		cond := status.ComputeConfigRejectedCondition(invalid)
+		r.recorder.Event(contour, corev1.EventTypeWarning, operatorv1alpha1.ConfigRejectedConditionType, cond.Message)
+		conditions = append(conditions, cond)
+		handleResult("configmap", err)
+		return syncContourStatus()
+	}
+	handleResult("configmap", err)
+	if err == nil && meta.FindStatusCondition(contour.Status.Conditions, operatorv1alpha1.ConfigRejectedConditionType) != nil {
+		conditions = append(conditions, status.ComputeConfigRejectedCondition(nil))
+	}
+	expiring, err := r.reissueExpiringCertificates(ctx, contour)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to verify expiry of certificates for contour %s/%s: %w",
+			contour.Namespace, contour.Name, err))
+	case expiring != nil:
+		conditions = append(conditions, *expiring)
+	}
+	if contour.CertificatesRotationRequested() {
+		requested := contour.Annotations[operatorv1alpha1.RotateCertificatesAnnotation]
+		err := tracing.Ensure(ctx, "certificates rotation", func() error {
+			return objjob.RotateCertificates(ctx, cli, contour, contourImage)
+		})
+		if err != nil {
+			handleResult("certificates rotation", err)
+			return syncContourStatus()
+		}
+		r.log.Info("started certificates rotation", "namespace", contour.Namespace, "name", contour.Name,
+			"rotation", requested)
+		// Recorded in the status, which restarts the pods once the certificates
+		// are regenerated.
+		contour.Status.CertificatesRotation = requested
+		errs = append(errs, retryable.New(fmt.Errorf("rotating certificates of contour %s/%s",
+			contour.Namespace, contour.Name), certificatesRotationRetryPeriod))
+		return syncContourStatus()
+	}
+	// A finished certgen Job kept until its TTL expires requeues the contour
+	// with a retryable error, which must not be wrapped.
+	err = tracing.Ensure(ctx, "job", func() error { return objjob.EnsureJob(ctx, cli, contour, contourImage) })
+	if isRetryable(err) {
+		errs = append(errs, err)
+	} else {
+		handleResult("job", err)
+	}
+	if contour.Status.CertificatesRotation != "" {
+		// Restart the Contour and Envoy pods together once certgen regenerated
+		// the certificates, since they only trust certificates of the same CA.
+		exist, err := objjob.CertificatesExist(ctx, cli, contour)
+		switch {
+		case err != nil:
+			handleResult("certificates", err)
+			return syncContourStatus()
+		case !exist:
+			errs = append(errs, retryable.New(fmt.Errorf("waiting for certificates of contour %s/%s",
+				contour.Namespace, contour.Name), certificatesRotationRetryPeriod))
+			return syncContourStatus()
+		}
+	}
+	// Resources rendered by another operator version are re-rendered from the
+	// contour below, and the migration is recorded once they are ensured.
+	renderedBy, migrated, err := r.renderedByOtherVersion(ctx, contour)
+	handleResult("rendering version", err)
+	ensure("deployment", func() error { return objdeploy.EnsureDeployment(ctx, cli, contour, contourImage) })
+	ensure("security context constraints", func() error { return objscc.EnsureSCC(ctx, cli, contour) })
+	// A change of the Envoy pod template deferred to a maintenance window and a
+	// canary rollout that waits for updated pods requeue the contour with a
+	// retryable error, which must not be wrapped.
+	err = tracing.Ensure(ctx, "daemonset", func() error {
+		return objds.EnsureDaemonSet(ctx, cli, contour, contourImage, envoyImage)
+	})
+	if isRetryable(err) {
+		errs = append(errs, err)
+	} else {
+		handleResult("daemonset", err)
+	}
+	err = tracing.Ensure(ctx, "envoy rollout", func() error { return objds.EnsureRollout(ctx, cli, contour) })
+	if isRetryable(err) {
+		errs = append(errs, err)
+	} else {
+		handleResult("envoy rollout", err)
+	}
+	ensure("contour service", func() error { return objsvc.EnsureContourService(ctx, cli, contour) })
 
+	// A node port allocated to another service is reported on the contour and
+	// retried without tearing down the resources ensured above.
+	conflicted, servicesEnsured := false, true
+	ensureEnvoyService := func(resource string, fn func() error) {
+		err := tracing.Ensure(ctx, resource, fn)
+		if conflict, ok := err.(*objsvc.NodePortConflictError); ok {
+			if !conflicted {
+				cond := status.ComputeNodePortConflictCondition(conflict.Port, conflict.Service)
+				r.recorder.Event(contour, corev1.EventTypeWarning, operatorv1alpha1.NodePortConflictConditionType, cond.Message)
+				conditions = append(conditions, cond)
+			}
+			conflicted = true
+			errs = append(errs, retryable.New(fmt.Errorf("failed to ensure %s for contour %s/%s: %w",
+				resource, contour.Namespace, contour.Name, err), nodePortConflictRetryPeriod))
+			return
+		}
+		handleResult(resource, err)
+		servicesEnsured = servicesEnsured && err == nil
+	}
 	switch contour.Spec.NetworkPublishing.Envoy.Type {
 	case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType:
-		handleResult("envoy service", objsvc.EnsureEnvoyService(ctx, cli, contour))
+		ensureEnvoyService("envoy service", func() error { return objsvc.EnsureEnvoyService(ctx, cli, contour) })
+	case operatorv1alpha1.RoutePublishingType:
+		ensureEnvoyService("envoy service", func() error { return objsvc.EnsureEnvoyService(ctx, cli, contour) })
+		ensure("envoy route", func() error { return objroute.EnsureRoute(ctx, cli, contour) })
+	}
+	// The envoy http service is deleted once httpService is unset.
+	ensureEnvoyService("envoy http service", func() error { return objsvc.EnsureEnvoyHTTPService(ctx, cli, contour) })
+	ensureEnvoyService("additional envoy services", func() error { return objsvc.EnsureAdditionalEnvoyServices(ctx, cli, contour) })
+	if !conflicted && servicesEnsured &&
+		meta.FindStatusCondition(contour.Status.Conditions, operatorv1alpha1.NodePortConflictConditionType) != nil {
+		conditions = append(conditions, status.ComputeNodePortConflictCondition(0, ""))
 	}
+	ensure("prometheusrule", func() error { return objrule.EnsurePrometheusRule(ctx, cli, contour) })
+	ensure("vertical pod autoscalers", func() error { return objvpa.EnsureVPAs(ctx, cli, contour) })
 
+	if migrated && len(errs) == 0 {
+		r.recordMigration(contour, renderedBy)
+	}
+	if contour.ResyncRequested() && len(errs) == 0 {
+		// Recorded in the status, so that the resync is completed once.
+		contour.Status.Resync = contour.Annotations[operatorv1alpha1.ResyncAnnotation]
+		r.recorder.Eventf(contour, corev1.EventTypeNormal, "Resynced", "Resynced managed resources for resync %s",
+			contour.Status.Resync)
+		r.log.Info("resynced contour", "namespace", contour.Namespace, "name", contour.Name,
+			"resync", contour.Status.Resync)
+	}
 	return syncContourStatus()
 }
 
+// reissueExpiringCertificates records the expiry of the xDS certificates of
+// contour and requests their rotation with the rotate-certificates annotation
+// once they expire within certificatesReissueWindow, so that they are
+// regenerated and the Contour and Envoy pods are restarted together as in a
+// rotation requested by a user. It returns the CertificatesExpiring condition
+// of contour, or nil while the certificates do not exist.
+func (r *reconciler) reissueExpiringCertificates(ctx context.Context, contour *operatorv1alpha1.Contour) (*metav1.Condition, error) {
+	expiry, err := objjob.CertificatesExpiry(ctx, r.client, contour)
+	if err != nil || expiry == nil {
+		return nil, err
+	}
+	metrics.XDSCertificateExpiry.WithLabelValues(contour.Namespace, contour.Name).Set(float64(expiry.Unix()))
+	if time.Until(*expiry) > certificatesReissueWindow {
+		cond := status.ComputeCertificatesExpiringCondition(*expiry, false)
+		return &cond, nil
+	}
+	// Named after the expiry, so that the certificates are re-issued once.
+	rotation := "expiry-" + expiry.UTC().Format("20060102T150405Z")
+	if contour.Annotations[operatorv1alpha1.RotateCertificatesAnnotation] != rotation {
+		// Only the annotation is patched, and only the annotations of the
+		// patched copy are kept, since the spec of contour may be expanded from
+		// its network publishing preset and the response of the server is not.
+		base := contour.DeepCopy()
+		patched := contour.DeepCopy()
+		if patched.Annotations == nil {
+			patched.Annotations = map[string]string{}
+		}
+		patched.Annotations[operatorv1alpha1.RotateCertificatesAnnotation] = rotation
+		if err := r.client.Patch(ctx, patched, client.MergeFrom(base)); err != nil {
+			return nil, fmt.Errorf("failed to request rotation of certificates: %w", err)
+		}
+		contour.Annotations = patched.Annotations
+		r.recorder.Eventf(contour, corev1.EventTypeWarning, operatorv1alpha1.CertificatesExpiringConditionType,
+			"Re-issuing xDS certificates expiring at %s", expiry.UTC().Format(time.RFC3339))
+		r.log.Info("requested rotation of expiring certificates", "namespace", contour.Namespace, "name", contour.Name,
+			"rotation", rotation)
+	}
+	cond := status.ComputeCertificatesExpiringCondition(*expiry, true)
+	return &cond, nil
+}
+
+// renderedByOtherVersion returns the version of the operator that rendered the
+// Contour Deployment or Envoy DaemonSet of contour, and true if it is not the
+// version of the running operator. Resources that do not exist are not
+// migrated.
+func (r *reconciler) renderedByOtherVersion(ctx context.Context, contour *operatorv1alpha1.Contour) (string, bool, error) {
+	var objs []metav1.Object
+	deploy, err := objdeploy.CurrentDeployment(ctx, r.client, contour)
+	switch {
+	case err == nil:
+		objs = append(objs, deploy)
+	case !errors.IsNotFound(err):
+		return "", false, err
+	}
+	ds, err := objds.CurrentDaemonSet(ctx, r.client, contour)
+	switch {
+	case err == nil:
+		objs = append(objs, ds)
+	case !errors.IsNotFound(err):
+		return "", false, err
+	}
+	for _, obj := range objs {
+		if version, other := objcontour.RenderedByOtherVersion(obj); other {
+			return version, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// recordMigration records that the resources of contour rendered by operator
+// version from were re-rendered by the running operator.
+func (r *reconciler) recordMigration(contour *operatorv1alpha1.Contour, from string) {
+	if from == "" {
+		from = "unknown"
+	}
+	metrics.MigratedContours.WithLabelValues(from).Inc()
+	r.recorder.Eventf(contour, corev1.EventTypeNormal, "Migrated",
+		"Re-rendered resources of operator version %s with operator version %s", from, operatorconfig.Version)
+	r.log.Info("migrated contour", "namespace", contour.Namespace, "name", contour.Name,
+		"from", from, "to", operatorconfig.Version)
+}
+
+// images returns the Contour and Envoy images of contour, i.e. the images of
+// the image flavor of the release of its version or the operator's default
+// images, and its UpgradeBlocked condition. An upgrade from the running release
+// that fails the pre-upgrade checks keeps the images of the running release.
+func (r *reconciler) images(ctx context.Context, contour *operatorv1alpha1.Contour) (string, string, metav1.Condition) {
+	rel, ok := release.LookupFlavored(contour.Spec.Version, string(contour.Spec.ImageFlavor))
+	if !ok {
+		return r.config.Defaults.ContourImage(), r.config.Defaults.EnvoyImage(), status.ComputeUpgradeBlockedCondition("", nil)
+	}
+	running, ok := release.Lookup(contour.Status.Version)
+	if !ok || running.Version == rel.Version {
+		return rel.ContourImage, rel.EnvoyImage, status.ComputeUpgradeBlockedCondition(rel.Version, nil)
+	}
+	problems, err := upgrade.Check(ctx, r.client, contour, rel, r.config.KubernetesVersion)
+	if err != nil {
+		problems = []string{fmt.Sprintf("Failed to run pre-upgrade checks: %v.", err)}
+	}
+	if len(problems) > 0 {
+		r.log.Info("blocked contour upgrade", "namespace", contour.Namespace, "name", contour.Name,
+			"version", rel.Version, "problems", problems)
+		// The running release may have no images of the flavor.
+		if flavored, ok := running.Flavored(string(contour.Spec.ImageFlavor)); ok {
+			running = flavored
+		}
+		return running.ContourImage, running.EnvoyImage, status.ComputeUpgradeBlockedCondition(rel.Version, problems)
+	}
+	return rel.ContourImage, rel.EnvoyImage, status.ComputeUpgradeBlockedCondition(rel.Version, nil)
+}
+
+// isRetryable returns true if err is a retryable error.
+func isRetryable(err error) bool {
+	_, ok := err.(retryable.Error)
+	return ok
+}
+
 // ensureContourForGatewayClass ensures all necessary resources exist for the given contour
 // when the contour is being managed by a GatewayClass.
 func (r *reconciler) ensureContourForGatewayClass(ctx context.Context, contour *operatorv1alpha1.Contour) error {
@@ -273,6 +778,7 @@ func (r *reconciler) ensureContourForGatewayClass(ctx context.Context, contour *
 	gc, err := objgc.Get(ctx, cli, gcRef)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to verify the existence of gatewayclass %s: %w", gcRef, err))
+		gc = nil
 	} else {
 		owned := objgc.IsController(gc)
 		if owned {
@@ -281,7 +787,19 @@ func (r *reconciler) ensureContourForGatewayClass(ctx context.Context, contour *
 			}
 		}
 	}
-	if err := status.SyncContour(ctx, cli, contour); err != nil {
+	var conditions []metav1.Condition
+	claimants, err := objcontour.GatewayClassRefsExist(ctx, cli, gcRef)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list contours referencing gatewayclass %s: %w", gcRef, err))
+	} else if claimant := objgc.Claimant(gc, claimants); claimant != nil {
+		// Only one of the contours claiming the gatewayclass is managed by it.
+		name := ""
+		if claimant.Namespace != contour.Namespace || claimant.Name != contour.Name {
+			name = claimant.Namespace + "/" + claimant.Name
+		}
+		conditions = append(conditions, status.ComputeConflictedCondition(gcRef, name))
+	}
+	if err := status.SyncContour(ctx, cli, contour, conditions...); err != nil {
 		errs = append(errs, fmt.Errorf("failed to sync status for contour %s/%s: %w", contour.Namespace, contour.Name, err))
 	} else {
 		r.log.Info("synced status for contour", "namespace", contour.Namespace, "name", contour.Name)
@@ -306,20 +824,50 @@ func (r *reconciler) ensureContourDeleted(ctx context.Context, contour *operator
 		}
 	}
 
-	switch contour.Spec.NetworkPublishing.Envoy.Type {
-	case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType:
-		handleResult("envoy service", objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour))
+	// ensure traces deleting resource with fn in a span of the reconcile.
+	ensure := func(resource string, fn func() error) {
+		handleResult(resource, tracing.Ensure(ctx, resource, fn))
 	}
 
-	handleResult("service", objsvc.EnsureContourServiceDeleted(ctx, cli, contour))
-	handleResult("daemonset", objds.EnsureDaemonSetDeleted(ctx, cli, contour))
-	handleResult("deployment", objdeploy.EnsureDeploymentDeleted(ctx, cli, contour))
-	handleResult("job", objjob.EnsureJobDeleted(ctx, cli, contour))
-	handleResult("configmap", objcm.Delete(ctx, cli, objcm.NewCfgForContour(contour)))
-	handleResult("rbac", objutil.EnsureRBACDeleted(ctx, cli, contour))
-	handleResult("namespace", objns.EnsureNamespaceDeleted(ctx, cli, contour))
+	if !contour.RetainsServices() {
+		switch contour.Spec.NetworkPublishing.Envoy.Type {
+		case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType:
+			ensure("envoy service", func() error { return objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour) })
+		case operatorv1alpha1.RoutePublishingType:
+			ensure("envoy route", func() error { return objroute.EnsureRouteDeleted(ctx, cli, contour) })
+			ensure("envoy service", func() error { return objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour) })
+		}
+		ensure("envoy http service", func() error { return objsvc.EnsureEnvoyHTTPServiceDeleted(ctx, cli, contour) })
+		ensure("additional envoy services", func() error { return objsvc.EnsureAdditionalEnvoyServicesDeleted(ctx, cli, contour) })
+		ensure("service", func() error { return objsvc.EnsureContourServiceDeleted(ctx, cli, contour) })
+	}
+
+	ensure("daemonset", func() error { return objds.EnsureDaemonSetDeleted(ctx, cli, contour) })
+	ensure("security context constraints", func() error { return objscc.EnsureSCCDeleted(ctx, cli, contour) })
+	ensure("deployment", func() error { return objdeploy.EnsureDeploymentDeleted(ctx, cli, contour) })
+	ensure("job", func() error { return objjob.EnsureJobDeleted(ctx, cli, contour) })
+	if !contour.RetainsCertificates() {
+		ensure("certificates", func() error { return objjob.EnsureCertificatesDeleted(ctx, cli, contour) })
+	}
+	ensure("default certificate", func() error { return objcert.EnsureDefaultCertificateDeleted(ctx, cli, contour) })
+	ensure("prometheusrule", func() error { return objrule.EnsurePrometheusRuleDeleted(ctx, cli, contour) })
+	ensure("vertical pod autoscalers", func() error { return objvpa.EnsureVPAsDeleted(ctx, cli, contour) })
+	ensure("configmap", func() error { return objcm.Delete(ctx, cli, objcm.NewCfgForContour(contour)) })
+	if !contour.RetainsRBAC() {
+		ensure("rbac", func() error { return objutil.EnsureRBACDeleted(ctx, cli, contour) })
+	}
+	nsErr := tracing.Ensure(ctx, "namespace", func() error {
+		return objns.EnsureNamespaceDeleted(ctx, cli, contour, r.config.OperatorNamespace)
+	})
+	handleResult("namespace", nsErr)
+	if unowned, ok := nsErr.(*objns.UnownedWorkloadsError); ok {
+		return r.reportNamespaceRemovalBlocked(ctx, contour, unowned, utilerrors.NewAggregate(errs))
+	}
 
 	if len(errs) == 0 {
+		if err := r.waitForNamespaceRemoved(ctx, contour); err != nil {
+			return err
+		}
 		if err := objcontour.EnsureFinalizerRemoved(ctx, cli, contour); err != nil {
 			errs = append(errs, fmt.Errorf("failed to remove finalizer from contour %s/%s: %w", contour.Namespace, contour.Name, err))
 		} else {
@@ -329,3 +877,44 @@ func (r *reconciler) ensureContourDeleted(ctx context.Context, contour *operator
 
 	return utilerrors.NewAggregate(errs)
 }
+
+// reportNamespaceRemovalBlocked reports the unowned workloads blocking the
+// removal of the spec namespace of contour with the NamespaceRemovalBlocked
+// condition and a warning event, returning err as a retryable error so that
+// the finalizer of contour is kept until the workloads are removed or the
+// namespace is retained.
+func (r *reconciler) reportNamespaceRemovalBlocked(ctx context.Context, contour *operatorv1alpha1.Contour,
+	unowned *objns.UnownedWorkloadsError, err error) error {
+	cond := status.ComputeNamespaceRemovalBlockedCondition(unowned)
+	r.recorder.Event(contour, corev1.EventTypeWarning, operatorv1alpha1.NamespaceRemovalBlockedConditionType, cond.Message)
+	if err := status.SyncContour(ctx, r.client, contour, cond); err != nil {
+		return fmt.Errorf("failed to sync status of contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	return retryable.New(err, namespaceRemovalBlockedRetryPeriod)
+}
+
+// waitForNamespaceRemoved returns a retryable error while the spec namespace
+// removed on deletion of contour is terminating, so that the finalizer of
+// contour is kept until the namespace is gone. A namespace terminating for
+// longer than namespaceTerminatingTimeout is reported on contour with the
+// NamespaceTerminating condition and a warning event, and no longer keeps the
+// finalizer, since the finalizers of other controllers blocking the namespace
+// may never be removed.
+func (r *reconciler) waitForNamespaceRemoved(ctx context.Context, contour *operatorv1alpha1.Contour) error {
+	ns, err := objns.Terminating(ctx, r.client, contour)
+	if err != nil || ns == nil {
+		return err
+	}
+	if time.Since(ns.DeletionTimestamp.Time) < namespaceTerminatingTimeout {
+		return retryable.New(fmt.Errorf("namespace %s of contour %s/%s is terminating", ns.Name, contour.Namespace,
+			contour.Name), namespaceTerminatingRetryPeriod)
+	}
+	cond := status.ComputeNamespaceTerminatingCondition(ns.Name, *ns.DeletionTimestamp, objns.TerminationBlockers(ns))
+	r.recorder.Event(contour, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	if err := status.SyncContour(ctx, r.client, contour, cond); err != nil {
+		return fmt.Errorf("failed to sync status of contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	r.log.Info("releasing contour with a stuck terminating namespace", "namespace", contour.Namespace,
+		"name", contour.Name, "spec-namespace", ns.Name)
+	return nil
+}
@@ -16,6 +16,7 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	objutil "github.com/projectcontour/contour-operator/internal/objects"
@@ -27,8 +28,14 @@ import (
 	objgc "github.com/projectcontour/contour-operator/internal/objects/gatewayclass"
 	objjob "github.com/projectcontour/contour-operator/internal/objects/job"
 	objns "github.com/projectcontour/contour-operator/internal/objects/namespace"
+	objroute "github.com/projectcontour/contour-operator/internal/objects/route"
+	objscc "github.com/projectcontour/contour-operator/internal/objects/scc"
 	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	"github.com/projectcontour/contour-operator/internal/operator/drain"
 	"github.com/projectcontour/contour-operator/internal/operator/status"
+	"github.com/projectcontour/contour-operator/internal/operator/tracing"
+	"github.com/projectcontour/contour-operator/internal/registry"
 	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 	"github.com/projectcontour/contour-operator/pkg/validation"
 
@@ -48,14 +55,25 @@ import (
 
 const (
 	controllerName = "gateway_controller"
+	// imageVerificationRetryPeriod is how long to wait before retrying to
+	// resolve or verify the images of a gateway.
+	imageVerificationRetryPeriod = time.Minute
 )
 
 // Config holds all the things necessary for the controller to run.
 type Config struct {
-	// ContourImage is the name of the Contour container image.
-	ContourImage string
-	// EnvoyImage is the name of the Envoy container image.
-	EnvoyImage string
+	// Defaults holds the operator defaults, i.e. the Contour and Envoy container
+	// images, which may change while the controller runs.
+	Defaults *operatorconfig.Live
+	// WatchNamespaces is the list of namespaces the operator is restricted to.
+	// If empty, the operator is not restricted to any namespaces.
+	WatchNamespaces []string
+	// DrainTimeout is how long in-flight reconciles are given to finish
+	// when the operator is stopped.
+	DrainTimeout time.Duration
+	// Images pins the Contour and Envoy images to their digest and verifies
+	// their signatures before they are rolled out, if enabled.
+	Images *registry.Pinner
 }
 
 // reconciler reconciles a Gateway object.
@@ -73,7 +91,9 @@ func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 		config: cfg,
 		log:    ctrl.Log.WithName(controllerName),
 	}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler: drain.Reconciler(tracing.Reconciler(controllerName, r), cfg.DrainTimeout),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +101,10 @@ func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 	if err := c.Watch(&source.Kind{Type: &gatewayv1alpha1.Gateway{}}, r.enqueueRequestForOwnedGateway()); err != nil {
 		return nil, err
 	}
+	// Resync all owned gateways when the operator defaults change.
+	if err := c.Watch(&source.Channel{Source: cfg.Defaults.Subscribe()}, r.enqueueRequestForAllOwnedGateways()); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
@@ -112,6 +136,34 @@ func (r *reconciler) enqueueRequestForOwnedGateway() handler.EventHandler {
 	})
 }
 
+// enqueueRequestForAllOwnedGateways returns an event handler that maps events to
+// all Gateway objects that reference a GatewayClass owned by the operator.
+func (r *reconciler) enqueueRequestForAllOwnedGateways() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ client.Object) []reconcile.Request {
+		ctx := context.Background()
+		gateways := &gatewayv1alpha1.GatewayList{}
+		if err := r.client.List(ctx, gateways); err != nil {
+			r.log.Error(err, "failed to list gateways")
+			return []reconcile.Request{}
+		}
+		var requests []reconcile.Request
+		for i := range gateways.Items {
+			gw := &gateways.Items[i]
+			gc, err := objgw.ClassForGateway(ctx, r.client, gw)
+			if err != nil || gc == nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: gw.Namespace,
+					Name:      gw.Name,
+				},
+			})
+		}
+		return requests
+	})
+}
+
 func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	_ = r.log.WithValues("gateway", req.NamespacedName)
 
@@ -142,10 +194,18 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to validate gateway %s/%s: %w", gw.Namespace, gw.Name, err)
 		}
+		if err := validation.WatchedNamespace(cntr, r.config.WatchNamespaces); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to validate gateway %s/%s: %w", gw.Namespace, gw.Name, err)
+		}
 		switch {
 		case objgw.IsFinalized(gw):
+			// A retryable error, i.e. of a canary rollout in progress, requeues
+			// the gateway once the resources below are finalized.
 			if err := r.ensureGateway(ctx, gw, cntr); err != nil {
-				return ctrl.Result{}, fmt.Errorf("failed to get ensure gateway %s/%s: %w", req.Namespace, req.Name, err)
+				if _, ok := err.(retryable.Error); !ok {
+					return ctrl.Result{}, fmt.Errorf("failed to get ensure gateway %s/%s: %w", req.Namespace, req.Name, err)
+				}
+				errs = append(errs, err)
 			}
 			// The gateway is valid, so finalize dependent resources of gateway.
 			gc, err := objgc.Get(ctx, r.client, gw.Spec.GatewayClassName)
@@ -189,7 +249,12 @@ func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 	if len(errs) != 0 {
-		return ctrl.Result{}, retryable.NewMaybeRetryableAggregate(errs)
+		err := retryable.NewMaybeRetryableAggregate(errs)
+		if e, ok := err.(retryable.Error); ok {
+			r.log.Error(e, "got retryable error; requeueing", "after", e.After())
+			return ctrl.Result{RequeueAfter: e.After()}, nil
+		}
+		return ctrl.Result{}, err
 	}
 	return ctrl.Result{}, nil
 }
@@ -207,31 +272,73 @@ func (r *reconciler) ensureGateway(ctx context.Context, gw *gatewayv1alpha1.Gate
 		}
 	}
 
-	handleResult("namespace", objns.EnsureNamespace(ctx, cli, contour))
-	handleResult("rbac", objutil.EnsureRBAC(ctx, cli, contour))
+	// ensure traces ensuring resource with fn in a span of the reconcile.
+	ensure := func(resource string, fn func() error) {
+		handleResult(resource, tracing.Ensure(ctx, resource, fn))
+	}
+
+	ensure("namespace", func() error { return objns.EnsureNamespace(ctx, cli, contour) })
+	ensure("rbac", func() error { return objutil.EnsureRBAC(ctx, cli, contour) })
 
 	if len(errs) > 0 {
 		return retryable.NewMaybeRetryableAggregate(errs)
 	}
 
 	// configmap error/logging messages are different, hence not using handleResult
-	if err := objcm.Ensure(ctx, cli, objcm.NewCfgForGateway(gw)); err != nil {
+	err := tracing.Ensure(ctx, "configmap", func() error { return objcm.Ensure(ctx, cli, objcm.NewCfgForGateway(gw)) })
+	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to ensure configmap for gateway %s/%s: %w", gw.Namespace, gw.Name, err))
 	} else {
 		r.log.Info("ensured configmap for gateway", "namespace", gw.Namespace, "name", gw.Name)
 	}
 
-	contourImage := r.config.ContourImage
-	envoyImage := r.config.EnvoyImage
+	contourImage := r.config.Defaults.Mirror(r.config.Defaults.ContourImage())
+	envoyImage := r.config.Defaults.Mirror(r.config.Defaults.EnvoyImage())
+	if r.config.Images.Enabled() {
+		contourImage, envoyImage, err = r.config.Images.PinImages(ctx, contourImage, envoyImage)
+		if err != nil {
+			// Fail the rollout instead of rolling out images that can not be verified.
+			errs = append(errs, retryable.New(fmt.Errorf("failed to verify images for gateway %s/%s: %w",
+				gw.Namespace, gw.Name, err), imageVerificationRetryPeriod))
+			return retryable.NewMaybeRetryableAggregate(errs)
+		}
+	}
 
-	handleResult("job", objjob.EnsureJob(ctx, cli, contour, contourImage))
-	handleResult("deployment", objdeploy.EnsureDeployment(ctx, cli, contour, contourImage))
-	handleResult("daemonset", objds.EnsureDaemonSet(ctx, cli, contour, contourImage, envoyImage))
-	handleResult("contour service", objsvc.EnsureContourService(ctx, cli, contour))
+	// A finished certgen Job kept until its TTL expires requeues the gateway
+	// with a retryable error, which must not be wrapped.
+	err = tracing.Ensure(ctx, "job", func() error { return objjob.EnsureJob(ctx, cli, contour, contourImage) })
+	if _, ok := err.(retryable.Error); ok {
+		errs = append(errs, err)
+	} else {
+		handleResult("job", err)
+	}
+	ensure("deployment", func() error { return objdeploy.EnsureDeployment(ctx, cli, contour, contourImage) })
+	ensure("security context constraints", func() error { return objscc.EnsureSCC(ctx, cli, contour) })
+	// A change of the Envoy pod template deferred to a maintenance window and a
+	// canary rollout that waits for updated pods requeue the gateway with a
+	// retryable error, which must not be wrapped.
+	err = tracing.Ensure(ctx, "daemonset", func() error {
+		return objds.EnsureDaemonSet(ctx, cli, contour, contourImage, envoyImage)
+	})
+	if _, ok := err.(retryable.Error); ok {
+		errs = append(errs, err)
+	} else {
+		handleResult("daemonset", err)
+	}
+	err = tracing.Ensure(ctx, "envoy rollout", func() error { return objds.EnsureRollout(ctx, cli, contour) })
+	if _, ok := err.(retryable.Error); ok {
+		errs = append(errs, err)
+	} else {
+		handleResult("envoy rollout", err)
+	}
+	ensure("contour service", func() error { return objsvc.EnsureContourService(ctx, cli, contour) })
 
 	switch contour.Spec.NetworkPublishing.Envoy.Type {
 	case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType:
-		handleResult("envoy service", objsvc.EnsureEnvoyService(ctx, cli, contour))
+		ensure("envoy service", func() error { return objsvc.EnsureEnvoyService(ctx, cli, contour) })
+	case operatorv1alpha1.RoutePublishingType:
+		ensure("envoy service", func() error { return objsvc.EnsureEnvoyService(ctx, cli, contour) })
+		ensure("envoy route", func() error { return objroute.EnsureRoute(ctx, cli, contour) })
 	}
 
 	return retryable.NewMaybeRetryableAggregate(errs)
@@ -255,17 +362,26 @@ func (r *reconciler) ensureGatewayDeleted(ctx context.Context, gw *gatewayv1alph
 		}
 	}
 
+	// ensure traces deleting resource with fn in a span of the reconcile.
+	ensure := func(resource string, fn func() error) {
+		handleResult(resource, tracing.Ensure(ctx, resource, fn))
+	}
+
 	switch contour.Spec.NetworkPublishing.Envoy.Type {
 	case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType:
-		handleResult("envoy service", objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour))
+		ensure("envoy service", func() error { return objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour) })
+	case operatorv1alpha1.RoutePublishingType:
+		ensure("envoy route", func() error { return objroute.EnsureRouteDeleted(ctx, cli, contour) })
+		ensure("envoy service", func() error { return objsvc.EnsureEnvoyServiceDeleted(ctx, cli, contour) })
 	}
 
-	handleResult("contour service", objsvc.EnsureContourServiceDeleted(ctx, cli, contour))
-	handleResult("daemonset", objds.EnsureDaemonSetDeleted(ctx, cli, contour))
-	handleResult("deployment", objdeploy.EnsureDeploymentDeleted(ctx, cli, contour))
-	handleResult("job", objjob.EnsureJobDeleted(ctx, cli, contour))
-	handleResult("configmap", objcm.Delete(ctx, cli, objcm.NewCfgForGateway(gw)))
-	handleResult("rbac", objutil.EnsureRBACDeleted(ctx, cli, contour))
+	ensure("contour service", func() error { return objsvc.EnsureContourServiceDeleted(ctx, cli, contour) })
+	ensure("daemonset", func() error { return objds.EnsureDaemonSetDeleted(ctx, cli, contour) })
+	ensure("security context constraints", func() error { return objscc.EnsureSCCDeleted(ctx, cli, contour) })
+	ensure("deployment", func() error { return objdeploy.EnsureDeploymentDeleted(ctx, cli, contour) })
+	ensure("job", func() error { return objjob.EnsureJobDeleted(ctx, cli, contour) })
+	ensure("configmap", func() error { return objcm.Delete(ctx, cli, objcm.NewCfgForGateway(gw)) })
+	ensure("rbac", func() error { return objutil.EnsureRBACDeleted(ctx, cli, contour) })
 
 	if len(errs) > 0 {
 		return utilerrors.NewAggregate(errs)
@@ -16,10 +16,13 @@ package gatewayclass
 import (
 	"context"
 	"fmt"
+	"time"
 
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
 	objgc "github.com/projectcontour/contour-operator/internal/objects/gatewayclass"
+	"github.com/projectcontour/contour-operator/internal/operator/drain"
 	"github.com/projectcontour/contour-operator/internal/operator/status"
+	"github.com/projectcontour/contour-operator/internal/operator/tracing"
 	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 	"github.com/projectcontour/contour-operator/pkg/validation"
 
@@ -40,20 +43,29 @@ const (
 	controllerName = "gatewayclass_controller"
 )
 
+// Config holds all the things necessary for the controller to run.
+type Config struct {
+	// DrainTimeout is how long in-flight reconciles are given to finish
+	// when the operator is stopped.
+	DrainTimeout time.Duration
+}
+
 // Reconciler reconciles a GatewayClass object.
 type reconciler struct {
 	client client.Client
 	log    logr.Logger
 }
 
-// New creates the gatewayclass controller from mgr. The controller will be pre-configured
+// New creates the gatewayclass controller from mgr and cfg. The controller will be pre-configured
 // to watch for GatewayClass objects.
-func New(mgr manager.Manager) (controller.Controller, error) {
+func New(mgr manager.Manager, cfg Config) (controller.Controller, error) {
 	r := &reconciler{
 		client: mgr.GetClient(),
 		log:    ctrl.Log.WithName(controllerName),
 	}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler: drain.Reconciler(tracing.Reconciler(controllerName, r), cfg.DrainTimeout),
+	})
 	if err != nil {
 		return nil, err
 	}
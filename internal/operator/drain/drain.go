@@ -0,0 +1,76 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler returns a reconcile.Reconciler that runs r with a context that
+// outlives the context of the controller by timeout. When the operator is
+// stopped, the controller stops dequeuing requests and waits for in-flight
+// reconciles, so this gives them up to timeout to finish, i.e. to update
+// the status of the object being reconciled.
+func Reconciler(r reconcile.Reconciler, timeout time.Duration) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		drainCtx, cancel := Context(ctx, timeout)
+		defer cancel()
+		return r.Reconcile(drainCtx, req)
+	})
+}
+
+// Context returns a context that carries the values of parent and is done
+// timeout after parent is done, or when the returned cancel function is called.
+func Context(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(valueContext{parent})
+	var once sync.Once
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-stop:
+			return
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-stop:
+		}
+	}()
+	return ctx, func() {
+		once.Do(func() { close(stop) })
+		cancel()
+	}
+}
+
+// valueContext is a context that carries the values of its parent context,
+// i.e. the reconcile logger, but not its deadline or cancellation.
+type valueContext struct {
+	parent context.Context
+}
+
+func (valueContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (valueContext) Done() <-chan struct{}       { return nil }
+func (valueContext) Err() error                  { return nil }
+
+func (c valueContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
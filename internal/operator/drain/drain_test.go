@@ -0,0 +1,55 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testKey struct{}
+
+func TestContext(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.WithValue(context.Background(), testKey{}, "value"))
+	ctx, cancel := Context(parent, 50*time.Millisecond)
+	defer cancel()
+
+	if ctx.Value(testKey{}) != "value" {
+		t.Fatalf("expected context to carry parent values")
+	}
+
+	cancelParent()
+	select {
+	case <-ctx.Done():
+		t.Fatalf("expected context to outlive its parent")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected context to be done after the drain timeout")
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ctx, cancel := Context(context.Background(), time.Hour)
+	cancel()
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatalf("expected context to be done after cancel")
+	}
+}
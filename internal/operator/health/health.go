@@ -0,0 +1,108 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// syncTimeout is how long a check waits for an informer to sync before
+// reporting it as not ready.
+const syncTimeout = time.Second
+
+// Checker is a named health check.
+type Checker struct {
+	// Name is the name of the check, i.e. the path of the check
+	// under the readiness endpoint.
+	Name string
+	// Check is the health check.
+	Check healthz.Checker
+}
+
+// InformerSyncCheckers returns a checker for each of objs that fails until the
+// informer of the object's GVK has synced.
+func InformerSyncCheckers(informers cache.Informers, scheme *runtime.Scheme, objs ...client.Object) ([]Checker, error) {
+	var checkers []Checker
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gvk for %T: %w", obj, err)
+		}
+		checkers = append(checkers, Checker{
+			Name:  checkName(gvk),
+			Check: informerSynced(informers, gvk, obj),
+		})
+	}
+	return checkers, nil
+}
+
+// WebhookServerChecker returns a checker that fails until the webhook server
+// serves TLS at port of the local host, i.e. once its certificate is mounted.
+func WebhookServerChecker(port int) Checker {
+	addr := net.JoinHostPort("localhost", strconv.Itoa(port))
+	return Checker{
+		Name: "webhook",
+		Check: func(_ *http.Request) error {
+			dialer := &net.Dialer{Timeout: syncTimeout}
+			// Only the TLS handshake is checked, not the certificate, which is
+			// issued for the webhook Service.
+			conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+			if err != nil {
+				return fmt.Errorf("webhook server is not serving at %s: %w", addr, err)
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// informerSynced returns a healthz.Checker that returns an error if the
+// informer for obj has not synced.
+func informerSynced(informers cache.Informers, gvk schema.GroupVersionKind, obj client.Object) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), syncTimeout)
+		defer cancel()
+		informer, err := informers.GetInformer(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("failed to get informer for %s: %w", gvk, err)
+		}
+		if !informer.HasSynced() {
+			return fmt.Errorf("informer for %s has not synced", gvk)
+		}
+		return nil
+	}
+}
+
+// checkName returns the name of the informer sync check for gvk,
+// i.e. "informer-deployment.apps".
+func checkName(gvk schema.GroupVersionKind) string {
+	name := "informer-" + strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "." + gvk.Group
+	}
+	return name
+}
@@ -0,0 +1,100 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestInformerSyncCheckers(t *testing.T) {
+	testCases := []struct {
+		description string
+		synced      bool
+		expected    bool
+	}{
+		{
+			description: "informers synced",
+			synced:      true,
+			expected:    true,
+		},
+		{
+			description: "informers not synced",
+			synced:      false,
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		informers := &informertest.FakeInformers{Scheme: scheme.Scheme}
+		objs := []client.Object{&appsv1.Deployment{}, &corev1.Service{}}
+		for _, obj := range objs {
+			informer, err := informers.FakeInformerFor(obj)
+			if err != nil {
+				t.Fatalf("%q: failed with error: %#v", tc.description, err)
+			}
+			informer.Synced = tc.synced
+		}
+		checkers, err := InformerSyncCheckers(informers, scheme.Scheme, objs...)
+		if err != nil {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		names := []string{"informer-deployment.apps", "informer-service"}
+		if len(checkers) != len(names) {
+			t.Fatalf("%q: expected %d checkers, got %d", tc.description, len(names), len(checkers))
+		}
+		for i, c := range checkers {
+			if c.Name != names[i] {
+				t.Fatalf("%q: expected checker name %s, got %s", tc.description, names[i], c.Name)
+			}
+			err := c.Check(httptest.NewRequest("GET", "/readyz", nil))
+			switch {
+			case err != nil && tc.expected:
+				t.Fatalf("%q: failed with error: %#v", tc.description, err)
+			case err == nil && !tc.expected:
+				t.Fatalf("%q: expected to fail but received no error", tc.description)
+			}
+		}
+	}
+}
+
+func TestWebhookServerChecker(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	_, p, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %v", err)
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+	checker := WebhookServerChecker(port)
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	if err := checker.Check(req); err != nil {
+		t.Errorf("expected check to pass while the server is serving, got %v", err)
+	}
+	srv.Close()
+	if err := checker.Check(req); err == nil {
+		t.Error("expected check to fail once the server is closed")
+	}
+}
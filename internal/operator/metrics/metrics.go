@@ -0,0 +1,96 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MigratedContours counts the Contours whose resources were re-rendered after
+// an upgrade of the operator, by the operator version that rendered them.
+var MigratedContours = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "contour_operator_migrated_contours_total",
+	Help: "Number of Contours whose resources were re-rendered after an upgrade of the operator.",
+}, []string{"from_version"})
+
+// ContourConditions is 1 for the status of each status condition of a Contour
+// and 0 for the other statuses, i.e. to alert on
+// contour_operator_contour_condition{type="Degraded",status="True"} == 1.
+var ContourConditions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "contour_operator_contour_condition",
+	Help: "Status of the status conditions of Contours.",
+}, []string{"namespace", "name", "type", "status"})
+
+// DriftRepairs counts the updates of the resources managed for a Contour that
+// repaired fields last changed by another manager than the operator, i.e. by
+// a manual edit, by the kind of the resource.
+var DriftRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "contour_operator_drift_repairs_total",
+	Help: "Number of updates of the resources managed for Contours that repaired fields changed by other managers.",
+}, []string{"namespace", "name", "kind"})
+
+// XDSCertificateExpiry is the expiry of the first of the xDS certificates and
+// CAs generated for a Contour to expire, in seconds since the epoch, i.e. to
+// alert on contour_operator_xds_certificate_expiry_timestamp_seconds - time() < 86400.
+var XDSCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "contour_operator_xds_certificate_expiry_timestamp_seconds",
+	Help: "Expiry of the first xDS certificate of Contours to expire, in seconds since the epoch.",
+}, []string{"namespace", "name"})
+
+// conditionStatuses are the statuses of a condition.
+var conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+
+// SetContourConditions sets ContourConditions of the Contour named name in
+// namespace ns to its current conditions, deleting those of the previous
+// conditions that are no longer set.
+func SetContourConditions(ns, name string, previous, current []metav1.Condition) {
+	types := map[string]bool{}
+	for _, c := range current {
+		types[c.Type] = true
+		for _, status := range conditionStatuses {
+			value := 0.0
+			if c.Status == status {
+				value = 1
+			}
+			ContourConditions.WithLabelValues(ns, name, c.Type, string(status)).Set(value)
+		}
+	}
+	for _, c := range previous {
+		if !types[c.Type] {
+			deleteContourCondition(ns, name, c.Type)
+		}
+	}
+}
+
+// DeleteContourConditions deletes ContourConditions of the conditions of the
+// deleted Contour named name in namespace ns.
+func DeleteContourConditions(ns, name string, conditions []metav1.Condition) {
+	for _, c := range conditions {
+		deleteContourCondition(ns, name, c.Type)
+	}
+}
+
+// deleteContourCondition deletes ContourConditions of the condition of type
+// conditionType of the Contour named name in namespace ns.
+func deleteContourCondition(ns, name, conditionType string) {
+	for _, status := range conditionStatuses {
+		ContourConditions.DeleteLabelValues(ns, name, conditionType, string(status))
+	}
+}
+
+func init() {
+	crmetrics.Registry.MustRegister(MigratedContours, ContourConditions, DriftRepairs, XDSCertificateExpiry)
+}
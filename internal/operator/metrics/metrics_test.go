@@ -0,0 +1,43 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetContourConditions(t *testing.T) {
+	available := metav1.Condition{Type: "Available", Status: metav1.ConditionTrue}
+	degraded := metav1.Condition{Type: "Degraded", Status: metav1.ConditionTrue}
+	SetContourConditions("default", "contour", nil, []metav1.Condition{available, degraded})
+	if v := testutil.ToFloat64(ContourConditions.WithLabelValues("default", "contour", "Degraded", "True")); v != 1 {
+		t.Errorf("expected the status of the degraded condition to be 1, got %v", v)
+	}
+	if v := testutil.ToFloat64(ContourConditions.WithLabelValues("default", "contour", "Degraded", "False")); v != 0 {
+		t.Errorf("expected the other statuses of the degraded condition to be 0, got %v", v)
+	}
+
+	// Conditions that are no longer set are deleted.
+	SetContourConditions("default", "contour", []metav1.Condition{available, degraded}, []metav1.Condition{available})
+	if n := testutil.CollectAndCount(ContourConditions); n != 3 {
+		t.Errorf("expected 3 series of the available condition, got %d", n)
+	}
+	DeleteContourConditions("default", "contour", []metav1.Condition{available})
+	if n := testutil.CollectAndCount(ContourConditions); n != 0 {
+		t.Errorf("expected the series of the deleted contour to be deleted, got %d", n)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// metricsPath is the path metrics are served on.
+	metricsPath = "/metrics"
+	// shutdownTimeout is how long the server waits for in-flight scrapes to complete.
+	shutdownTimeout = 5 * time.Second
+	// allowedReviewTTL and deniedReviewTTL are how long the review of a bearer
+	// token that is allowed or denied access is cached, so scrapes do not send a
+	// TokenReview and a SubjectAccessReview every time.
+	allowedReviewTTL = time.Minute
+	deniedReviewTTL  = 10 * time.Second
+)
+
+// Config is the configuration of a secure metrics server.
+type Config struct {
+	// BindAddress is the TCP address the server binds to.
+	BindAddress string
+	// CertFile is the path of the PEM-encoded serving certificate.
+	CertFile string
+	// KeyFile is the path of the PEM-encoded serving certificate key.
+	KeyFile string
+	// ClientCAFile is the path of the PEM-encoded CA bundle used to verify
+	// client certificates. If set, clients presenting a certificate signed
+	// by the CA are authorized.
+	ClientCAFile string
+	// TokenAuth determines whether bearer tokens are authenticated using
+	// a TokenReview and authorized using a SubjectAccessReview for
+	// "get" on the "/metrics" non-resource URL. The reviews of a token are
+	// cached for up to a minute.
+	TokenAuth bool
+}
+
+// AuthRequired returns true if the server requires clients to authenticate.
+func (c *Config) AuthRequired() bool {
+	return c.ClientCAFile != "" || c.TokenAuth
+}
+
+// Server serves the controller-runtime metrics registry over TLS, optionally
+// requiring client certificate or bearer token authentication.
+type Server struct {
+	config Config
+	client client.Client
+	log    logr.Logger
+
+	mu sync.Mutex
+	// reviews are the cached reviews of bearer tokens, keyed by the SHA-256
+	// hash of the token.
+	reviews map[string]cachedReview
+}
+
+// cachedReview is whether a bearer token is allowed access and when the review
+// expires.
+type cachedReview struct {
+	allowed bool
+	expires time.Time
+}
+
+// NewServer returns a Server for cfg, using cli to review bearer tokens.
+func NewServer(cfg Config, cli client.Client, log logr.Logger) (*Server, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("metrics certificate and key files are required")
+	}
+	return &Server{config: cfg, client: cli, log: log, reviews: map[string]cachedReview{}}, nil
+}
+
+// Start serves metrics until ctx is done.
+func (s *Server) Start(ctx context.Context) error {
+	cert, err := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load metrics certificate: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if s.config.ClientCAFile != "" {
+		pem, err := ioutil.ReadFile(s.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read metrics client CA file %s: %w", s.config.ClientCAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in metrics client CA file %s", s.config.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		// Clients without a certificate may still authenticate with a token.
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	ln, err := net.Listen("tcp", s.config.BindAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics address %s: %w", s.config.BindAddress, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, s.authorize(promhttp.HandlerFor(crmetrics.Registry, promhttp.HandlerOpts{})))
+	srv := &http.Server{Handler: mux, TLSConfig: tlsCfg}
+
+	errChan := make(chan error, 1)
+	go func() {
+		s.log.Info("serving metrics over TLS", "address", ln.Addr().String(), "auth", s.config.AuthRequired())
+		if err := srv.ServeTLS(ln, "", ""); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NeedLeaderElection returns false since every operator replica serves metrics.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// authorize wraps next, rejecting requests that fail authentication when
+// authentication is required.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.config.AuthRequired() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// A verified client certificate is sufficient.
+		if s.config.ClientCAFile != "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.config.TokenAuth {
+			token := bearerToken(r)
+			if token == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			allowed, err := s.tokenAllowed(r.Context(), token)
+			if err != nil {
+				s.log.Error(err, "failed to review metrics token")
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// tokenAllowed returns true if token authenticates a user that is authorized to
+// get the metrics path. Reviews are cached for allowedReviewTTL or
+// deniedReviewTTL; failed reviews are not cached.
+func (s *Server) tokenAllowed(ctx context.Context, token string) (bool, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.reviews[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.allowed, nil
+	}
+	allowed, err := s.reviewToken(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	ttl := deniedReviewTTL
+	if allowed {
+		ttl = allowedReviewTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reviews == nil {
+		s.reviews = map[string]cachedReview{}
+	}
+	for k, review := range s.reviews {
+		if !now.Before(review.expires) {
+			delete(s.reviews, k)
+		}
+	}
+	s.reviews[key] = cachedReview{allowed: allowed, expires: now.Add(ttl)}
+	return allowed, nil
+}
+
+// reviewToken returns true if token authenticates a user that is authorized to
+// get the metrics path, using a TokenReview and a SubjectAccessReview.
+func (s *Server) reviewToken(ctx context.Context, token string) (bool, error) {
+	tr := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token}}
+	if err := s.client.Create(ctx, tr); err != nil {
+		return false, fmt.Errorf("failed to create token review: %w", err)
+	}
+	if !tr.Status.Authenticated {
+		return false, nil
+	}
+	extra := map[string]authzv1.ExtraValue{}
+	for k, v := range tr.Status.User.Extra {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+	sar := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			User:   tr.Status.User.Username,
+			UID:    tr.Status.User.UID,
+			Groups: tr.Status.User.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authzv1.NonResourceAttributes{
+				Path: metricsPath,
+				Verb: "get",
+			},
+		},
+	}
+	if err := s.client.Create(ctx, sar); err != nil {
+		return false, fmt.Errorf("failed to create subject access review: %w", err)
+	}
+	return sar.Status.Allowed, nil
+}
+
+// bearerToken returns the bearer token of r, or an empty string if r does not
+// provide one.
+func bearerToken(r *http.Request) string {
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	parts := strings.SplitN(auth, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,160 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAuthorize(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      Config
+		header      string
+		verified    bool
+		expected    int
+	}{
+		{
+			description: "no authentication required",
+			config:      Config{},
+			expected:    http.StatusOK,
+		},
+		{
+			description: "verified client certificate",
+			config:      Config{ClientCAFile: "ca.pem"},
+			verified:    true,
+			expected:    http.StatusOK,
+		},
+		{
+			description: "client certificate required but not provided",
+			config:      Config{ClientCAFile: "ca.pem"},
+			expected:    http.StatusUnauthorized,
+		},
+		{
+			description: "token required but not provided",
+			config:      Config{TokenAuth: true},
+			expected:    http.StatusUnauthorized,
+		},
+		{
+			description: "token required but basic auth provided",
+			config:      Config{TokenAuth: true},
+			header:      "Basic Zm9vOmJhcg==",
+			expected:    http.StatusUnauthorized,
+		},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, tc := range testCases {
+		s := &Server{config: tc.config, log: ctrl.Log}
+		req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		if tc.verified {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+		}
+		rec := httptest.NewRecorder()
+		s.authorize(ok).ServeHTTP(rec, req)
+		if rec.Code != tc.expected {
+			t.Fatalf("%q: expected status %d, got %d", tc.description, tc.expected, rec.Code)
+		}
+	}
+}
+
+// reviewClient allows the token "allowed" and counts the reviews it creates.
+type reviewClient struct {
+	client.Client
+	reviews int
+}
+
+func (c *reviewClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.reviews++
+	switch review := obj.(type) {
+	case *authnv1.TokenReview:
+		review.Status.Authenticated = true
+		review.Status.User.Username = review.Spec.Token
+	case *authzv1.SubjectAccessReview:
+		review.Status.Allowed = review.Spec.User == "allowed"
+	}
+	return nil
+}
+
+func TestTokenAllowedCache(t *testing.T) {
+	cli := &reviewClient{}
+	s := &Server{config: Config{TokenAuth: true}, client: cli, log: ctrl.Log}
+	for _, tc := range []struct {
+		token   string
+		allowed bool
+		reviews int
+	}{
+		{token: "allowed", allowed: true, reviews: 2},
+		{token: "allowed", allowed: true, reviews: 2},
+		{token: "denied", allowed: false, reviews: 4},
+		{token: "denied", allowed: false, reviews: 4},
+	} {
+		allowed, err := s.tokenAllowed(context.Background(), tc.token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != tc.allowed || cli.reviews != tc.reviews {
+			t.Fatalf("%q: expected allowed %t after %d reviews, got %t after %d reviews", tc.token,
+				tc.allowed, tc.reviews, allowed, cli.reviews)
+		}
+	}
+
+	// Expired reviews are reviewed again.
+	for key, review := range s.reviews {
+		review.expires = time.Now().Add(-time.Second)
+		s.reviews[key] = review
+	}
+	if _, err := s.tokenAllowed(context.Background(), "allowed"); err != nil {
+		t.Fatal(err)
+	}
+	if cli.reviews != 6 {
+		t.Fatalf("expected an expired review to be reviewed again, got %d reviews", cli.reviews)
+	}
+	if len(s.reviews) != 1 {
+		t.Fatalf("expected expired reviews to be removed, got %d reviews", len(s.reviews))
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	testCases := map[string]string{
+		"":                "",
+		"Bearer":          "",
+		"Bearer abc":      "abc",
+		"bearer  abc ":    "abc",
+		"Basic Zm9vOmJhc": "",
+	}
+	for header, expected := range testCases {
+		req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+		req.Header.Set("Authorization", header)
+		if actual := bearerToken(req); actual != expected {
+			t.Fatalf("%q: expected token %q, got %q", header, expected, actual)
+		}
+	}
+}
@@ -16,27 +16,51 @@ package operator
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcrd "github.com/projectcontour/contour-operator/internal/objects/crd"
 	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
 	contourcontroller "github.com/projectcontour/contour-operator/internal/operator/controller/contour"
 	gwcontroller "github.com/projectcontour/contour-operator/internal/operator/controller/gateway"
 	gccontroller "github.com/projectcontour/contour-operator/internal/operator/controller/gatewayclass"
+	"github.com/projectcontour/contour-operator/internal/operator/health"
+	"github.com/projectcontour/contour-operator/internal/operator/metrics"
+	"github.com/projectcontour/contour-operator/internal/operator/tracing"
+	"github.com/projectcontour/contour-operator/internal/operator/webhook"
+	"github.com/projectcontour/contour-operator/internal/registry"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
 const (
 	operatorName = "contour_operator"
+	// registryTimeout is how long a request to an image registry may take when
+	// image digests are resolved or signatures verified.
+	registryTimeout = 30 * time.Second
+	// tracingFlushTimeout is how long the spans that are not exported yet are
+	// given to be flushed when the operator is stopped.
+	tracingFlushTimeout = 5 * time.Second
 )
 
 // Clients holds the API clients required by Operator.
@@ -50,17 +74,27 @@ type Client struct {
 // them together. Operator knows what specific resource types should produce
 // operator events.
 type Operator struct {
-	client  Client
-	manager manager.Manager
-	log     logr.Logger
+	client   Client
+	manager  manager.Manager
+	defaults *operatorconfig.Live
+	log      logr.Logger
+	// contourController is the contour controller, or nil if it is disabled.
+	contourController controller.Controller
+	// images pins and verifies the images of the contours of both the contour
+	// and gateway controllers.
+	images *registry.Pinner
 }
 
 // +kubebuilder:rbac:groups=operator.projectcontour.io,resources=contours,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=operator.projectcontour.io,resources=contours/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.projectcontour.io,resources=contours/finalizers,verbs=update
 // cert-gen needs create/update secrets.
 // +kubebuilder:rbac:groups="",resources=namespaces;secrets;serviceaccounts;services,verbs=get;list;watch;delete;create;update
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;delete;create;update
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=networking.x-k8s.io,resources=gatewayclasses;gateways;backendpolicies;httproutes;tlsroutes,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=networking.x-k8s.io,resources=gatewayclasses/status;gateways/status;backendpolicies/status;httproutes/status;tlsroutes/status,verbs=create;get;update
@@ -70,36 +104,183 @@ type Operator struct {
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses;ingressclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/status,verbs=create;get;update
 // +kubebuilder:rbac:groups=projectcontour.io,resources=httpproxies;tlscertificatedelegations;extensionservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=projectcontour.io,resources=tlscertificatedelegations,verbs=create;update;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=projectcontour.io,resources=httpproxies/status;extensionservices/status,verbs=create;get;update
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings;roles;rolebindings,verbs=get;list;delete;create;update;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;delete;create;update
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;delete;create;update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;delete;create;update
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;delete;create;update
-// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+// +kubebuilder:rbac:groups=apps,resources=replicasets;controllerrevisions,verbs=get;list;watch
+// The workloads of a spec namespace are listed before the namespace is removed.
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=list
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=list
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=list
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;delete;create;update
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create;update
+// The operator must be able to use the SCCs it grants to Envoy.
+// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;delete;create;update;use
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;create;update
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // New creates a new operator from cliCfg and opCfg.
 func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
+	// Pods and nodes are only read during canary Envoy rollouts, which does not
+	// justify caching every pod and node of the cluster.
 	nonCached := []client.Object{&operatorv1alpha1.Contour{}, &gatewayv1alpha1.GatewayClass{},
-		&gatewayv1alpha1.Gateway{}, &apiextensionsv1.CustomResourceDefinition{}}
+		&gatewayv1alpha1.Gateway{}, &apiextensionsv1.CustomResourceDefinition{}, &corev1.Pod{}, &corev1.Node{}}
 	mgrOpts := manager.Options{
-		Scheme:                GetOperatorScheme(),
-		LeaderElection:        opCfg.LeaderElection,
-		LeaderElectionID:      opCfg.LeaderElectionID,
-		MetricsBindAddress:    opCfg.MetricsBindAddress,
-		ClientDisableCacheFor: nonCached,
+		Scheme:                 GetOperatorScheme(),
+		LeaderElection:         opCfg.LeaderElection,
+		LeaderElectionID:       opCfg.LeaderElectionID,
+		MetricsBindAddress:     opCfg.MetricsBindAddress,
+		HealthProbeBindAddress: opCfg.HealthProbeBindAddress,
+		// Give controllers time to drain in-flight reconciles when stopped.
+		GracefulShutdownTimeout: &opCfg.DrainTimeout,
 	}
+	secureMetrics := opCfg.MetricsCertFile != "" && opCfg.MetricsBindAddress != "0"
+	if secureMetrics {
+		// The manager only serves plaintext metrics, so serve them separately.
+		mgrOpts.MetricsBindAddress = "0"
+	}
+	if opCfg.LeaderElection {
+		mgrOpts.LeaderElectionNamespace = opCfg.LeaderElectionNamespace
+		if mgrOpts.LeaderElectionNamespace == "" {
+			// The manager only detects its namespace in a cluster.
+			mgrOpts.LeaderElectionNamespace = opCfg.OperatorNamespace
+		}
+		// Unset durations fall back to the manager's defaults.
+		if opCfg.LeaseDuration > 0 {
+			mgrOpts.LeaseDuration = &opCfg.LeaseDuration
+		}
+		if opCfg.RenewDeadline > 0 {
+			mgrOpts.RenewDeadline = &opCfg.RenewDeadline
+		}
+		if opCfg.RetryPeriod > 0 {
+			mgrOpts.RetryPeriod = &opCfg.RetryPeriod
+		}
+		// The operator exits once the manager stops, so the lock is safe to
+		// release early.
+		mgrOpts.LeaderElectionReleaseOnCancel = opCfg.LeaderElectionReleaseOnCancel
+	}
+	if opCfg.WebhookPort > 0 {
+		mgrOpts.Port = opCfg.WebhookPort
+		mgrOpts.CertDir = opCfg.WebhookCertDir
+	}
+	if len(opCfg.WatchNamespaces) > 0 {
+		mgrOpts.NewCache = cache.MultiNamespacedCacheBuilder(opCfg.WatchNamespaces)
+		// A namespace-scoped cache is unable to get cluster-scoped resources,
+		// so they must be read directly from the API server.
+		nonCached = append(nonCached, &corev1.Namespace{}, &rbacv1.ClusterRole{}, &rbacv1.ClusterRoleBinding{},
+			&networkingv1.IngressClass{})
+	}
+	mgrOpts.ClientDisableCacheFor = nonCached
 	mgr, err := ctrl.NewManager(cliCfg, mgrOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
 
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return nil, fmt.Errorf("failed to add healthz check: %w", err)
+	}
+
+	if secureMetrics {
+		srv, err := metrics.NewServer(metrics.Config{
+			BindAddress:  opCfg.MetricsBindAddress,
+			CertFile:     opCfg.MetricsCertFile,
+			KeyFile:      opCfg.MetricsKeyFile,
+			ClientCAFile: opCfg.MetricsClientCAFile,
+			TokenAuth:    opCfg.MetricsTokenAuth,
+		}, mgr.GetClient(), ctrl.Log.WithName(operatorName).WithName("metrics"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metrics server: %w", err)
+		}
+		if err := mgr.Add(srv); err != nil {
+			return nil, fmt.Errorf("failed to add metrics server: %w", err)
+		}
+	}
+
+	defaults := operatorconfig.NewLive(opCfg)
+	if opCfg.ConfigFile != "" {
+		watcher := &operatorconfig.FileWatcher{
+			Path: opCfg.ConfigFile,
+			Live: defaults,
+			Log:  ctrl.Log.WithName(operatorName).WithName("config"),
+		}
+		if err := watcher.Load(); err != nil {
+			return nil, err
+		}
+		if err := mgr.Add(watcher); err != nil {
+			return nil, fmt.Errorf("failed to add config file watcher: %w", err)
+		}
+	}
+
+	if opCfg.WebhookPort > 0 {
+		decoder, err := admission.NewDecoder(mgr.GetScheme())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook decoder: %w", err)
+		}
+		// The webhook server does not need leader election, so every replica
+		// serves the webhook.
+		mgr.GetWebhookServer().Register(webhook.ContourPath, &ctrlwebhook.Admission{
+			Handler: &webhook.ContourValidator{Client: mgr.GetClient(), Decoder: decoder},
+		})
+		// A replica is not ready, and is not an endpoint of the webhook Service,
+		// until it serves the webhook.
+		checker := health.WebhookServerChecker(opCfg.WebhookPort)
+		if err := mgr.AddReadyzCheck(checker.Name, checker.Check); err != nil {
+			return nil, fmt.Errorf("failed to add readyz check %s: %w", checker.Name, err)
+		}
+	}
+
+	if opCfg.SkipContourCRDs {
+		ctrl.Log.WithName(operatorName).Info("contour crd installation disabled")
+	} else {
+		// The leader installs the CRDs once it starts.
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			if err := objcrd.EnsureContourCRDs(ctx, mgr.GetClient()); err != nil {
+				return fmt.Errorf("failed to ensure contour crds: %w", err)
+			}
+			ctrl.Log.WithName(operatorName).Info("ensured contour crds")
+			return nil
+		})); err != nil {
+			return nil, fmt.Errorf("failed to add contour crd installer: %w", err)
+		}
+	}
+
+	keys, err := registry.LoadKeys(opCfg.ImageVerificationKeyFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image verification keys: %w", err)
+	}
+	creds, err := registry.LoadCredentials(opCfg.RegistryCredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry credentials: %w", err)
+	}
+	pinner := registry.NewPinner(&http.Client{Timeout: registryTimeout}, opCfg.ResolveImageDigests, keys, creds)
+
 	// Create and register the contour controller with the operator manager.
-	if _, err := contourcontroller.New(mgr, contourcontroller.Config{
-		ContourImage: opCfg.ContourImage,
-		EnvoyImage:   opCfg.EnvoyImage,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to create contour controller: %w", err)
+	var contourController controller.Controller
+	if opCfg.DisableContourController {
+		ctrl.Log.WithName(operatorName).Info("contour controller disabled")
+	} else {
+		if contourController, err = contourcontroller.New(mgr, contourcontroller.Config{
+			Defaults:          defaults,
+			WatchNamespaces:   opCfg.WatchNamespaces,
+			DrainTimeout:      opCfg.DrainTimeout,
+			KubernetesVersion: kubernetesVersion(cliCfg),
+			Images:            pinner,
+			OperatorNamespace: opCfg.OperatorNamespace,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create contour controller: %w", err)
+		}
+		if err := addInformerSyncChecks(mgr, &operatorv1alpha1.Contour{}, &appsv1.Deployment{},
+			&appsv1.DaemonSet{}); err != nil {
+			return nil, err
+		}
 	}
 
 	restMapper, err := apiutil.NewDiscoveryRESTMapper(cliCfg)
@@ -108,18 +289,34 @@ func New(cliCfg *rest.Config, opCfg *operatorconfig.Config) (*Operator, error) {
 	}
 
 	return &Operator{
-		manager: mgr,
-		client:  Client{mgr.GetClient(), restMapper},
-		log:     ctrl.Log.WithName(operatorName),
+		manager:           mgr,
+		client:            Client{mgr.GetClient(), restMapper},
+		defaults:          defaults,
+		log:               ctrl.Log.WithName(operatorName),
+		contourController: contourController,
+		images:            pinner,
 	}, nil
 }
 
 // Start creates Gateway API controllers (if configured) and starts the operator
-// synchronously until a message is received from ctx.
+// synchronously until a message is received from ctx. Once ctx is done, Start
+// returns after in-flight reconciles are drained or the drain timeout expires.
 func (o *Operator) Start(ctx context.Context, opCfg *operatorconfig.Config) error {
 	if err := o.createGatewayControllers(opCfg); err != nil {
 		return fmt.Errorf("failed to create gateway controllers: %w", err)
 	}
+	shutdownTracing, err := tracing.Setup(ctx, opCfg.TracingEndpoint, opCfg.TracingInsecure)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		// The spans of drained reconciles are flushed once the manager exits.
+		flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			o.log.Error(err, "failed to flush reconcile spans")
+		}
+	}()
 
 	errChan := make(chan error)
 	go func() {
@@ -129,29 +326,79 @@ func (o *Operator) Start(ctx context.Context, opCfg *operatorconfig.Config) erro
 	// Wait for the manager to exit or an explicit stop.
 	select {
 	case <-ctx.Done():
-		return nil
+		// The manager stops its controllers and waits for them to drain.
+		return <-errChan
 	case err := <-errChan:
 		return err
 	}
 }
 
-// createGatewayControllers creates Gateway and GatewayClass controllers.
+// createGatewayControllers creates Gateway and GatewayClass controllers unless
+// they are disabled by opCfg.
 func (o *Operator) createGatewayControllers(opCfg *operatorconfig.Config) error {
+	if opCfg.DisableGatewayControllers {
+		o.log.Info("gateway controllers disabled")
+		return nil
+	}
 	if !o.gatewayCRDsExist() {
 		o.log.Info("Gateway CRDs not found; starting operator without gateway controllers")
 	} else {
 		// Create and register the gatewayclass controller with the operator manager.
-		if _, err := gccontroller.New(o.manager); err != nil {
+		if _, err := gccontroller.New(o.manager, gccontroller.Config{DrainTimeout: opCfg.DrainTimeout}); err != nil {
 			return fmt.Errorf("failed to create gatewayclass controller: %w", err)
 		}
 		// Create and register the gateway controller with the operator manager.
 		cfg := gwcontroller.Config{
-			ContourImage: opCfg.ContourImage,
-			EnvoyImage:   opCfg.EnvoyImage,
+			Defaults:        o.defaults,
+			WatchNamespaces: opCfg.WatchNamespaces,
+			DrainTimeout:    opCfg.DrainTimeout,
+			Images:          o.images,
 		}
 		if _, err := gwcontroller.New(o.manager, cfg); err != nil {
 			return fmt.Errorf("failed to create gateway controller: %w", err)
 		}
+		if err := addInformerSyncChecks(o.manager, &gatewayv1alpha1.GatewayClass{},
+			&gatewayv1alpha1.Gateway{}); err != nil {
+			return err
+		}
+		// Reconcile contours referencing a GatewayClass created after them.
+		if o.contourController != nil {
+			if err := contourcontroller.WatchGatewayClasses(o.contourController, o.manager); err != nil {
+				return fmt.Errorf("failed to watch gatewayclasses of contour controller: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// kubernetesVersion returns the version of the cluster of cliCfg, or an empty
+// string if the version can not be discovered.
+func kubernetesVersion(cliCfg *rest.Config) string {
+	log := ctrl.Log.WithName(operatorName)
+	dc, err := discovery.NewDiscoveryClientForConfig(cliCfg)
+	if err != nil {
+		log.Error(err, "failed to create discovery client; skipping kubernetes version pre-upgrade checks")
+		return ""
+	}
+	info, err := dc.ServerVersion()
+	if err != nil {
+		log.Error(err, "failed to get kubernetes version; skipping kubernetes version pre-upgrade checks")
+		return ""
+	}
+	return info.GitVersion
+}
+
+// addInformerSyncChecks adds a readiness check to mgr for each of objs that
+// fails until the informer watching the object's GVK has synced.
+func addInformerSyncChecks(mgr manager.Manager, objs ...client.Object) error {
+	checkers, err := health.InformerSyncCheckers(mgr.GetCache(), mgr.GetScheme(), objs...)
+	if err != nil {
+		return fmt.Errorf("failed to create informer sync checks: %w", err)
+	}
+	for _, c := range checkers {
+		if err := mgr.AddReadyzCheck(c.Name, c.Check); err != nil {
+			return fmt.Errorf("failed to add readyz check %s: %w", c.Name, err)
+		}
 	}
 	return nil
 }
@@ -16,8 +16,11 @@ package status
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
+	"github.com/projectcontour/contour-operator/internal/objects/rollback"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -30,8 +33,12 @@ import (
 var clock utilclock.Clock = utilclock.RealClock{}
 
 // computeContourAvailableCondition computes the contour Available status condition
-// type based on deployment, ds, set, exists and admitted.
-func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.DaemonSet, set, exists, admitted bool) metav1.Condition {
+// type based on deployment, ds, svc, set, exists and admitted. The Envoy data
+// plane is available once all the pods of ds run the current pod template and
+// are ready, and svc, the Envoy service of a contour published by a load
+// balancer or nil, has a load balancer address.
+func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.DaemonSet, svc *corev1.Service,
+	set, exists, admitted bool) metav1.Condition {
 	switch {
 	case set:
 		switch {
@@ -75,7 +82,8 @@ func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.
 				Message: "Envoy daemonset does not exist.",
 			}
 		}
-		dsAvailable := ds.Status.NumberAvailable > 0
+		envoyUnavailable := envoyUnavailableMessage(ds, svc)
+		dsAvailable := envoyUnavailable == ""
 		for _, cond := range deployment.Status.Conditions {
 			if cond.Type != appsv1.DeploymentAvailable {
 				continue
@@ -94,7 +102,7 @@ func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.
 					Type:    operatorv1alpha1.ContourAvailableConditionType,
 					Status:  metav1.ConditionFalse,
 					Reason:  "ContourUnavailable",
-					Message: "Envoy daemonset does not have minimum availability.",
+					Message: envoyUnavailable,
 				}
 			case cond.Status == corev1.ConditionFalse:
 				if dsAvailable {
@@ -106,11 +114,10 @@ func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.
 					}
 				}
 				return metav1.Condition{
-					Type:   operatorv1alpha1.ContourAvailableConditionType,
-					Status: metav1.ConditionFalse,
-					Reason: "ContourUnavailable",
-					Message: fmt.Sprintf("Envoy daemonset does not have minimum availability. Contour %s",
-						strings.ToLower(cond.Message)),
+					Type:    operatorv1alpha1.ContourAvailableConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  "ContourUnavailable",
+					Message: fmt.Sprintf("%s Contour %s", envoyUnavailable, strings.ToLower(cond.Message)),
 				}
 			case cond.Status == corev1.ConditionUnknown:
 				return metav1.Condition{
@@ -131,6 +138,235 @@ func computeContourAvailableCondition(deployment *appsv1.Deployment, ds *appsv1.
 	}
 }
 
+// envoyUnavailableMessage returns why the Envoy data plane of ds and svc is not
+// available, or an empty string if it is available.
+func envoyUnavailableMessage(ds *appsv1.DaemonSet, svc *corev1.Service) string {
+	desired := ds.Status.DesiredNumberScheduled
+	switch {
+	case ds.Status.ObservedGeneration < ds.Generation:
+		return "Envoy daemonset update is not observed yet."
+	case desired == 0:
+		return "Envoy daemonset does not have minimum availability."
+	case ds.Status.UpdatedNumberScheduled < desired:
+		return fmt.Sprintf("Envoy daemonset has %d of %d pods updated.", ds.Status.UpdatedNumberScheduled, desired)
+	case ds.Status.NumberReady < desired:
+		return fmt.Sprintf("Envoy daemonset has %d of %d pods ready.", ds.Status.NumberReady, desired)
+	case svc != nil && len(svc.Status.LoadBalancer.Ingress) == 0:
+		return "Envoy service does not have a load balancer address."
+	}
+	return ""
+}
+
+// computeEnvoyRolloutHaltedCondition computes the contour EnvoyRolloutHalted
+// status condition type based on the canary rollout state.
+func computeEnvoyRolloutHaltedCondition(rollout *objds.RolloutStatus) metav1.Condition {
+	if rollout.Halted {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.EnvoyRolloutHaltedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "UpdatedPodUnhealthy",
+			Message: rollout.Message,
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.EnvoyRolloutHaltedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "UpdatedPodsHealthy",
+		Message: "Updated envoy pods are healthy.",
+	}
+}
+
+// computeContourDegradedCondition computes the contour Degraded status condition
+// type based on the rollbacks of deployment and ds, either of which may be nil.
+func computeContourDegradedCondition(deployment *appsv1.Deployment, ds *appsv1.DaemonSet) metav1.Condition {
+	var reasons []string
+	if deployment != nil {
+		if reason, ok := rollback.RolledBack(deployment); ok {
+			reasons = append(reasons, fmt.Sprintf("Rolled back deployment %s: %s", deployment.Name, reason))
+		}
+	}
+	if ds != nil {
+		if reason, ok := rollback.RolledBack(ds); ok {
+			reasons = append(reasons, fmt.Sprintf("Rolled back daemonset %s: %s", ds.Name, reason))
+		}
+	}
+	if len(reasons) > 0 {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.ContourDegradedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "RolledBack",
+			Message: strings.Join(reasons, " "),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.ContourDegradedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "DesiredTemplatesApplied",
+		Message: "Contour and Envoy run their desired pod templates.",
+	}
+}
+
+// ComputeUpgradeBlockedCondition computes the contour UpgradeBlocked status
+// condition type based on the failed pre-upgrade checks of the upgrade to the
+// target version.
+func ComputeUpgradeBlockedCondition(target string, problems []string) metav1.Condition {
+	if len(problems) > 0 {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.UpgradeBlockedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "PreUpgradeChecksFailed",
+			Message: fmt.Sprintf("Upgrade to Contour %s is blocked. %s", target, strings.Join(problems, " ")),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.UpgradeBlockedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "PreUpgradeChecksPassed",
+		Message: "No incompatibilities with the Contour version were detected.",
+	}
+}
+
+// ComputeImagesVerifiedCondition computes the contour ImagesVerified status
+// condition type based on the error of pinning and verifying its images.
+func ComputeImagesVerifiedCondition(err error) metav1.Condition {
+	if err != nil {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.ImagesVerifiedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "VerificationFailed",
+			Message: fmt.Sprintf("Images are not rolled out: %v.", err),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.ImagesVerifiedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Verified",
+		Message: "Images were resolved and verified.",
+	}
+}
+
+// ComputeNamespaceTerminatingCondition computes the contour NamespaceTerminating
+// status condition type of spec namespace ns terminating since the given time,
+// because of blockers.
+func ComputeNamespaceTerminatingCondition(ns string, since metav1.Time, blockers []string) metav1.Condition {
+	msg := fmt.Sprintf("Namespace %s is terminating since %s.", ns, since.UTC().Format(time.RFC3339))
+	if len(blockers) > 0 {
+		msg = fmt.Sprintf("%s Remaining: %s.", msg, strings.Join(blockers, "; "))
+	}
+	msg += " The contour is deleted without waiting for the namespace."
+	return metav1.Condition{
+		Type:    operatorv1alpha1.NamespaceTerminatingConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NamespaceStuckTerminating",
+		Message: msg,
+	}
+}
+
+// ComputeNamespaceRemovalBlockedCondition computes the contour
+// NamespaceRemovalBlocked status condition type of the error of removing the
+// spec namespace of the contour.
+func ComputeNamespaceRemovalBlockedCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:   operatorv1alpha1.NamespaceRemovalBlockedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: "UnownedWorkloads",
+		Message: fmt.Sprintf("The contour is not deleted until the workloads are removed or the namespace is retained: %v.",
+			err),
+	}
+}
+
+// ComputeCertificatesExpiringCondition computes the contour CertificatesExpiring
+// status condition type of xDS certificates expiring at expiry, which are
+// re-issued if reissued is true.
+func ComputeCertificatesExpiringCondition(expiry time.Time, reissued bool) metav1.Condition {
+	if reissued {
+		return metav1.Condition{
+			Type:   operatorv1alpha1.CertificatesExpiringConditionType,
+			Status: metav1.ConditionTrue,
+			Reason: "Reissuing",
+			Message: fmt.Sprintf("An xDS certificate expires at %s, so the certificates are re-issued.",
+				expiry.UTC().Format(time.RFC3339)),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.CertificatesExpiringConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Valid",
+		Message: fmt.Sprintf("The xDS certificates are valid until %s.", expiry.UTC().Format(time.RFC3339)),
+	}
+}
+
+// ComputeConfigRejectedCondition computes the contour ConfigRejected status
+// condition type of the error of validating the rendered Contour
+// configuration, or of a valid configuration if err is nil.
+func ComputeConfigRejectedCondition(err error) metav1.Condition {
+	if err != nil {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.ConfigRejectedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "InvalidConfig",
+			Message: fmt.Sprintf("The configuration is not rolled out: %v.", err),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.ConfigRejectedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ValidConfig",
+		Message: "The configuration is valid.",
+	}
+}
+
+// ComputeNodePortConflictCondition computes the contour NodePortConflict status
+// condition type of node port allocated to the service svc, or of the node
+// ports of the Envoy service being allocated if svc is empty.
+func ComputeNodePortConflictCondition(port int32, svc string) metav1.Condition {
+	if svc != "" {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.NodePortConflictConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NodePortAllocated",
+			Message: fmt.Sprintf("Node port %d of the Envoy service is already allocated to service %s.", port, svc),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.NodePortConflictConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NodePortsAllocated",
+		Message: "The node ports of the Envoy service are allocated.",
+	}
+}
+
+// ComputeConflictedCondition computes the contour Conflicted status condition
+// type of a contour referencing the GatewayClass named gc, which is claimed by
+// the contour claimant, or by the contour itself if claimant is empty.
+func ComputeConflictedCondition(gc, claimant string) metav1.Condition {
+	if claimant != "" {
+		return metav1.Condition{
+			Type:    operatorv1alpha1.ConflictedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "GatewayClassClaimed",
+			Message: fmt.Sprintf("GatewayClass %s is managed for contour %s.", gc, claimant),
+		}
+	}
+	return metav1.Condition{
+		Type:    operatorv1alpha1.ConflictedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "GatewayClassOwned",
+		Message: fmt.Sprintf("GatewayClass %s is managed for the contour.", gc),
+	}
+}
+
+// computeContourSuspendedCondition computes the Suspended status condition of
+// a contour in maintenance mode.
+func computeContourSuspendedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    operatorv1alpha1.SuspendedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Suspended",
+		Message: "Contour is scaled to zero and Envoy pods are removed until the suspend annotation is removed.",
+	}
+}
+
 // computeGatewayClassAdmittedCondition computes the Available status condition based
 // upon the GatewayClass status specification.
 func computeGatewayClassAdmittedCondition(owned, valid bool) metav1.Condition {
@@ -221,13 +457,53 @@ func conditionChanged(a, b metav1.Condition) bool {
 // removeGatewayCondition returns a newly created []metav1.Condition that contains all items
 // from conditions that are not equal to condition type t.
 func removeGatewayCondition(conditions []metav1.Condition, t gatewayv1alpha1.GatewayConditionType) []metav1.Condition {
+	return removeCondition(conditions, string(t))
+}
+
+// removeCondition returns conditions without the conditions of type t.
+func removeCondition(conditions []metav1.Condition, t string) []metav1.Condition {
 	var new []metav1.Condition
 	if len(conditions) > 0 {
 		for _, c := range conditions {
-			if c.Type != string(t) {
+			if c.Type != t {
 				new = append(new, c)
 			}
 		}
 	}
 	return new
 }
+
+// Transitions returns the conditions of current whose status differs from the
+// condition of the same type in previous, or that previous does not have.
+func Transitions(previous, current []metav1.Condition) []metav1.Condition {
+	var transitions []metav1.Condition
+	for _, c := range current {
+		transitioned := true
+		for _, p := range previous {
+			if p.Type == c.Type {
+				transitioned = p.Status != c.Status
+				break
+			}
+		}
+		if transitioned {
+			transitions = append(transitions, c)
+		}
+	}
+	return transitions
+}
+
+// Abnormal returns true if the status of condition c of a contour requires
+// attention, i.e. the contour is not Available or is Degraded.
+func Abnormal(c metav1.Condition) bool {
+	switch c.Type {
+	case operatorv1alpha1.ContourAvailableConditionType, operatorv1alpha1.ImagesVerifiedConditionType:
+		return c.Status == metav1.ConditionFalse
+	case operatorv1alpha1.ContourDegradedConditionType, operatorv1alpha1.EnvoyRolloutHaltedConditionType,
+		operatorv1alpha1.UpgradeBlockedConditionType, operatorv1alpha1.NamespaceTerminatingConditionType,
+		operatorv1alpha1.NamespaceRemovalBlockedConditionType, operatorv1alpha1.ConflictedConditionType,
+		operatorv1alpha1.NodePortConflictConditionType, operatorv1alpha1.ConfigRejectedConditionType,
+		operatorv1alpha1.CertificatesExpiringConditionType:
+		return c.Status == metav1.ConditionTrue
+	}
+	return false
+}
@@ -42,6 +42,8 @@ func TestComputeContourAvailableCondition(t *testing.T) {
 		description      string
 		deployConditions []appsv1.DeploymentCondition
 		dsAvailable      int32
+		dsRollingOut     bool
+		svc              *corev1.Service
 		gcSet            bool
 		gcExists         bool
 		gcAdmitted       bool
@@ -102,6 +104,46 @@ func TestComputeContourAvailableCondition(t *testing.T) {
 				Status: metav1.ConditionTrue,
 			},
 		},
+		{
+			description: "deployment available, daemonset rolling out",
+			deployConditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			},
+			dsAvailable:  int32(1),
+			dsRollingOut: true,
+			expect: metav1.Condition{
+				Type:   operatorv1alpha1.ContourAvailableConditionType,
+				Status: metav1.ConditionFalse,
+			},
+		},
+		{
+			description: "deployment available, daemonset available, load balancer pending",
+			deployConditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			},
+			dsAvailable: int32(1),
+			svc:         &corev1.Service{},
+			expect: metav1.Condition{
+				Type:   operatorv1alpha1.ContourAvailableConditionType,
+				Status: metav1.ConditionFalse,
+			},
+		},
+		{
+			description: "deployment available, daemonset available, load balancer provisioned",
+			deployConditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+			},
+			dsAvailable: int32(1),
+			svc: &corev1.Service{
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.1"}}},
+				},
+			},
+			expect: metav1.Condition{
+				Type:   operatorv1alpha1.ContourAvailableConditionType,
+				Status: metav1.ConditionTrue,
+			},
+		},
 		{
 			description: "gatewayclass set",
 			gcSet:       true,
@@ -146,11 +188,17 @@ func TestComputeContourAvailableCondition(t *testing.T) {
 				Name: fmt.Sprintf("contour-%d", i+1),
 			},
 			Status: appsv1.DaemonSetStatus{
-				NumberAvailable: tc.dsAvailable,
+				DesiredNumberScheduled: 1,
+				UpdatedNumberScheduled: tc.dsAvailable,
+				NumberReady:            tc.dsAvailable,
+				NumberAvailable:        tc.dsAvailable,
 			},
 		}
+		if tc.dsRollingOut {
+			ds.Status.UpdatedNumberScheduled = 0
+		}
 
-		actual := computeContourAvailableCondition(deploy, ds, tc.gcSet, tc.gcExists, tc.gcAdmitted)
+		actual := computeContourAvailableCondition(deploy, ds, tc.svc, tc.gcSet, tc.gcExists, tc.gcAdmitted)
 		if !apiequality.Semantic.DeepEqual(actual.Type, tc.expect.Type) ||
 			!apiequality.Semantic.DeepEqual(actual.Status, tc.expect.Status) {
 			t.Fatalf("%q: expected %#v, got %#v", tc.description, tc.expect, actual)
@@ -291,3 +339,47 @@ func TestMergeConditions(t *testing.T) {
 		}
 	}
 }
+
+func TestTransitions(t *testing.T) {
+	now := time.Now()
+	available := newCondition(operatorv1alpha1.ContourAvailableConditionType, metav1.ConditionTrue, "ContourAvailable", "", now)
+	unavailable := newCondition(operatorv1alpha1.ContourAvailableConditionType, metav1.ConditionFalse, "ContourUnavailable", "", now)
+	degraded := newCondition(operatorv1alpha1.ContourDegradedConditionType, metav1.ConditionTrue, "DeploymentRolledBack", "", now)
+	testCases := []struct {
+		description string
+		previous    []metav1.Condition
+		current     []metav1.Condition
+		expected    []string
+	}{
+		{
+			description: "no change",
+			previous:    []metav1.Condition{available},
+			current:     []metav1.Condition{available},
+		},
+		{
+			description: "status changed",
+			previous:    []metav1.Condition{available, degraded},
+			current:     []metav1.Condition{unavailable, degraded},
+			expected:    []string{operatorv1alpha1.ContourAvailableConditionType},
+		},
+		{
+			description: "condition added",
+			previous:    []metav1.Condition{available},
+			current:     []metav1.Condition{available, degraded},
+			expected:    []string{operatorv1alpha1.ContourDegradedConditionType},
+		},
+	}
+	for _, tc := range testCases {
+		var types []string
+		for _, c := range Transitions(tc.previous, tc.current) {
+			types = append(types, c.Type)
+		}
+		if !apiequality.Semantic.DeepEqual(types, tc.expected) {
+			t.Errorf("%s, expected transitions %v, got %v", tc.description, tc.expected, types)
+		}
+	}
+
+	if !Abnormal(unavailable) || !Abnormal(degraded) || Abnormal(available) {
+		t.Errorf("expected unavailable and degraded contours to be abnormal")
+	}
+}
@@ -16,7 +16,6 @@ package status
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
 	"github.com/projectcontour/contour-operator/internal/equality"
@@ -24,35 +23,52 @@ import (
 	objdeploy "github.com/projectcontour/contour-operator/internal/objects/deployment"
 	objgw "github.com/projectcontour/contour-operator/internal/objects/gateway"
 	objgc "github.com/projectcontour/contour-operator/internal/objects/gatewayclass"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
 	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
-// syncContourStatus computes the current status of contour and updates status upon
-// any changes since last sync.
-func SyncContour(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
-	var err error
+// SyncContour computes the current status of contour, including the given
+// conditions computed by the caller and the certificates rotation of contour
+// started by the caller, and updates status upon any changes since last sync.
+func SyncContour(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, conditions ...metav1.Condition) error {
 	var errs []error
-	var gcExists, admitted bool
 
 	latest := &operatorv1alpha1.Contour{}
-	key := types.NamespacedName{
-		Namespace: contour.Namespace,
-		Name:      contour.Name,
-	}
-	if err := cli.Get(ctx, key, latest); err != nil {
-		if errors.IsNotFound(err) {
-			// The contour may have been deleted during status sync.
+	err := updateStatus(ctx, cli, client.ObjectKeyFromObject(contour), latest, func() client.Object {
+		var updated *operatorv1alpha1.Contour
+		updated, errs = computeContourStatus(ctx, cli, contour, latest, conditions...)
+		if !equality.ContourStatusChanged(latest.Status, updated.Status) {
 			return nil
 		}
-		return fmt.Errorf("failed to get contour %s/%s: %w", contour.Namespace, contour.Name, err)
+		return updated
+	})
+	switch {
+	case errors.IsNotFound(err):
+		// The contour may have been deleted during status sync.
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to update contour %s/%s status: %w", contour.Namespace, contour.Name, err))
 	}
 
+	return retryable.NewMaybeRetryableAggregate(errs)
+}
+
+// computeContourStatus returns a copy of latest with the current status of
+// contour, including the given conditions, along with the errors of getting
+// the resources the status is computed from.
+func computeContourStatus(ctx context.Context, cli client.Client, contour, latest *operatorv1alpha1.Contour,
+	conditions ...metav1.Condition) (*operatorv1alpha1.Contour, []error) {
+	var err error
+	var errs []error
+	var gcExists, admitted bool
+
 	updated := latest.DeepCopy()
 
 	set := latest.GatewayClassSet()
@@ -73,6 +89,9 @@ func SyncContour(ctx context.Context, cli client.Client, contour *operatorv1alph
 		errs = append(errs, fmt.Errorf("failed to get deployment for contour %s/%s status: %w", latest.Namespace, latest.Name, err))
 	} else {
 		updated.Status.AvailableContours = deploy.Status.AvailableReplicas
+		if version := objdeploy.RunningVersion(deploy); version != "" {
+			updated.Status.Version = version
+		}
 	}
 	ds, err := objds.CurrentDaemonSet(ctx, cli, latest)
 	if err != nil {
@@ -81,59 +100,67 @@ func SyncContour(ctx context.Context, cli client.Client, contour *operatorv1alph
 		updated.Status.AvailableEnvoys = ds.Status.NumberAvailable
 	}
 
-	updated.Status.Conditions = mergeConditions(updated.Status.Conditions,
-		computeContourAvailableCondition(deploy, ds, set, gcExists, admitted))
-
-	if equality.ContourStatusChanged(latest.Status, updated.Status) {
-		if err := cli.Status().Update(ctx, updated); err != nil {
-			switch {
-			case errors.IsNotFound(err):
-				// The contour may have been deleted during status sync.
-				return retryable.NewMaybeRetryableAggregate(errs)
-			case strings.Contains(err.Error(), "the object has been modified"):
-				// Retry if the object was modified during status sync.
-				if err := SyncContour(ctx, cli, updated); err != nil {
-					errs = append(errs, fmt.Errorf("failed to update contour %s/%s status: %w", latest.Namespace,
-						latest.Name, err))
-				}
-			default:
-				errs = append(errs, fmt.Errorf("failed to update contour %s/%s status: %w", latest.Namespace,
-					latest.Name, err))
-			}
+	var svc *corev1.Service
+	if latest.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType {
+		if svc, err = objsvc.CurrentEnvoyService(ctx, cli, latest); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get envoy service for contour %s/%s status: %w", latest.Namespace,
+				latest.Name, err))
+			// The data plane is not available without a load balancer address.
+			svc = &corev1.Service{}
 		}
 	}
 
-	return retryable.NewMaybeRetryableAggregate(errs)
+	updated.Status.Conditions = mergeConditions(updated.Status.Conditions,
+		computeContourAvailableCondition(deploy, ds, svc, set, gcExists, admitted))
+	updated.Status.Conditions = mergeConditions(updated.Status.Conditions, computeContourDegradedCondition(deploy, ds))
+	rollout, err := objds.CurrentRolloutStatus(ctx, cli, latest)
+	switch {
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to get envoy rollout for contour %s/%s status: %w", latest.Namespace, latest.Name, err))
+	case rollout == nil:
+		updated.Status.Conditions = removeCondition(updated.Status.Conditions, operatorv1alpha1.EnvoyRolloutHaltedConditionType)
+	default:
+		updated.Status.Conditions = mergeConditions(updated.Status.Conditions, computeEnvoyRolloutHaltedCondition(rollout))
+	}
+
+	if !set {
+		// Only contours referencing a gatewayclass can conflict.
+		updated.Status.Conditions = removeCondition(updated.Status.Conditions, operatorv1alpha1.ConflictedConditionType)
+	}
+
+	if latest.Suspended() {
+		updated.Status.Conditions = mergeConditions(updated.Status.Conditions, computeContourSuspendedCondition())
+	} else {
+		updated.Status.Conditions = removeCondition(updated.Status.Conditions, operatorv1alpha1.SuspendedConditionType)
+	}
+
+	updated.Status.Conditions = mergeConditions(updated.Status.Conditions, conditions...)
+	if contour.Status.CertificatesRotation != "" {
+		updated.Status.CertificatesRotation = contour.Status.CertificatesRotation
+	}
+	if contour.Status.Resync != "" {
+		updated.Status.Resync = contour.Status.Resync
+	}
+
+	return updated, errs
 }
 
 // SyncGatewayClass computes the current status of gc and updates status upon
 // any changes since last sync.
 func SyncGatewayClass(ctx context.Context, cli client.Client, gc *gatewayv1alpha1.GatewayClass, owned, valid bool) error {
-	var errs []error
-
 	latest := &gatewayv1alpha1.GatewayClass{}
-	key := types.NamespacedName{
-		Namespace: gc.Namespace,
-		Name:      gc.Name,
-	}
-	if err := cli.Get(ctx, key, latest); err != nil {
-		if errors.IsNotFound(err) {
+	err := updateStatus(ctx, cli, client.ObjectKeyFromObject(gc), latest, func() client.Object {
+		updated := latest.DeepCopy()
+		updated.Status.Conditions = mergeConditions(updated.Status.Conditions, computeGatewayClassAdmittedCondition(owned, valid))
+		if !equality.GatewayClassStatusChanged(latest.Status, updated.Status) {
 			return nil
 		}
-		return fmt.Errorf("failed to sync status for gateway class %s: %w", gc.Name, err)
+		return updated
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to update gatewayclass %s status: %w", gc.Name, err)
 	}
-
-	updated := latest.DeepCopy()
-
-	updated.Status.Conditions = mergeConditions(updated.Status.Conditions, computeGatewayClassAdmittedCondition(owned, valid))
-
-	if equality.GatewayClassStatusChanged(latest.Status, updated.Status) {
-		if err := cli.Status().Update(ctx, updated); err != nil {
-			errs = append(errs, fmt.Errorf("failed to update gatewayclass %s status: %w", latest.Name, err))
-		}
-	}
-
-	return retryable.NewMaybeRetryableAggregate(errs)
+	return nil
 }
 
 // SyncGateway computes the current status of gw and updates status based on
@@ -142,17 +169,29 @@ func SyncGateway(ctx context.Context, cli client.Client, gw *gatewayv1alpha1.Gat
 	var errs []error
 
 	latest := &gatewayv1alpha1.Gateway{}
-	key := types.NamespacedName{
-		Namespace: gw.Namespace,
-		Name:      gw.Name,
-	}
-	if err := cli.Get(ctx, key, latest); err != nil {
-		if errors.IsNotFound(err) {
+	err := updateStatus(ctx, cli, client.ObjectKeyFromObject(gw), latest, func() client.Object {
+		var updated *gatewayv1alpha1.Gateway
+		updated, errs = computeGatewayStatus(ctx, cli, gw, latest)
+		if !equality.GatewayStatusChanged(latest.Status, updated.Status) {
 			return nil
 		}
-		return fmt.Errorf("failed to sync status for gateway %s/%s: %w", gw.Namespace, gw.Name, err)
+		return updated
+	})
+	switch {
+	case errors.IsNotFound(err):
+		// The gateway may have been deleted during status sync.
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to update gateway %s/%s status: %w", gw.Namespace, gw.Name, err))
 	}
 
+	return retryable.NewMaybeRetryableAggregate(errs)
+}
+
+// computeGatewayStatus returns a copy of latest with the current status of gw,
+// along with the errors of getting the resources the status is computed from.
+func computeGatewayStatus(ctx context.Context, cli client.Client, gw, latest *gatewayv1alpha1.Gateway) (*gatewayv1alpha1.Gateway, []error) {
+	var errs []error
+
 	updated := latest.DeepCopy()
 
 	gcName := latest.Spec.GatewayClassName
@@ -194,12 +233,26 @@ func SyncGateway(ctx context.Context, cli client.Client, gw *gatewayv1alpha1.Gat
 		computeGatewayReadyCondition(gcExists, gcAdmitted, cntrAvailable))
 
 	updated.Status.Addresses = []gatewayv1alpha1.GatewayAddress{}
-	if equality.GatewayStatusChanged(latest.Status, updated.Status) {
-		if err := cli.Status().Update(ctx, updated); err != nil {
-			errs = append(errs, fmt.Errorf("failed to update gateway %s/%s status: %w", latest.Namespace,
-				latest.Name, err))
-		}
-	}
 
-	return retryable.NewMaybeRetryableAggregate(errs)
+	return updated, errs
+}
+
+// updateStatus gets the latest object named key into latest and updates the
+// status of the object returned by sync, i.e. a copy of latest computed by
+// sync, unless sync returns nil because the status did not change. The status
+// is recomputed from the latest object and updated again upon a conflict, i.e.
+// if the object was modified since it was read, so that concurrent writers of
+// the object do not fail the sync.
+func updateStatus(ctx context.Context, cli client.Client, key types.NamespacedName, latest client.Object,
+	sync func() client.Object) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := cli.Get(ctx, key, latest); err != nil {
+			return err
+		}
+		updated := sync()
+		if updated == nil {
+			return nil
+		}
+		return cli.Status().Update(ctx, updated)
+	})
 }
@@ -0,0 +1,91 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+)
+
+// conflictingClient is a client whose first conflicts status updates fail
+// with a conflict, as if the object was modified concurrently.
+type conflictingClient struct {
+	client.Client
+	conflicts int
+	updates   int
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return &conflictingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	client *conflictingClient
+}
+
+func (w *conflictingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	w.client.updates++
+	if w.client.updates <= w.client.conflicts {
+		return errors.NewConflict(schema.GroupResource{Resource: "gatewayclasses"}, obj.GetName(), nil)
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestSyncGatewayClassConflict(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := gatewayv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	gc := &gatewayv1alpha1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gc"},
+		Spec:       gatewayv1alpha1.GatewayClassSpec{Controller: operatorv1alpha1.GatewayClassControllerRef},
+	}
+	cli := &conflictingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(gc).Build(), conflicts: 2}
+
+	if err := SyncGatewayClass(ctx, cli, gc, true, true); err != nil {
+		t.Fatalf("expected conflicts to be retried, got %v", err)
+	}
+	if cli.updates != 3 {
+		t.Errorf("expected 3 status updates, got %d", cli.updates)
+	}
+	latest := &gatewayv1alpha1.GatewayClass{}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(gc), latest); err != nil {
+		t.Fatal(err)
+	}
+	if len(latest.Status.Conditions) != 1 || latest.Status.Conditions[0].Status != metav1.ConditionTrue {
+		t.Errorf("expected the gatewayclass to be admitted, got %v", latest.Status.Conditions)
+	}
+
+	// Updates keep failing once the retries are exhausted.
+	cli = &conflictingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(gc).Build(), conflicts: 100}
+	if err := SyncGatewayClass(ctx, cli, gc, true, true); err == nil {
+		t.Errorf("expected an error when every update conflicts")
+	}
+}
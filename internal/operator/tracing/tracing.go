@@ -0,0 +1,111 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing traces the reconciles of the operator controllers and the
+// resources they ensure with OpenTelemetry, i.e. to diagnose slow reconciles
+// against a throttled API server.
+package tracing
+
+import (
+	"context"
+
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// tracerName is the name of the tracer of the operator.
+	tracerName = "github.com/projectcontour/contour-operator"
+	// serviceName is the service name of the spans of the operator.
+	serviceName = "contour-operator"
+)
+
+// Setup exports the spans of the operator to the OTLP/HTTP collector at
+// endpoint, i.e. "otel-collector.observability:4318", over TLS unless insecure
+// is set. If endpoint is empty, spans are not recorded. The returned function
+// flushes the spans that are not exported yet and stops exporting them.
+func Setup(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(operatorconfig.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Reconciler returns a reconcile.Reconciler that runs r in a span named after
+// controller, recording the request and the error of the reconcile.
+func Reconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		ctx, span := tracer().Start(ctx, controller+" reconcile", trace.WithAttributes(
+			attribute.String("controller", controller),
+			attribute.String("namespace", req.Namespace),
+			attribute.String("name", req.Name),
+		))
+		defer span.End()
+		result, err := r.Reconcile(ctx, req)
+		if result.RequeueAfter > 0 {
+			span.SetAttributes(attribute.String("requeue_after", result.RequeueAfter.String()))
+		}
+		end(span, err)
+		return result, err
+	})
+}
+
+// Ensure runs ensure in a child span of ctx named after resource, recording
+// the error it returns.
+func Ensure(ctx context.Context, resource string, ensure func() error) error {
+	_, span := tracer().Start(ctx, "ensure "+resource, trace.WithAttributes(attribute.String("resource", resource)))
+	defer span.End()
+	err := ensure()
+	end(span, err)
+	return err
+}
+
+// end records err, if any, as the status of span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// tracer returns the tracer of the operator from the global tracer provider,
+// which is a no-op provider unless Setup exports spans.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
@@ -0,0 +1,79 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconciler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	r := Reconciler("test_controller", reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if err := Ensure(ctx, "deployment", func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		return ctrl.Result{}, Ensure(ctx, "daemonset", func() error { return fmt.Errorf("failed") })
+	}))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "contour"}}
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Errorf("expected the error of the reconcile")
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	reconcileSpan, ok := spans["test_controller reconcile"]
+	if !ok {
+		t.Fatalf("expected a span of the reconcile, got %v", spans)
+	}
+	if reconcileSpan.Status().Code != codes.Error {
+		t.Errorf("expected the span of the reconcile to record its error")
+	}
+	for name, code := range map[string]codes.Code{"ensure deployment": codes.Unset, "ensure daemonset": codes.Error} {
+		s, ok := spans[name]
+		if !ok {
+			t.Fatalf("expected span %q, got %v", name, spans)
+		}
+		if s.Parent().SpanID() != reconcileSpan.SpanContext().SpanID() {
+			t.Errorf("expected span %q to be a child of the span of the reconcile", name)
+		}
+		if s.Status().Code != code {
+			t.Errorf("expected span %q to have status %v, got %v", name, code, s.Status().Code)
+		}
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("expected disabled tracing to shut down, got %v", err)
+	}
+}
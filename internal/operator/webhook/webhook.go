@@ -0,0 +1,81 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook serves the validating admission webhook of Contours.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/pkg/validation"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ContourPath is the path the Contour validating webhook is served at.
+const ContourPath = "/validate-operator-projectcontour-io-v1alpha1-contour"
+
+// +kubebuilder:webhook:path=/validate-operator-projectcontour-io-v1alpha1-contour,mutating=false,failurePolicy=fail,sideEffects=None,groups=operator.projectcontour.io,resources=contours,verbs=create;update,versions=v1alpha1,name=vcontour.operator.projectcontour.io,admissionReviewVersions=v1
+
+// ContourValidator denies creating and updating Contours that fail validation.
+// It is served by every operator replica, whether or not it is the leader, so
+// the webhook stays available while leadership fails over.
+type ContourValidator struct {
+	// Client reads the resources Contours are validated against.
+	Client client.Client
+	// Decoder decodes the Contours of admission requests.
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ContourValidator{}
+
+// Handle validates the Contour of req. Updates changing fields that cannot be
+// changed once a Contour is created are denied. Updates of Contours being
+// deleted or that do not change the spec are allowed, so finalizers of Contours that
+// became invalid can be removed. Validation errors caused by API server errors
+// are returned as warnings instead of denying the request, since the Contour
+// controller reports them in the status of the Contour once they persist.
+func (v *ContourValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	contour := &operatorv1alpha1.Contour{}
+	if err := v.Decoder.Decode(req, contour); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		old := &operatorv1alpha1.Contour{}
+		if err := v.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if !contour.DeletionTimestamp.IsZero() || apiequality.Semantic.DeepEqual(old.Spec, contour.Spec) {
+			return admission.Allowed("")
+		}
+		if err := validation.ContourUpdate(old, contour); err != nil {
+			return admission.Denied(fmt.Sprintf("invalid contour update: %v", err))
+		}
+	}
+	if err := validation.Contour(ctx, v.Client, contour); err != nil {
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			return admission.Allowed("").WithWarnings(fmt.Sprintf("failed to validate contour: %v", err))
+		}
+		return admission.Denied(fmt.Sprintf("invalid contour: %v", err))
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,134 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func raw(t *testing.T, contour *operatorv1alpha1.Contour) runtime.RawExtension {
+	t.Helper()
+
+	data, err := json.Marshal(contour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runtime.RawExtension{Raw: data}
+}
+
+func TestContourValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &ContourValidator{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Decoder: decoder}
+
+	valid := objcontour.New(objcontour.Config{
+		Name:        "webhook-test",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	invalid := valid.DeepCopy()
+	invalid.Spec.NetworkPublishing.Envoy.ExternalIPs = []string{"not-an-ip"}
+	deleting := invalid.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	labeled := invalid.DeepCopy()
+	labeled.Labels = map[string]string{"team": "edge"}
+	shared := valid.DeepCopy()
+	shared.Spec.Namespace.Shared = true
+
+	testCases := []struct {
+		description string
+		operation   admissionv1.Operation
+		object      *operatorv1alpha1.Contour
+		old         *operatorv1alpha1.Contour
+		expected    bool
+	}{
+		{
+			description: "create valid contour",
+			operation:   admissionv1.Create,
+			object:      valid,
+			expected:    true,
+		},
+		{
+			description: "create invalid contour",
+			operation:   admissionv1.Create,
+			object:      invalid,
+			expected:    false,
+		},
+		{
+			description: "update contour to be invalid",
+			operation:   admissionv1.Update,
+			object:      invalid,
+			old:         valid,
+			expected:    false,
+		},
+		{
+			description: "update metadata of invalid contour",
+			operation:   admissionv1.Update,
+			object:      labeled,
+			old:         invalid,
+			expected:    true,
+		},
+		{
+			description: "update contour to share its namespace",
+			operation:   admissionv1.Update,
+			object:      shared,
+			old:         valid,
+			expected:    false,
+		},
+		{
+			description: "update invalid contour being deleted",
+			operation:   admissionv1.Update,
+			object:      deleting,
+			old:         valid,
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: tc.operation,
+			Object:    raw(t, tc.object),
+		}}
+		if tc.old != nil {
+			req.OldObject = raw(t, tc.old)
+		}
+		resp := v.Handle(context.Background(), req)
+		if resp.Allowed != tc.expected {
+			t.Errorf("%q: expected allowed %t, got %t: %v", tc.description, tc.expected, resp.Allowed, resp.Result)
+		}
+	}
+}
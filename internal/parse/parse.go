@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/docker/distribution/reference"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Image parses s, returning and error if s is not a syntactically
@@ -37,6 +38,29 @@ func Image(s string) error {
 	return nil
 }
 
+// Namespaces parses s as a comma-separated list of namespace names, returning
+// an error if any name is not a valid namespace name. Empty entries and
+// duplicates are ignored.
+func Namespaces(s string) ([]string, error) {
+	var namespaces []string
+	seen := map[string]struct{}{}
+	for _, ns := range strings.Split(s, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", ns, strings.Join(errs, ", "))
+		}
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
 // StringInPodExec parses the output of cmd for expectedString executed in the specified
 // pod ns/name, returning an error if expectedString was not found.
 func StringInPodExec(ns, name, expectedString string, cmd []string) error {
@@ -14,6 +14,7 @@
 package parse
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -71,3 +72,47 @@ func TestImage(t *testing.T) {
 		}
 	}
 }
+
+func TestNamespaces(t *testing.T) {
+	testCases := []struct {
+		description string
+		namespaces  string
+		expected    []string
+		valid       bool
+	}{
+		{
+			description: "empty string",
+			namespaces:  "",
+			valid:       true,
+		},
+		{
+			description: "single namespace",
+			namespaces:  "projectcontour",
+			expected:    []string{"projectcontour"},
+			valid:       true,
+		},
+		{
+			description: "multiple namespaces with whitespace and duplicates",
+			namespaces:  "projectcontour, contour-operator,,projectcontour",
+			expected:    []string{"projectcontour", "contour-operator"},
+			valid:       true,
+		},
+		{
+			description: "invalid namespace name",
+			namespaces:  "projectcontour,Contour_Operator",
+			valid:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		namespaces, err := Namespaces(tc.namespaces)
+		switch {
+		case err != nil && tc.valid:
+			t.Fatalf("%q: %v", tc.description, err)
+		case err == nil && !tc.valid:
+			t.Fatalf("%q: expected an error but received nil", tc.description)
+		case tc.valid && !reflect.DeepEqual(namespaces, tc.expected):
+			t.Fatalf("%q: expected namespaces %v, got %v", tc.description, tc.expected, namespaces)
+		}
+	}
+}
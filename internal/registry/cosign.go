@@ -0,0 +1,143 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// signatureAnnotation is the annotation of a cosign signature layer holding
+	// the base64-encoded signature of the layer's payload.
+	signatureAnnotation = "dev.cosignproject.cosign/signature"
+	// signatureMediaType is the media type of the manifest of cosign signatures.
+	signatureMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// signatureManifest is the manifest of the cosign signatures of an image.
+type signatureManifest struct {
+	Layers []struct {
+		Digest      digest.Digest     `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// signaturePayload is the simple signing payload of a cosign signature.
+type signaturePayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// LoadKeys returns the ECDSA public keys of the PEM-encoded files at paths,
+// i.e. the "cosign.pub" files generated by "cosign generate-key-pair".
+func LoadKeys(paths []string) ([]*ecdsa.PublicKey, error) {
+	var keys []*ecdsa.PublicKey
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("key file %s does not contain a PEM-encoded public key", path)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+		}
+		ecdsaKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key file %s does not contain an ECDSA public key", path)
+		}
+		keys = append(keys, ecdsaKey)
+	}
+	return keys, nil
+}
+
+// verify returns an error unless the image named with digest dgst has a
+// cosign signature that verifies with one of the keys of p. Verified digests
+// are cached.
+func (p *Pinner) verify(ctx context.Context, named reference.Named, dgst digest.Digest) error {
+	key := reference.TrimNamed(named).String() + "@" + dgst.String()
+	p.mu.Lock()
+	_, ok := p.verified[key]
+	p.mu.Unlock()
+	if ok {
+		return nil
+	}
+	// Cosign stores the signatures of an image under the tag "<algorithm>-<hex>.sig".
+	sigTag := fmt.Sprintf("%s-%s.sig", dgst.Algorithm(), dgst.Hex())
+	data, err := p.fetch(ctx, named, "manifests/"+sigTag, []string{signatureMediaType})
+	if err != nil {
+		return fmt.Errorf("failed to get signatures of image %s: %w", key, err)
+	}
+	manifest := &signatureManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return fmt.Errorf("failed to decode signatures of image %s: %w", key, err)
+	}
+	for _, layer := range manifest.Layers {
+		sig, ok := layer.Annotations[signatureAnnotation]
+		if !ok || layer.Digest.Validate() != nil {
+			continue
+		}
+		payload, err := p.fetch(ctx, named, "blobs/"+layer.Digest.String(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to get signature payload of image %s: %w", key, err)
+		}
+		if layer.Digest.Algorithm().FromBytes(payload) != layer.Digest {
+			continue
+		}
+		if verifyPayload(payload, sig, dgst, p.keys) {
+			p.mu.Lock()
+			p.verified[key] = struct{}{}
+			p.mu.Unlock()
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s has no signature that verifies with the configured keys", key)
+}
+
+// verifyPayload returns true if payload signs dgst and sig is its signature by
+// one of keys.
+func verifyPayload(payload []byte, sig string, dgst digest.Digest, keys []*ecdsa.PublicKey) bool {
+	p := &signaturePayload{}
+	if err := json.Unmarshal(payload, p); err != nil || p.Critical.Image.DockerManifestDigest != dgst.String() {
+		return false
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256(payload)
+	for _, key := range keys {
+		if ecdsa.VerifyASN1(key, hash[:], raw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Credentials are the usernames and passwords of registries, keyed by the
+// registry API host, i.e. "registry-1.docker.io".
+type Credentials map[string]Credential
+
+// Credential is the username and password of a registry.
+type Credential struct {
+	Username string
+	Password string
+}
+
+// LoadCredentials returns the credentials of the docker config file at path,
+// i.e. the ".dockerconfigjson" key of a kubernetes.io/dockerconfigjson Secret.
+// An empty path returns no credentials.
+func LoadCredentials(path string) (Credentials, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry credentials file %s: %w", path, err)
+	}
+	creds, err := parseCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry credentials file %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// parseCredentials parses data as a docker config file.
+func parseCredentials(data []byte) (Credentials, error) {
+	var cfg struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	creds := Credentials{}
+	for server, auth := range cfg.Auths {
+		cred := Credential{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s: %w", server, err)
+			}
+			userPass := strings.SplitN(string(decoded), ":", 2)
+			if len(userPass) != 2 {
+				return nil, fmt.Errorf("invalid auth of registry %s: must be a base64-encoded "+
+					"\"username:password\"", server)
+			}
+			cred = Credential{Username: userPass[0], Password: userPass[1]}
+		}
+		creds[registryHost(server)] = cred
+	}
+	return creds, nil
+}
+
+// registryHost returns the registry API host of server, a key of the auths of a
+// docker config file, i.e. "https://index.docker.io/v1/" or "quay.io".
+func registryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	switch host {
+	case "docker.io", "index.docker.io":
+		return dockerHubRegistry
+	}
+	return host
+}
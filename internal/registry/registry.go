@@ -0,0 +1,295 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry resolves container image tags to digests and verifies
+// cosign signatures of images using the registry API. Registries are accessed
+// with the credentials of the registry, or anonymously if it has none.
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// dockerHubRegistry is the registry API host of images without a domain,
+	// i.e. "docker.io/envoyproxy/envoy".
+	dockerHubRegistry = "registry-1.docker.io"
+	// digestCacheTTL is how long the digest of a tag is cached, so reconciles
+	// do not query the registry every time.
+	digestCacheTTL = 5 * time.Minute
+	// maxResponseSize is the maximum size of a manifest or signature payload.
+	maxResponseSize = 4 << 20
+)
+
+// manifestMediaTypes are the manifest media types accepted from registries.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Pinner resolves image tags to digests and verifies the signatures of the
+// resolved images.
+type Pinner struct {
+	client  *http.Client
+	resolve bool
+	keys    []*ecdsa.PublicKey
+	creds   Credentials
+
+	mu       sync.Mutex
+	digests  map[string]cachedDigest
+	verified map[string]struct{}
+}
+
+// cachedDigest is the digest of a tag and when it was resolved.
+type cachedDigest struct {
+	digest   digest.Digest
+	resolved time.Time
+}
+
+// NewPinner returns a Pinner using client and creds. If resolve is true,
+// images are pinned to the digest of their tag. If keys is not empty, images
+// must have a cosign signature that verifies with one of keys.
+func NewPinner(client *http.Client, resolve bool, keys []*ecdsa.PublicKey, creds Credentials) *Pinner {
+	return &Pinner{
+		client:   client,
+		resolve:  resolve,
+		keys:     keys,
+		creds:    creds,
+		digests:  map[string]cachedDigest{},
+		verified: map[string]struct{}{},
+	}
+}
+
+// Enabled returns true if p resolves or verifies images.
+func (p *Pinner) Enabled() bool {
+	return p != nil && (p.resolve || len(p.keys) > 0)
+}
+
+// Pin returns image pinned to its digest if p resolves or verifies images,
+// otherwise image, returning an error if the digest can not be resolved or the
+// signature of the image does not verify.
+func (p *Pinner) Pin(ctx context.Context, image string) (string, error) {
+	if !p.Enabled() {
+		return image, nil
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse image %q: %w", image, err)
+	}
+	dgst, err := p.digest(ctx, named)
+	if err != nil {
+		return "", err
+	}
+	if len(p.keys) > 0 {
+		if err := p.verify(ctx, named, dgst); err != nil {
+			return "", err
+		}
+	}
+	// A verified image is pinned to the verified digest, since its tag may be
+	// pushed again before the image is pulled.
+	if !p.resolve && len(p.keys) == 0 {
+		return image, nil
+	}
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return "", fmt.Errorf("failed to pin image %q: %w", image, err)
+	}
+	return pinned.String(), nil
+}
+
+// PinImages returns the Contour and Envoy images pinned by Pin, or an error if
+// an image can not be resolved or its signature does not verify.
+func (p *Pinner) PinImages(ctx context.Context, contourImage, envoyImage string) (string, string, error) {
+	pinnedContour, err := p.Pin(ctx, contourImage)
+	if err != nil {
+		return "", "", err
+	}
+	pinnedEnvoy, err := p.Pin(ctx, envoyImage)
+	if err != nil {
+		return "", "", err
+	}
+	return pinnedContour, pinnedEnvoy, nil
+}
+
+// digest returns the digest of named, resolving its tag if it is not
+// referenced by digest.
+func (p *Pinner) digest(ctx context.Context, named reference.Named) (digest.Digest, error) {
+	if canonical, ok := named.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	named = reference.TagNameOnly(named)
+	key := named.String()
+	p.mu.Lock()
+	cached, ok := p.digests[key]
+	p.mu.Unlock()
+	if ok && time.Since(cached.resolved) < digestCacheTTL {
+		return cached.digest, nil
+	}
+	tag := named.(reference.Tagged).Tag()
+	resp, err := p.get(ctx, named, http.MethodHead, "manifests/"+tag, manifestMediaTypes)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve digest of image %q: %w", key, err)
+	}
+	resp.Body.Close()
+	dgst, err := digest.Parse(resp.Header.Get("Docker-Content-Digest"))
+	if err != nil {
+		return "", fmt.Errorf("registry returned an invalid digest for image %q: %w", key, err)
+	}
+	p.mu.Lock()
+	p.digests[key] = cachedDigest{digest: dgst, resolved: time.Now()}
+	p.mu.Unlock()
+	return dgst, nil
+}
+
+// fetch returns the body of the registry API path of the repository of named,
+// which must not be larger than maxResponseSize.
+func (p *Pinner) fetch(ctx context.Context, named reference.Named, path string, accept []string) ([]byte, error) {
+	resp, err := p.get(ctx, named, http.MethodGet, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxResponseSize {
+		return nil, fmt.Errorf("response to %s exceeds %d bytes", path, maxResponseSize)
+	}
+	return data, nil
+}
+
+// get sends a request with method for the registry API path of the repository
+// of named, authenticating if the registry requires it, and returns the
+// response if its status is OK.
+func (p *Pinner) get(ctx context.Context, named reference.Named, method, path string, accept []string) (*http.Response, error) {
+	registry := reference.Domain(named)
+	if registry == "docker.io" {
+		registry = dockerHubRegistry
+	}
+	target := fmt.Sprintf("https://%s/v2/%s/%s", registry, reference.Path(named), path)
+	resp, err := p.do(ctx, method, target, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		auth, err := p.authorization(ctx, registry, challenge)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = p.do(ctx, method, target, accept, auth); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s returned status %d for %s", registry, resp.StatusCode, path)
+	}
+	return resp, nil
+}
+
+// do sends a request with method for target, using auth as Authorization
+// header if it is not empty.
+func (p *Pinner) do(ctx context.Context, method, target string, accept []string, auth string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return p.client.Do(req.WithContext(ctx))
+}
+
+// authorization returns the Authorization header answering the challenge of
+// registry, using the credentials of registry for a Basic challenge or to get
+// the token of a Bearer challenge, i.e.
+// `Bearer realm="https://auth.docker.io/token",service="..."`. The token of a
+// Bearer challenge is anonymous if registry has no credentials.
+func (p *Pinner) authorization(ctx context.Context, registry, challenge string) (string, error) {
+	cred, hasCred := p.creds[registry]
+	var basic string
+	if hasCred {
+		basic = "Basic " + base64.StdEncoding.EncodeToString([]byte(cred.Username+":"+cred.Password))
+	}
+	switch {
+	case strings.HasPrefix(challenge, "Basic ") && hasCred:
+		return basic, nil
+	case strings.HasPrefix(challenge, "Basic "):
+		return "", fmt.Errorf("registry %s requires credentials", registry)
+	case !strings.HasPrefix(challenge, "Bearer "):
+		return "", fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry returned an invalid token realm %q", params["realm"])
+	}
+	query := realm.Query()
+	for _, name := range []string{"service", "scope"} {
+		if params[name] != "" {
+			query.Set(name, params[name])
+		}
+	}
+	realm.RawQuery = query.Encode()
+	resp, err := p.do(ctx, http.MethodGet, realm.String(), nil, basic)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token realm %s returned status %d", realm.Host, resp.StatusCode)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	if token == "" {
+		return "", nil
+	}
+	return "Bearer " + token, nil
+}
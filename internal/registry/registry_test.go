@@ -0,0 +1,215 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func TestPin(t *testing.T) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := digest.FromString("signed manifest")
+	unsigned := digest.FromString("unsigned manifest")
+	payload := []byte(fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":%q}}}`, signed))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, signer, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := json.Marshal(map[string]interface{}{
+		"layers": []interface{}{map[string]interface{}{
+			"digest":      digest.FromBytes(payload),
+			"annotations": map[string]string{signatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token":"anonymous"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/contour/manifests/signed":
+			w.Header().Set("Docker-Content-Digest", signed.String())
+		case "/v2/contour/manifests/unsigned":
+			w.Header().Set("Docker-Content-Digest", unsigned.String())
+		case "/v2/contour/manifests/sha256-" + signed.Hex() + ".sig":
+			w.Write(manifest)
+		case "/v2/contour/blobs/" + digest.FromBytes(payload).String():
+			w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	testCases := []struct {
+		description string
+		image       string
+		resolve     bool
+		keys        []*ecdsa.PublicKey
+		expected    string
+		expectErr   bool
+	}{
+		{
+			description: "disabled",
+			image:       host + "/contour:missing",
+			expected:    host + "/contour:missing",
+		},
+		{
+			description: "resolve tag",
+			image:       host + "/contour:unsigned",
+			resolve:     true,
+			expected:    host + "/contour@" + unsigned.String(),
+		},
+		{
+			description: "resolve missing tag",
+			image:       host + "/contour:missing",
+			resolve:     true,
+			expectErr:   true,
+		},
+		{
+			description: "verified tag is pinned",
+			image:       host + "/contour:signed",
+			keys:        []*ecdsa.PublicKey{&other.PublicKey, &signer.PublicKey},
+			expected:    host + "/contour@" + signed.String(),
+		},
+		{
+			description: "resolve and verify signed image",
+			image:       host + "/contour:signed",
+			resolve:     true,
+			keys:        []*ecdsa.PublicKey{&signer.PublicKey},
+			expected:    host + "/contour@" + signed.String(),
+		},
+		{
+			description: "verify image signed with another key",
+			image:       host + "/contour:signed",
+			keys:        []*ecdsa.PublicKey{&other.PublicKey},
+			expectErr:   true,
+		},
+		{
+			description: "verify unsigned image",
+			image:       host + "/contour:unsigned",
+			keys:        []*ecdsa.PublicKey{&signer.PublicKey},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := NewPinner(srv.Client(), tc.resolve, tc.keys, nil)
+		actual, err := p.Pin(context.Background(), tc.image)
+		switch {
+		case tc.expectErr && err == nil:
+			t.Errorf("%q: expected an error", tc.description)
+		case !tc.expectErr && err != nil:
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		case actual != tc.expected:
+			t.Errorf("%q: expected image %q, got %q", tc.description, tc.expected, actual)
+		}
+	}
+}
+
+func TestPinWithCredentials(t *testing.T) {
+	dgst := digest.FromString("private manifest")
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "robot" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"robot"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer robot" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", dgst.String())
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "https://")
+	image := host + "/private/contour:v1.15.0"
+
+	if _, err := NewPinner(srv.Client(), true, nil, nil).Pin(context.Background(), image); err == nil {
+		t.Errorf("expected an error resolving a private image without credentials")
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+	creds, err := parseCredentials([]byte(fmt.Sprintf(`{"auths":{"https://%s/v1/":{"auth":%q}}}`, host, auth)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := NewPinner(srv.Client(), true, nil, creds).Pin(context.Background(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := host + "/private/contour@" + dgst.String(); actual != expected {
+		t.Errorf("expected image %q, got %q", expected, actual)
+	}
+}
+
+func TestParseCredentials(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+	creds, err := parseCredentials([]byte(fmt.Sprintf(`{"auths":{
+		"https://index.docker.io/v1/":{"auth":%q},
+		"quay.io":{"username":"robot","password":"secret"}}}`, auth)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Credentials{
+		dockerHubRegistry: {Username: "user", Password: "pass"},
+		"quay.io":         {Username: "robot", Password: "secret"},
+	}
+	if !reflect.DeepEqual(creds, expected) {
+		t.Errorf("expected credentials %v, got %v", expected, creds)
+	}
+
+	if _, err := parseCredentials([]byte(`{"auths":{"quay.io":{"auth":"bm9jb2xvbg=="}}}`)); err == nil {
+		t.Errorf("expected an error for an auth without a password")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"github.com/docker/distribution/reference"
+)
+
+// Release is a Contour version and the Contour and Envoy images known to be
+// compatible with it.
+type Release struct {
+	// Version is the Contour version, i.e. "v1.15.1".
+	Version string
+	// ContourImage is the Contour image of the release.
+	ContourImage string
+	// EnvoyImage is the Envoy image supported by the Contour release.
+	EnvoyImage string
+	// MinKubernetesVersion is the oldest Kubernetes version supported by the
+	// release, i.e. "v1.19.0".
+	MinKubernetesVersion string
+}
+
+// releases are the Contour releases managed by the operator, in ascending
+// version order. See the Contour compatibility matrix for the supported Envoy
+// and Kubernetes versions: https://projectcontour.io/resources/compatibility-matrix/
+var releases = []Release{
+	{
+		Version:              "v1.13.1",
+		ContourImage:         "docker.io/projectcontour/contour:v1.13.1",
+		EnvoyImage:           "docker.io/envoyproxy/envoy:v1.17.1",
+		MinKubernetesVersion: "v1.18.0",
+	},
+	{
+		Version:              "v1.14.1",
+		ContourImage:         "docker.io/projectcontour/contour:v1.14.1",
+		EnvoyImage:           "docker.io/envoyproxy/envoy:v1.17.2",
+		MinKubernetesVersion: "v1.18.0",
+	},
+	{
+		Version:              "v1.15.1",
+		ContourImage:         "docker.io/projectcontour/contour:v1.15.1",
+		EnvoyImage:           "docker.io/envoyproxy/envoy:v1.18.3",
+		MinKubernetesVersion: "v1.19.0",
+	},
+}
+
+// flavorRepositories maps image flavors, i.e. "Distroless", to the image
+// repository of each flavor by the repository of the standard image. The tag
+// of an image is the same across flavors. Contour images are built from
+// scratch, so they already are distroless. Upstream Contour and Envoy publish
+// no FIPS-validated images, so the repositories of FIPS images are added here
+// once they are.
+var flavorRepositories = map[string]map[string]string{
+	"Distroless": {
+		"docker.io/projectcontour/contour": "docker.io/projectcontour/contour",
+		"docker.io/envoyproxy/envoy":       "docker.io/envoyproxy/envoy-distroless",
+	},
+	"FIPS": {},
+}
+
+// Flavored returns r with the Contour and Envoy images of flavor and true, or
+// false if r has no images of flavor. An empty or "Standard" flavor returns r.
+func (r Release) Flavored(flavor string) (Release, bool) {
+	if flavor == "" || flavor == "Standard" {
+		return r, true
+	}
+	contourImage, ok := flavorImage(r.ContourImage, flavor)
+	if !ok {
+		return Release{}, false
+	}
+	envoyImage, ok := flavorImage(r.EnvoyImage, flavor)
+	if !ok {
+		return Release{}, false
+	}
+	r.ContourImage = contourImage
+	r.EnvoyImage = envoyImage
+	return r, true
+}
+
+// flavorImage returns image of flavor and true, or false if the repository of
+// image has no images of flavor.
+func flavorImage(image, flavor string) (string, bool) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", false
+	}
+	named, ok := ref.(reference.NamedTagged)
+	if !ok {
+		return "", false
+	}
+	repository, ok := flavorRepositories[flavor][named.Name()]
+	if !ok {
+		return "", false
+	}
+	return repository + ":" + named.Tag(), true
+}
+
+// Lookup returns the Release of version and true, or false if version is not
+// a release managed by the operator.
+func Lookup(version string) (Release, bool) {
+	for _, r := range releases {
+		if r.Version == version {
+			return r, true
+		}
+	}
+	return Release{}, false
+}
+
+// LookupFlavored returns the Release of version with the images of flavor and
+// true, or false if version is not a release managed by the operator or has no
+// images of flavor.
+func LookupFlavored(version, flavor string) (Release, bool) {
+	r, ok := Lookup(version)
+	if !ok {
+		return Release{}, false
+	}
+	return r.Flavored(flavor)
+}
+
+// Versions returns the versions of the releases managed by the operator, in
+// ascending order.
+func Versions() []string {
+	var versions []string
+	for _, r := range releases {
+		versions = append(versions, r.Version)
+	}
+	return versions
+}
+
+// VersionFromImage returns the tag of image, i.e. "v1.15.1" for image
+// "docker.io/projectcontour/contour:v1.15.1", or an empty string if image
+// is invalid or has no tag.
+func VersionFromImage(image string) string {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return ""
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+	return ""
+}
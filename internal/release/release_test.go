@@ -0,0 +1,84 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	for _, v := range Versions() {
+		r, ok := Lookup(v)
+		if !ok {
+			t.Fatalf("expected release %s to exist", v)
+		}
+		if VersionFromImage(r.ContourImage) != v {
+			t.Errorf("expected contour image %s of release %s to be tagged %s", r.ContourImage, v, v)
+		}
+	}
+	if _, ok := Lookup("v0.1.0"); ok {
+		t.Errorf("expected release v0.1.0 not to exist")
+	}
+}
+
+func TestVersionFromImage(t *testing.T) {
+	testCases := map[string]string{
+		"docker.io/projectcontour/contour:v1.15.1":  "v1.15.1",
+		"registry.example.com:5000/contour:v1.14.1": "v1.14.1",
+		"docker.io/projectcontour/contour":          "",
+		"docker.io/projectcontour/contour@sha256:0123456789012345678901234567890123456789012345678901234567890123": "",
+		"Contour:$tag": "",
+	}
+	for image, expected := range testCases {
+		if actual := VersionFromImage(image); actual != expected {
+			t.Errorf("expected version %q for image %s, got %q", expected, image, actual)
+		}
+	}
+}
+
+func TestFlavored(t *testing.T) {
+	r, _ := Lookup("v1.15.1")
+	testCases := []struct {
+		flavor       string
+		expected     bool
+		contourImage string
+		envoyImage   string
+	}{
+		{
+			flavor:       "Standard",
+			expected:     true,
+			contourImage: "docker.io/projectcontour/contour:v1.15.1",
+			envoyImage:   "docker.io/envoyproxy/envoy:v1.18.3",
+		},
+		{
+			flavor:       "Distroless",
+			expected:     true,
+			contourImage: "docker.io/projectcontour/contour:v1.15.1",
+			envoyImage:   "docker.io/envoyproxy/envoy-distroless:v1.18.3",
+		},
+		{
+			flavor: "FIPS",
+		},
+	}
+	for _, tc := range testCases {
+		actual, ok := r.Flavored(tc.flavor)
+		if ok != tc.expected {
+			t.Fatalf("%s: expected flavor to exist: %t, got %t", tc.flavor, tc.expected, ok)
+		}
+		if actual.ContourImage != tc.contourImage || actual.EnvoyImage != tc.envoyImage {
+			t.Errorf("%s: expected images %s and %s, got %s and %s", tc.flavor, tc.contourImage, tc.envoyImage,
+				actual.ContourImage, actual.EnvoyImage)
+		}
+	}
+}
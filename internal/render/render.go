@@ -0,0 +1,354 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render renders the resources the operator manages for a Contour
+// without a cluster, i.e. to review them or apply them with GitOps tools.
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objcert "github.com/projectcontour/contour-operator/internal/objects/certificate"
+	objcm "github.com/projectcontour/contour-operator/internal/objects/configmap"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
+	objdeploy "github.com/projectcontour/contour-operator/internal/objects/deployment"
+	objjob "github.com/projectcontour/contour-operator/internal/objects/job"
+	objns "github.com/projectcontour/contour-operator/internal/objects/namespace"
+	objrule "github.com/projectcontour/contour-operator/internal/objects/prometheusrule"
+	objroute "github.com/projectcontour/contour-operator/internal/objects/route"
+	objscc "github.com/projectcontour/contour-operator/internal/objects/scc"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+	objvpa "github.com/projectcontour/contour-operator/internal/objects/vpa"
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	"github.com/projectcontour/contour-operator/internal/release"
+	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
+	"github.com/projectcontour/contour-operator/pkg/validation"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// Options configures the rendering of the resources of a Contour.
+type Options struct {
+	// ContourImage and EnvoyImage are the images of a Contour without a
+	// version, i.e. the images of the operator's configuration.
+	ContourImage string
+	EnvoyImage   string
+	// RegistryMirror replaces the registry of the images, if set.
+	RegistryMirror string
+	// OpenShift determines whether or not the OpenShift resources, i.e.
+	// SecurityContextConstraints and Routes, are rendered.
+	OpenShift bool
+}
+
+// lists are the lists of the resource kinds managed for a Contour, in the
+// order they are rendered.
+func lists() []client.ObjectList {
+	return []client.ObjectList{
+		&corev1.NamespaceList{},
+		&corev1.ServiceAccountList{},
+		&rbacv1.ClusterRoleList{},
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+		&corev1.ConfigMapList{},
+		&batchv1.JobList{},
+		&appsv1.DeploymentList{},
+		&appsv1.DaemonSetList{},
+		&corev1.ServiceList{},
+	}
+}
+
+// kind is a resource kind of an optional API managed for a Contour.
+type kind struct {
+	gvk   schema.GroupVersionKind
+	scope meta.RESTScope
+}
+
+// openShiftKinds are the OpenShift resource kinds managed for a Contour.
+var openShiftKinds = []kind{
+	{objscc.GroupVersionKind, meta.RESTScopeRoot},
+	{objroute.GroupVersionKind, meta.RESTScopeNamespace},
+}
+
+// defaultCertificateKinds are the resource kinds of the default certificate of
+// a Contour.
+var defaultCertificateKinds = []kind{
+	{objcert.GroupVersionKind, meta.RESTScopeNamespace},
+	{objcert.DelegationGroupVersionKind, meta.RESTScopeNamespace},
+}
+
+// alertKinds are the resource kinds of the alerts of a Contour.
+var alertKinds = []kind{
+	{objrule.GroupVersionKind, meta.RESTScopeNamespace},
+}
+
+// vpaKinds are the resource kinds of the VerticalPodAutoscalers of a Contour.
+var vpaKinds = []kind{
+	{objvpa.GroupVersionKind, meta.RESTScopeNamespace},
+}
+
+// optionalKinds returns the resource kinds of optional APIs managed for
+// contour with opts, which are rendered last.
+func optionalKinds(contour *operatorv1alpha1.Contour, opts Options) []kind {
+	var kinds []kind
+	if contour.Spec.DefaultCertificate != nil {
+		kinds = append(kinds, defaultCertificateKinds...)
+	}
+	if contour.Spec.Alerts != nil {
+		kinds = append(kinds, alertKinds...)
+	}
+	if contour.Spec.VerticalPodAutoscaler != nil {
+		kinds = append(kinds, vpaKinds...)
+	}
+	if opts.OpenShift {
+		kinds = append(kinds, openShiftKinds...)
+	}
+	return kinds
+}
+
+// fieldManager is the field manager of the dry-run applies of Contours.
+const fieldManager = "contour-operator-render"
+
+// ReadContour decodes a Contour from the YAML or JSON document of r, returning
+// an error if the document is not a valid Contour.
+func ReadContour(r io.Reader) (*operatorv1alpha1.Contour, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contour: %w", err)
+	}
+	contour := &operatorv1alpha1.Contour{}
+	if err := yaml.UnmarshalStrict(data, contour); err != nil {
+		return nil, fmt.Errorf("failed to decode contour: %w", err)
+	}
+	gvk := operatorv1alpha1.GroupVersion.WithKind("Contour")
+	if contour.GroupVersionKind() != gvk {
+		return nil, fmt.Errorf("expected a %s, got %s", gvk, contour.GroupVersionKind())
+	}
+	return contour, nil
+}
+
+// ServerDefaults returns contour with the defaults of the Contour CRD applied
+// by the API server of cli, using a dry-run apply that changes nothing.
+func ServerDefaults(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) (*operatorv1alpha1.Contour, error) {
+	defaulted := contour.DeepCopy()
+	defaulted.SetGroupVersionKind(operatorv1alpha1.GroupVersion.WithKind("Contour"))
+	defaulted.ResourceVersion = ""
+	defaulted.ManagedFields = nil
+	defaulted.Status = operatorv1alpha1.ContourStatus{}
+	if err := cli.Patch(ctx, defaulted, client.Apply, client.DryRunAll, client.FieldOwner(fieldManager),
+		client.ForceOwnership); err != nil {
+		return nil, fmt.Errorf("failed to apply defaults to contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	return defaulted, nil
+}
+
+// step ensures a resource of a Contour.
+type step struct {
+	resource string
+	ensure   func() error
+}
+
+// Objects returns the resources the operator manages for contour, which must
+// have the defaults of the Contour CRD applied, sorted by kind, namespace and
+// name. An error is returned if contour is invalid.
+func Objects(ctx context.Context, contour *operatorv1alpha1.Contour, opts Options) ([]*unstructured.Unstructured, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	kinds := optionalKinds(contour, opts)
+	for _, k := range kinds {
+		scheme.AddKnownTypeWithName(k.gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(k.gvk.GroupVersion().WithKind(k.gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		mapper.Add(k.gvk, k.scope)
+	}
+	contour = contour.DeepCopy()
+	contour.ResourceVersion = ""
+	cli := &objutil.MappedClient{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(contour).Build(),
+		Mapper: mapper,
+	}
+
+	if err := validation.Contour(ctx, cli, contour); err != nil {
+		return nil, fmt.Errorf("invalid contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+	contour = objcontour.WithPublishingPreset(contour)
+	contourImage, envoyImage := opts.ContourImage, opts.EnvoyImage
+	if rel, ok := release.LookupFlavored(contour.Spec.Version, string(contour.Spec.ImageFlavor)); ok {
+		contourImage, envoyImage = rel.ContourImage, rel.EnvoyImage
+	}
+	contourImage = operatorconfig.MirrorImage(contourImage, opts.RegistryMirror)
+	envoyImage = operatorconfig.MirrorImage(envoyImage, opts.RegistryMirror)
+
+	// The resources are ensured in the order of the contour controller.
+	steps := []step{
+		{"namespace", func() error { return objns.EnsureNamespace(ctx, cli, contour) }},
+		{"rbac", func() error { return objutil.EnsureRBAC(ctx, cli, contour) }},
+		{"default certificate", func() error { return objcert.EnsureDefaultCertificate(ctx, cli, contour) }},
+		{"configmap", func() error { return objcm.Ensure(ctx, cli, objcm.NewCfgForContour(contour)) }},
+		{"job", func() error { return objjob.EnsureJob(ctx, cli, contour, contourImage) }},
+		{"deployment", func() error { return objdeploy.EnsureDeployment(ctx, cli, contour, contourImage) }},
+		{"security context constraints", func() error { return objscc.EnsureSCC(ctx, cli, contour) }},
+		{"daemonset", func() error { return objds.EnsureDaemonSet(ctx, cli, contour, contourImage, envoyImage) }},
+		{"envoy rollout", func() error { return objds.EnsureRollout(ctx, cli, contour) }},
+		{"contour service", func() error { return objsvc.EnsureContourService(ctx, cli, contour) }},
+		{"envoy service", func() error { return objsvc.EnsureEnvoyService(ctx, cli, contour) }},
+		{"envoy http service", func() error { return objsvc.EnsureEnvoyHTTPService(ctx, cli, contour) }},
+		{"additional envoy services", func() error { return objsvc.EnsureAdditionalEnvoyServices(ctx, cli, contour) }},
+	}
+	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.RoutePublishingType && opts.OpenShift {
+		steps = append(steps, step{"envoy route", func() error { return objroute.EnsureRoute(ctx, cli, contour) }})
+	}
+	steps = append(steps, step{"prometheusrule", func() error { return objrule.EnsurePrometheusRule(ctx, cli, contour) }},
+		step{"vertical pod autoscalers", func() error { return objvpa.EnsureVPAs(ctx, cli, contour) }})
+	for _, step := range steps {
+		// A retryable error waits for the cluster, i.e. for a canary rollout to
+		// progress, which never happens without one.
+		if err := step.ensure(); err != nil {
+			if _, ok := err.(retryable.Error); !ok {
+				return nil, fmt.Errorf("failed to render %s for contour %s/%s: %w", step.resource,
+					contour.Namespace, contour.Name, err)
+			}
+		}
+	}
+
+	var objs []*unstructured.Unstructured
+	for _, list := range lists() {
+		if err := cli.List(ctx, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		var kindObjs []*unstructured.Unstructured
+		for _, item := range items {
+			obj, err := clean(item, scheme)
+			if err != nil {
+				return nil, err
+			}
+			kindObjs = append(kindObjs, obj)
+		}
+		sortByName(kindObjs)
+		objs = append(objs, kindObjs...)
+	}
+	for _, k := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(k.gvk.GroupVersion().WithKind(k.gvk.Kind + "List"))
+		if err := cli.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj, err := clean(&list.Items[i], scheme)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// clean returns obj as an unstructured object with its kind set and without
+// the fields set by the API server, i.e. status and resourceVersion.
+func clean(obj runtime.Object, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	u.SetResourceVersion("")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u, nil
+}
+
+// Write writes objs to w as a stream of YAML documents.
+func Write(w io.Writer, objs []*unstructured.Unstructured) error {
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDir writes each of objs to a file of dir named after its position,
+// kind, namespace and name, i.e. "09-deployment-projectcontour-contour.yaml",
+// so applying the files in name order creates the resources in order.
+func WriteDir(dir string, objs []*unstructured.Unstructured) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		parts := []string{fmt.Sprintf("%02d", i+1), strings.ToLower(obj.GetKind())}
+		if obj.GetNamespace() != "" {
+			parts = append(parts, obj.GetNamespace())
+		}
+		parts = append(parts, obj.GetName())
+		path := filepath.Join(dir, strings.Join(parts, "-")+".yaml")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// sortByName sorts objs by namespace and name.
+func sortByName(objs []*unstructured.Unstructured) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+}
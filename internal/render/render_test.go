@@ -0,0 +1,91 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+)
+
+func TestObjects(t *testing.T) {
+	contour := objcontour.New(objcontour.Config{
+		Name:        "contour",
+		Namespace:   "default",
+		SpecNs:      "projectcontour",
+		Replicas:    2,
+		NetworkType: operatorv1alpha1.LoadBalancerServicePublishingType,
+	})
+	contour.Spec.Version = "v1.15.1"
+	opts := Options{RegistryMirror: "registry.example.com/mirror"}
+
+	objs, err := Objects(context.Background(), contour, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]int{}
+	for _, obj := range objs {
+		kinds[obj.GetKind()]++
+		if obj.GetResourceVersion() != "" {
+			t.Errorf("expected %s %s to have no resource version", obj.GetKind(), obj.GetName())
+		}
+		if _, ok := obj.Object["status"]; ok {
+			t.Errorf("expected %s %s to have no status", obj.GetKind(), obj.GetName())
+		}
+	}
+	for kind, expected := range map[string]int{"Namespace": 1, "Job": 1, "Deployment": 1, "DaemonSet": 1, "Service": 2} {
+		if kinds[kind] != expected {
+			t.Errorf("expected %d %s objects, got %d", expected, kind, kinds[kind])
+		}
+	}
+	if objs[0].GetKind() != "Namespace" {
+		t.Errorf("expected the namespace to be rendered first, got %s", objs[0].GetKind())
+	}
+
+	out := &bytes.Buffer{}
+	if err := Write(out, objs); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "---\n") != len(objs) {
+		t.Errorf("expected %d documents", len(objs))
+	}
+	if !strings.Contains(out.String(), "registry.example.com/mirror/projectcontour/contour:v1.15.1") {
+		t.Errorf("expected the mirrored contour image of the version to be rendered")
+	}
+
+	// Optional APIs are rendered for the contour fields that use them.
+	contour.Spec.Alerts = &operatorv1alpha1.AlertParameters{}
+	if objs, err = Objects(context.Background(), contour, opts); err != nil {
+		t.Fatal(err)
+	}
+	if last := objs[len(objs)-1]; last.GetKind() != "PrometheusRule" {
+		t.Errorf("expected the prometheusrule of the alerts to be rendered last, got %s", last.GetKind())
+	}
+	contour.Spec.VerticalPodAutoscaler = &operatorv1alpha1.VerticalPodAutoscalerParameters{}
+	if objs, err = Objects(context.Background(), contour, opts); err != nil {
+		t.Fatal(err)
+	}
+	if last := objs[len(objs)-1]; last.GetKind() != "VerticalPodAutoscaler" {
+		t.Errorf("expected the verticalpodautoscalers to be rendered last, got %s", last.GetKind())
+	}
+
+	contour.Spec.Version = "v1.2.0"
+	if _, err := Objects(context.Background(), contour, opts); err == nil {
+		t.Errorf("expected an invalid contour to fail rendering")
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report describes the state of Contours and the resources managed for
+// them in a human-friendly form, i.e. for the "status" subcommand.
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
+	objdeploy "github.com/projectcontour/contour-operator/internal/objects/deployment"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultEvents is the default number of recent events reported per Contour.
+const DefaultEvents = 10
+
+// Options selects the Contours to report on.
+type Options struct {
+	// Namespace is the namespace of the Contours. If empty, Contours of all
+	// namespaces are reported.
+	Namespace string
+	// Name is the name of the Contour. If empty, all Contours of Namespace are
+	// reported.
+	Name string
+	// Events is the maximum number of recent events reported per Contour.
+	Events int
+}
+
+// Report is the state of a Contour and the resources managed for it.
+type Report struct {
+	// Contour is the reported Contour.
+	Contour operatorv1alpha1.Contour
+	// Contours is the number of ready Contour replicas out of the desired
+	// replicas, i.e. "2/2", or "-" if the Deployment does not exist.
+	Contours string
+	// Envoys is the number of ready Envoy pods out of the scheduled pods,
+	// i.e. "3/3", or "-" if the DaemonSet does not exist.
+	Envoys string
+	// Address is the address Envoy is published at, or "-" if it is not
+	// published yet.
+	Address string
+	// Events are the most recent events of the Contour and its workloads,
+	// the newest last.
+	Events []corev1.Event
+}
+
+// Contours returns the Reports of the Contours selected by opts, sorted by
+// namespace and name.
+func Contours(ctx context.Context, cli client.Client, opts Options) ([]Report, error) {
+	contours, err := List(ctx, cli, opts)
+	if err != nil {
+		return nil, err
+	}
+	var reports []Report
+	for i := range contours {
+		r, err := report(ctx, cli, &contours[i], opts.Events)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// List returns the Contours selected by opts, sorted by namespace and name.
+func List(ctx context.Context, cli client.Client, opts Options) ([]operatorv1alpha1.Contour, error) {
+	var contours []operatorv1alpha1.Contour
+	if opts.Name != "" {
+		contour := &operatorv1alpha1.Contour{}
+		key := client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}
+		if err := cli.Get(ctx, key, contour); err != nil {
+			return nil, fmt.Errorf("failed to get contour %s/%s: %w", opts.Namespace, opts.Name, err)
+		}
+		contours = append(contours, *contour)
+	} else {
+		list := &operatorv1alpha1.ContourList{}
+		if err := cli.List(ctx, list, client.InNamespace(opts.Namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list contours: %w", err)
+		}
+		contours = list.Items
+	}
+	sort.Slice(contours, func(i, j int) bool {
+		if contours[i].Namespace != contours[j].Namespace {
+			return contours[i].Namespace < contours[j].Namespace
+		}
+		return contours[i].Name < contours[j].Name
+	})
+	return contours, nil
+}
+
+// report returns the Report of contour with at most events recent events.
+func report(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, events int) (Report, error) {
+	r := Report{Contour: *contour, Contours: "-", Envoys: "-", Address: "-"}
+	// Events of the workloads and their pods, i.e. "contour-5d8b9c7f4-x2z9k".
+	workloads := map[string]struct{}{}
+
+	deploy, err := objdeploy.CurrentDeployment(ctx, cli, contour)
+	switch {
+	case err == nil:
+		desired := int32(1)
+		if deploy.Spec.Replicas != nil {
+			desired = *deploy.Spec.Replicas
+		}
+		r.Contours = fmt.Sprintf("%d/%d", deploy.Status.ReadyReplicas, desired)
+		workloads[deploy.Name] = struct{}{}
+	case !errors.IsNotFound(err):
+		return r, fmt.Errorf("failed to get deployment for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+
+	ds, err := objds.CurrentDaemonSet(ctx, cli, contour)
+	switch {
+	case err == nil:
+		r.Envoys = fmt.Sprintf("%d/%d", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+		workloads[ds.Name] = struct{}{}
+	case !errors.IsNotFound(err):
+		return r, fmt.Errorf("failed to get daemonset for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+
+	svc, err := objsvc.CurrentEnvoyService(ctx, cli, contour)
+	switch {
+	case err == nil:
+		if address := serviceAddress(svc); address != "" {
+			r.Address = address
+		}
+		workloads[svc.Name] = struct{}{}
+	case !errors.IsNotFound(err):
+		return r, fmt.Errorf("failed to get envoy service for contour %s/%s: %w", contour.Namespace, contour.Name, err)
+	}
+
+	if r.Events, err = recentEvents(ctx, cli, contour, workloads, events); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// serviceAddress returns the address svc is published at, i.e. the ingress
+// addresses of a LoadBalancer Service, the node ports of a NodePort Service or
+// the cluster IP of a ClusterIP Service.
+func serviceAddress(svc *corev1.Service) string {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		var addresses []string
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				addresses = append(addresses, ingress.Hostname)
+			} else if ingress.IP != "" {
+				addresses = append(addresses, ingress.IP)
+			}
+		}
+		return strings.Join(addresses, ",")
+	case corev1.ServiceTypeNodePort:
+		var ports []string
+		for _, port := range svc.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d", port.NodePort))
+		}
+		return "<nodes>:" + strings.Join(ports, ",")
+	default:
+		return svc.Spec.ClusterIP
+	}
+}
+
+// recentEvents returns the limit most recent events of contour and of the
+// objects in its spec namespace named after one of workloads, the newest last.
+func recentEvents(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour,
+	workloads map[string]struct{}, limit int) ([]corev1.Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	var events []corev1.Event
+	for _, ns := range []string{contour.Namespace, contour.Spec.Namespace.Name} {
+		list := &corev1.EventList{}
+		if err := cli.List(ctx, list, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("failed to list events in namespace %s: %w", ns, err)
+		}
+		for _, e := range list.Items {
+			obj := e.InvolvedObject
+			switch {
+			case obj.Kind == "Contour" && obj.Namespace == contour.Namespace && obj.Name == contour.Name:
+			case ns == contour.Spec.Namespace.Name && ownedBy(obj.Name, workloads):
+			default:
+				continue
+			}
+			events = append(events, e)
+		}
+		if contour.Namespace == contour.Spec.Namespace.Name {
+			break
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return lastSeen(events[i]).Before(lastSeen(events[j]))
+	})
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+// ownedBy returns true if name is one of workloads or the name of one of
+// their pods or replica sets, i.e. prefixed by the name of a workload.
+func ownedBy(name string, workloads map[string]struct{}) bool {
+	for w := range workloads {
+		if name == w || strings.HasPrefix(name, w+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// lastSeen returns when e was last observed.
+func lastSeen(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+// Write writes reports to w as tables, relative to now: a summary of all
+// Contours followed by the conditions and recent events of each Contour.
+func Write(w io.Writer, reports []Report, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tAVAILABLE\tVERSION\tCONTOUR\tENVOY\tADDRESS\tAGE")
+	for _, r := range reports {
+		c := r.Contour
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Namespace, c.Name,
+			conditionStatus(c, operatorv1alpha1.ContourAvailableConditionType), orDash(c.Status.Version),
+			r.Contours, r.Envoys, r.Address, since(c.CreationTimestamp.Time, now))
+	}
+	for _, r := range reports {
+		c := r.Contour
+		fmt.Fprintf(tw, "\nContour %s/%s\n", c.Namespace, c.Name)
+		fmt.Fprintln(tw, "CONDITION\tSTATUS\tREASON\tAGE\tMESSAGE")
+		for _, cond := range c.Status.Conditions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", cond.Type, cond.Status, cond.Reason,
+				since(cond.LastTransitionTime.Time, now), cond.Message)
+		}
+		if len(r.Events) == 0 {
+			fmt.Fprintln(tw, "No recent events.")
+			continue
+		}
+		fmt.Fprintln(tw, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE")
+		for _, e := range r.Events {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s/%s\t%s\n", since(lastSeen(e), now), e.Type, e.Reason,
+				strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name, strings.TrimSpace(e.Message))
+		}
+	}
+	return tw.Flush()
+}
+
+// conditionStatus returns the status of the condition of contour with type
+// condType, or "Unknown" if contour has no such condition.
+func conditionStatus(contour operatorv1alpha1.Contour, condType string) string {
+	for _, cond := range contour.Status.Conditions {
+		if cond.Type == condType {
+			return string(cond.Status)
+		}
+	}
+	return "Unknown"
+}
+
+// since returns the time elapsed from t to now in the short form of kubectl,
+// i.e. "5m", or "-" if t is zero.
+func since(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(now.Sub(t))
+}
+
+// orDash returns s, or "-" if s is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
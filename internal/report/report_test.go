@@ -0,0 +1,123 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestContours(t *testing.T) {
+	now := time.Date(2021, time.June, 7, 12, 0, 0, 0, time.UTC)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	contour := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{Name: "contour", Namespace: "default"},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+		Status: operatorv1alpha1.ContourStatus{
+			Version: "v1.15.1",
+			Conditions: []metav1.Condition{{
+				Type:               operatorv1alpha1.ContourAvailableConditionType,
+				Status:             metav1.ConditionFalse,
+				Reason:             "EnvoyUnavailable",
+				Message:            "Envoy DaemonSet not available.",
+				LastTransitionTime: metav1.NewTime(now.Add(-5 * time.Minute)),
+			}},
+		},
+	}
+	replicas := int32(2)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "contour", Namespace: "projectcontour"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+	}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "envoy", Namespace: "projectcontour"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 1},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "envoy", Namespace: "projectcontour"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}},
+		}},
+	}
+	event := func(name, ns string, obj corev1.ObjectReference, reason string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: ns},
+			InvolvedObject: obj,
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " message",
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+	events := []*corev1.Event{
+		event("pod", "projectcontour", corev1.ObjectReference{Kind: "Pod", Name: "envoy-x2z9k"}, "BackOff", time.Minute),
+		event("contour", "default", corev1.ObjectReference{Kind: "Contour", Namespace: "default", Name: "contour"},
+			"InvalidSpec", 2*time.Minute),
+		event("other", "projectcontour", corev1.ObjectReference{Kind: "Pod", Name: "other-x2z9k"}, "Unrelated", time.Minute),
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(contour, deploy, ds, svc)
+	for _, e := range events {
+		builder = builder.WithObjects(e)
+	}
+	cli := builder.Build()
+
+	reports, err := Contours(context.Background(), cli, Options{Namespace: "default", Events: DefaultEvents})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(reports))
+	}
+	r := reports[0]
+	if r.Contours != "2/2" || r.Envoys != "1/3" || r.Address != "192.0.2.10" {
+		t.Errorf("unexpected readiness %s and %s or address %s", r.Contours, r.Envoys, r.Address)
+	}
+	if len(r.Events) != 2 || r.Events[0].Reason != "InvalidSpec" || r.Events[1].Reason != "BackOff" {
+		t.Errorf("expected the contour and envoy pod events, oldest first, got %v", r.Events)
+	}
+
+	out := &bytes.Buffer{}
+	if err := Write(out, reports, now); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"192.0.2.10", "EnvoyUnavailable", "pod/envoy-x2z9k", "v1.15.1"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scaffold generates Contours for common scenarios, i.e. for the
+// "init" subcommand.
+package scaffold
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	operatorconfig "github.com/projectcontour/contour-operator/internal/operator/config"
+	"github.com/projectcontour/contour-operator/pkg/validation"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// Scenario is a common way of running Contour.
+type Scenario string
+
+const (
+	// CloudLoadBalancerScenario publishes Envoy with a load balancer of a
+	// cloud provider.
+	CloudLoadBalancerScenario Scenario = "cloud-lb"
+	// NodePortScenario publishes Envoy on the node ports of bare metal
+	// clusters without load balancers.
+	NodePortScenario Scenario = "nodeport"
+	// GatewayAPIScenario manages Contour for the Gateways of a GatewayClass.
+	GatewayAPIScenario Scenario = "gateway-api"
+)
+
+// Scenarios are the supported scenarios.
+var Scenarios = []Scenario{CloudLoadBalancerScenario, NodePortScenario, GatewayAPIScenario}
+
+// Options are the answers for generating a Contour.
+type Options struct {
+	// Scenario is the scenario of the Contour.
+	Scenario Scenario
+	// Name and Namespace are the name and namespace of the Contour.
+	Name      string
+	Namespace string
+	// SpecNamespace is the namespace Contour and Envoy run in.
+	SpecNamespace string
+	// Replicas is the number of Contour replicas.
+	Replicas int32
+	// Version is the Contour version, or empty for the operator's images.
+	Version string
+	// Provider is the load balancer provider of the cloud-lb and gateway-api
+	// scenarios, i.e. "AWS".
+	Provider operatorv1alpha1.LoadBalancerProviderType
+	// Internal determines whether or not the load balancer of the cloud-lb and
+	// gateway-api scenarios is only reachable from the cloud network.
+	Internal bool
+	// HTTPNodePort and HTTPSNodePort are the node ports of the nodeport
+	// scenario, or 0 to have them allocated.
+	HTTPNodePort  int32
+	HTTPSNodePort int32
+	// GatewayClass is the name of the GatewayClass of the gateway-api scenario.
+	GatewayClass string
+}
+
+// Defaults returns the default Options of the cloud-lb scenario.
+func Defaults() Options {
+	return Options{
+		Scenario:      CloudLoadBalancerScenario,
+		Name:          "contour",
+		Namespace:     operatorconfig.DefaultOperatorNamespace,
+		SpecNamespace: "projectcontour",
+		Replicas:      2,
+		Provider:      operatorv1alpha1.AWSLoadBalancerProvider,
+		GatewayClass:  "contour",
+	}
+}
+
+// Objects returns the Contour of opts and, for the gateway-api scenario, the
+// GatewayClass it is managed for, returning an error if they are invalid.
+func Objects(ctx context.Context, opts Options) ([]client.Object, error) {
+	cfg := objcontour.Config{
+		Name:      opts.Name,
+		Namespace: opts.Namespace,
+		SpecNs:    opts.SpecNamespace,
+		Replicas:  opts.Replicas,
+	}
+	switch opts.Scenario {
+	case CloudLoadBalancerScenario, GatewayAPIScenario:
+		cfg.NetworkType = operatorv1alpha1.LoadBalancerServicePublishingType
+	case NodePortScenario:
+		cfg.NetworkType = operatorv1alpha1.NodePortServicePublishingType
+		cfg.NodePorts = []operatorv1alpha1.NodePort{
+			{Name: "http", PortNumber: nodePort(opts.HTTPNodePort)},
+			{Name: "https", PortNumber: nodePort(opts.HTTPSNodePort)},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scenario %q; supported scenarios are %s", opts.Scenario, scenarioNames())
+	}
+	if opts.Scenario == GatewayAPIScenario {
+		cfg.GatewayClass = &opts.GatewayClass
+	}
+	contour := objcontour.New(cfg)
+	contour.SetGroupVersionKind(operatorv1alpha1.GroupVersion.WithKind("Contour"))
+	contour.Spec.Version = opts.Version
+	if cfg.NetworkType == operatorv1alpha1.LoadBalancerServicePublishingType {
+		contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type = opts.Provider
+		if opts.Internal {
+			contour.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope = operatorv1alpha1.InternalLoadBalancer
+		} else {
+			contour.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope = operatorv1alpha1.ExternalLoadBalancer
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := operatorv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := validation.Contour(ctx, cli, contour); err != nil {
+		return nil, fmt.Errorf("invalid contour: %w", err)
+	}
+	objs := []client.Object{contour}
+
+	if opts.Scenario == GatewayAPIScenario {
+		scope := "Namespace"
+		gc := &gatewayv1alpha1.GatewayClass{
+			Spec: gatewayv1alpha1.GatewayClassSpec{
+				Controller: operatorv1alpha1.GatewayClassControllerRef,
+				ParametersRef: &gatewayv1alpha1.ParametersReference{
+					Group:     operatorv1alpha1.GatewayClassParamsRefGroup,
+					Kind:      operatorv1alpha1.GatewayClassParamsRefKind,
+					Name:      contour.Name,
+					Scope:     &scope,
+					Namespace: &contour.Namespace,
+				},
+			},
+		}
+		gc.SetGroupVersionKind(gatewayv1alpha1.SchemeGroupVersion.WithKind("GatewayClass"))
+		gc.Name = opts.GatewayClass
+		if err := validation.GatewayClass(gc); err != nil {
+			return nil, err
+		}
+		objs = append(objs, gc)
+	}
+	return objs, nil
+}
+
+// nodePort returns port as a node port, or nil to have it allocated.
+func nodePort(port int32) *int32 {
+	if port == 0 {
+		return nil
+	}
+	return &port
+}
+
+// scenarioNames returns the comma-separated names of the supported scenarios.
+func scenarioNames() string {
+	var names []string
+	for _, s := range Scenarios {
+		names = append(names, string(s))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Write writes objs to w as a stream of YAML documents, without the fields
+// set by the API server.
+func Write(w io.Writer, objs []client.Object) error {
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(content, "status")
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", obj.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prompt asks the questions of the scenario of opts on out, reading the
+// answers from in. An empty answer keeps the value of opts.
+func Prompt(in io.Reader, out io.Writer, opts *Options) error {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+	scenario := p.ask(fmt.Sprintf("Scenario (%s)", scenarioNames()), string(opts.Scenario))
+	opts.Scenario = Scenario(scenario)
+	opts.Name = p.ask("Contour name", opts.Name)
+	opts.Namespace = p.ask("Contour namespace", opts.Namespace)
+	opts.SpecNamespace = p.ask("Namespace to run Contour and Envoy in", opts.SpecNamespace)
+	opts.Replicas = p.askInt("Contour replicas", opts.Replicas)
+	opts.Version = p.ask("Contour version, i.e. v1.15.1 (empty for the operator's images)", opts.Version)
+	switch opts.Scenario {
+	case CloudLoadBalancerScenario, GatewayAPIScenario:
+		opts.Provider = operatorv1alpha1.LoadBalancerProviderType(p.ask("Load balancer provider (AWS, Azure, GCP, Generic)",
+			string(opts.Provider)))
+		opts.Internal = p.ask("Internal load balancer (yes, no)", yesNo(opts.Internal)) == "yes"
+	case NodePortScenario:
+		opts.HTTPNodePort = p.askInt("HTTP node port (0 to allocate)", opts.HTTPNodePort)
+		opts.HTTPSNodePort = p.askInt("HTTPS node port (0 to allocate)", opts.HTTPSNodePort)
+	}
+	if opts.Scenario == GatewayAPIScenario {
+		opts.GatewayClass = p.ask("GatewayClass name", opts.GatewayClass)
+	}
+	return p.err
+}
+
+// prompter asks questions, keeping the first error reading an answer.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	err error
+}
+
+// ask asks question, returning the answer or def if the answer is empty.
+func (p *prompter) ask(question, def string) string {
+	if p.err != nil {
+		return def
+	}
+	fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	answer, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		if err != io.EOF {
+			p.err = err
+		}
+		return def
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// askInt asks question, returning the answer as a number or def if the answer
+// is empty or not a number.
+func (p *prompter) askInt(question string, def int32) int32 {
+	answer := p.ask(question, strconv.Itoa(int(def)))
+	n, err := strconv.ParseInt(answer, 10, 32)
+	if err != nil {
+		fmt.Fprintf(p.out, "Invalid number %q, using %d.\n", answer, def)
+		return def
+	}
+	return int32(n)
+}
+
+// yesNo returns b as "yes" or "no".
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,110 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+)
+
+func TestObjects(t *testing.T) {
+	testCases := []struct {
+		description string
+		mutate      func(opts *Options)
+		expected    []string
+		expectErr   bool
+	}{
+		{
+			description: "internal cloud load balancer",
+			mutate: func(opts *Options) {
+				opts.Provider = operatorv1alpha1.GCPLoadBalancerProvider
+				opts.Internal = true
+			},
+			expected: []string{"type: LoadBalancerService", "type: GCP", "scope: Internal"},
+		},
+		{
+			description: "node ports",
+			mutate: func(opts *Options) {
+				opts.Scenario = NodePortScenario
+				opts.HTTPNodePort = 30080
+			},
+			expected: []string{"type: NodePortService", "portNumber: 30080"},
+		},
+		{
+			description: "gateway api",
+			mutate: func(opts *Options) {
+				opts.Scenario = GatewayAPIScenario
+				opts.GatewayClass = "sample"
+			},
+			expected: []string{"gatewayClassRef: sample", "kind: GatewayClass", "controller: projectcontour.io/contour-operator"},
+		},
+		{
+			description: "unsupported scenario",
+			mutate:      func(opts *Options) { opts.Scenario = "ingress" },
+			expectErr:   true,
+		},
+		{
+			description: "unsupported version",
+			mutate:      func(opts *Options) { opts.Version = "v1.2.0" },
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := Defaults()
+		tc.mutate(&opts)
+		objs, err := Objects(context.Background(), opts)
+		if err != nil {
+			if !tc.expectErr {
+				t.Errorf("%q: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if tc.expectErr {
+			t.Errorf("%q: expected an error", tc.description)
+			continue
+		}
+		out := &bytes.Buffer{}
+		if err := Write(out, objs); err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range tc.expected {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("%q: expected output to contain %q, got:\n%s", tc.description, expected, out.String())
+			}
+		}
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	opts := Defaults()
+	in := strings.NewReader("nodeport\n\n\nedge\nthree\n\n30080\n")
+	if err := Prompt(in, ioutil.Discard, &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Scenario != NodePortScenario || opts.SpecNamespace != "edge" || opts.Name != "contour" {
+		t.Errorf("unexpected answers %+v", opts)
+	}
+	if opts.Replicas != 2 {
+		t.Errorf("expected an invalid number to keep the default replicas, got %d", opts.Replicas)
+	}
+	if opts.HTTPNodePort != 30080 || opts.HTTPSNodePort != 0 {
+		t.Errorf("expected the answered node ports, got %d and %d", opts.HTTPNodePort, opts.HTTPSNodePort)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upgrade checks the compatibility of a Contour with the release it is
+// upgraded to before the release's images are applied.
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/release"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// specFeatures are the fields of the Contour spec that require a minimum
+// Contour version, rendered into the Contour configuration file.
+var specFeatures = []struct {
+	field      string
+	minVersion string
+	used       func(*operatorv1alpha1.Contour) bool
+}{
+	{
+		field:      "spec.circuitBreakers",
+		minVersion: "v1.27.0",
+		used:       func(c *operatorv1alpha1.Contour) bool { return c.Spec.CircuitBreakers != nil },
+	},
+	{
+		field:      "spec.serverHeaderTransformation",
+		minVersion: "v1.19.0",
+		used:       func(c *operatorv1alpha1.Contour) bool { return c.Spec.ServerHeaderTransformation != "" },
+	},
+	{
+		field:      "spec.networking.dnsLookupFamily",
+		minVersion: "v1.25.0",
+		used: func(c *operatorv1alpha1.Contour) bool {
+			return c.Spec.Networking != nil && c.Spec.Networking.DNSLookupFamily == operatorv1alpha1.AllDNSLookupFamily
+		},
+	},
+	{
+		field:      "spec.accessLog.level",
+		minVersion: "v1.24.0",
+		used:       func(c *operatorv1alpha1.Contour) bool { return c.Spec.AccessLog != nil && c.Spec.AccessLog.Level != "" },
+	},
+}
+
+// proxyFeatures are the fields of HTTPProxies that require a minimum Contour
+// version. A field of "spec.routes[]" is checked on every route.
+var proxyFeatures = []struct {
+	path       []string
+	minVersion string
+}{
+	{path: []string{"spec", "virtualhost", "rateLimitPolicy"}, minVersion: "v1.13.0"},
+	{path: []string{"spec", "routes[]", "rateLimitPolicy"}, minVersion: "v1.13.0"},
+	{path: []string{"spec", "routes[]", "cookieRewritePolicies"}, minVersion: "v1.19.0"},
+	{path: []string{"spec", "virtualhost", "jwtProviders"}, minVersion: "v1.21.0"},
+}
+
+// httpProxyGVK is the GroupVersionKind of the list of HTTPProxies.
+var httpProxyGVK = schema.GroupVersionKind{Group: "projectcontour.io", Version: "v1", Kind: "HTTPProxyList"}
+
+// Check returns the incompatibilities of contour with the target release,
+// given the Kubernetes version of the cluster, which may be empty if unknown.
+func Check(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour, target release.Release,
+	kubeVersion string) ([]string, error) {
+	targetVersion, err := version.ParseSemantic(target.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release version %q: %w", target.Version, err)
+	}
+	var problems []string
+	if kubeVersion != "" && target.MinKubernetesVersion != "" {
+		kube, err := version.ParseGeneric(kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubernetes version %q: %w", kubeVersion, err)
+		}
+		if kube.LessThan(version.MustParseGeneric(target.MinKubernetesVersion)) {
+			problems = append(problems, fmt.Sprintf("Contour %s requires Kubernetes %s or later, the cluster runs %s.",
+				target.Version, target.MinKubernetesVersion, kubeVersion))
+		}
+	}
+	for _, f := range specFeatures {
+		if f.used(contour) && targetVersion.LessThan(version.MustParseSemantic(f.minVersion)) {
+			problems = append(problems, fmt.Sprintf("%s requires Contour %s or later.", f.field, f.minVersion))
+		}
+	}
+
+	proxies := &unstructured.UnstructuredList{}
+	proxies.SetGroupVersionKind(httpProxyGVK)
+	if err := cli.List(ctx, proxies); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("failed to list httpproxies: %w", err)
+	}
+	for _, f := range proxyFeatures {
+		if !targetVersion.LessThan(version.MustParseSemantic(f.minVersion)) {
+			continue
+		}
+		var users []string
+		for _, proxy := range proxies.Items {
+			if hasField(proxy.Object, f.path) {
+				users = append(users, proxy.GetNamespace()+"/"+proxy.GetName())
+			}
+		}
+		if len(users) > 0 {
+			problems = append(problems, fmt.Sprintf("HTTPProxy %s uses %s, which requires Contour %s or later.",
+				strings.Join(users, ", "), strings.Join(f.path, "."), f.minVersion))
+		}
+	}
+	return problems, nil
+}
+
+// hasField returns true if obj has the field at path, where a "[]" suffix of
+// an element of path matches the field in any item of the list.
+func hasField(obj interface{}, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	name := strings.TrimSuffix(path[0], "[]")
+	value, ok := m[name]
+	if !ok {
+		return false
+	}
+	if name == path[0] {
+		return hasField(value, path[1:])
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if hasField(item, path[1:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright Project Contour Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"testing"
+
+	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/release"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheck(t *testing.T) {
+	target, _ := release.Lookup("v1.15.1")
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(httpProxyGVK.GroupVersion().WithKind("HTTPProxy"), &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(httpProxyGVK, &unstructured.UnstructuredList{})
+	cli := fake.NewClientBuilder().WithScheme(s).Build()
+	proxy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"virtualhost": map[string]interface{}{"jwtProviders": []interface{}{}},
+		},
+	}}
+	proxy.SetGroupVersionKind(httpProxyGVK.GroupVersion().WithKind("HTTPProxy"))
+	proxy.SetNamespace("default")
+	proxy.SetName("jwt")
+	cliWithProxy := fake.NewClientBuilder().WithScheme(s).WithObjects(proxy).Build()
+
+	testCases := []struct {
+		description string
+		cli         client.Client
+		contour     *operatorv1alpha1.Contour
+		kubeVersion string
+		expected    int
+	}{
+		{
+			description: "compatible contour",
+			cli:         cli,
+			contour:     &operatorv1alpha1.Contour{},
+			kubeVersion: "v1.21.1-gke.100",
+		},
+		{
+			description: "unsupported kubernetes version",
+			cli:         cli,
+			contour:     &operatorv1alpha1.Contour{},
+			kubeVersion: "v1.18.3",
+			expected:    1,
+		},
+		{
+			description: "unsupported spec fields",
+			cli:         cli,
+			contour: &operatorv1alpha1.Contour{
+				Spec: operatorv1alpha1.ContourSpec{
+					ServerHeaderTransformation: operatorv1alpha1.PassThroughServerHeader,
+					AccessLog:                  &operatorv1alpha1.AccessLogParameters{Level: operatorv1alpha1.ErrorAccessLogLevel},
+				},
+			},
+			expected: 2,
+		},
+		{
+			description: "unsupported httpproxy field",
+			cli:         cliWithProxy,
+			contour:     &operatorv1alpha1.Contour{},
+			expected:    1,
+		},
+	}
+
+	for _, tc := range testCases {
+		problems, err := Check(context.Background(), tc.cli, tc.contour, target, tc.kubeVersion)
+		if err != nil {
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
+		}
+		if len(problems) != tc.expected {
+			t.Errorf("%q: expected %d problems, got %v", tc.description, tc.expected, problems)
+		}
+	}
+}
+
+func TestHasField(t *testing.T) {
+	proxy := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"virtualhost": map[string]interface{}{"fqdn": "example.com"},
+			"routes": []interface{}{
+				map[string]interface{}{"services": []interface{}{}},
+				map[string]interface{}{"cookieRewritePolicies": []interface{}{}},
+			},
+		},
+	}
+	if !hasField(proxy, []string{"spec", "routes[]", "cookieRewritePolicies"}) {
+		t.Errorf("expected route field cookieRewritePolicies to be found")
+	}
+	if hasField(proxy, []string{"spec", "virtualhost", "jwtProviders"}) {
+		t.Errorf("expected virtualhost field jwtProviders not to be found")
+	}
+}
@@ -17,47 +17,183 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
+	"time"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	"github.com/projectcontour/contour-operator/internal/maintenance"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
 	objcontour "github.com/projectcontour/contour-operator/internal/objects/contour"
+	objds "github.com/projectcontour/contour-operator/internal/objects/daemonset"
 	objgw "github.com/projectcontour/contour-operator/internal/objects/gateway"
 	objgc "github.com/projectcontour/contour-operator/internal/objects/gatewayclass"
+	objjob "github.com/projectcontour/contour-operator/internal/objects/job"
+	objroute "github.com/projectcontour/contour-operator/internal/objects/route"
+	objsvc "github.com/projectcontour/contour-operator/internal/objects/service"
+	"github.com/projectcontour/contour-operator/internal/release"
 	retryable "github.com/projectcontour/contour-operator/internal/retryableerror"
 	"github.com/projectcontour/contour-operator/pkg/slice"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1alpha1 "sigs.k8s.io/gateway-api/apis/v1alpha1"
 )
 
-const gatewayClassNamespacedParamRef = "Namespace"
+const (
+	gatewayClassNamespacedParamRef = "Namespace"
+	// hstsPreloadMinMaxAge is the minimum HSTS max-age, in seconds, accepted
+	// by browser preload lists.
+	hstsPreloadMinMaxAge = 31536000
+	// minNodePort and maxNodePort are the default node port range of a
+	// cluster, i.e. the --service-node-port-range flag of the API server.
+	minNodePort = 30000
+	maxNodePort = 32767
+	// maxClientIPAffinitySeconds is the maximum timeout of the ClientIP session
+	// affinity of a Service accepted by the API server.
+	maxClientIPAffinitySeconds = 86400
+)
+
+// jsonFieldNameRegex matches the name of a JSON access log field.
+var jsonFieldNameRegex = regexp.MustCompile(`^@?[A-Za-z0-9_]+$`)
+
+// headerNameRegex matches an HTTP header name, i.e. an RFC 7230 token.
+var headerNameRegex = regexp.MustCompile("^[-!#$%&'*+.^_`|~0-9A-Za-z]+$")
 
 // Contour returns true if contour is valid.
 func Contour(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
-	// TODO [danehans]: Remove when https://github.com/projectcontour/contour-operator/issues/18 is fixed.
-	exist, err := objcontour.OtherContoursExistInSpecNs(ctx, cli, contour)
-	if err != nil {
-		return fmt.Errorf("failed to verify if other contours exist in namespace %s: %w",
-			contour.Spec.Namespace.Name, err)
+	// The network publishing a preset expands to is validated.
+	if err := PublishingPreset(contour); err != nil {
+		return err
+	}
+	contour = objcontour.WithPublishingPreset(contour)
+
+	if err := Version(contour); err != nil {
+		return err
 	}
-	if exist {
-		return fmt.Errorf("other contours exist in namespace %s", contour.Spec.Namespace.Name)
+
+	if err := ImageFlavor(contour); err != nil {
+		return err
+	}
+
+	if err := ResourceNameTemplate(contour); err != nil {
+		return err
+	}
+
+	if err := ResourceNames(contour); err != nil {
+		return err
+	}
+
+	if err := SpecNamespace(ctx, cli, contour); err != nil {
+		return err
 	}
 
 	if err := ContainerPorts(contour); err != nil {
 		return err
 	}
 
-	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.NodePortServicePublishingType {
+	if err := ServicePorts(contour); err != nil {
+		return err
+	}
+
+	if err := ExtraPorts(contour); err != nil {
+		return err
+	}
+
+	if err := HostPorts(contour); err != nil {
+		return err
+	}
+
+	if err := HTTP3(contour); err != nil {
+		return err
+	}
+
+	if err := SessionAffinity(contour); err != nil {
+		return err
+	}
+
+	if err := AppProtocols(contour); err != nil {
+		return err
+	}
+
+	if err := HTTPService(contour); err != nil {
+		return err
+	}
+
+	if err := AdditionalServices(contour); err != nil {
+		return err
+	}
+
+	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.NodePortServicePublishingType ||
+		(contour.Spec.NetworkPublishing.Envoy.HTTPService != nil &&
+			contour.Spec.NetworkPublishing.Envoy.HTTPService.Type == operatorv1alpha1.NodePortServicePublishingType) {
 		if err := NodePorts(contour); err != nil {
 			return err
 		}
 	}
 
+	if err := ExternalIPs(contour); err != nil {
+		return err
+	}
+
+	if err := EnvoyOperatingSystems(contour); err != nil {
+		return err
+	}
+
+	if err := RootNamespaces(contour); err != nil {
+		return err
+	}
+
+	if err := ClusterDomain(contour); err != nil {
+		return err
+	}
+
+	if err := RetentionPolicy(contour); err != nil {
+		return err
+	}
+
+	if err := Policy(contour); err != nil {
+		return err
+	}
+
+	if err := SecurityHeaders(contour); err != nil {
+		return err
+	}
+
+	if err := Timeouts(contour); err != nil {
+		return err
+	}
+
+	if err := LeaderElection(ctx, cli, contour); err != nil {
+		return err
+	}
+
+	if err := KubernetesClient(contour); err != nil {
+		return err
+	}
+
+	if err := Logging(contour); err != nil {
+		return err
+	}
+
+	if err := Certgen(contour); err != nil {
+		return err
+	}
+
+	if err := AccessLog(contour); err != nil {
+		return err
+	}
+
+	if err := UpdatePolicy(contour); err != nil {
+		return err
+	}
+
 	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.LoadBalancerServicePublishingType {
 		if err := LoadBalancerAddress(contour); err != nil {
 			return err
@@ -65,6 +201,27 @@ func Contour(ctx context.Context, cli client.Client, contour *operatorv1alpha1.C
 		if err := LoadBalancerProvider(contour); err != nil {
 			return err
 		}
+		if err := AWSNetworkLoadBalancer(contour); err != nil {
+			return err
+		}
+		if err := AllocateLoadBalancerNodePorts(contour); err != nil {
+			return err
+		}
+		if err := HealthCheckNodePort(contour); err != nil {
+			return err
+		}
+		if err := GCPLoadBalancer(contour); err != nil {
+			return err
+		}
+		if err := AzureLoadBalancer(contour); err != nil {
+			return err
+		}
+	}
+
+	if contour.Spec.NetworkPublishing.Envoy.Type == operatorv1alpha1.RoutePublishingType {
+		if err := Route(cli, contour); err != nil {
+			return err
+		}
 	}
 
 	if err := IngressClass(ctx, cli, contour); err != nil {
@@ -74,6 +231,432 @@ func Contour(ctx context.Context, cli client.Client, contour *operatorv1alpha1.C
 	return nil
 }
 
+// ContourUpdate returns an error if the update of contour from old changes a
+// field that cannot be changed once contour is created, since the resources
+// generated for contour would be orphaned under their previous names.
+func ContourUpdate(old, contour *operatorv1alpha1.Contour) error {
+	if old.Spec.Namespace.Shared != contour.Spec.Namespace.Shared {
+		return fmt.Errorf("spec.namespace.shared cannot be changed from %t to %t",
+			old.Spec.Namespace.Shared, contour.Spec.Namespace.Shared)
+	}
+	if old.Spec.ResourceNameTemplate != contour.Spec.ResourceNameTemplate {
+		return fmt.Errorf("spec.resourceNameTemplate cannot be changed from %q to %q",
+			old.Spec.ResourceNameTemplate, contour.Spec.ResourceNameTemplate)
+	}
+	return nil
+}
+
+// WatchedNamespace validates that the resources of contour are managed in one of the
+// provided watch namespaces, returning an error if the spec namespace of contour is
+// not watched. WatchedNamespace always succeeds if watchNamespaces is empty.
+func WatchedNamespace(contour *operatorv1alpha1.Contour, watchNamespaces []string) error {
+	if len(watchNamespaces) == 0 {
+		return nil
+	}
+	if !slice.ContainsString(watchNamespaces, contour.Spec.Namespace.Name) {
+		return fmt.Errorf("namespace %s is not one of the operator's watch namespaces %s",
+			contour.Spec.Namespace.Name, strings.Join(watchNamespaces, ","))
+	}
+	return nil
+}
+
+// SpecNamespace returns an error if contour can not run in its spec namespace,
+// i.e. another Contour runs in the namespace and either Contour does not share
+// the namespace.
+func SpecNamespace(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	ns := contour.Spec.Namespace.Name
+	others, err := objcontour.OtherContoursInSpecNs(ctx, cli, contour)
+	if err != nil {
+		return fmt.Errorf("failed to verify if other contours exist in namespace %s: %w", ns, err)
+	}
+	for _, other := range others {
+		if !contour.Spec.Namespace.Shared || !other.Spec.Namespace.Shared {
+			return fmt.Errorf("other contours exist in namespace %s; set spec.namespace.shared for all "+
+				"contours in the namespace to share it", ns)
+		}
+		if other.Name == contour.Name {
+			return fmt.Errorf("contour %s/%s shares namespace %s with a contour of the same name",
+				other.Namespace, other.Name, ns)
+		}
+	}
+	if contour.Spec.Namespace.Shared && contour.Spec.ResourceNameTemplate != "" &&
+		!strings.Contains(contour.Spec.ResourceNameTemplate, objcontour.ContourNamePlaceholder) {
+		return fmt.Errorf("resource name template %q of contour %s/%s must contain %s to share namespace %s",
+			contour.Spec.ResourceNameTemplate, contour.Namespace, contour.Name, objcontour.ContourNamePlaceholder, ns)
+	}
+	if contour.Spec.Namespace.Shared {
+		// Service names are the most restrictive of the generated names.
+		name := objcontour.ResourceName(contour, "contour")
+		if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+			return fmt.Errorf("invalid generated resource name %q for contour %s/%s: %s", name,
+				contour.Namespace, contour.Name, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// RetentionPolicy returns an error if the retention policy of contour can not be
+// honored, i.e. resources to retain would be garbage collected with contour since
+// they have owner references to it.
+func RetentionPolicy(contour *operatorv1alpha1.Contour) error {
+	if contour.Spec.Ownership != operatorv1alpha1.OwnerReferencesOwnershipType ||
+		contour.Spec.Namespace.Name != contour.Namespace {
+		return nil
+	}
+	if contour.RetainsServices() || contour.RetainsRBAC() {
+		return fmt.Errorf("contour %s/%s retains services or rbac in its own namespace, which requires "+
+			"ownership %s", contour.Namespace, contour.Name, operatorv1alpha1.LabelsOwnershipType)
+	}
+	return nil
+}
+
+// Policy returns an error if the global header policies of contour are invalid,
+// i.e. a header name is not a valid HTTP header name or the request policy sets
+// the "Host" header, which Contour does not support globally.
+func Policy(contour *operatorv1alpha1.Contour) error {
+	policy := contour.Spec.Policy
+	if policy == nil {
+		return nil
+	}
+	for kind, headers := range map[string]*operatorv1alpha1.HeadersPolicy{
+		"request":  policy.RequestHeaders,
+		"response": policy.ResponseHeaders,
+	} {
+		if headers == nil {
+			continue
+		}
+		names := headers.Remove
+		for name := range headers.Set {
+			if kind == "request" && strings.EqualFold(name, "Host") {
+				return fmt.Errorf("%s header policy can not set the Host header", kind)
+			}
+			names = append(names, name)
+		}
+		for _, name := range names {
+			if !headerNameRegex.MatchString(name) {
+				return fmt.Errorf("invalid %s header policy header name %q", kind, name)
+			}
+		}
+	}
+	return nil
+}
+
+// SecurityHeaders returns an error if the security headers of contour are
+// invalid, i.e. HSTS preload is requested without includeSubDomains or with a
+// max-age of less than a year, which preload lists reject.
+func SecurityHeaders(contour *operatorv1alpha1.Contour) error {
+	sec := contour.Spec.Security
+	if sec == nil || sec.Headers == nil || sec.Headers.HSTS == nil || !sec.Headers.HSTS.Preload {
+		return nil
+	}
+	hsts := sec.Headers.HSTS
+	if !hsts.IncludeSubDomains || hsts.MaxAgeSeconds < hstsPreloadMinMaxAge {
+		return fmt.Errorf("hsts preload requires includeSubDomains and a maxAgeSeconds of at least %d",
+			hstsPreloadMinMaxAge)
+	}
+	return nil
+}
+
+// Timeouts returns an error if a timeout of contour is neither a valid duration
+// nor "infinity", or the connection shutdown grace period is "infinity".
+func Timeouts(contour *operatorv1alpha1.Contour) error {
+	t := contour.Spec.Timeouts
+	if t == nil {
+		return nil
+	}
+	timeouts := []struct {
+		name, value string
+		infinite    bool
+	}{
+		{"request timeout", t.RequestTimeout, true},
+		{"connection idle timeout", t.ConnectionIdleTimeout, true},
+		{"stream idle timeout", t.StreamIdleTimeout, true},
+		{"max connection duration", t.MaxConnectionDuration, true},
+		{"delayed close timeout", t.DelayedCloseTimeout, true},
+		{"connection shutdown grace period", t.ConnectionShutdownGracePeriod, false},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value == "" || timeout.infinite && timeout.value == "infinity" {
+			continue
+		}
+		if _, err := time.ParseDuration(timeout.value); err != nil {
+			return fmt.Errorf("invalid %s %q: %v", timeout.name, timeout.value, err)
+		}
+	}
+	return nil
+}
+
+// KubernetesClient returns an error if the Kubernetes client rate limits of
+// contour are invalid, i.e. QPS or burst is negative or burst is less than QPS.
+func KubernetesClient(contour *operatorv1alpha1.Contour) error {
+	kc := contour.Spec.KubernetesClient
+	if kc == nil {
+		return nil
+	}
+	if kc.QPS < 0 || kc.Burst < 0 {
+		return fmt.Errorf("kubernetes client qps %d and burst %d must not be negative", kc.QPS, kc.Burst)
+	}
+	if kc.QPS > 0 && kc.Burst > 0 && kc.Burst < kc.QPS {
+		return fmt.Errorf("kubernetes client burst %d must not be less than qps %d", kc.Burst, kc.QPS)
+	}
+	return nil
+}
+
+// maxKubernetesVerbosity is the highest log verbosity of the Kubernetes client
+// of Contour.
+const maxKubernetesVerbosity = 9
+
+// Logging returns an error if the logging of contour is invalid, i.e. the
+// Kubernetes client verbosity is out of range.
+func Logging(contour *operatorv1alpha1.Contour) error {
+	logging := contour.Spec.Logging
+	if logging == nil {
+		return nil
+	}
+	if logging.KubernetesVerbosity < 0 || logging.KubernetesVerbosity > maxKubernetesVerbosity {
+		return fmt.Errorf("kubernetes client log verbosity %d must be between 0 and %d",
+			logging.KubernetesVerbosity, maxKubernetesVerbosity)
+	}
+	return nil
+}
+
+// Certgen returns an error if the certgen parameters of contour are invalid,
+// i.e. a resource request exceeds its limit, which would fail the creation of
+// the certgen Job.
+func Certgen(contour *operatorv1alpha1.Contour) error {
+	certgen := contour.Spec.Certgen
+	if certgen == nil || certgen.Resources == nil {
+		return nil
+	}
+	for name, request := range certgen.Resources.Requests {
+		if limit, ok := certgen.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("certgen %s request %s must not exceed its limit %s", name, request.String(),
+				limit.String())
+		}
+	}
+	return nil
+}
+
+// Contour's default leader election durations.
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// LeaderElection returns an error if the leader election of contour is invalid,
+// i.e. a duration is malformed, the lease duration does not exceed the renew
+// deadline or the renew deadline does not exceed the retry period, or another
+// Contour elects a leader with the same ConfigMap.
+func LeaderElection(ctx context.Context, cli client.Client, contour *operatorv1alpha1.Contour) error {
+	if le := contour.Spec.LeaderElection; le != nil {
+		if le.ConfigMapName != "" {
+			if errs := validation.IsDNS1123Subdomain(le.ConfigMapName); len(errs) > 0 {
+				return fmt.Errorf("invalid leader election configmap name %q: %s", le.ConfigMapName,
+					strings.Join(errs, ", "))
+			}
+		}
+		if le.ConfigMapNamespace != "" {
+			if errs := validation.IsDNS1123Label(le.ConfigMapNamespace); len(errs) > 0 {
+				return fmt.Errorf("invalid leader election configmap namespace %q: %s", le.ConfigMapNamespace,
+					strings.Join(errs, ", "))
+			}
+		}
+		durations := []struct {
+			name, value string
+			parsed      time.Duration
+		}{
+			{name: "lease duration", value: le.LeaseDuration, parsed: defaultLeaseDuration},
+			{name: "renew deadline", value: le.RenewDeadline, parsed: defaultRenewDeadline},
+			{name: "retry period", value: le.RetryPeriod, parsed: defaultRetryPeriod},
+		}
+		for i, d := range durations {
+			if d.value == "" {
+				continue
+			}
+			parsed, err := time.ParseDuration(d.value)
+			if err != nil {
+				return fmt.Errorf("invalid leader election %s %q: %v", d.name, d.value, err)
+			}
+			durations[i].parsed = parsed
+		}
+		for i := 1; i < len(durations); i++ {
+			if durations[i-1].parsed <= durations[i].parsed {
+				return fmt.Errorf("leader election %s %s must be greater than %s %s", durations[i-1].name,
+					durations[i-1].parsed, durations[i].name, durations[i].parsed)
+			}
+		}
+	}
+
+	key := objcontour.LeaderElectionConfigMap(contour)
+	contours := &operatorv1alpha1.ContourList{}
+	if err := cli.List(ctx, contours); err != nil {
+		return fmt.Errorf("failed to list contours: %w", err)
+	}
+	for i := range contours.Items {
+		other := &contours.Items[i]
+		if other.Name == contour.Name && other.Namespace == contour.Namespace {
+			continue
+		}
+		if objcontour.LeaderElectionConfigMap(other) == key {
+			return fmt.Errorf("contour %s/%s elects a leader with configmap %s; set a distinct "+
+				"spec.leaderElection.configMapName", other.Namespace, other.Name, key)
+		}
+	}
+	return nil
+}
+
+// AccessLog returns an error if the access log of contour is invalid, i.e. JSON
+// fields are specified for a format other than json or a JSON field is malformed.
+func AccessLog(contour *operatorv1alpha1.Contour) error {
+	log := contour.Spec.AccessLog
+	if log == nil {
+		return nil
+	}
+	if log.Format != operatorv1alpha1.JSONAccessLogFormat {
+		if len(log.JSONFields) > 0 || log.IncludeContourInstance {
+			return fmt.Errorf("json access log fields require access log format %s",
+				operatorv1alpha1.JSONAccessLogFormat)
+		}
+		return nil
+	}
+	var namesFound []string
+	for _, field := range log.JSONFields {
+		name := field
+		if i := strings.Index(field, "="); i >= 0 {
+			name = field[:i]
+			if field[i+1:] == "" {
+				return fmt.Errorf("json access log field %q has an empty format", field)
+			}
+		}
+		if !jsonFieldNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid json access log field name %q", name)
+		}
+		if slice.ContainsString(namesFound, name) {
+			return fmt.Errorf("duplicate json access log field %q", name)
+		}
+		namesFound = append(namesFound, name)
+	}
+	if log.IncludeContourInstance && slice.ContainsString(namesFound, "contour_instance") {
+		return fmt.Errorf("json access log field \"contour_instance\" is reserved when includeContourInstance is set")
+	}
+	return nil
+}
+
+// UpdatePolicy returns an error if a maintenance window of the update policy of
+// contour is invalid, i.e. its duration is not positive or exceeds one week.
+func UpdatePolicy(contour *operatorv1alpha1.Contour) error {
+	if !contour.MaintenanceWindowsExist() {
+		return nil
+	}
+	for _, w := range contour.Spec.UpdatePolicy.MaintenanceWindows {
+		if _, _, err := maintenance.Parse(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Version returns an error if the version of contour is not a release managed
+// by the operator, or is set with a gatewayClassRef, since Gateways run the
+// operator's default images.
+func Version(contour *operatorv1alpha1.Contour) error {
+	version := contour.Spec.Version
+	if version == "" {
+		return nil
+	}
+	if contour.GatewayClassSet() {
+		return fmt.Errorf("contour version %s is not supported with a gatewayclass", version)
+	}
+	if _, ok := release.Lookup(version); !ok {
+		return fmt.Errorf("unsupported contour version %q; supported versions are %s", version,
+			strings.Join(release.Versions(), ", "))
+	}
+	return nil
+}
+
+// ImageFlavor returns an error if the image flavor of contour is not the
+// standard flavor and its version is unset or has no images of the flavor, or
+// if the flavor is set with a gatewayClassRef.
+func ImageFlavor(contour *operatorv1alpha1.Contour) error {
+	flavor := contour.Spec.ImageFlavor
+	if flavor == "" || flavor == operatorv1alpha1.StandardImageFlavor {
+		return nil
+	}
+	if contour.GatewayClassSet() {
+		return fmt.Errorf("image flavor %s is not supported with a gatewayclass", flavor)
+	}
+	rel, ok := release.Lookup(contour.Spec.Version)
+	if !ok {
+		return fmt.Errorf("image flavor %s requires a supported contour version", flavor)
+	}
+	if _, ok := rel.Flavored(string(flavor)); !ok {
+		return fmt.Errorf("contour version %s has no %s images", rel.Version, flavor)
+	}
+	return nil
+}
+
+// ResourceNameTemplate returns an error if the resource name template of contour
+// is invalid, i.e. it does not contain the resource placeholder or generates an
+// invalid resource name.
+func ResourceNameTemplate(contour *operatorv1alpha1.Contour) error {
+	tmpl := contour.Spec.ResourceNameTemplate
+	if tmpl == "" {
+		return nil
+	}
+	if !strings.Contains(tmpl, objcontour.ResourceNamePlaceholder) {
+		return fmt.Errorf("resource name template %q must contain %s", tmpl, objcontour.ResourceNamePlaceholder)
+	}
+	stripped := strings.NewReplacer(
+		objcontour.ResourceNamePlaceholder, "",
+		objcontour.ContourNamePlaceholder, "",
+		objcontour.ContourNamespacePlaceholder, "",
+	).Replace(tmpl)
+	if strings.ContainsAny(stripped, "{}") {
+		return fmt.Errorf("resource name template %q contains an unknown placeholder; supported placeholders "+
+			"are %s, %s and %s", tmpl, objcontour.ResourceNamePlaceholder, objcontour.ContourNamePlaceholder,
+			objcontour.ContourNamespacePlaceholder)
+	}
+	return ResourceNames(contour)
+}
+
+// ResourceNames returns an error if a name of the resources generated for
+// contour is invalid, i.e. a name rendered from its resource name template or
+// suffixed with the name of a contour sharing its namespace is longer than 63
+// characters. The names of Services are the most restrictive, so every name
+// but the name of the certgen Job, which contains the Contour version, must be
+// a DNS-1035 label.
+func ResourceNames(contour *operatorv1alpha1.Contour) error {
+	bases := []string{"contour", "envoy", "envoy-http", objutil.CertGenRbacName}
+	for _, svc := range contour.Spec.NetworkPublishing.Envoy.AdditionalServices {
+		bases = append(bases, "envoy-"+svc.Name)
+	}
+	if contour.EnvoyBlueGreenRollout() {
+		bases = append(bases, "envoy-"+objds.BlueEnvoyFleet, "envoy-"+objds.GreenEnvoyFleet)
+	}
+	var names []string
+	for _, base := range bases {
+		names = append(names, objcontour.ResourceName(contour, base))
+	}
+	for _, base := range objjob.CertsSecretNames {
+		names = append(names, objcontour.CertsSecretName(contour, base))
+	}
+	if le := contour.Spec.LeaderElection; le == nil || le.ConfigMapName == "" {
+		names = append(names, objcontour.LeaderElectionConfigMap(contour).Name)
+	}
+	for _, name := range names {
+		if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+			return fmt.Errorf("invalid generated resource name %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+	// The name of the Job is the value of the job-name label of its pods.
+	job := objjob.JobName(contour)
+	if errs := validation.IsValidLabelValue(job); len(errs) > 0 {
+		return fmt.Errorf("invalid generated resource name %q: %s", job, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // ContainerPorts validates container ports of contour, returning an
 // error if the container ports do not meet the API specification.
 func ContainerPorts(contour *operatorv1alpha1.Contour) error {
@@ -103,6 +686,208 @@ func ContainerPorts(contour *operatorv1alpha1.Contour) error {
 	return fmt.Errorf("http and https container ports are unspecified")
 }
 
+// ServicePorts validates the Envoy Service port overrides of contour, returning
+// an error if they are set for a Route, do not map to a declared container port
+// or their container ports, names or port numbers are not unique.
+func ServicePorts(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if len(envoy.ServicePorts) == 0 {
+		return nil
+	}
+	if envoy.Type == operatorv1alpha1.RoutePublishingType {
+		return fmt.Errorf("service ports are not supported by network publishing type %s", envoy.Type)
+	}
+	declared := map[string]bool{}
+	for _, p := range envoy.ContainerPorts {
+		declared[p.Name] = true
+	}
+	containerPorts, names, ports := map[string]bool{}, map[string]bool{"http3": true}, map[int32]bool{}
+	for _, p := range envoy.ServicePorts {
+		switch {
+		case !declared[p.ContainerPort]:
+			return fmt.Errorf("service port %q maps to undeclared container port %q", p.Name, p.ContainerPort)
+		case containerPorts[p.ContainerPort]:
+			return fmt.Errorf("duplicate service port of container port %q", p.ContainerPort)
+		case names[p.Name]:
+			return fmt.Errorf("invalid service port name %q; names must be unique and \"http3\" is reserved", p.Name)
+		case ports[p.Port]:
+			return fmt.Errorf("duplicate service port number %d", p.Port)
+		}
+		containerPorts[p.ContainerPort], names[p.Name], ports[p.Port] = true, true, true
+	}
+	// A container port without an override is published by its default port.
+	for _, containerPort := range []string{"http", "https"} {
+		if containerPorts[containerPort] {
+			continue
+		}
+		if name, port := objsvc.EnvoyServicePort(contour, containerPort); names[name] || ports[port] {
+			return fmt.Errorf("service port %s/%d of container port %q is already used", name, port, containerPort)
+		}
+	}
+	return nil
+}
+
+// PublishingPreset returns an error if the network publishing preset of contour
+// is set along with a type of envoy other than NodePortService or the
+// LoadBalancerService default, or with a gatewayClassRef.
+func PublishingPreset(contour *operatorv1alpha1.Contour) error {
+	preset := contour.Spec.NetworkPublishing.Preset
+	if preset == "" {
+		return nil
+	}
+	if contour.GatewayClassSet() {
+		return fmt.Errorf("network publishing preset %s is not supported with a gatewayclass", preset)
+	}
+	switch envoy := contour.Spec.NetworkPublishing.Envoy; envoy.Type {
+	case "", operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType:
+	default:
+		return fmt.Errorf("network publishing preset %s is not supported by network publishing type %s", preset,
+			envoy.Type)
+	}
+	return nil
+}
+
+// HostPorts validates the host ports of contour, returning an error if a host
+// port maps to an undeclared container port, if a container port or host port
+// number is not unique, or if the BlueGreen Envoy rollout strategy is used.
+func HostPorts(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if len(envoy.HostPorts) == 0 {
+		return nil
+	}
+	if contour.EnvoyBlueGreenRollout() {
+		return fmt.Errorf("host ports are not supported by envoy rollout strategy %s",
+			operatorv1alpha1.BlueGreenEnvoyRolloutStrategy)
+	}
+	declared := map[string]bool{}
+	for _, p := range envoy.ContainerPorts {
+		declared[p.Name] = true
+	}
+	for _, p := range envoy.ExtraPorts {
+		declared[p.Name] = true
+	}
+	containerPorts, hostPorts := map[string]bool{}, map[int32]bool{}
+	for _, p := range envoy.HostPorts {
+		switch {
+		case !declared[p.ContainerPort]:
+			return fmt.Errorf("host port %d maps to undeclared container port %q", p.HostPort, p.ContainerPort)
+		case containerPorts[p.ContainerPort]:
+			return fmt.Errorf("duplicate host port of container port %q", p.ContainerPort)
+		case hostPorts[p.HostPort]:
+			return fmt.Errorf("duplicate host port number %d", p.HostPort)
+		}
+		containerPorts[p.ContainerPort], hostPorts[p.HostPort] = true, true
+	}
+	return nil
+}
+
+// ExtraPorts validates the extra ports of contour, returning an error if a name
+// or port number is reserved or not unique, or if a node port is set for a
+// network publishing type other than NodePortService.
+func ExtraPorts(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	names := map[string]bool{"http": true, "https": true, "http3": true}
+	containerPorts := map[int32]bool{}
+	servicePorts := map[int32]bool{}
+	for _, p := range envoy.ContainerPorts {
+		containerPorts[p.PortNumber] = true
+		name, port := objsvc.EnvoyServicePort(contour, p.Name)
+		names[name], servicePorts[port] = true, true
+	}
+	nodePorts := map[int32]bool{}
+	for _, p := range envoy.NodePorts {
+		if p.PortNumber != nil {
+			nodePorts[*p.PortNumber] = true
+		}
+	}
+	for _, p := range envoy.ExtraPorts {
+		switch {
+		case names[p.Name]:
+			return fmt.Errorf("invalid extra port name %q; names must be unique and differ from the http, https and http3 ports", p.Name)
+		case containerPorts[p.ContainerPort]:
+			return fmt.Errorf("duplicate container port number %d of extra port %q", p.ContainerPort, p.Name)
+		case servicePorts[p.ServicePort]:
+			return fmt.Errorf("duplicate service port number %d of extra port %q", p.ServicePort, p.Name)
+		}
+		names[p.Name], containerPorts[p.ContainerPort], servicePorts[p.ServicePort] = true, true, true
+		if p.NodePort == nil {
+			continue
+		}
+		if envoy.Type != operatorv1alpha1.NodePortServicePublishingType {
+			return fmt.Errorf("node port of extra port %q is only supported by network publishing type %s", p.Name,
+				operatorv1alpha1.NodePortServicePublishingType)
+		}
+		if nodePorts[*p.NodePort] {
+			return fmt.Errorf("duplicate node port number %d of extra port %q", *p.NodePort, p.Name)
+		}
+		nodePorts[*p.NodePort] = true
+	}
+	return nil
+}
+
+// HTTP3 validates publishing HTTP/3 for contour, returning an error if HTTP/3 is
+// enabled for a Route, an AWS Classic load balancer or TLS termination at an AWS
+// load balancer, none of which support UDP.
+func HTTP3(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if !envoy.HTTP3 {
+		return nil
+	}
+	switch envoy.Type {
+	case operatorv1alpha1.RoutePublishingType:
+		return fmt.Errorf("http3 is not supported by network publishing type %s", envoy.Type)
+	case operatorv1alpha1.LoadBalancerServicePublishingType:
+		params := envoy.LoadBalancer.ProviderParameters
+		if params.Type != operatorv1alpha1.AWSLoadBalancerProvider {
+			return nil
+		}
+		if params.AWS == nil || params.AWS.Type != operatorv1alpha1.AWSNetworkLoadBalancer {
+			return fmt.Errorf("http3 is not supported by aws load balancer type %s", operatorv1alpha1.AWSClassicLoadBalancer)
+		}
+		if params.AWS.TLSTermination != nil {
+			return fmt.Errorf("http3 is not supported by tls termination at the aws load balancer")
+		}
+	}
+	return nil
+}
+
+// SessionAffinity validates the session affinity of the Envoy Service of contour,
+// returning an error if session affinity is set for a Route, its parameters are
+// set without ClientIP session affinity, or the timeout is out of range.
+func SessionAffinity(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if envoy.SessionAffinity == corev1.ServiceAffinityClientIP && envoy.Type == operatorv1alpha1.RoutePublishingType {
+		return fmt.Errorf("session affinity is not supported by network publishing type %s", envoy.Type)
+	}
+	if envoy.SessionAffinityConfig == nil {
+		return nil
+	}
+	if envoy.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return fmt.Errorf("session affinity config is only supported by session affinity %s", corev1.ServiceAffinityClientIP)
+	}
+	if ip := envoy.SessionAffinityConfig.ClientIP; ip != nil && ip.TimeoutSeconds != nil &&
+		(*ip.TimeoutSeconds <= 0 || *ip.TimeoutSeconds > maxClientIPAffinitySeconds) {
+		return fmt.Errorf("invalid session affinity timeout %d; must be between 1 and %d seconds", *ip.TimeoutSeconds,
+			maxClientIPAffinitySeconds)
+	}
+	return nil
+}
+
+// AppProtocols validates the application protocol overrides of contour,
+// returning an error if an application protocol is not a qualified name.
+func AppProtocols(contour *operatorv1alpha1.Contour) error {
+	protocols := contour.Spec.NetworkPublishing.AppProtocols
+	for port, protocol := range map[string]*string{"http": protocols.HTTP, "https": protocols.HTTPS, "xds": protocols.XDS} {
+		if protocol == nil || *protocol == "" {
+			continue
+		}
+		if errs := validation.IsQualifiedName(*protocol); len(errs) > 0 {
+			return fmt.Errorf("invalid application protocol %q of port %s: %s", *protocol, port, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
 // NodePorts validates nodeports of contour, returning an error if the nodeports
 // do not meet the API specification.
 func NodePorts(contour *operatorv1alpha1.Contour) error {
@@ -120,7 +905,7 @@ func NodePorts(contour *operatorv1alpha1.Contour) error {
 		return fmt.Errorf("duplicate nodeport names detected")
 	}
 	if ports[0].PortNumber != nil && ports[1].PortNumber != nil {
-		if ports[0].PortNumber == ports[1].PortNumber {
+		if *ports[0].PortNumber == *ports[1].PortNumber {
 			return fmt.Errorf("duplicate nodeport port numbers detected")
 		}
 	}
@@ -128,6 +913,186 @@ func NodePorts(contour *operatorv1alpha1.Contour) error {
 	return nil
 }
 
+// HealthCheckNodePort validates the health check node port of the load
+// balancer of contour, returning an error if it is outside of the default node
+// port range or is used by another node port of contour.
+func HealthCheckNodePort(contour *operatorv1alpha1.Contour) error {
+	port := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.HealthCheckNodePort
+	if port == nil {
+		return nil
+	}
+	if *port < minNodePort || *port > maxNodePort {
+		return fmt.Errorf("health check node port %d must be within the node port range %d-%d", *port,
+			minNodePort, maxNodePort)
+	}
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	var others []*int32
+	for _, p := range envoy.NodePorts {
+		others = append(others, p.PortNumber)
+	}
+	for _, p := range envoy.ExtraPorts {
+		others = append(others, p.NodePort)
+	}
+	for _, svc := range envoy.AdditionalServices {
+		for _, p := range svc.Ports {
+			others = append(others, p.NodePort)
+		}
+	}
+	for _, other := range others {
+		if other != nil && *other == *port {
+			return fmt.Errorf("health check node port %d is used by another node port", *port)
+		}
+	}
+	return nil
+}
+
+// HTTPService validates the Envoy HTTP Service of contour, returning an error
+// if its type is unsupported or the network publishing type of contour does not
+// support publishing Envoy's HTTP network endpoint on a separate Service.
+func HTTPService(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if envoy.HTTPService == nil {
+		return nil
+	}
+	if envoy.Type == operatorv1alpha1.RoutePublishingType {
+		return fmt.Errorf("http service is not supported by network publishing type %s", envoy.Type)
+	}
+	switch envoy.HTTPService.Type {
+	case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType,
+		operatorv1alpha1.ClusterIPServicePublishingType:
+		return nil
+	}
+	return fmt.Errorf("invalid http service type %q; only %s, %s and %s are supported", envoy.HTTPService.Type,
+		operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType,
+		operatorv1alpha1.ClusterIPServicePublishingType)
+}
+
+// AdditionalServices validates the additional Envoy Services of contour,
+// returning an error if their names or ports are not unique, a name collides
+// with the Envoy HTTP Service, or a node port is set for a Service of a type
+// other than NodePortService.
+func AdditionalServices(contour *operatorv1alpha1.Contour) error {
+	envoyPorts := map[string]bool{"http": true, "https": true, "http3": contour.Spec.NetworkPublishing.Envoy.HTTP3}
+	for _, p := range contour.Spec.NetworkPublishing.Envoy.ExtraPorts {
+		envoyPorts[p.Name] = true
+	}
+	names := map[string]bool{}
+	for _, svc := range contour.Spec.NetworkPublishing.Envoy.AdditionalServices {
+		if errs := validation.IsDNS1123Label(svc.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid additional service name %q: %s", svc.Name, strings.Join(errs, ", "))
+		}
+		if svc.Name == "http" {
+			return fmt.Errorf("additional service name %q is reserved for the http service", svc.Name)
+		}
+		if names[svc.Name] {
+			return fmt.Errorf("duplicate additional service name %q", svc.Name)
+		}
+		names[svc.Name] = true
+		switch svc.Type {
+		case operatorv1alpha1.LoadBalancerServicePublishingType, operatorv1alpha1.NodePortServicePublishingType,
+			operatorv1alpha1.ClusterIPServicePublishingType:
+		default:
+			return fmt.Errorf("invalid type %q of additional service %q", svc.Type, svc.Name)
+		}
+		portNames, ports := map[string]bool{}, map[int32]bool{}
+		for _, p := range svc.Ports {
+			if !envoyPorts[p.Name] {
+				return fmt.Errorf("invalid port name %q of additional service %q; only \"http\", \"https\" and extra ports are supported",
+					p.Name, svc.Name)
+			}
+			if portNames[p.Name] || ports[p.Port] {
+				return fmt.Errorf("duplicate port %s/%d of additional service %q", p.Name, p.Port, svc.Name)
+			}
+			portNames[p.Name], ports[p.Port] = true, true
+			if p.NodePort != nil && svc.Type != operatorv1alpha1.NodePortServicePublishingType {
+				return fmt.Errorf("node port of additional service %q is only supported by type %s", svc.Name,
+					operatorv1alpha1.NodePortServicePublishingType)
+			}
+		}
+	}
+	return nil
+}
+
+// RootNamespaces validates the root namespaces of contour, returning an error if
+// a root namespace is not a valid namespace name.
+func RootNamespaces(contour *operatorv1alpha1.Contour) error {
+	for _, ns := range contour.Spec.RootNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid root namespace %q: %s", ns, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// ClusterDomain validates the cluster domain of contour, returning an error if
+// it is not a valid DNS subdomain.
+func ClusterDomain(contour *operatorv1alpha1.Contour) error {
+	domain := contour.Spec.ClusterDomain
+	if domain == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster domain %q: %s", domain, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ExternalIPs validates the external IPs of contour, returning an error if external
+// IPs are specified for a publishing type other than NodePortService or ClusterIPService,
+// or an external IP is not a valid IPv4 or IPv6 address.
+func ExternalIPs(contour *operatorv1alpha1.Contour) error {
+	envoy := contour.Spec.NetworkPublishing.Envoy
+	if len(envoy.ExternalIPs) == 0 {
+		return nil
+	}
+	if envoy.Type != operatorv1alpha1.NodePortServicePublishingType &&
+		envoy.Type != operatorv1alpha1.ClusterIPServicePublishingType {
+		return fmt.Errorf("external IPs are only supported by network publishing types %s and %s",
+			operatorv1alpha1.NodePortServicePublishingType, operatorv1alpha1.ClusterIPServicePublishingType)
+	}
+	var ipsFound []string
+	for _, ip := range envoy.ExternalIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid external IP %q, should be string with IPv4 or IPv6 format", ip)
+		}
+		if len(ipsFound) > 0 && slice.ContainsString(ipsFound, ip) {
+			return fmt.Errorf("duplicate external IP %q", ip)
+		}
+		ipsFound = append(ipsFound, ip)
+	}
+	return nil
+}
+
+// EnvoyOperatingSystems validates the Envoy node operating systems of contour,
+// returning an error if an operating system is not a valid label value or is
+// duplicated, or if the Envoy node selector selects the operating system label
+// with another value, since the Envoy pods would not be scheduled onto any node.
+func EnvoyOperatingSystems(contour *operatorv1alpha1.Contour) error {
+	placement := contour.Spec.NodePlacement
+	if placement == nil || placement.Envoy == nil {
+		return nil
+	}
+	var osesFound []string
+	for _, os := range placement.Envoy.OperatingSystems {
+		if os == "" {
+			return fmt.Errorf("invalid empty envoy operating system")
+		}
+		if errs := validation.IsValidLabelValue(os); len(errs) > 0 {
+			return fmt.Errorf("invalid envoy operating system %q: %s", os, strings.Join(errs, ", "))
+		}
+		if slice.ContainsString(osesFound, os) {
+			return fmt.Errorf("duplicate envoy operating system %q", os)
+		}
+		osesFound = append(osesFound, os)
+	}
+	if os, found := placement.Envoy.NodeSelector[corev1.LabelOSStable]; found &&
+		!slice.ContainsString(objcontour.EnvoyOperatingSystems(contour), os) {
+		return fmt.Errorf("envoy node selector %s=%s does not match the envoy operating systems %s",
+			corev1.LabelOSStable, os, strings.Join(objcontour.EnvoyOperatingSystems(contour), ","))
+	}
+	return nil
+}
+
 // LoadBalancerAddress validates LoadBalancer "address" parameter of contour, returning an
 // error if "address" does not meet the API specification.
 func LoadBalancerAddress(contour *operatorv1alpha1.Contour) error {
@@ -154,24 +1119,124 @@ func LoadBalancerAddress(contour *operatorv1alpha1.Contour) error {
 // and error if parameters for different provider are specified the for the one specified
 // with "type" parameter.
 func LoadBalancerProvider(contour *operatorv1alpha1.Contour) error {
-	switch contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type {
-	case operatorv1alpha1.AWSLoadBalancerProvider:
-		if contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure != nil ||
-			contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.GCP != nil {
-			return fmt.Errorf("aws provider chosen, other providers parameters should not be specified")
+	params := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters
+	paramsSet := map[operatorv1alpha1.LoadBalancerProviderType]bool{
+		operatorv1alpha1.AWSLoadBalancerProvider:     params.AWS != nil,
+		operatorv1alpha1.AzureLoadBalancerProvider:   params.Azure != nil,
+		operatorv1alpha1.GCPLoadBalancerProvider:     params.GCP != nil,
+		operatorv1alpha1.MetalLBLoadBalancerProvider: params.MetalLB != nil,
+		operatorv1alpha1.GenericLoadBalancerProvider: params.Generic != nil,
+	}
+	if _, ok := paramsSet[params.Type]; !ok {
+		return nil
+	}
+	for provider, set := range paramsSet {
+		if set && provider != params.Type {
+			return fmt.Errorf("%s provider chosen, other providers parameters should not be specified",
+				strings.ToLower(string(params.Type)))
 		}
-	case operatorv1alpha1.AzureLoadBalancerProvider:
-		if contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS != nil ||
-			contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.GCP != nil {
-			return fmt.Errorf("azure provider chosen, other providers parameters should not be specified")
+	}
+	if params.Type == operatorv1alpha1.GenericLoadBalancerProvider && params.Generic != nil {
+		if errs := apivalidation.ValidateAnnotations(params.Generic.Annotations, field.NewPath("annotations")); len(errs) > 0 {
+			return fmt.Errorf("invalid generic provider parameters: %s", errs.ToAggregate().Error())
 		}
-	case operatorv1alpha1.GCPLoadBalancerProvider:
-		if contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS != nil ||
-			contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure != nil {
-			return fmt.Errorf("gcp provider chosen, other providers parameters should not be specified")
+	}
+
+	return nil
+}
+
+// AWSNetworkLoadBalancer validates the AWS Network Load Balancer parameters of
+// contour, returning an error if subnets or targetType are specified for a Classic
+// load balancer, an HTTP backend protocol is specified for an NLB, or the number of
+// subnets does not match the number of allocation IDs.
+func AWSNetworkLoadBalancer(contour *operatorv1alpha1.Contour) error {
+	params := contour.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters
+	if params.Type != operatorv1alpha1.AWSLoadBalancerProvider || params.AWS == nil {
+		return nil
+	}
+	if params.AWS.Type != operatorv1alpha1.AWSNetworkLoadBalancer {
+		if len(params.AWS.Subnets) > 0 || params.AWS.TargetType != "" {
+			return fmt.Errorf("subnets and targetType are only supported by aws load balancer type %s",
+				operatorv1alpha1.AWSNetworkLoadBalancer)
 		}
+		return nil
 	}
+	if params.AWS.TLSTermination != nil && params.AWS.TLSTermination.BackendProtocol == operatorv1alpha1.AWSHTTPBackendProtocol {
+		return fmt.Errorf("backend protocol %s is not supported by aws load balancer type %s",
+			operatorv1alpha1.AWSHTTPBackendProtocol, operatorv1alpha1.AWSNetworkLoadBalancer)
+	}
+	if len(params.AWS.AllocationIDs) > 0 && len(params.AWS.Subnets) > 0 &&
+		len(params.AWS.AllocationIDs) != len(params.AWS.Subnets) {
+		return fmt.Errorf("number of allocationIds %d does not match number of subnets %d",
+			len(params.AWS.AllocationIDs), len(params.AWS.Subnets))
+	}
+	return nil
+}
+
+// AllocateLoadBalancerNodePorts validates disabling the node ports of the
+// load balancer of contour, returning an error if the load balancer is an AWS
+// Classic load balancer or an AWS Network Load Balancer with instance targets.
+func AllocateLoadBalancerNodePorts(contour *operatorv1alpha1.Contour) error {
+	lb := contour.Spec.NetworkPublishing.Envoy.LoadBalancer
+	if lb.AllocateLoadBalancerNodePorts == nil || *lb.AllocateLoadBalancerNodePorts ||
+		lb.ProviderParameters.Type != operatorv1alpha1.AWSLoadBalancerProvider {
+		return nil
+	}
+	aws := lb.ProviderParameters.AWS
+	if aws == nil || aws.Type != operatorv1alpha1.AWSNetworkLoadBalancer ||
+		aws.TargetType != operatorv1alpha1.AWSNetworkLoadBalancerIPTarget {
+		return fmt.Errorf("load balancer node ports are required by aws load balancers without %s targets",
+			operatorv1alpha1.AWSNetworkLoadBalancerIPTarget)
+	}
+	return nil
+}
+
+// AzureLoadBalancer validates the Azure load balancer parameters of contour, returning
+// an error if publicIPName is specified for an internal load balancer.
+func AzureLoadBalancer(contour *operatorv1alpha1.Contour) error {
+	lb := contour.Spec.NetworkPublishing.Envoy.LoadBalancer
+	if lb.ProviderParameters.Type != operatorv1alpha1.AzureLoadBalancerProvider || lb.ProviderParameters.Azure == nil {
+		return nil
+	}
+	if lb.ProviderParameters.Azure.PublicIPName != nil && lb.Scope == operatorv1alpha1.InternalLoadBalancer {
+		return fmt.Errorf("publicIPName is only supported by azure load balancers with scope %s",
+			operatorv1alpha1.ExternalLoadBalancer)
+	}
+	return nil
+}
 
+// GCPLoadBalancer validates the GCP load balancer parameters of contour, returning
+// an error if globalAccess is specified for an external load balancer.
+func GCPLoadBalancer(contour *operatorv1alpha1.Contour) error {
+	lb := contour.Spec.NetworkPublishing.Envoy.LoadBalancer
+	if lb.ProviderParameters.Type != operatorv1alpha1.GCPLoadBalancerProvider || lb.ProviderParameters.GCP == nil {
+		return nil
+	}
+	if lb.ProviderParameters.GCP.GlobalAccess && lb.Scope != operatorv1alpha1.InternalLoadBalancer {
+		return fmt.Errorf("globalAccess is only supported by gcp load balancers with scope %s",
+			operatorv1alpha1.InternalLoadBalancer)
+	}
+	return nil
+}
+
+// Route validates the Route network publishing parameters of contour, returning
+// an error if the route.openshift.io API group is not served by the cluster or
+// the parameters do not meet the API specification.
+func Route(cli client.Client, contour *operatorv1alpha1.Contour) error {
+	params := contour.Spec.NetworkPublishing.Envoy.Route
+	if params != nil && params.DestinationCACertificate != nil &&
+		params.Termination != operatorv1alpha1.ReencryptRouteTermination {
+		return fmt.Errorf("destinationCACertificate is only supported with %s route termination",
+			operatorv1alpha1.ReencryptRouteTermination)
+	}
+	available, err := objroute.APIAvailable(cli)
+	if err != nil {
+		return fmt.Errorf("failed to verify the existence of the %s api group: %w", objroute.GroupVersionKind.Group, err)
+	}
+	if !available {
+		return fmt.Errorf("network publishing type %s requires the %s api group",
+			operatorv1alpha1.RoutePublishingType, objroute.GroupVersionKind.Group)
+	}
 	return nil
 }
 
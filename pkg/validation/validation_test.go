@@ -21,11 +21,15 @@ import (
 	"testing"
 
 	operatorv1alpha1 "github.com/projectcontour/contour-operator/api/v1alpha1"
+	objutil "github.com/projectcontour/contour-operator/internal/objects"
+	objroute "github.com/projectcontour/contour-operator/internal/objects/route"
 	"github.com/projectcontour/contour-operator/internal/operator"
 	"github.com/projectcontour/contour-operator/pkg/validation"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -275,6 +279,12 @@ func TestLoadBalancerProvider(t *testing.T) {
 			additionalProvider: "Azure",
 			expected:           false,
 		},
+		{
+			description:        "metallb provider with aws provider parameters specified",
+			provider:           "MetalLB",
+			additionalProvider: "AWS",
+			expected:           false,
+		},
 	}
 
 	name := "test-validation"
@@ -329,8 +339,2172 @@ func TestLoadBalancerProvider(t *testing.T) {
 			case "Azure":
 				cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure.Subnet = &testString
 			}
+		case "MetalLB":
+			cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Type = operatorv1alpha1.MetalLBLoadBalancerProvider
+			cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.MetalLB = &operatorv1alpha1.MetalLBLoadBalancerParameters{}
+			switch tc.additionalProvider {
+			case "AWS":
+				cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS.AllocationIDs = strings.Split(testString, "")
+			}
+		}
+		err := validation.LoadBalancerProvider(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAWSNetworkLoadBalancer(t *testing.T) {
+	testCases := []struct {
+		description string
+		params      *operatorv1alpha1.AWSLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "default aws parameters",
+			expected:    true,
+		},
+		{
+			description: "nlb with matching allocation ids and subnets",
+			params: &operatorv1alpha1.AWSLoadBalancerParameters{
+				Type:          operatorv1alpha1.AWSNetworkLoadBalancer,
+				AllocationIDs: []string{"eipalloc-0123456789", "eipalloc-1234567890"},
+				Subnets:       []string{"subnet-0123456789", "subnet-1234567890"},
+				TargetType:    operatorv1alpha1.AWSNetworkLoadBalancerIPTarget,
+			},
+			expected: true,
+		},
+		{
+			description: "nlb with mismatched allocation ids and subnets",
+			params: &operatorv1alpha1.AWSLoadBalancerParameters{
+				Type:          operatorv1alpha1.AWSNetworkLoadBalancer,
+				AllocationIDs: []string{"eipalloc-0123456789"},
+				Subnets:       []string{"subnet-0123456789", "subnet-1234567890"},
+			},
+			expected: false,
+		},
+		{
+			description: "nlb with http backend protocol",
+			params: &operatorv1alpha1.AWSLoadBalancerParameters{
+				Type: operatorv1alpha1.AWSNetworkLoadBalancer,
+				TLSTermination: &operatorv1alpha1.AWSTLSTermination{
+					CertificateARN:  "arn:aws:acm:us-east-1:123456789012:certificate/test",
+					BackendProtocol: operatorv1alpha1.AWSHTTPBackendProtocol,
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "classic load balancer with subnets",
+			params: &operatorv1alpha1.AWSLoadBalancerParameters{
+				Type:    operatorv1alpha1.AWSClassicLoadBalancer,
+				Subnets: []string{"subnet-0123456789"},
+			},
+			expected: false,
+		},
+		{
+			description: "classic load balancer with target type",
+			params: &operatorv1alpha1.AWSLoadBalancerParameters{
+				Type:       operatorv1alpha1.AWSClassicLoadBalancer,
+				TargetType: operatorv1alpha1.AWSNetworkLoadBalancerInstanceTarget,
+			},
+			expected: false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+					LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+						Scope: operatorv1alpha1.ExternalLoadBalancer,
+						ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+							Type: operatorv1alpha1.AWSLoadBalancerProvider,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.AWS = tc.params
+		err := validation.AWSNetworkLoadBalancer(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestGCPLoadBalancer(t *testing.T) {
+	testCases := []struct {
+		description string
+		scope       operatorv1alpha1.LoadBalancerScope
+		params      *operatorv1alpha1.GCPLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "default gcp parameters",
+			scope:       operatorv1alpha1.ExternalLoadBalancer,
+			expected:    true,
+		},
+		{
+			description: "internal load balancer with global access",
+			scope:       operatorv1alpha1.InternalLoadBalancer,
+			params:      &operatorv1alpha1.GCPLoadBalancerParameters{GlobalAccess: true},
+			expected:    true,
+		},
+		{
+			description: "external load balancer with global access",
+			scope:       operatorv1alpha1.ExternalLoadBalancer,
+			params:      &operatorv1alpha1.GCPLoadBalancerParameters{GlobalAccess: true},
+			expected:    false,
+		},
+		{
+			description: "external load balancer with network endpoint groups",
+			scope:       operatorv1alpha1.ExternalLoadBalancer,
+			params:      &operatorv1alpha1.GCPLoadBalancerParameters{NetworkEndpointGroups: true},
+			expected:    true,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+					LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+						ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+							Type: operatorv1alpha1.GCPLoadBalancerProvider,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope = tc.scope
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.GCP = tc.params
+		err := validation.GCPLoadBalancer(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAzureLoadBalancer(t *testing.T) {
+	pipName := "envoy-pip"
+
+	testCases := []struct {
+		description string
+		scope       operatorv1alpha1.LoadBalancerScope
+		params      *operatorv1alpha1.AzureLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "default azure parameters",
+			scope:       operatorv1alpha1.ExternalLoadBalancer,
+			expected:    true,
+		},
+		{
+			description: "external load balancer with public ip name",
+			scope:       operatorv1alpha1.ExternalLoadBalancer,
+			params:      &operatorv1alpha1.AzureLoadBalancerParameters{PublicIPName: &pipName},
+			expected:    true,
+		},
+		{
+			description: "internal load balancer with public ip name",
+			scope:       operatorv1alpha1.InternalLoadBalancer,
+			params:      &operatorv1alpha1.AzureLoadBalancerParameters{PublicIPName: &pipName},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+					LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+						ProviderParameters: operatorv1alpha1.ProviderLoadBalancerParameters{
+							Type: operatorv1alpha1.AzureLoadBalancerProvider,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.Scope = tc.scope
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters.Azure = tc.params
+		err := validation.AzureLoadBalancer(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestGenericLoadBalancer(t *testing.T) {
+	testCases := []struct {
+		description string
+		params      operatorv1alpha1.ProviderLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "generic provider without parameters",
+			params: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.GenericLoadBalancerProvider,
+			},
+			expected: true,
+		},
+		{
+			description: "generic provider with valid annotations",
+			params: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.GenericLoadBalancerProvider,
+				Generic: &operatorv1alpha1.GenericLoadBalancerParameters{
+					Annotations: map[string]string{
+						"service.beta.kubernetes.io/do-loadbalancer-protocol": "tcp",
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "generic provider with invalid annotation name",
+			params: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.GenericLoadBalancerProvider,
+				Generic: &operatorv1alpha1.GenericLoadBalancerParameters{
+					Annotations: map[string]string{
+						"do-loadbalancer protocol": "tcp",
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "generic provider with aws provider parameters specified",
+			params: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.GenericLoadBalancerProvider,
+				AWS:  &operatorv1alpha1.AWSLoadBalancerParameters{},
+			},
+			expected: false,
+		},
+		{
+			description: "aws provider with generic provider parameters specified",
+			params: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type:    operatorv1alpha1.AWSLoadBalancerProvider,
+				Generic: &operatorv1alpha1.GenericLoadBalancerParameters{},
+			},
+			expected: false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = tc.params
+		err := validation.LoadBalancerProvider(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestExternalIPs(t *testing.T) {
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		ips         []string
+		expected    bool
+	}{
+		{
+			description: "no external ips",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "nodeport service with ipv4 and ipv6 external ips",
+			netType:     operatorv1alpha1.NodePortServicePublishingType,
+			ips:         []string{"192.0.2.10", "2001:db8::10"},
+			expected:    true,
+		},
+		{
+			description: "clusterip service with external ip",
+			netType:     operatorv1alpha1.ClusterIPServicePublishingType,
+			ips:         []string{"192.0.2.10"},
+			expected:    true,
+		},
+		{
+			description: "load balancer service with external ip",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ips:         []string{"192.0.2.10"},
+			expected:    false,
+		},
+		{
+			description: "invalid external ip",
+			netType:     operatorv1alpha1.ClusterIPServicePublishingType,
+			ips:         []string{"192.0.2"},
+			expected:    false,
+		},
+		{
+			description: "duplicate external ips",
+			netType:     operatorv1alpha1.ClusterIPServicePublishingType,
+			ips:         []string{"192.0.2.10", "192.0.2.10"},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.ExternalIPs = tc.ips
+		err := validation.ExternalIPs(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestWatchedNamespace(t *testing.T) {
+	testCases := []struct {
+		description     string
+		watchNamespaces []string
+		expected        bool
+	}{
+		{
+			description: "all namespaces watched",
+			expected:    true,
+		},
+		{
+			description:     "spec namespace watched",
+			watchNamespaces: []string{"contour-operator", "projectcontour"},
+			expected:        true,
+		},
+		{
+			description:     "spec namespace not watched",
+			watchNamespaces: []string{"contour-operator"},
+			expected:        false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := validation.WatchedNamespace(cntr, tc.watchNamespaces)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	testCases := []struct {
+		description string
+		policy      *operatorv1alpha1.PolicyParameters
+		expected    bool
+	}{
+		{
+			description: "no policy",
+			expected:    true,
+		},
+		{
+			description: "valid request and response header policies",
+			policy: &operatorv1alpha1.PolicyParameters{
+				RequestHeaders: &operatorv1alpha1.HeadersPolicy{
+					Set:    map[string]string{"X-Environment": "production"},
+					Remove: []string{"X-Debug"},
+				},
+				ResponseHeaders: &operatorv1alpha1.HeadersPolicy{
+					Set: map[string]string{"Host": "example.com"},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "request header policy sets host",
+			policy: &operatorv1alpha1.PolicyParameters{
+				RequestHeaders: &operatorv1alpha1.HeadersPolicy{
+					Set: map[string]string{"host": "example.com"},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "invalid header name to remove",
+			policy: &operatorv1alpha1.PolicyParameters{
+				ResponseHeaders: &operatorv1alpha1.HeadersPolicy{
+					Remove: []string{"X Debug"},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "invalid header name to set",
+			policy: &operatorv1alpha1.PolicyParameters{
+				RequestHeaders: &operatorv1alpha1.HeadersPolicy{
+					Set: map[string]string{"X-Environment:": "production"},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.Policy = tc.policy
+		err := validation.Policy(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	testCases := []struct {
+		description string
+		hsts        *operatorv1alpha1.HSTSPolicy
+		expected    bool
+	}{
+		{
+			description: "no hsts",
+			expected:    true,
+		},
+		{
+			description: "hsts without preload",
+			hsts:        &operatorv1alpha1.HSTSPolicy{MaxAgeSeconds: 300},
+			expected:    true,
+		},
+		{
+			description: "hsts preload",
+			hsts:        &operatorv1alpha1.HSTSPolicy{MaxAgeSeconds: 63072000, IncludeSubDomains: true, Preload: true},
+			expected:    true,
+		},
+		{
+			description: "hsts preload without subdomains",
+			hsts:        &operatorv1alpha1.HSTSPolicy{MaxAgeSeconds: 63072000, Preload: true},
+			expected:    false,
+		},
+		{
+			description: "hsts preload with short max age",
+			hsts:        &operatorv1alpha1.HSTSPolicy{MaxAgeSeconds: 300, IncludeSubDomains: true, Preload: true},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.Security = &operatorv1alpha1.SecurityParameters{
+			Headers: &operatorv1alpha1.SecurityHeaders{HSTS: tc.hsts},
+		}
+		err := validation.SecurityHeaders(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	testCases := []struct {
+		description string
+		timeouts    *operatorv1alpha1.TimeoutParameters
+		expected    bool
+	}{
+		{
+			description: "no timeouts",
+			expected:    true,
+		},
+		{
+			description: "valid timeouts",
+			timeouts: &operatorv1alpha1.TimeoutParameters{
+				RequestTimeout:                "infinity",
+				StreamIdleTimeout:             "1h30m",
+				ConnectionShutdownGracePeriod: "10s",
+			},
+			expected: true,
+		},
+		{
+			description: "invalid stream idle timeout",
+			timeouts:    &operatorv1alpha1.TimeoutParameters{StreamIdleTimeout: "5 minutes"},
+			expected:    false,
+		},
+		{
+			description: "infinite connection shutdown grace period",
+			timeouts:    &operatorv1alpha1.TimeoutParameters{ConnectionShutdownGracePeriod: "infinity"},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.Timeouts = tc.timeouts
+		err := validation.Timeouts(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	testCases := []struct {
+		description string
+		accessLog   *operatorv1alpha1.AccessLogParameters
+		expected    bool
+	}{
+		{
+			description: "no access log",
+			expected:    true,
+		},
+		{
+			description: "json fields",
+			accessLog: &operatorv1alpha1.AccessLogParameters{
+				Format:                 operatorv1alpha1.JSONAccessLogFormat,
+				JSONFields:             []string{"@timestamp", "host=%REQ(:AUTHORITY)%"},
+				IncludeContourInstance: true,
+			},
+			expected: true,
+		},
+		{
+			description: "json fields with envoy format",
+			accessLog: &operatorv1alpha1.AccessLogParameters{
+				JSONFields: []string{"@timestamp"},
+			},
+			expected: false,
+		},
+		{
+			description: "duplicate json field",
+			accessLog: &operatorv1alpha1.AccessLogParameters{
+				Format:     operatorv1alpha1.JSONAccessLogFormat,
+				JSONFields: []string{"path", "path=%REQ(:PATH)%"},
+			},
+			expected: false,
+		},
+		{
+			description: "json field with empty format",
+			accessLog: &operatorv1alpha1.AccessLogParameters{
+				Format:     operatorv1alpha1.JSONAccessLogFormat,
+				JSONFields: []string{"host="},
+			},
+			expected: false,
+		},
+		{
+			description: "invalid json field name",
+			accessLog: &operatorv1alpha1.AccessLogParameters{
+				Format:     operatorv1alpha1.JSONAccessLogFormat,
+				JSONFields: []string{"request id"},
+			},
+			expected: false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.AccessLog = tc.accessLog
+		err := validation.AccessLog(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	testCases := []struct {
+		description  string
+		version      string
+		gatewayClass string
+		expected     bool
+	}{
+		{
+			description: "no version",
+			expected:    true,
+		},
+		{
+			description: "supported version",
+			version:     "v1.15.1",
+			expected:    true,
+		},
+		{
+			description: "unsupported version",
+			version:     "v1.2.0",
+			expected:    false,
+		},
+		{
+			description:  "supported version with a gatewayclass",
+			version:      "v1.15.1",
+			gatewayClass: "contour",
+			expected:     false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.Version = tc.version
+		cntr.Spec.GatewayClassRef = nil
+		if tc.gatewayClass != "" {
+			cntr.Spec.GatewayClassRef = &tc.gatewayClass
+		}
+		err := validation.Version(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestImageFlavor(t *testing.T) {
+	testCases := []struct {
+		description  string
+		version      string
+		flavor       operatorv1alpha1.ImageFlavor
+		gatewayClass string
+		expected     bool
+	}{
+		{
+			description: "standard flavor without version",
+			flavor:      operatorv1alpha1.StandardImageFlavor,
+			expected:    true,
+		},
+		{
+			description: "distroless flavor",
+			version:     "v1.15.1",
+			flavor:      operatorv1alpha1.DistrolessImageFlavor,
+			expected:    true,
+		},
+		{
+			description: "distroless flavor without version",
+			flavor:      operatorv1alpha1.DistrolessImageFlavor,
+			expected:    false,
+		},
+		{
+			description: "flavor without images",
+			version:     "v1.15.1",
+			flavor:      operatorv1alpha1.FIPSImageFlavor,
+			expected:    false,
+		},
+		{
+			description:  "distroless flavor with a gatewayclass",
+			version:      "v1.15.1",
+			flavor:       operatorv1alpha1.DistrolessImageFlavor,
+			gatewayClass: "contour",
+			expected:     false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.Version = tc.version
+		cntr.Spec.ImageFlavor = tc.flavor
+		cntr.Spec.GatewayClassRef = nil
+		if tc.gatewayClass != "" {
+			cntr.Spec.GatewayClassRef = &tc.gatewayClass
+		}
+		err := validation.ImageFlavor(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestClusterDomain(t *testing.T) {
+	testCases := map[string]bool{
+		"":               true,
+		"cluster.local":  true,
+		"corp.test":      true,
+		"Cluster.Local":  false,
+		"cluster..local": false,
+	}
+	cntr := &operatorv1alpha1.Contour{}
+	for domain, expected := range testCases {
+		cntr.Spec.ClusterDomain = domain
+		err := validation.ClusterDomain(cntr)
+		if err != nil && expected {
+			t.Errorf("%q: failed with error: %v", domain, err)
+		}
+		if err == nil && !expected {
+			t.Errorf("%q: expected to fail but received no error", domain)
+		}
+	}
+}
+
+func TestHTTPService(t *testing.T) {
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		httpService *operatorv1alpha1.HTTPServiceParameters
+		expected    bool
+	}{
+		{
+			description: "no http service",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			expected:    true,
+		},
+		{
+			description: "internal load balancer http service",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			httpService: &operatorv1alpha1.HTTPServiceParameters{
+				Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+				LoadBalancer: operatorv1alpha1.LoadBalancerStrategy{
+					Scope: operatorv1alpha1.InternalLoadBalancer,
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "nodeport http service",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			httpService: &operatorv1alpha1.HTTPServiceParameters{Type: operatorv1alpha1.NodePortServicePublishingType},
+			expected:    true,
+		},
+		{
+			description: "http service of route",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			httpService: &operatorv1alpha1.HTTPServiceParameters{Type: operatorv1alpha1.ClusterIPServicePublishingType},
+			expected:    false,
+		},
+		{
+			description: "route http service",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			httpService: &operatorv1alpha1.HTTPServiceParameters{Type: operatorv1alpha1.RoutePublishingType},
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.HTTPService = tc.httpService
+		err := validation.HTTPService(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAdditionalServices(t *testing.T) {
+	nodePort := int32(30080)
+	testCases := []struct {
+		description string
+		services    []operatorv1alpha1.AdditionalEnvoyService
+		expected    bool
+	}{
+		{
+			description: "no additional services",
+			expected:    true,
+		},
+		{
+			description: "internal and cluster ip services",
+			services: []operatorv1alpha1.AdditionalEnvoyService{
+				{Name: "internal", Type: operatorv1alpha1.LoadBalancerServicePublishingType},
+				{Name: "mesh", Type: operatorv1alpha1.ClusterIPServicePublishingType,
+					Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "https", Port: 8443}}},
+			},
+			expected: true,
+		},
+		{
+			description: "duplicate service names",
+			services: []operatorv1alpha1.AdditionalEnvoyService{
+				{Name: "internal", Type: operatorv1alpha1.LoadBalancerServicePublishingType},
+				{Name: "internal", Type: operatorv1alpha1.ClusterIPServicePublishingType},
+			},
+			expected: false,
+		},
+		{
+			description: "reserved service name",
+			services: []operatorv1alpha1.AdditionalEnvoyService{
+				{Name: "http", Type: operatorv1alpha1.LoadBalancerServicePublishingType},
+			},
+			expected: false,
+		},
+		{
+			description: "duplicate port numbers",
+			services: []operatorv1alpha1.AdditionalEnvoyService{
+				{Name: "internal", Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+					Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "http", Port: 80}, {Name: "https", Port: 80}}},
+			},
+			expected: false,
+		},
+		{
+			description: "node port of a load balancer service",
+			services: []operatorv1alpha1.AdditionalEnvoyService{
+				{Name: "internal", Type: operatorv1alpha1.LoadBalancerServicePublishingType,
+					Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "http", Port: 80, NodePort: &nodePort}}},
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.AdditionalServices = tc.services
+		err := validation.AdditionalServices(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestExtraPorts(t *testing.T) {
+	nodePort := int32(30432)
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		ports       []operatorv1alpha1.EnvoyExtraPort
+		expected    bool
+	}{
+		{
+			description: "no extra ports",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "tcp proxy port",
+			netType:     operatorv1alpha1.NodePortServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyExtraPort{{Name: "postgres", ContainerPort: 15432, ServicePort: 5432, NodePort: &nodePort}},
+			expected:    true,
+		},
+		{
+			description: "reserved name",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyExtraPort{{Name: "https", ContainerPort: 15432, ServicePort: 5432}},
+			expected:    false,
+		},
+		{
+			description: "container port of http",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyExtraPort{{Name: "postgres", ContainerPort: 8080, ServicePort: 5432}},
+			expected:    false,
+		},
+		{
+			description: "service port of https",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyExtraPort{{Name: "postgres", ContainerPort: 15432, ServicePort: 443}},
+			expected:    false,
+		},
+		{
+			description: "node port of a load balancer service",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyExtraPort{{Name: "postgres", ContainerPort: 15432, ServicePort: 5432, NodePort: &nodePort}},
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.ContainerPorts = []operatorv1alpha1.ContainerPort{
+			{Name: "http", PortNumber: 8080},
+			{Name: "https", PortNumber: 8443},
+		}
+		cntr.Spec.NetworkPublishing.Envoy.ExtraPorts = tc.ports
+		err := validation.ExtraPorts(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestHTTP3(t *testing.T) {
+	nlb := &operatorv1alpha1.AWSLoadBalancerParameters{Type: operatorv1alpha1.AWSNetworkLoadBalancer}
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		provider    operatorv1alpha1.ProviderLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "nodeport service",
+			netType:     operatorv1alpha1.NodePortServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "aws network load balancer",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			provider:    operatorv1alpha1.ProviderLoadBalancerParameters{Type: operatorv1alpha1.AWSLoadBalancerProvider, AWS: nlb},
+			expected:    true,
+		},
+		{
+			description: "aws classic load balancer",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			provider:    operatorv1alpha1.ProviderLoadBalancerParameters{Type: operatorv1alpha1.AWSLoadBalancerProvider},
+			expected:    false,
+		},
+		{
+			description: "route",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = tc.provider
+		cntr.Spec.NetworkPublishing.Envoy.HTTP3 = true
+		err := validation.HTTP3(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestSessionAffinity(t *testing.T) {
+	timeout, tooLong := int32(600), int32(86401)
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		affinity    corev1.ServiceAffinity
+		timeout     *int32
+		expected    bool
+	}{
+		{
+			description: "no session affinity",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			expected:    true,
+		},
+		{
+			description: "client ip session affinity with a timeout",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			affinity:    corev1.ServiceAffinityClientIP,
+			timeout:     &timeout,
+			expected:    true,
+		},
+		{
+			description: "timeout without client ip session affinity",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			timeout:     &timeout,
+			expected:    false,
+		},
+		{
+			description: "timeout out of range",
+			netType:     operatorv1alpha1.NodePortServicePublishingType,
+			affinity:    corev1.ServiceAffinityClientIP,
+			timeout:     &tooLong,
+			expected:    false,
+		},
+		{
+			description: "client ip session affinity of a route",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			affinity:    corev1.ServiceAffinityClientIP,
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.SessionAffinity = tc.affinity
+		if tc.timeout != nil {
+			cntr.Spec.NetworkPublishing.Envoy.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: tc.timeout},
+			}
+		}
+		err := validation.SessionAffinity(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAllocateLoadBalancerNodePorts(t *testing.T) {
+	allocate, disable := true, false
+	testCases := []struct {
+		description string
+		allocate    *bool
+		provider    operatorv1alpha1.ProviderLoadBalancerParameters
+		expected    bool
+	}{
+		{
+			description: "node ports of an aws classic load balancer",
+			allocate:    &allocate,
+			provider:    operatorv1alpha1.ProviderLoadBalancerParameters{Type: operatorv1alpha1.AWSLoadBalancerProvider},
+			expected:    true,
+		},
+		{
+			description: "no node ports for an aws network load balancer with ip targets",
+			allocate:    &disable,
+			provider: operatorv1alpha1.ProviderLoadBalancerParameters{
+				Type: operatorv1alpha1.AWSLoadBalancerProvider,
+				AWS: &operatorv1alpha1.AWSLoadBalancerParameters{
+					Type:       operatorv1alpha1.AWSNetworkLoadBalancer,
+					TargetType: operatorv1alpha1.AWSNetworkLoadBalancerIPTarget,
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "no node ports for a generic load balancer",
+			allocate:    &disable,
+			provider:    operatorv1alpha1.ProviderLoadBalancerParameters{Type: operatorv1alpha1.GenericLoadBalancerProvider},
+			expected:    true,
+		},
+		{
+			description: "no node ports for an aws classic load balancer",
+			allocate:    &disable,
+			provider:    operatorv1alpha1.ProviderLoadBalancerParameters{Type: operatorv1alpha1.AWSLoadBalancerProvider},
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.AllocateLoadBalancerNodePorts = tc.allocate
+		cntr.Spec.NetworkPublishing.Envoy.LoadBalancer.ProviderParameters = tc.provider
+		err := validation.AllocateLoadBalancerNodePorts(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestAppProtocols(t *testing.T) {
+	testCases := map[string]bool{
+		"":                  true,
+		"http":              true,
+		"kubernetes.io/h2c": true,
+		"h2c/":              false,
+		"not a protocol":    false,
+	}
+	for protocol, expected := range testCases {
+		protocol := protocol
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.AppProtocols.HTTP = &protocol
+		err := validation.AppProtocols(cntr)
+		if err != nil && expected {
+			t.Errorf("%q: failed with error: %v", protocol, err)
+		}
+		if err == nil && !expected {
+			t.Errorf("%q: expected to fail but received no error", protocol)
+		}
+	}
+}
+
+func TestServicePorts(t *testing.T) {
+	testCases := []struct {
+		description string
+		netType     operatorv1alpha1.NetworkPublishingType
+		ports       []operatorv1alpha1.EnvoyServicePortMapping
+		expected    bool
+	}{
+		{
+			description: "no service ports",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			expected:    true,
+		},
+		{
+			description: "renamed and renumbered ports",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports: []operatorv1alpha1.EnvoyServicePortMapping{
+				{ContainerPort: "http", Name: "web", Port: 8080},
+				{ContainerPort: "https", Name: "web-tls", Port: 8443},
+			},
+			expected: true,
+		},
+		{
+			description: "duplicate container port",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports: []operatorv1alpha1.EnvoyServicePortMapping{
+				{ContainerPort: "http", Name: "web", Port: 8080},
+				{ContainerPort: "http", Name: "web-2", Port: 8081},
+			},
+			expected: false,
+		},
+		{
+			description: "port number of the default https port",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyServicePortMapping{{ContainerPort: "http", Name: "web", Port: 443}},
+			expected:    false,
+		},
+		{
+			description: "undeclared container port",
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			ports:       []operatorv1alpha1.EnvoyServicePortMapping{{ContainerPort: "grpc", Name: "grpc", Port: 9000}},
+			expected:    false,
+		},
+		{
+			description: "service ports of a route",
+			netType:     operatorv1alpha1.RoutePublishingType,
+			ports:       []operatorv1alpha1.EnvoyServicePortMapping{{ContainerPort: "https", Name: "web-tls", Port: 8443}},
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.NetworkPublishing.Envoy.ContainerPorts = []operatorv1alpha1.ContainerPort{
+			{Name: "http", PortNumber: 8080},
+			{Name: "https", PortNumber: 8443},
+		}
+		cntr.Spec.NetworkPublishing.Envoy.ServicePorts = tc.ports
+		err := validation.ServicePorts(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestPublishingPreset(t *testing.T) {
+	gcRef := "contour"
+	testCases := []struct {
+		description string
+		preset      operatorv1alpha1.NetworkPublishingPreset
+		netType     operatorv1alpha1.NetworkPublishingType
+		gcRef       *string
+		expected    bool
+	}{
+		{
+			description: "no preset",
+			netType:     operatorv1alpha1.ClusterIPServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "local development preset with the default type",
+			preset:      operatorv1alpha1.LocalDevelopmentPublishingPreset,
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "local development preset with node ports",
+			preset:      operatorv1alpha1.LocalDevelopmentPublishingPreset,
+			netType:     operatorv1alpha1.NodePortServicePublishingType,
+			expected:    true,
+		},
+		{
+			description: "local development preset with a route",
+			preset:      operatorv1alpha1.LocalDevelopmentPublishingPreset,
+			netType:     operatorv1alpha1.RoutePublishingType,
+			expected:    false,
+		},
+		{
+			description: "local development preset with a gatewayclass",
+			preset:      operatorv1alpha1.LocalDevelopmentPublishingPreset,
+			netType:     operatorv1alpha1.LoadBalancerServicePublishingType,
+			gcRef:       &gcRef,
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Preset = tc.preset
+		cntr.Spec.NetworkPublishing.Envoy.Type = tc.netType
+		cntr.Spec.GatewayClassRef = tc.gcRef
+		err := validation.PublishingPreset(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestHostPorts(t *testing.T) {
+	testCases := []struct {
+		description string
+		strategy    operatorv1alpha1.EnvoyRolloutStrategyType
+		ports       []operatorv1alpha1.EnvoyHostPortMapping
+		expected    bool
+	}{
+		{
+			description: "no host ports",
+			strategy:    operatorv1alpha1.BlueGreenEnvoyRolloutStrategy,
+			expected:    true,
+		},
+		{
+			description: "http, https and extra host ports",
+			ports: []operatorv1alpha1.EnvoyHostPortMapping{
+				{ContainerPort: "http", HostPort: 80},
+				{ContainerPort: "https", HostPort: 443},
+				{ContainerPort: "postgres", HostPort: 5432},
+			},
+			expected: true,
+		},
+		{
+			description: "duplicate container port",
+			ports: []operatorv1alpha1.EnvoyHostPortMapping{
+				{ContainerPort: "http", HostPort: 80},
+				{ContainerPort: "http", HostPort: 8080},
+			},
+			expected: false,
+		},
+		{
+			description: "duplicate host port",
+			ports: []operatorv1alpha1.EnvoyHostPortMapping{
+				{ContainerPort: "http", HostPort: 80},
+				{ContainerPort: "https", HostPort: 80},
+			},
+			expected: false,
+		},
+		{
+			description: "undeclared container port",
+			ports:       []operatorv1alpha1.EnvoyHostPortMapping{{ContainerPort: "grpc", HostPort: 9000}},
+			expected:    false,
+		},
+		{
+			description: "host ports of a blue-green rollout",
+			strategy:    operatorv1alpha1.BlueGreenEnvoyRolloutStrategy,
+			ports:       []operatorv1alpha1.EnvoyHostPortMapping{{ContainerPort: "https", HostPort: 443}},
+			expected:    false,
+		},
+	}
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NetworkPublishing.Envoy.ContainerPorts = []operatorv1alpha1.ContainerPort{
+			{Name: "http", PortNumber: 8080},
+			{Name: "https", PortNumber: 8443},
+		}
+		cntr.Spec.NetworkPublishing.Envoy.ExtraPorts = []operatorv1alpha1.EnvoyExtraPort{
+			{Name: "postgres", ContainerPort: 5432, ServicePort: 5432},
+		}
+		if tc.strategy != "" {
+			cntr.Spec.EnvoyRollout = &operatorv1alpha1.EnvoyRollout{Strategy: tc.strategy}
+		}
+		cntr.Spec.NetworkPublishing.Envoy.HostPorts = tc.ports
+		err := validation.HostPorts(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestEnvoyOperatingSystems(t *testing.T) {
+	testCases := []struct {
+		description string
+		oses        []string
+		selector    map[string]string
+		expected    bool
+	}{
+		{
+			description: "default operating system",
+			expected:    true,
+		},
+		{
+			description: "default operating system with a linux node selector",
+			selector:    map[string]string{"kubernetes.io/os": "linux"},
+			expected:    true,
+		},
+		{
+			description: "default operating system with a windows node selector",
+			selector:    map[string]string{"kubernetes.io/os": "windows"},
+			expected:    false,
+		},
+		{
+			description: "windows operating system with a windows node selector",
+			oses:        []string{"windows"},
+			selector:    map[string]string{"kubernetes.io/os": "windows"},
+			expected:    true,
+		},
+		{
+			description: "duplicate operating systems",
+			oses:        []string{"linux", "linux"},
+			expected:    false,
+		},
+		{
+			description: "invalid operating system",
+			oses:        []string{"linux/amd64"},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.NodePlacement = &operatorv1alpha1.NodePlacement{
+			Envoy: &operatorv1alpha1.EnvoyNodePlacement{
+				NodeSelector:     tc.selector,
+				OperatingSystems: tc.oses,
+			},
+		}
+		err := validation.EnvoyOperatingSystems(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestKubernetesClient(t *testing.T) {
+	testCases := []struct {
+		description string
+		params      *operatorv1alpha1.KubernetesClientParameters
+		expected    bool
+	}{
+		{
+			description: "default rate limits",
+			expected:    true,
+		},
+		{
+			description: "qps and burst",
+			params:      &operatorv1alpha1.KubernetesClientParameters{QPS: 50, Burst: 100},
+			expected:    true,
+		},
+		{
+			description: "qps only",
+			params:      &operatorv1alpha1.KubernetesClientParameters{QPS: 50},
+			expected:    true,
+		},
+		{
+			description: "burst less than qps",
+			params:      &operatorv1alpha1.KubernetesClientParameters{QPS: 100, Burst: 50},
+			expected:    false,
+		},
+		{
+			description: "negative qps",
+			params:      &operatorv1alpha1.KubernetesClientParameters{QPS: -1},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.KubernetesClient = tc.params
+		err := validation.KubernetesClient(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestLogging(t *testing.T) {
+	testCases := []struct {
+		description string
+		params      *operatorv1alpha1.LoggingParameters
+		expected    bool
+	}{
+		{
+			description: "default logging",
+			expected:    true,
+		},
+		{
+			description: "debug logs with kubernetes verbosity",
+			params:      &operatorv1alpha1.LoggingParameters{Debug: true, KubernetesVerbosity: 9},
+			expected:    true,
+		},
+		{
+			description: "kubernetes verbosity out of range",
+			params:      &operatorv1alpha1.LoggingParameters{KubernetesVerbosity: 10},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.Logging = tc.params
+		err := validation.Logging(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestCertgen(t *testing.T) {
+	testCases := []struct {
+		description string
+		params      *operatorv1alpha1.CertgenParameters
+		expected    bool
+	}{
+		{
+			description: "default certgen",
+			expected:    true,
+		},
+		{
+			description: "requests within limits",
+			params: &operatorv1alpha1.CertgenParameters{
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "request exceeding its limit",
+			params: &operatorv1alpha1.CertgenParameters{
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		cntr.Spec.Certgen = tc.params
+		err := validation.Certgen(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestRootNamespaces(t *testing.T) {
+	testCases := []struct {
+		description string
+		namespaces  []string
+		expected    bool
+	}{
+		{
+			description: "no root namespaces",
+			expected:    true,
+		},
+		{
+			description: "valid root namespaces",
+			namespaces:  []string{"root-ns-1", "root-ns-2"},
+			expected:    true,
+		},
+		{
+			description: "invalid root namespace",
+			namespaces:  []string{"Root_NS"},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.RootNamespaces = tc.namespaces
+		err := validation.RootNamespaces(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestHealthCheckNodePort(t *testing.T) {
+	port := func(p int32) *int32 { return &p }
+	testCases := []struct {
+		description string
+		mutate      func(*operatorv1alpha1.EnvoyNetworkPublishing)
+		expected    bool
+	}{
+		{
+			description: "unset health check node port",
+			mutate:      func(*operatorv1alpha1.EnvoyNetworkPublishing) {},
+			expected:    true,
+		},
+		{
+			description: "health check node port in range",
+			mutate: func(e *operatorv1alpha1.EnvoyNetworkPublishing) {
+				e.LoadBalancer.HealthCheckNodePort = port(32000)
+			},
+			expected: true,
+		},
+		{
+			description: "health check node port out of range",
+			mutate: func(e *operatorv1alpha1.EnvoyNetworkPublishing) {
+				e.LoadBalancer.HealthCheckNodePort = port(8080)
+			},
+			expected: false,
+		},
+		{
+			description: "health check node port used by the https node port",
+			mutate: func(e *operatorv1alpha1.EnvoyNetworkPublishing) {
+				e.LoadBalancer.HealthCheckNodePort = port(30443)
+				e.NodePorts = []operatorv1alpha1.NodePort{
+					{Name: "http", PortNumber: port(30080)},
+					{Name: "https", PortNumber: port(30443)},
+				}
+			},
+			expected: false,
+		},
+		{
+			description: "health check node port used by an extra port",
+			mutate: func(e *operatorv1alpha1.EnvoyNetworkPublishing) {
+				e.LoadBalancer.HealthCheckNodePort = port(31000)
+				e.ExtraPorts = []operatorv1alpha1.EnvoyExtraPort{
+					{Name: "metrics", ContainerPort: 8002, ServicePort: 8002, NodePort: port(31000)},
+				}
+			},
+			expected: false,
+		},
+		{
+			description: "health check node port used by an additional service",
+			mutate: func(e *operatorv1alpha1.EnvoyNetworkPublishing) {
+				e.LoadBalancer.HealthCheckNodePort = port(31000)
+				e.AdditionalServices = []operatorv1alpha1.AdditionalEnvoyService{{
+					Name:  "internal",
+					Type:  operatorv1alpha1.NodePortServicePublishingType,
+					Ports: []operatorv1alpha1.EnvoyServicePort{{Name: "http", Port: 80, NodePort: port(31000)}},
+				}}
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{}
+		tc.mutate(&cntr.Spec.NetworkPublishing.Envoy)
+		err := validation.HealthCheckNodePort(cntr)
+		if err != nil && tc.expected {
+			t.Errorf("%q: failed with error: %v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Errorf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestNodePorts(t *testing.T) {
+	httpPort := int32(30080)
+	httpsPort := int32(30443)
+	otherHTTPPort := int32(30080)
+
+	testCases := []struct {
+		description string
+		ports       []operatorv1alpha1.NodePort
+		expected    bool
+	}{
+		{
+			description: "default http and https nodeports",
+			expected:    true,
+		},
+		{
+			description: "user-specified http and https nodeports",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name:       "https",
+					PortNumber: &httpsPort,
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "invalid port name",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name:       "foo",
+					PortNumber: &httpsPort,
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "auto-assigned https port number",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name: "https",
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "auto-assigned http and https port numbers",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name: "http",
+				},
+				{
+					Name: "https",
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "duplicate nodeport names",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name:       "http",
+					PortNumber: &httpsPort,
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "duplicate nodeport numbers",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name:       "https",
+					PortNumber: &httpPort,
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "duplicate nodeport numbers of different fields",
+			ports: []operatorv1alpha1.NodePort{
+				{
+					Name:       "http",
+					PortNumber: &httpPort,
+				},
+				{
+					Name:       "https",
+					PortNumber: &otherHTTPPort,
+				},
+			},
+			expected: false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		TypeMeta: metav1.TypeMeta{},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.NodePortServicePublishingType,
+					NodePorts: []operatorv1alpha1.NodePort{
+						{
+							Name:       "http",
+							PortNumber: &httpPort,
+						},
+						{
+							Name:       "https",
+							PortNumber: &httpsPort,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if tc.ports != nil {
+			cntr.Spec.NetworkPublishing.Envoy.NodePorts = tc.ports
+		}
+		err := validation.NodePorts(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestIngressClass(t *testing.T) {
+	ctx := context.Background()
+
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "ns",
+		},
+		Spec: operatorv1alpha1.ContourSpec{},
+	}
+
+	ic := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ic",
+		},
+		Spec: networkingv1.IngressClassSpec{},
+	}
+
+	testCases := map[string]struct {
+		class    string
+		exists   bool
+		expected bool
+	}{
+		"undefined ingress class name": {
+			expected: true,
+		},
+		"ingress class name exists": {
+			class:    "test-ic",
+			expected: true,
+		},
+		"ingress class name doesn't exist": {
+			class:    "not-exist",
+			expected: false,
+		},
+	}
+
+	// Build and create and instance of the client
+	builder := fake.NewClientBuilder()
+	scheme := operator.GetOperatorScheme()
+	builder.WithScheme(scheme)
+	cl := builder.Build()
+
+	// Created the referenced ingress class.
+	if err := cl.Create(ctx, ic); err != nil {
+		t.Fatalf("failed to create ingressclass: %#v", err)
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mutated := cntr.DeepCopy()
+			if len(tc.class) > 0 {
+				mutated.Spec.IngressClassName = pointer.StringPtr(tc.class)
+			}
+			err := validation.IngressClass(ctx, cl, mutated)
+			if err != nil && tc.expected {
+				t.Fatalf("failed with error: %#v", err)
+			}
+			if err == nil && !tc.expected {
+				t.Fatalf("expected to fail but received no error")
+			}
+		})
+	}
+}
+
+func TestResourceNameTemplate(t *testing.T) {
+	testCases := []struct {
+		description string
+		template    string
+		expected    bool
+	}{
+		{
+			description: "unset template",
+			expected:    true,
+		},
+		{
+			description: "contour name prefix",
+			template:    "{contour-name}-{resource}",
+			expected:    true,
+		},
+		{
+			description: "all placeholders",
+			template:    "acme-{contour-namespace}-{contour-name}-{resource}",
+			expected:    true,
+		},
+		{
+			description: "missing resource placeholder",
+			template:    "{contour-name}-envoy",
+			expected:    false,
+		},
+		{
+			description: "unknown placeholder",
+			template:    "{team}-{resource}",
+			expected:    false,
+		},
+		{
+			description: "invalid generated name",
+			template:    "{resource}-",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				ResourceNameTemplate: tc.template,
+			},
+		}
+		err := validation.ResourceNameTemplate(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestContourUpdate(t *testing.T) {
+	testCases := []struct {
+		description string
+		mutate      func(*operatorv1alpha1.Contour)
+		expected    bool
+	}{
+		{
+			description: "unchanged spec",
+			mutate:      func(*operatorv1alpha1.Contour) {},
+			expected:    true,
+		},
+		{
+			description: "changed replicas",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.Replicas = 3 },
+			expected:    true,
+		},
+		{
+			description: "share namespace",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.Namespace.Shared = true },
+			expected:    false,
+		},
+		{
+			description: "changed resource name template",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.ResourceNameTemplate = "{contour-name}-{resource}" },
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		old := &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				Replicas:  2,
+				Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			},
+		}
+		cntr := old.DeepCopy()
+		tc.mutate(cntr)
+		err := validation.ContourUpdate(old, cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestResourceNames(t *testing.T) {
+	prefix := func(n int) string { return strings.Repeat("a", n) + "-{resource}" }
+	testCases := []struct {
+		description string
+		mutate      func(*operatorv1alpha1.Contour)
+		expected    bool
+	}{
+		{
+			description: "default names",
+			mutate:      func(*operatorv1alpha1.Contour) {},
+			expected:    true,
+		},
+		{
+			description: "valid template",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.ResourceNameTemplate = prefix(30) },
+			expected:    true,
+		},
+		{
+			description: "template generating names that are too long",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.ResourceNameTemplate = prefix(53) },
+			expected:    false,
+		},
+		{
+			description: "additional service name too long",
+			mutate: func(c *operatorv1alpha1.Contour) {
+				c.Spec.ResourceNameTemplate = prefix(30)
+				c.Spec.NetworkPublishing.Envoy.AdditionalServices = []operatorv1alpha1.AdditionalEnvoyService{
+					{Name: strings.Repeat("b", 30)},
+				}
+			},
+			expected: false,
+		},
+		{
+			description: "certgen job name too long",
+			mutate:      func(c *operatorv1alpha1.Contour) { c.Spec.ResourceNameTemplate = prefix(45) },
+			expected:    false,
+		},
+		{
+			description: "shared namespace with a long contour name",
+			mutate: func(c *operatorv1alpha1.Contour) {
+				c.Name = strings.Repeat("a", 52)
+				c.Spec.Namespace.Shared = true
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+			},
+		}
+		tc.mutate(cntr)
+		err := validation.ResourceNames(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestSpecNamespace(t *testing.T) {
+	ctx := context.Background()
+
+	newContour := func(ns, name string, shared bool) *operatorv1alpha1.Contour {
+		return &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				Namespace: operatorv1alpha1.NamespaceSpec{
+					Name:   "projectcontour",
+					Shared: shared,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description string
+		existing    *operatorv1alpha1.Contour
+		contour     *operatorv1alpha1.Contour
+		expected    bool
+	}{
+		{
+			description: "no other contours",
+			contour:     newContour("ns", "test", false),
+			expected:    true,
+		},
+		{
+			description: "other contour in spec namespace",
+			existing:    newContour("ns", "other", false),
+			contour:     newContour("ns", "test", false),
+			expected:    false,
+		},
+		{
+			description: "other contour doesn't share spec namespace",
+			existing:    newContour("ns", "other", false),
+			contour:     newContour("ns", "test", true),
+			expected:    false,
+		},
+		{
+			description: "both contours share spec namespace",
+			existing:    newContour("ns", "other", true),
+			contour:     newContour("ns", "test", true),
+			expected:    true,
+		},
+		{
+			description: "shared contours with the same name",
+			existing:    newContour("other-ns", "test", true),
+			contour:     newContour("ns", "test", true),
+			expected:    false,
+		},
+		{
+			description: "shared contour template without contour name",
+			existing:    newContour("ns", "other", true),
+			contour: func() *operatorv1alpha1.Contour {
+				c := newContour("ns", "test", true)
+				c.Spec.ResourceNameTemplate = "acme-{resource}"
+				return c
+			}(),
+			expected: false,
+		},
+		{
+			description: "shared contour name yields invalid service name",
+			contour:     newContour("ns", "test.example", true),
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		builder := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme())
+		if tc.existing != nil {
+			builder.WithObjects(tc.existing)
 		}
-		err := validation.LoadBalancerProvider(cntr)
+		cl := builder.Build()
+		err := validation.SpecNamespace(ctx, cl, tc.contour)
 		if err != nil && tc.expected {
 			t.Fatalf("%q: failed with error: %#v", tc.description, err)
 		}
@@ -340,136 +2514,77 @@ func TestLoadBalancerProvider(t *testing.T) {
 	}
 }
 
-func TestNodePorts(t *testing.T) {
-	httpPort := int32(30080)
-	httpsPort := int32(30443)
-
+func TestLeaderElection(t *testing.T) {
+	ctx := context.TODO()
+	newContour := func(name, specNs string, params *operatorv1alpha1.LeaderElectionParameters) *operatorv1alpha1.Contour {
+		return &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				Namespace:      operatorv1alpha1.NamespaceSpec{Name: specNs},
+				LeaderElection: params,
+			},
+		}
+	}
 	testCases := []struct {
 		description string
-		ports       []operatorv1alpha1.NodePort
+		existing    *operatorv1alpha1.Contour
+		contour     *operatorv1alpha1.Contour
 		expected    bool
 	}{
 		{
-			description: "default http and https nodeports",
+			description: "default leader election",
+			contour:     newContour("test", "projectcontour", nil),
 			expected:    true,
 		},
 		{
-			description: "user-specified http and https nodeports",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name:       "http",
-					PortNumber: &httpPort,
-				},
-				{
-					Name:       "https",
-					PortNumber: &httpsPort,
-				},
-			},
+			description: "valid durations",
+			contour: newContour("test", "projectcontour", &operatorv1alpha1.LeaderElectionParameters{
+				LeaseDuration: "60s",
+				RenewDeadline: "40s",
+				RetryPeriod:   "5s",
+			}),
 			expected: true,
 		},
 		{
-			description: "invalid port name",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name:       "http",
-					PortNumber: &httpPort,
-				},
-				{
-					Name:       "foo",
-					PortNumber: &httpsPort,
-				},
-			},
-			expected: false,
-		},
-		{
-			description: "auto-assigned https port number",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name:       "http",
-					PortNumber: &httpPort,
-				},
-				{
-					Name: "https",
-				},
-			},
-			expected: true,
+			description: "lease duration does not exceed the default renew deadline",
+			contour:     newContour("test", "projectcontour", &operatorv1alpha1.LeaderElectionParameters{LeaseDuration: "10s"}),
+			expected:    false,
 		},
 		{
-			description: "auto-assigned http and https port numbers",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name: "http",
-				},
-				{
-					Name: "https",
-				},
-			},
-			expected: true,
+			description: "invalid configmap name",
+			contour:     newContour("test", "projectcontour", &operatorv1alpha1.LeaderElectionParameters{ConfigMapName: "Lock"}),
+			expected:    false,
 		},
 		{
-			description: "duplicate nodeport names",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name:       "http",
-					PortNumber: &httpPort,
-				},
-				{
-					Name:       "http",
-					PortNumber: &httpsPort,
-				},
-			},
+			description: "contour of another namespace with the same configmap",
+			existing: newContour("other", "other-ns", &operatorv1alpha1.LeaderElectionParameters{
+				ConfigMapNamespace: "projectcontour",
+			}),
+			contour:  newContour("test", "projectcontour", nil),
 			expected: false,
 		},
 		{
-			description: "duplicate nodeport numbers",
-			ports: []operatorv1alpha1.NodePort{
-				{
-					Name:       "http",
-					PortNumber: &httpPort,
-				},
-				{
-					Name:       "https",
-					PortNumber: &httpPort,
-				},
-			},
-			expected: false,
-		},
-	}
-
-	name := "test-validation"
-	cntr := &operatorv1alpha1.Contour{
-		TypeMeta: metav1.TypeMeta{},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: fmt.Sprintf("%s-ns", name),
-		},
-		Spec: operatorv1alpha1.ContourSpec{
-			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
-			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
-				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
-					Type: operatorv1alpha1.NodePortServicePublishingType,
-					NodePorts: []operatorv1alpha1.NodePort{
-						{
-							Name:       "http",
-							PortNumber: &httpPort,
-						},
-						{
-							Name:       "https",
-							PortNumber: &httpsPort,
-						},
-					},
-				},
-			},
+			description: "contour of another namespace with a distinct configmap",
+			existing: newContour("other", "other-ns", &operatorv1alpha1.LeaderElectionParameters{
+				ConfigMapName:      "other-lock",
+				ConfigMapNamespace: "projectcontour",
+			}),
+			contour:  newContour("test", "projectcontour", nil),
+			expected: true,
 		},
 	}
 
 	for _, tc := range testCases {
-		if tc.ports != nil {
-			cntr.Spec.NetworkPublishing.Envoy.NodePorts = tc.ports
+		builder := fake.NewClientBuilder().WithScheme(operator.GetOperatorScheme())
+		if tc.existing != nil {
+			builder.WithObjects(tc.existing)
 		}
-		err := validation.NodePorts(cntr)
+		err := validation.LeaderElection(ctx, builder.Build(), tc.contour)
 		if err != nil && tc.expected {
-			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+			t.Fatalf("%q: failed with error: %v", tc.description, err)
 		}
 		if err == nil && !tc.expected {
 			t.Fatalf("%q: expected to fail but received no error", tc.description)
@@ -477,67 +2592,72 @@ func TestNodePorts(t *testing.T) {
 	}
 }
 
-func TestIngressClass(t *testing.T) {
-	ctx := context.Background()
-
+func TestRoute(t *testing.T) {
 	cntr := &operatorv1alpha1.Contour{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
 			Namespace: "ns",
 		},
-		Spec: operatorv1alpha1.ContourSpec{},
-	}
-
-	ic := &networkingv1.IngressClass{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "test-ic",
+		Spec: operatorv1alpha1.ContourSpec{
+			NetworkPublishing: operatorv1alpha1.NetworkPublishing{
+				Envoy: operatorv1alpha1.EnvoyNetworkPublishing{
+					Type: operatorv1alpha1.RoutePublishingType,
+				},
+			},
 		},
-		Spec: networkingv1.IngressClassSpec{},
 	}
 
-	testCases := map[string]struct {
-		class    string
-		exists   bool
-		expected bool
+	testCases := []struct {
+		description string
+		apiExists   bool
+		params      *operatorv1alpha1.RouteParameters
+		expected    bool
 	}{
-		"undefined ingress class name": {
-			expected: true,
+		{
+			description: "route api exists",
+			apiExists:   true,
+			expected:    true,
 		},
-		"ingress class name exists": {
-			class:    "test-ic",
+		{
+			description: "route api does not exist",
+			apiExists:   false,
+			expected:    false,
+		},
+		{
+			description: "reencrypt termination with destination ca",
+			apiExists:   true,
+			params: &operatorv1alpha1.RouteParameters{
+				Termination:              operatorv1alpha1.ReencryptRouteTermination,
+				DestinationCACertificate: pointer.StringPtr("test-ca"),
+			},
 			expected: true,
 		},
-		"ingress class name doesn't exist": {
-			class:    "not-exist",
+		{
+			description: "passthrough termination with destination ca",
+			apiExists:   true,
+			params: &operatorv1alpha1.RouteParameters{
+				Termination:              operatorv1alpha1.PassthroughRouteTermination,
+				DestinationCACertificate: pointer.StringPtr("test-ca"),
+			},
 			expected: false,
 		},
 	}
 
-	// Build and create and instance of the client
-	builder := fake.NewClientBuilder()
-	scheme := operator.GetOperatorScheme()
-	builder.WithScheme(scheme)
-	cl := builder.Build()
-
-	// Created the referenced ingress class.
-	if err := cl.Create(ctx, ic); err != nil {
-		t.Fatalf("failed to create ingressclass: %#v", err)
-	}
-
-	for name, tc := range testCases {
-		t.Run(name, func(t *testing.T) {
-			mutated := cntr.DeepCopy()
-			if len(tc.class) > 0 {
-				mutated.Spec.IngressClassName = pointer.StringPtr(tc.class)
-			}
-			err := validation.IngressClass(ctx, cl, mutated)
-			if err != nil && tc.expected {
-				t.Fatalf("failed with error: %#v", err)
-			}
-			if err == nil && !tc.expected {
-				t.Fatalf("expected to fail but received no error")
-			}
-		})
+	for _, tc := range testCases {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		if tc.apiExists {
+			mapper.Add(objroute.GroupVersionKind, meta.RESTScopeNamespace)
+		}
+		cl := &objutil.MappedClient{Client: fake.NewClientBuilder().Build(), Mapper: mapper}
+		mutated := cntr.DeepCopy()
+		mutated.Spec.NetworkPublishing.Envoy.Route = tc.params
+		err := validation.Route(cl, mutated)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
 	}
 }
 
@@ -1464,3 +3584,116 @@ func newGatewayClassAdmittedStatus() gatewayv1alpha1.GatewayClassStatus {
 		},
 	}
 }
+
+func TestRetentionPolicy(t *testing.T) {
+	testCases := []struct {
+		description string
+		ownership   operatorv1alpha1.OwnershipType
+		specNs      string
+		policy      *operatorv1alpha1.RetentionPolicy
+		expected    bool
+	}{
+		{
+			description: "no retention policy",
+			ownership:   operatorv1alpha1.OwnerReferencesOwnershipType,
+			specNs:      "test-validation-ns",
+			expected:    true,
+		},
+		{
+			description: "retained services with labels ownership",
+			ownership:   operatorv1alpha1.LabelsOwnershipType,
+			specNs:      "test-validation-ns",
+			policy:      &operatorv1alpha1.RetentionPolicy{Services: operatorv1alpha1.RetainAction},
+			expected:    true,
+		},
+		{
+			description: "retained services with owner references in another namespace",
+			ownership:   operatorv1alpha1.OwnerReferencesOwnershipType,
+			specNs:      "projectcontour",
+			policy:      &operatorv1alpha1.RetentionPolicy{Services: operatorv1alpha1.RetainAction},
+			expected:    true,
+		},
+		{
+			description: "retained rbac with owner references in the contour namespace",
+			ownership:   operatorv1alpha1.OwnerReferencesOwnershipType,
+			specNs:      "test-validation-ns",
+			policy:      &operatorv1alpha1.RetentionPolicy{RBAC: operatorv1alpha1.RetainAction},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	for _, tc := range testCases {
+		cntr := &operatorv1alpha1.Contour{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: fmt.Sprintf("%s-ns", name),
+			},
+			Spec: operatorv1alpha1.ContourSpec{
+				Namespace:       operatorv1alpha1.NamespaceSpec{Name: tc.specNs},
+				Ownership:       tc.ownership,
+				RetentionPolicy: tc.policy,
+			},
+		}
+		err := validation.RetentionPolicy(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	testCases := []struct {
+		description string
+		windows     []operatorv1alpha1.MaintenanceWindow
+		expected    bool
+	}{
+		{
+			description: "no maintenance windows",
+			expected:    true,
+		},
+		{
+			description: "valid maintenance windows",
+			windows: []operatorv1alpha1.MaintenanceWindow{
+				{Start: "02:00", Duration: "4h"},
+				{Days: []operatorv1alpha1.Weekday{"Saturday", "Sunday"}, Start: "22:30", Duration: "90m"},
+			},
+			expected: true,
+		},
+		{
+			description: "invalid maintenance window start",
+			windows:     []operatorv1alpha1.MaintenanceWindow{{Start: "25:00", Duration: "4h"}},
+			expected:    false,
+		},
+		{
+			description: "maintenance window longer than a week",
+			windows:     []operatorv1alpha1.MaintenanceWindow{{Start: "02:00", Duration: "169h"}},
+			expected:    false,
+		},
+	}
+
+	name := "test-validation"
+	cntr := &operatorv1alpha1.Contour{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("%s-ns", name),
+		},
+		Spec: operatorv1alpha1.ContourSpec{
+			Namespace: operatorv1alpha1.NamespaceSpec{Name: "projectcontour"},
+		},
+	}
+
+	for _, tc := range testCases {
+		cntr.Spec.UpdatePolicy = &operatorv1alpha1.UpdatePolicy{MaintenanceWindows: tc.windows}
+		err := validation.UpdatePolicy(cntr)
+		if err != nil && tc.expected {
+			t.Fatalf("%q: failed with error: %#v", tc.description, err)
+		}
+		if err == nil && !tc.expected {
+			t.Fatalf("%q: expected to fail but received no error", tc.description)
+		}
+	}
+}